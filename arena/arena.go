@@ -0,0 +1,136 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package arena implements a typed, chunked allocator for workloads that
+build many small values, such as the nodes of a tree or skip list, use
+them for a while and then discard them all at once.
+
+Values are carved out of large chunks, so the garbage collector tracks
+a handful of allocations rather than one per value, and Reset makes
+every chunk available again without returning it to the runtime.
+Values handed out by an arena must not be used after it is reset.
+*/
+package arena
+
+// defaultChunkSize is the number of values in a chunk when no size is
+// given.
+const defaultChunkSize = 1024
+
+// Arena allocates values of type T from chunks.  The zero value is an
+// empty arena with the default chunk size.  It is not safe for
+// concurrent use.
+type Arena[T any] struct {
+	chunks    [][]T
+	current   int // index of the chunk being allocated from
+	used      int // values allocated from the current chunk
+	chunkSize int
+	len       int
+}
+
+// New returns an empty arena that allocates chunks of chunkSize
+// values, or of 1024 if chunkSize is not positive.
+func New[T any](chunkSize int) *Arena[T] {
+	return &Arena[T]{chunkSize: chunkSize}
+}
+
+// New returns a pointer to a new zero value.
+func (a *Arena[T]) New() *T {
+	return &a.Make(1)[0]
+}
+
+// Make returns a slice of n zero values, with capacity n so that
+// appending to it never writes over other values in the arena.  Slices
+// longer than a chunk get a chunk of their own.
+func (a *Arena[T]) Make(n int) []T {
+	if n < 0 {
+		panic(`arena: negative length`)
+	}
+	if n == 0 {
+		return []T{}
+	}
+
+	if len(a.chunks) == 0 || a.used+n > len(a.chunks[a.current]) {
+		a.advance(n)
+	}
+	s := a.chunks[a.current][a.used : a.used+n : a.used+n]
+	a.used += n
+	a.len += n
+	return s
+}
+
+// advance moves to the next chunk with room for n values, reusing the
+// chunks left by Reset when they are large enough and allocating a new
+// one otherwise.
+func (a *Arena[T]) advance(n int) {
+	next := a.current + 1
+	if len(a.chunks) == 0 {
+		next = 0
+	}
+	size := a.chunkSize
+	if size <= 0 {
+		size = defaultChunkSize
+	}
+
+	if next >= len(a.chunks) || len(a.chunks[next]) < n {
+		a.chunks = append(a.chunks, nil)
+		copy(a.chunks[next+1:], a.chunks[next:])
+		a.chunks[next] = make([]T, max(size, n))
+	}
+	a.current = next
+	a.used = 0
+}
+
+// Len returns the number of values allocated since the arena was
+// created or last reset.
+func (a *Arena[T]) Len() int {
+	return a.len
+}
+
+// Cap returns the number of values the arena's chunks can hold.
+func (a *Arena[T]) Cap() int {
+	total := 0
+	for _, chunk := range a.chunks {
+		total += len(chunk)
+	}
+	return total
+}
+
+// Reset frees every value allocated from the arena, zeroing them so
+// that anything they referenced can be collected, and keeps the chunks
+// to allocate from again.  Values allocated before the reset must no
+// longer be used.
+func (a *Arena[T]) Reset() {
+	for i := 0; i < len(a.chunks) && i <= a.current; i++ {
+		if i == a.current {
+			clear(a.chunks[i][:a.used])
+		} else {
+			clear(a.chunks[i])
+		}
+	}
+	a.current = 0
+	a.used = 0
+	a.len = 0
+}
+
+// Release drops the arena's chunks, returning their memory to the
+// runtime once no values allocated from them are referenced.
+func (a *Arena[T]) Release() {
+	a.chunks = nil
+	a.current = 0
+	a.used = 0
+	a.len = 0
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package arena
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type node struct {
+	value int
+	next  *node
+}
+
+func TestNew(t *testing.T) {
+	a := New[node](4)
+	var head *node
+	for i := range 10 {
+		n := a.New()
+		assert.Equal(t, node{}, *n)
+		n.value = i
+		n.next = head
+		head = n
+	}
+	assert.Equal(t, 10, a.Len())
+	assert.Equal(t, 12, a.Cap())
+
+	for i := 9; i >= 0; i-- {
+		assert.Equal(t, i, head.value)
+		head = head.next
+	}
+	assert.Nil(t, head)
+}
+
+func TestMake(t *testing.T) {
+	a := New[int](8)
+	first := a.Make(3)
+	second := a.Make(3)
+	assert.Len(t, first, 3)
+	assert.Equal(t, 3, cap(first))
+
+	// appending reallocates rather than writing over second
+	first = append(first, 1)
+	assert.Equal(t, []int{0, 0, 0}, second)
+
+	// a slice that does not fit moves on to a new chunk
+	third := a.Make(4)
+	assert.Len(t, third, 4)
+	assert.Equal(t, 16, a.Cap())
+
+	// and one larger than a chunk gets its own
+	large := a.Make(20)
+	assert.Len(t, large, 20)
+	assert.Equal(t, 36, a.Cap())
+	assert.Equal(t, 30, a.Len())
+
+	assert.Equal(t, []int{}, a.Make(0))
+	assert.Panics(t, func() { a.Make(-1) })
+}
+
+func TestReset(t *testing.T) {
+	a := New[*int](4)
+	for range 10 {
+		p := a.New()
+		*p = new(int)
+	}
+	capacity := a.Cap()
+
+	a.Reset()
+	assert.Equal(t, 0, a.Len())
+	assert.Equal(t, capacity, a.Cap())
+	for _, chunk := range a.chunks {
+		for _, p := range chunk {
+			assert.Nil(t, p)
+		}
+	}
+
+	// the chunks are reused
+	for range 10 {
+		assert.Nil(t, *a.New())
+	}
+	assert.Equal(t, capacity, a.Cap())
+
+	// a slice too large for the next chunk gets a new one in its place
+	a.Reset()
+	a.Make(3)
+	a.Make(6)
+	assert.Equal(t, capacity+6, a.Cap())
+	a.Make(4)
+	assert.Equal(t, capacity+6, a.Cap())
+
+	a.Release()
+	assert.Equal(t, 0, a.Cap())
+	assert.NotNil(t, a.New())
+}
+
+func TestZeroValue(t *testing.T) {
+	var a Arena[int]
+	*a.New() = 1
+	assert.Equal(t, defaultChunkSize, a.Cap())
+}
+
+func BenchmarkArena(b *testing.B) {
+	a := New[node](0)
+	for i := 0; i < b.N; i++ {
+		var head *node
+		for j := range 1000 {
+			n := a.New()
+			n.value = j
+			n.next = head
+			head = n
+		}
+		a.Reset()
+	}
+}
+
+func BenchmarkHeap(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		var head *node
+		for j := range 1000 {
+			head = &node{value: j, next: head}
+		}
+	}
+}
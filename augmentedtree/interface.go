@@ -24,6 +24,9 @@ tree can tell you if that plane overlaps with a plane defined by
 intersections against a point by constructing a range of encompassed
 solely if a single point.
 
+For a single dimension, NewRange and Point construct half-open intervals
+that can be added to and queried against the tree directly.
+
 The current tree is a simple top-down red-black binary search tree.
 
 TODO: Add a bottom-up implementation to assist with duplicate
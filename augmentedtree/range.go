@@ -0,0 +1,89 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package augmentedtree
+
+import "fmt"
+
+// Range is a one-dimensional, half-open interval [low, high).  The tree
+// itself works with inclusive ranges, so a Range reports its last
+// included value, high-1, as its high bound at the first dimension.
+// This makes ranges that merely touch, such as [0, 5) and [5, 10),
+// disjoint, while a query for a Point returns every range containing
+// it.  A Range only has a first dimension and is meant for trees
+// constructed with New(1).
+type Range struct {
+	// last is the last value in the range, high-1, which unlike high
+	// can be represented for a Point at math.MaxInt64.
+	low, last int64
+	id        uint64
+}
+
+// NewRange returns the range [low, high) with the provided ID.  It
+// panics if the range is empty, that is if high is not greater than
+// low.
+func NewRange(low, high int64, id uint64) *Range {
+	if high <= low {
+		panic(fmt.Sprintf(`augmentedtree: empty range [%d, %d)`, low, high))
+	}
+
+	return &Range{low: low, last: high - 1, id: id}
+}
+
+// Point returns the range containing solely the provided point, for use
+// with Query.  Any int64, including math.MaxInt64, is a valid point.
+func Point(point int64) *Range {
+	return &Range{low: point, last: point}
+}
+
+// Low returns the first value in the range.
+func (r *Range) Low() int64 {
+	return r.low
+}
+
+// High returns the value just past the end of the range.  For a Point
+// at math.MaxInt64, that value cannot be represented and High wraps
+// around to math.MinInt64; Last returns the end of any range.
+func (r *Range) High() int64 {
+	return r.last + 1
+}
+
+// Last returns the last value in the range, High()-1.
+func (r *Range) Last() int64 {
+	return r.last
+}
+
+// LowAtDimension returns the first value in the range.
+func (r *Range) LowAtDimension(uint64) int64 {
+	return r.low
+}
+
+// HighAtDimension returns the last value in the range.
+func (r *Range) HighAtDimension(uint64) int64 {
+	return r.last
+}
+
+// OverlapsAtDimension returns a bool indicating if the provided interval
+// shares a value with this range.
+func (r *Range) OverlapsAtDimension(iv Interval, dimension uint64) bool {
+	return r.low <= iv.HighAtDimension(dimension) &&
+		r.last >= iv.LowAtDimension(dimension)
+}
+
+// ID returns the ID of the range.
+func (r *Range) ID() uint64 {
+	return r.id
+}
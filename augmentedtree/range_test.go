@@ -0,0 +1,118 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package augmentedtree
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func rangeIDs(ivs Intervals) []uint64 {
+	ids := make([]uint64, 0, len(ivs))
+	for _, iv := range ivs {
+		ids = append(ids, iv.ID())
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func TestRangeHalfOpen(t *testing.T) {
+	it := New(1)
+	it.Add(NewRange(0, 5, 1), NewRange(5, 10, 2), NewRange(3, 7, 3))
+
+	assert.Equal(t, []uint64{1, 3}, rangeIDs(it.Query(Point(4))))
+	assert.Equal(t, []uint64{2, 3}, rangeIDs(it.Query(Point(5))))
+	assert.Equal(t, []uint64{2}, rangeIDs(it.Query(Point(9))))
+	assert.Empty(t, it.Query(Point(10)))
+	assert.Empty(t, it.Query(Point(-1)))
+	assert.Equal(t, []uint64{1, 3}, rangeIDs(it.Query(NewRange(0, 5, 0))))
+	assert.Equal(t, []uint64{1, 2, 3}, rangeIDs(it.Query(NewRange(4, 6, 0))))
+
+	r := NewRange(2, 4, 7)
+	assert.Equal(t, int64(2), r.Low())
+	assert.Equal(t, int64(4), r.High())
+	assert.Equal(t, uint64(7), r.ID())
+}
+
+func TestPointExtremes(t *testing.T) {
+	it := New(1)
+	it.Add(NewRange(math.MaxInt64-1, math.MaxInt64, 1), NewRange(math.MinInt64, 0, 2))
+
+	assert.Equal(t, []uint64{1}, rangeIDs(it.Query(Point(math.MaxInt64-1))))
+	assert.Empty(t, it.Query(Point(math.MaxInt64)))
+	assert.Equal(t, []uint64{2}, rangeIDs(it.Query(Point(math.MinInt64))))
+
+	it.Add(Point(math.MaxInt64))
+	assert.Len(t, it.Query(Point(math.MaxInt64)), 1)
+	assert.Equal(t, int64(math.MaxInt64), Point(math.MaxInt64).Last())
+}
+
+func TestNewRangeEmpty(t *testing.T) {
+	assert.Panics(t, func() { NewRange(3, 3, 0) })
+	assert.Panics(t, func() { NewRange(3, 2, 0) })
+}
+
+func TestRangeRandomOperations(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	it := newTree(1)
+	live := make(map[uint64]*Range)
+
+	for i := range 5000 {
+		if len(live) > 0 && rng.Intn(3) == 0 {
+			for id, r := range live {
+				it.Delete(r)
+				delete(live, id)
+				break
+			}
+		} else {
+			low := rng.Int63n(1000)
+			r := NewRange(low, low+1+rng.Int63n(50), uint64(i))
+			it.Add(r)
+			live[r.ID()] = r
+		}
+
+		if i%50 != 0 {
+			continue
+		}
+		checkRedBlack(t, it.root, 1)
+		if !assert.Equal(t, uint64(len(live)), it.Len()) {
+			return
+		}
+
+		low := rng.Int63n(1100) - 50
+		query := NewRange(low, low+1+rng.Int63n(20), 0)
+		var expected []uint64
+		for id, r := range live {
+			if r.Low() < query.High() && query.Low() < r.High() {
+				expected = append(expected, id)
+			}
+		}
+		sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+
+		result := it.Query(query)
+		if len(expected) == 0 {
+			assert.Empty(t, result)
+		} else if !assert.Equal(t, expected, rangeIDs(result)) {
+			return
+		}
+		result.Dispose()
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2015 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batcher
+
+import (
+	"time"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+// Reason describes why a batch was completed.
+type Reason uint8
+
+const (
+	// ReasonFlush means the batch was completed by an explicit flush.
+	ReasonFlush Reason = iota
+	// ReasonMaxItems means the batch reached MaxItems.
+	ReasonMaxItems
+	// ReasonMaxBytes means the batch reached MaxBytes.
+	ReasonMaxBytes
+	// ReasonMaxTime means MaxTime elapsed since the first item was added.
+	ReasonMaxTime
+)
+
+func (r Reason) String() string {
+	switch r {
+	case ReasonFlush:
+		return "flush"
+	case ReasonMaxItems:
+		return "max items"
+	case ReasonMaxBytes:
+		return "max bytes"
+	case ReasonMaxTime:
+		return "max time"
+	}
+	return "unknown"
+}
+
+// Batch is a completed batch along with the context in which it was
+// completed.
+type Batch[T any] struct {
+	// Items are the items of the batch, those of higher priority first
+	// (see Config.Priorities) and otherwise in the order they were added.
+	Items []T
+	// Reason is the condition that completed the batch.
+	Reason Reason
+	// Age is the time between the first item being added and the
+	// batch being completed. Zero for an empty batch.
+	Age time.Duration
+	// Bytes is the total size of the items as reported by
+	// CalculateBytes, or zero if none was configured.
+	Bytes uint
+	// Retries is the number of times the batch has been handed back
+	// with ReturnBatch.
+	Retries uint
+}
+
+// observe reports the completion of the batch to m, if it is set.
+func (b Batch[T]) observe(m common.Metrics) {
+	if m == nil {
+		return
+	}
+	m.Count("batcher.batches", 1)
+	m.Gauge("batcher.batch_items", float64(len(b.Items)))
+	m.Gauge("batcher.batch_bytes", float64(b.Bytes))
+	m.Time("batcher.batch_age", b.Age)
+}
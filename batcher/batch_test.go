@@ -0,0 +1,110 @@
+/*
+Copyright 2015 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBatchReasons(t *testing.T) {
+	b, err := New[string](Config[string]{
+		MaxItems:       3,
+		MaxBytes:       10,
+		MaxTime:        20 * time.Millisecond,
+		CalculateBytes: func(s string) uint { return uint(len(s)) },
+	})
+	require.NoError(t, err)
+	defer b.Dispose()
+
+	require.NoError(t, b.Put("a"))
+	require.NoError(t, b.Put("b"))
+	require.NoError(t, b.Put("c"))
+	batch, err := b.GetBatch()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, batch.Items)
+	assert.Equal(t, ReasonMaxItems, batch.Reason)
+	assert.Equal(t, uint(3), batch.Bytes)
+
+	require.NoError(t, b.Put("0123456789"))
+	batch, err = b.GetBatch()
+	require.NoError(t, err)
+	assert.Equal(t, ReasonMaxBytes, batch.Reason)
+	assert.Equal(t, uint(10), batch.Bytes)
+
+	require.NoError(t, b.Put("d"))
+	require.NoError(t, b.Flush())
+	batch, err = b.GetBatch()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"d"}, batch.Items)
+	assert.Equal(t, ReasonFlush, batch.Reason)
+
+	require.NoError(t, b.Put("e"))
+	batch, err = b.GetBatch()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"e"}, batch.Items)
+	assert.Equal(t, ReasonMaxTime, batch.Reason)
+	assert.True(t, batch.Age >= 20*time.Millisecond)
+}
+
+func TestGetBatchEmptyFlush(t *testing.T) {
+	b, err := New[int](Config[int]{})
+	require.NoError(t, err)
+	defer b.Dispose()
+
+	require.NoError(t, b.Flush())
+	batch, err := b.GetBatch()
+	require.NoError(t, err)
+	assert.Empty(t, batch.Items)
+	assert.Equal(t, time.Duration(0), batch.Age)
+}
+
+func TestGetBatchDisposed(t *testing.T) {
+	b, err := New[int](Config[int]{})
+	require.NoError(t, err)
+	b.Dispose()
+
+	_, err = b.GetBatch()
+	assert.Equal(t, ErrDisposed, err)
+}
+
+func TestKeyedBatcherGetBatch(t *testing.T) {
+	kb, err := NewKeyed[string, int](KeyedConfig[string, int]{
+		Config: Config[int]{MaxItems: 2},
+	})
+	require.NoError(t, err)
+	defer kb.Dispose()
+
+	require.NoError(t, kb.Put("a", 1))
+	require.NoError(t, kb.Put("a", 2))
+	key, batch, err := kb.GetBatch()
+	require.NoError(t, err)
+	assert.Equal(t, "a", key)
+	assert.Equal(t, []int{1, 2}, batch.Items)
+	assert.Equal(t, ReasonMaxItems, batch.Reason)
+}
+
+func TestReasonString(t *testing.T) {
+	assert.Equal(t, "flush", ReasonFlush.String())
+	assert.Equal(t, "max items", ReasonMaxItems.String())
+	assert.Equal(t, "max bytes", ReasonMaxBytes.String())
+	assert.Equal(t, "max time", ReasonMaxTime.String())
+	assert.Equal(t, "unknown", Reason(42).String())
+}
@@ -21,7 +21,7 @@ for processing.
 Batch readiness can be determined by:
   - Maximum number of bytes per batch
   - Maximum number of items per batch
-  - Maximum amount of time waiting for a batch
+  - Maximum amount of time since the first item of a batch was added
 
 Example usage:
 
@@ -43,8 +43,12 @@ Example usage:
 package batcher
 
 import (
+	"context"
 	"errors"
+	"sync"
 	"time"
+
+	"github.com/Workiva/go-datastructures/common"
 )
 
 // mutex is a simple TryLock-capable mutex using channels
@@ -73,15 +77,29 @@ func (m *mutex) TryLock() bool {
 	}
 }
 
-// ErrDisposed is returned when an operation is attempted on a disposed Batcher.
-var ErrDisposed = errors.New("batcher: disposed")
+// ErrDisposed is returned when an operation is attempted on a disposed
+// Batcher.  It is an alias of common.ErrDisposed, so it also matches the
+// queue package's ErrDisposed.
+var ErrDisposed = common.ErrDisposed
+
+// ErrRetriesExhausted is returned by ReturnBatch when the batch has
+// already been returned MaxRetries times.
+var ErrRetriesExhausted = errors.New("batcher: retries exhausted")
+
+// ErrUnknownPriority is returned by PutPriority when the priority was
+// not configured.
+var ErrUnknownPriority = errors.New("batcher: unknown priority")
+
+var errNoCalculateBytes = errors.New("batcher: must provide CalculateBytes function when MaxBytes is set")
 
 // CalculateBytes evaluates the number of bytes in an item added to a Batcher.
 type CalculateBytes[T any] func(T) uint
 
 // Config holds configuration options for creating a Batcher.
 type Config[T any] struct {
-	// MaxTime is the maximum time to wait before returning a batch.
+	// MaxTime is the maximum amount of time a batch may be open, measured
+	// from when its first item was added. Once it elapses the batch is
+	// completed regardless of whether anyone is waiting in Get.
 	// A zero value means no time limit.
 	MaxTime time.Duration
 
@@ -101,25 +119,75 @@ type Config[T any] struct {
 	// QueueLen is the buffer size for completed batches.
 	// Defaults to 10 if not specified.
 	QueueLen uint
+
+	// Overflow determines what happens when a batch is completed while
+	// QueueLen completed batches are already waiting for a consumer.
+	// Defaults to OverflowBlock.
+	Overflow OverflowPolicy
+
+	// Priorities configures priority classes above the default priority
+	// of 0 used by Put. Priorities[p-1] is the MaxTime applied to a batch
+	// once it holds an item put with priority p, letting urgent items
+	// complete a batch sooner; a zero value falls back to MaxTime. Items
+	// of higher priority are placed first within a batch.
+	Priorities []time.Duration
+
+	// MaxRetries is the number of times a batch may be handed back with
+	// ReturnBatch, or by a failing ErrorHandler, to be delivered again.
+	// A zero value disables retries.
+	MaxRetries uint
+
+	// OnRetriesExhausted is optionally called by a Batcher created with
+	// NewWithErrorHandler with a batch that failed on its final attempt
+	// along with the error from that attempt.
+	OnRetriesExhausted func(batch []T, err error)
+
+	// Concurrency is the number of goroutines invoking the handler of a
+	// Batcher created with NewWithHandler. Ignored by New.
+	// Defaults to 1 if not specified.
+	Concurrency uint
+
+	// Metrics optionally receives operational measurements:
+	//
+	//	batcher.batches      counter, batches completed
+	//	batcher.batch_items  gauge, the number of items in each batch
+	//	batcher.batch_bytes  gauge, the size in bytes of each batch
+	//	batcher.batch_age    timer, the age of each batch when completed
+	//	batcher.queued       gauge, completed batches awaiting a consumer
+	//	batcher.dropped      counter, batches discarded by OverflowDropOldest
+	//	batcher.waits        counter, producers blocked by a full queue
+	//	batcher.wait         timer, how long each blocked producer waited
+	//
+	// Batches handed back for a retry are not counted again.
+	Metrics common.Metrics
+}
+
+// Limits are the conditions under which a batch is complete.
+// A zero value for any field means that limit is not applied.
+type Limits struct {
+	MaxTime  time.Duration
+	MaxItems uint
+	MaxBytes uint
 }
 
 // Batcher provides an API for accumulating items into batches for processing.
 type Batcher[T any] struct {
-	maxTime        time.Duration
-	maxItems       uint
-	maxBytes       uint
+	limits         Limits
+	priorities     []time.Duration
+	maxRetries     uint
+	hint           uint
 	calculateBytes CalculateBytes[T]
 	disposed       bool
-	items          []T
-	batchChan      chan []T
-	availableBytes uint
+	pending        *pending[T]
+	batches        *outbox[Batch[T]]
 	lock           *mutex
+	workers        sync.WaitGroup
 }
 
 // New creates a new Batcher with the given configuration.
 func New[T any](config Config[T]) (*Batcher[T], error) {
 	if config.MaxBytes > 0 && config.CalculateBytes == nil {
-		return nil, errors.New("batcher: must provide CalculateBytes function when MaxBytes is set")
+		return nil, errNoCalculateBytes
 	}
 
 	queueLen := config.QueueLen
@@ -133,97 +201,227 @@ func New[T any](config Config[T]) (*Batcher[T], error) {
 	}
 
 	return &Batcher[T]{
-		maxTime:        config.MaxTime,
-		maxItems:       config.MaxItems,
-		maxBytes:       config.MaxBytes,
+		limits: Limits{
+			MaxTime:  config.MaxTime,
+			MaxItems: config.MaxItems,
+			MaxBytes: config.MaxBytes,
+		},
+		priorities:     config.Priorities,
+		maxRetries:     config.MaxRetries,
+		hint:           maxItems,
 		calculateBytes: config.CalculateBytes,
-		items:          make([]T, 0, maxItems),
-		batchChan:      make(chan []T, queueLen),
+		pending:        newPending[T](maxItems, len(config.Priorities)+1),
+		batches:        newOutbox[Batch[T]](queueLen, config.Overflow, config.Metrics),
 		lock:           newMutex(),
 	}, nil
 }
 
-// Put adds an item to the batcher.
+// Put adds an item to the batcher. If the item completes a batch while
+// the queue of completed batches is full, the configured OverflowPolicy
+// applies.
 // Returns ErrDisposed if the batcher has been disposed.
 func (b *Batcher[T]) Put(item T) error {
+	return b.PutContext(context.Background(), item)
+}
+
+// PutContext adds an item to the batcher. If the item completes a batch
+// while the queue of completed batches is full and the OverflowBlock
+// policy is in use, PutContext waits for room until the context is done,
+// in which case the item is not added and the context's error is returned.
+// Returns ErrDisposed if the batcher has been disposed or ErrQueueFull
+// if the item was rejected by the OverflowError policy.
+func (b *Batcher[T]) PutContext(ctx context.Context, item T) error {
 	b.lock.Lock()
+	defer b.lock.Unlock()
+
 	if b.disposed {
-		b.lock.Unlock()
 		return ErrDisposed
 	}
+	return b.put(ctx, 0, item, b.size(item))
+}
+
+// PutPriority adds an item with the given priority, which must be at
+// most len(Config.Priorities). Higher priorities are placed first within
+// a batch and may complete it sooner.
+// Returns ErrDisposed if the batcher has been disposed or
+// ErrUnknownPriority if the priority was not configured.
+func (b *Batcher[T]) PutPriority(priority uint, item T) error {
+	return b.PutPriorityContext(context.Background(), priority, item)
+}
 
-	b.items = append(b.items, item)
-	if b.calculateBytes != nil {
-		b.availableBytes += b.calculateBytes(item)
+// PutPriorityContext is like PutPriority, but gives up when the context
+// is done if it must wait for room in the queue of completed batches.
+// See PutContext.
+func (b *Batcher[T]) PutPriorityContext(ctx context.Context, priority uint, item T) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.disposed {
+		return ErrDisposed
 	}
-	if b.ready() {
-		b.flush()
+	if priority > uint(len(b.priorities)) {
+		return ErrUnknownPriority
 	}
+	return b.put(ctx, int(priority), item, b.size(item))
+}
 
-	b.lock.Unlock()
+// PutSized adds an item whose size in bytes is already known, skipping
+// CalculateBytes.
+// Returns ErrDisposed if the batcher has been disposed.
+func (b *Batcher[T]) PutSized(item T, bytes uint) error {
+	return b.PutSizedContext(context.Background(), item, bytes)
+}
+
+// PutSizedContext is like PutSized, but gives up when the context is
+// done if it must wait for room in the queue of completed batches.
+// See PutContext.
+func (b *Batcher[T]) PutSizedContext(ctx context.Context, item T, bytes uint) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.disposed {
+		return ErrDisposed
+	}
+	return b.put(ctx, 0, item, bytes)
+}
+
+// PutMany adds the items in order under a single acquisition of the
+// lock, completing as many batches as they fill. If the OverflowPolicy
+// rejects an item, the items before it remain added and the error is
+// returned.
+// Returns ErrDisposed if the batcher has been disposed.
+func (b *Batcher[T]) PutMany(items ...T) error {
+	return b.PutManyContext(context.Background(), items...)
+}
+
+// PutManyContext is like PutMany, but gives up when the context is
+// done if it must wait for room in the queue of completed batches,
+// leaving the items before the one waiting added. See PutContext.
+func (b *Batcher[T]) PutManyContext(ctx context.Context, items ...T) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.disposed {
+		return ErrDisposed
+	}
+	for _, item := range items {
+		if err := b.put(ctx, 0, item, b.size(item)); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // Get retrieves a batch from the batcher. This call will block until
-// one of the conditions for a "complete" batch is reached.
+// a batch is completed by one of the configured limits or a Flush.
 // Returns ErrDisposed if the batcher is disposed and no more batches are available.
 func (b *Batcher[T]) Get() ([]T, error) {
-	var timeout <-chan time.Time
-	if b.maxTime > 0 {
-		timeout = time.After(b.maxTime)
-	}
+	batch, err := b.GetBatch()
+	return batch.Items, err
+}
 
-	select {
-	case items, ok := <-b.batchChan:
-		if !ok {
-			return nil, ErrDisposed
-		}
-		return items, nil
-	case <-timeout:
-		for {
-			if b.lock.TryLock() {
-				select {
-				case items, ok := <-b.batchChan:
-					b.lock.Unlock()
-					if !ok {
-						return nil, ErrDisposed
-					}
-					return items, nil
-				default:
-				}
-
-				items := b.items
-				b.items = make([]T, 0, b.maxItems)
-				b.availableBytes = 0
-				b.lock.Unlock()
-				return items, nil
-			} else {
-				select {
-				case items, ok := <-b.batchChan:
-					if !ok {
-						return nil, ErrDisposed
-					}
-					return items, nil
-				default:
-				}
-			}
-		}
+// GetContext is like Get, but stops waiting once the context is done
+// and returns the context's error. A batch that is already available
+// is returned even if the context is done.
+func (b *Batcher[T]) GetContext(ctx context.Context) ([]T, error) {
+	batch, err := b.GetBatchContext(ctx)
+	return batch.Items, err
+}
+
+// GetBatch is like Get but also returns why the batch was completed,
+// its age and its size in bytes.
+func (b *Batcher[T]) GetBatch() (Batch[T], error) {
+	return b.GetBatchContext(context.Background())
+}
+
+// GetBatchContext is like GetBatch, but stops waiting once the context
+// is done. See GetContext.
+func (b *Batcher[T]) GetBatchContext(ctx context.Context) (Batch[T], error) {
+	return b.batches.receive(ctx)
+}
+
+// ReturnBatch hands back a batch obtained from GetBatch, typically
+// because processing it failed, so that it is delivered again ahead of
+// every batch not yet retrieved. It may be called after Close while
+// consumers are still draining the batcher.
+// Returns ErrRetriesExhausted if the batch has already been returned
+// MaxRetries times or ErrDisposed if the batcher has been disposed.
+func (b *Batcher[T]) ReturnBatch(batch Batch[T]) error {
+	if batch.Retries >= b.maxRetries {
+		return ErrRetriesExhausted
 	}
+	batch.Retries++
+	return b.batches.pushFront(batch)
 }
 
 // Flush forcibly completes the batch currently being built.
-// Returns ErrDisposed if the batcher has been disposed.
+// Returns ErrDisposed if the batcher has been disposed or ErrQueueFull
+// if the queue of completed batches is full and the OverflowError
+// policy is in use.
 func (b *Batcher[T]) Flush() error {
+	return b.FlushContext(context.Background())
+}
+
+// FlushContext is like Flush, but gives up when the context is done if
+// it must wait for room in the queue of completed batches, leaving the
+// batch being built in place and returning the context's error.
+func (b *Batcher[T]) FlushContext(ctx context.Context) error {
 	b.lock.Lock()
+	defer b.lock.Unlock()
+
 	if b.disposed {
-		b.lock.Unlock()
 		return ErrDisposed
 	}
-	b.flush()
-	b.lock.Unlock()
+	if err := b.batches.reserve(ctx); err != nil {
+		return err
+	}
+	b.flush(ReasonFlush)
+	return nil
+}
+
+// SetLimits replaces the item, byte and time limits. Item and byte
+// limits are checked against the batch being built on the next Put,
+// while a new MaxTime applies from the next batch started. A zero value
+// removes the corresponding limit.
+// Returns ErrDisposed if the batcher has been disposed or an error if
+// maxBytes is set without a CalculateBytes function.
+func (b *Batcher[T]) SetLimits(maxItems, maxBytes uint, maxTime time.Duration) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.disposed {
+		return ErrDisposed
+	}
+	if maxBytes > 0 && b.calculateBytes == nil {
+		return errNoCalculateBytes
+	}
+
+	b.limits = Limits{
+		MaxTime:  maxTime,
+		MaxItems: maxItems,
+		MaxBytes: maxBytes,
+	}
+	b.hint = maxItems
+	if b.hint == 0 {
+		b.hint = 100
+	}
 	return nil
 }
 
+// Limits returns the limits currently applied to new items.
+func (b *Batcher[T]) Limits() Limits {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.limits
+}
+
+// Dropped returns the number of completed batches that were discarded
+// by the OverflowDropOldest policy.
+func (b *Batcher[T]) Dropped() uint64 {
+	return b.batches.droppedCount()
+}
+
 // Dispose will dispose of the batcher. Any calls to Put or Flush
 // will return ErrDisposed. Calls to Get will return an error if
 // there are no more ready batches.
@@ -236,13 +434,13 @@ func (b *Batcher[T]) Dispose() {
 			}
 
 			b.disposed = true
-			b.items = nil
-			b.drainBatchChan()
-			close(b.batchChan)
+			b.pending.stop()
+			b.pending = nil
+			b.batches.discard()
 			b.lock.Unlock()
 			return
 		} else {
-			b.drainBatchChan()
+			b.batches.drain()
 		}
 	}
 }
@@ -255,28 +453,59 @@ func (b *Batcher[T]) IsDisposed() bool {
 	return disposed
 }
 
-func (b *Batcher[T]) flush() {
-	b.batchChan <- b.items
-	b.items = make([]T, 0, b.maxItems)
-	b.availableBytes = 0
+// put adds an item of the given priority and size, first making room in
+// the queue of completed batches if it will complete one. Caller must
+// hold the lock.
+func (b *Batcher[T]) put(ctx context.Context, priority int, item T, size uint) error {
+	if _, ok := b.pending.readyWith(1, size, b.limits); ok {
+		if err := b.batches.reserve(ctx); err != nil {
+			return err
+		}
+	}
+
+	b.pending.add(item, priority, size, b.maxTime(priority), b.timedFlush)
+	if reason, ok := b.pending.ready(b.limits); ok {
+		b.flush(reason)
+	}
+	return nil
 }
 
-func (b *Batcher[T]) ready() bool {
-	if b.maxItems != 0 && uint(len(b.items)) >= b.maxItems {
-		return true
+// maxTime returns the MaxTime that applies to an item of the priority.
+func (b *Batcher[T]) maxTime(priority int) time.Duration {
+	if priority > 0 && b.priorities[priority-1] > 0 {
+		return b.priorities[priority-1]
 	}
-	if b.maxBytes != 0 && b.availableBytes >= b.maxBytes {
-		return true
+	return b.limits.MaxTime
+}
+
+func (b *Batcher[T]) size(item T) uint {
+	if b.calculateBytes == nil {
+		return 0
 	}
-	return false
+	return b.calculateBytes(item)
 }
 
-func (b *Batcher[T]) drainBatchChan() {
-	for {
-		select {
-		case <-b.batchChan:
-		default:
-			return
-		}
+// flush completes the batch being built. Caller must hold the lock.
+func (b *Batcher[T]) flush(reason Reason) {
+	batch := b.pending.take(b.hint, reason)
+	batch.observe(b.batches.metrics)
+	b.batches.send(batch)
+}
+
+// timedFlush completes the batch identified by generation if it is
+// still the batch being built. Under the OverflowError policy a full
+// queue postpones the batch by another MaxTime rather than blocking
+// producers behind the lock.
+func (b *Batcher[T]) timedFlush(generation uint64) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.disposed || b.pending.generation != generation || b.pending.len() == 0 {
+		return
+	}
+	if err := b.batches.reserve(context.Background()); err != nil {
+		b.pending.rearm(b.timedFlush)
+		return
 	}
+	b.flush(ReasonMaxTime)
 }
@@ -17,11 +17,15 @@ limitations under the License.
 package batcher
 
 import (
+	"context"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/Workiva/go-datastructures/common"
 )
 
 func TestBatcherPut(t *testing.T) {
@@ -77,6 +81,51 @@ func TestBatcherMaxTime(t *testing.T) {
 	assert.Equal(t, []string{"item1"}, batch)
 }
 
+func TestBatcherMaxTimeWithoutWaitingConsumer(t *testing.T) {
+	b, err := New[string](Config[string]{
+		MaxItems: 100,
+		MaxTime:  50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer b.Dispose()
+
+	b.Put("item1")
+	b.Put("item2")
+
+	// nobody is in Get while the timer elapses
+	assert.Eventually(t, func() bool {
+		return len(b.batches.ch) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	batch, err := b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"item1", "item2"}, batch)
+}
+
+func TestBatcherMaxTimeMeasuredFromFirstItem(t *testing.T) {
+	b, err := New[int](Config[int]{
+		MaxItems: 2,
+		MaxTime:  50 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer b.Dispose()
+
+	// the first batch completes on size, so its timer must not
+	// cut the following batch short
+	b.Put(1)
+	b.Put(2)
+	batch, err := b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, batch)
+
+	start := time.Now()
+	b.Put(3)
+	batch, err = b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{3}, batch)
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+}
+
 func TestBatcherFlush(t *testing.T) {
 	b, err := New[string](Config[string]{
 		MaxItems: 100,
@@ -157,3 +206,226 @@ func TestBatcherRequiresCalculateBytesWithMaxBytes(t *testing.T) {
 	})
 	assert.Error(t, err)
 }
+
+func TestBatcherPutMany(t *testing.T) {
+	b, err := New[int](Config[int]{
+		MaxItems: 3,
+	})
+	require.NoError(t, err)
+	defer b.Dispose()
+
+	require.NoError(t, b.PutMany(1, 2, 3, 4, 5, 6, 7))
+
+	batch, err := b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, batch)
+	batch, err = b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{4, 5, 6}, batch)
+
+	require.NoError(t, b.Flush())
+	batch, err = b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{7}, batch)
+
+	b.Dispose()
+	assert.Equal(t, ErrDisposed, b.PutMany(8))
+}
+
+func TestBatcherPutManyOverflowError(t *testing.T) {
+	b, err := New[int](Config[int]{
+		MaxItems: 2,
+		QueueLen: 1,
+		Overflow: OverflowError,
+	})
+	require.NoError(t, err)
+	defer b.Dispose()
+
+	assert.Equal(t, ErrQueueFull, b.PutMany(1, 2, 3, 4, 5))
+
+	batch, err := b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, batch)
+	require.NoError(t, b.Flush())
+	batch, err = b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{3}, batch)
+}
+
+func TestBatcherPutSized(t *testing.T) {
+	calls := 0
+	b, err := New[string](Config[string]{
+		MaxBytes: 100,
+		CalculateBytes: func(s string) uint {
+			calls++
+			return uint(len(s))
+		},
+	})
+	require.NoError(t, err)
+	defer b.Dispose()
+
+	require.NoError(t, b.PutSized("a", 60))
+	require.NoError(t, b.PutSized("b", 40))
+	assert.Equal(t, 0, calls)
+
+	batch, err := b.GetBatch()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, batch.Items)
+	assert.Equal(t, uint(100), batch.Bytes)
+}
+
+func TestBatcherSetLimits(t *testing.T) {
+	b, err := New[int](Config[int]{
+		MaxItems: 2,
+	})
+	require.NoError(t, err)
+	defer b.Dispose()
+
+	require.NoError(t, b.PutMany(1, 2))
+	batch, err := b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, batch)
+
+	require.NoError(t, b.SetLimits(4, 0, 10*time.Millisecond))
+	assert.Equal(t, Limits{MaxItems: 4, MaxTime: 10 * time.Millisecond}, b.Limits())
+
+	require.NoError(t, b.PutMany(1, 2, 3, 4))
+	batch, err = b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4}, batch)
+
+	require.NoError(t, b.Put(5))
+	reasoned, err := b.GetBatch()
+	require.NoError(t, err)
+	assert.Equal(t, []int{5}, reasoned.Items)
+	assert.Equal(t, ReasonMaxTime, reasoned.Reason)
+
+	assert.Error(t, b.SetLimits(0, 10, 0))
+	b.Dispose()
+	assert.Equal(t, ErrDisposed, b.SetLimits(1, 0, 0))
+}
+
+func TestBatcherPriorityOrdering(t *testing.T) {
+	b, err := New[string](Config[string]{
+		MaxItems:   5,
+		Priorities: []time.Duration{0, 0},
+	})
+	require.NoError(t, err)
+	defer b.Dispose()
+
+	require.NoError(t, b.Put("data1"))
+	require.NoError(t, b.PutPriority(1, "ctl1"))
+	require.NoError(t, b.PutPriority(2, "urgent"))
+	require.NoError(t, b.Put("data2"))
+	require.NoError(t, b.PutPriority(1, "ctl2"))
+
+	batch, err := b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"urgent", "ctl1", "ctl2", "data1", "data2"}, batch)
+
+	assert.Equal(t, ErrUnknownPriority, b.PutPriority(3, "nope"))
+}
+
+func TestBatcherPriorityCompletesSooner(t *testing.T) {
+	b, err := New[string](Config[string]{
+		MaxTime:    time.Hour,
+		Priorities: []time.Duration{10 * time.Millisecond},
+	})
+	require.NoError(t, err)
+	defer b.Dispose()
+
+	require.NoError(t, b.Put("data"))
+	require.NoError(t, b.PutPriority(1, "ctl"))
+
+	start := time.Now()
+	batch, err := b.GetBatch()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ctl", "data"}, batch.Items)
+	assert.Equal(t, ReasonMaxTime, batch.Reason)
+	assert.True(t, time.Since(start) < time.Second)
+
+	// the next batch starts over with the default MaxTime
+	require.NoError(t, b.Put("data"))
+	time.Sleep(30 * time.Millisecond)
+	assert.Len(t, b.batches.ch, 0)
+}
+
+func TestBatcherMetrics(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		counts = map[string]int64{}
+		gauges = map[string]float64{}
+		timers = map[string]int{}
+	)
+	b, err := New[string](Config[string]{
+		MaxItems:       2,
+		QueueLen:       1,
+		Overflow:       OverflowDropOldest,
+		CalculateBytes: func(s string) uint { return uint(len(s)) },
+		Metrics: common.MetricsFuncs{
+			OnCount: func(name string, delta int64) {
+				mu.Lock()
+				defer mu.Unlock()
+				counts[name] += delta
+			},
+			OnGauge: func(name string, value float64) {
+				mu.Lock()
+				defer mu.Unlock()
+				gauges[name] = value
+			},
+			OnTime: func(name string, d time.Duration) {
+				mu.Lock()
+				defer mu.Unlock()
+				timers[name]++
+			},
+		},
+	})
+	require.NoError(t, err)
+	defer b.Dispose()
+
+	require.NoError(t, b.PutMany("a", "bc"))
+	require.NoError(t, b.PutMany("d", "ef", "g"))
+	require.NoError(t, b.Flush())
+
+	mu.Lock()
+	assert.Equal(t, int64(3), counts["batcher.batches"])
+	assert.Equal(t, int64(2), counts["batcher.dropped"])
+	assert.Equal(t, float64(1), gauges["batcher.batch_items"])
+	assert.Equal(t, float64(1), gauges["batcher.batch_bytes"])
+	assert.Equal(t, float64(1), gauges["batcher.queued"])
+	assert.Equal(t, 3, timers["batcher.batch_age"])
+	mu.Unlock()
+
+	batch, err := b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"g"}, batch)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, float64(0), gauges["batcher.queued"])
+}
+
+func TestBatcherGetContext(t *testing.T) {
+	b, err := New[int](Config[int]{MaxItems: 2})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	_, err = b.GetContext(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// a completed batch is returned even if the context is done
+	require.NoError(t, b.PutMany(1, 2))
+	batch, err := b.GetContext(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, batch)
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := b.GetBatchContext(context.Background())
+		result <- err
+	}()
+	time.Sleep(5 * time.Millisecond)
+	b.Dispose()
+	assert.ErrorIs(t, <-result, ErrDisposed)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2015 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batcher
+
+import "context"
+
+// Handler processes a completed batch.
+type Handler[T any] func([]T)
+
+// ErrorHandler processes a completed batch, returning an error if the
+// batch should be retried.
+type ErrorHandler[T any] func([]T) error
+
+// NewWithHandler creates a new Batcher that invokes the provided handler
+// with every completed batch instead of requiring the consumer to loop
+// on Get. config.Concurrency handlers may run at the same time. Get
+// must not be called on a Batcher created this way; call Close to stop
+// it once the handler has processed every completed batch.
+func NewWithHandler[T any](config Config[T], handler Handler[T]) (*Batcher[T], error) {
+	return NewWithErrorHandler(config, func(batch []T) error {
+		handler(batch)
+		return nil
+	})
+}
+
+// NewWithErrorHandler is like NewWithHandler, except that a batch for
+// which the handler returns an error is handed back with ReturnBatch to
+// be processed again before any other batch, up to config.MaxRetries
+// times. A batch that still fails is passed to config.OnRetriesExhausted.
+func NewWithErrorHandler[T any](config Config[T], handler ErrorHandler[T]) (*Batcher[T], error) {
+	b, err := New(config)
+	if err != nil {
+		return nil, err
+	}
+
+	concurrency := config.Concurrency
+	if concurrency == 0 {
+		concurrency = 1
+	}
+
+	b.workers.Add(int(concurrency))
+	for range concurrency {
+		go b.dispatch(handler, config.OnRetriesExhausted)
+	}
+	return b, nil
+}
+
+// dispatch feeds completed batches to the handler until the batcher
+// is closed or disposed.
+func (b *Batcher[T]) dispatch(handler ErrorHandler[T], exhausted func([]T, error)) {
+	defer b.workers.Done()
+	for {
+		batch, err := b.GetBatch()
+		if err != nil {
+			return
+		}
+
+		herr := handler(batch.Items)
+		if herr == nil {
+			continue
+		}
+		if b.ReturnBatch(batch) == ErrRetriesExhausted && exhausted != nil {
+			exhausted(batch.Items, herr)
+		}
+	}
+}
+
+// Close stops the batcher from accepting new items, completes the batch
+// still being built and closes the stream of completed batches, so no
+// accepted item is lost. Completed batches remain available to Get,
+// which returns ErrDisposed once they are exhausted. If the queue of
+// completed batches is full, Close waits for a consumer to make room
+// for the final batch unless the OverflowDropOldest policy is in use.
+// It waits holding the batcher's lock, so calls such as Put and
+// IsDisposed block until it returns, and it must not be called from
+// the only goroutine that calls Get while the queue may be full, since
+// nothing would then make room. A concurrent Dispose makes room and
+// lets it return.
+// For a Batcher created with NewWithHandler, Close blocks until the
+// handler has processed every batch.
+func (b *Batcher[T]) Close() {
+	b.lock.Lock()
+	if b.disposed {
+		b.lock.Unlock()
+		b.workers.Wait()
+		return
+	}
+
+	b.disposed = true
+	if b.pending.len() > 0 {
+		// OverflowError only applies to producers; the final batch
+		// waits for room instead.
+		if b.batches.reserve(context.Background()) != nil {
+			b.batches.wait(context.Background())
+		}
+		b.flush(ReasonFlush)
+	}
+	b.pending.stop()
+	b.pending = nil
+	b.batches.close()
+	b.lock.Unlock()
+
+	b.workers.Wait()
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2015 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batcher
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWithHandler(t *testing.T) {
+	var (
+		lock    sync.Mutex
+		batches [][]int
+	)
+	b, err := NewWithHandler(Config[int]{
+		MaxItems: 2,
+	}, func(batch []int) {
+		lock.Lock()
+		batches = append(batches, batch)
+		lock.Unlock()
+	})
+	require.NoError(t, err)
+
+	for i := range 6 {
+		require.NoError(t, b.Put(i))
+	}
+	b.Close()
+
+	assert.Equal(t, [][]int{{0, 1}, {2, 3}, {4, 5}}, batches)
+	assert.Equal(t, ErrDisposed, b.Put(6))
+}
+
+func TestNewWithHandlerConcurrency(t *testing.T) {
+	var (
+		lock    sync.Mutex
+		active  int
+		maxSeen int
+		total   int
+	)
+	b, err := NewWithHandler(Config[int]{
+		MaxItems:    1,
+		Concurrency: 3,
+		QueueLen:    20,
+	}, func(batch []int) {
+		lock.Lock()
+		active++
+		if active > maxSeen {
+			maxSeen = active
+		}
+		lock.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		lock.Lock()
+		active--
+		total += len(batch)
+		lock.Unlock()
+	})
+	require.NoError(t, err)
+
+	for i := range 12 {
+		require.NoError(t, b.Put(i))
+	}
+	b.Close()
+
+	assert.Equal(t, 12, total)
+	assert.True(t, maxSeen > 1 && maxSeen <= 3)
+}
+
+func TestNewWithHandlerValidatesConfig(t *testing.T) {
+	_, err := NewWithHandler(Config[string]{MaxBytes: 10}, func([]string) {})
+	assert.Error(t, err)
+}
+
+func TestCloseWithGet(t *testing.T) {
+	b, err := New[int](Config[int]{MaxItems: 2})
+	require.NoError(t, err)
+
+	b.Put(1)
+	b.Put(2)
+	b.Close()
+	b.Close() // no-op
+
+	batch, err := b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, batch)
+
+	_, err = b.Get()
+	assert.Equal(t, ErrDisposed, err)
+}
+
+func TestCloseFlushesPartialBatch(t *testing.T) {
+	b, err := New[int](Config[int]{MaxItems: 2})
+	require.NoError(t, err)
+
+	b.PutMany(1, 2, 3)
+	b.Close()
+	assert.Equal(t, ErrDisposed, b.Put(4))
+
+	batch, err := b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, batch)
+
+	final, err := b.GetBatch()
+	require.NoError(t, err)
+	assert.Equal(t, []int{3}, final.Items)
+	assert.Equal(t, ReasonFlush, final.Reason)
+
+	_, err = b.Get()
+	assert.Equal(t, ErrDisposed, err)
+}
+
+func TestCloseWaitsForRoomUnderOverflowError(t *testing.T) {
+	b, err := New[int](Config[int]{
+		MaxItems: 1,
+		QueueLen: 1,
+		Overflow: OverflowError,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, b.Put(1))
+	require.NoError(t, b.SetLimits(0, 0, 0))
+	require.NoError(t, b.Put(2))
+
+	closed := make(chan struct{})
+	go func() {
+		b.Close()
+		close(closed)
+	}()
+
+	batch, err := b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, batch)
+	<-closed
+
+	batch, err = b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{2}, batch)
+}
+
+func TestCloseWithHandlerFlushesPartialBatch(t *testing.T) {
+	var (
+		lock sync.Mutex
+		got  []int
+	)
+	b, err := NewWithHandler(Config[int]{MaxItems: 10}, func(batch []int) {
+		lock.Lock()
+		got = append(got, batch...)
+		lock.Unlock()
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, b.PutMany(1, 2, 3))
+	b.Close()
+
+	assert.Equal(t, []int{1, 2, 3}, got)
+}
+
+func TestReturnBatch(t *testing.T) {
+	b, err := New[int](Config[int]{
+		MaxItems:   1,
+		MaxRetries: 1,
+	})
+	require.NoError(t, err)
+	defer b.Dispose()
+
+	require.NoError(t, b.PutMany(1, 2))
+
+	batch, err := b.GetBatch()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, batch.Items)
+	require.NoError(t, b.ReturnBatch(batch))
+
+	retried, err := b.GetBatch()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, retried.Items)
+	assert.Equal(t, uint(1), retried.Retries)
+	assert.Equal(t, ErrRetriesExhausted, b.ReturnBatch(retried))
+
+	batch, err = b.GetBatch()
+	require.NoError(t, err)
+	assert.Equal(t, []int{2}, batch.Items)
+
+	b.Dispose()
+	assert.Equal(t, ErrDisposed, b.ReturnBatch(Batch[int]{}))
+}
+
+func TestReturnBatchAfterClose(t *testing.T) {
+	b, err := New[int](Config[int]{
+		MaxItems:   10,
+		MaxRetries: 3,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, b.Put(1))
+	b.Close()
+
+	batch, err := b.GetBatch()
+	require.NoError(t, err)
+	require.NoError(t, b.ReturnBatch(batch))
+
+	batch, err = b.GetBatch()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, batch.Items)
+
+	_, err = b.Get()
+	assert.Equal(t, ErrDisposed, err)
+}
+
+func TestNewWithErrorHandlerRetries(t *testing.T) {
+	var (
+		lock      sync.Mutex
+		attempts  = map[int]int{}
+		exhausted [][]int
+	)
+	failure := errors.New("unavailable")
+	b, err := NewWithErrorHandler(Config[int]{
+		MaxItems:   1,
+		MaxRetries: 2,
+		OnRetriesExhausted: func(batch []int, err error) {
+			lock.Lock()
+			defer lock.Unlock()
+			assert.Equal(t, failure, err)
+			exhausted = append(exhausted, batch)
+		},
+	}, func(batch []int) error {
+		lock.Lock()
+		defer lock.Unlock()
+		attempts[batch[0]]++
+		// 1 succeeds on its second attempt, 2 never does
+		if batch[0] == 2 || attempts[batch[0]] < 2 {
+			return failure
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, b.PutMany(1, 2))
+	b.Close()
+
+	assert.Equal(t, map[int]int{1: 2, 2: 3}, attempts)
+	assert.Equal(t, [][]int{{2}}, exhausted)
+}
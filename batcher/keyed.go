@@ -0,0 +1,369 @@
+/*
+Copyright 2015 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batcher
+
+import "context"
+
+// KeyedConfig holds configuration options for creating a KeyedBatcher.
+type KeyedConfig[K comparable, T any] struct {
+	// Config supplies the default limits for every key along with the
+	// shared CalculateBytes, QueueLen, Overflow and Metrics settings.
+	Config[T]
+
+	// LimitsFor optionally returns the limits to apply to the provided
+	// key. It is called once when a key's batch is started. If nil,
+	// the limits in Config are used for every key.
+	LimitsFor func(key K) Limits
+}
+
+// keyedBatch is a completed batch along with the key it belongs to.
+type keyedBatch[K comparable, T any] struct {
+	key   K
+	batch Batch[T]
+}
+
+// keyedPending is the batch being built for a single key.
+type keyedPending[T any] struct {
+	*pending[T]
+	limits Limits
+}
+
+// KeyedBatcher maintains an independent batch per key, each completed
+// according to its own limits. Completed batches from all keys are
+// delivered, along with their key, through a single Get.
+type KeyedBatcher[K comparable, T any] struct {
+	defaults       Limits
+	limitsFor      func(key K) Limits
+	calculateBytes CalculateBytes[T]
+	disposed       bool
+	pending        map[K]*keyedPending[T]
+	batches        *outbox[keyedBatch[K, T]]
+	lock           *mutex
+}
+
+// NewKeyed creates a new KeyedBatcher with the given configuration.
+func NewKeyed[K comparable, T any](config KeyedConfig[K, T]) (*KeyedBatcher[K, T], error) {
+	if config.MaxBytes > 0 && config.CalculateBytes == nil {
+		return nil, errNoCalculateBytes
+	}
+
+	queueLen := config.QueueLen
+	if queueLen == 0 {
+		queueLen = 10
+	}
+
+	return &KeyedBatcher[K, T]{
+		defaults: Limits{
+			MaxTime:  config.MaxTime,
+			MaxItems: config.MaxItems,
+			MaxBytes: config.MaxBytes,
+		},
+		limitsFor:      config.LimitsFor,
+		calculateBytes: config.CalculateBytes,
+		pending:        make(map[K]*keyedPending[T]),
+		batches:        newOutbox[keyedBatch[K, T]](queueLen, config.Overflow, config.Metrics),
+		lock:           newMutex(),
+	}, nil
+}
+
+// Put adds an item to the batch for the provided key. If the item
+// completes a batch while the queue of completed batches is full, the
+// configured OverflowPolicy applies.
+// Returns ErrDisposed if the batcher has been disposed.
+func (kb *KeyedBatcher[K, T]) Put(key K, item T) error {
+	return kb.PutContext(context.Background(), key, item)
+}
+
+// PutContext adds an item to the batch for the provided key, giving up
+// when the context is done if it must wait for room in the queue of
+// completed batches. See Batcher.PutContext.
+func (kb *KeyedBatcher[K, T]) PutContext(ctx context.Context, key K, item T) error {
+	kb.lock.Lock()
+	defer kb.lock.Unlock()
+
+	if kb.disposed {
+		return ErrDisposed
+	}
+	return kb.put(ctx, key, item, kb.size(item))
+}
+
+// PutSized adds an item whose size in bytes is already known to the
+// batch for the provided key, skipping CalculateBytes.
+// Returns ErrDisposed if the batcher has been disposed.
+func (kb *KeyedBatcher[K, T]) PutSized(key K, item T, bytes uint) error {
+	return kb.PutSizedContext(context.Background(), key, item, bytes)
+}
+
+// PutSizedContext is like PutSized, but gives up when the context is
+// done if it must wait for room in the queue of completed batches.
+// See Batcher.PutContext.
+func (kb *KeyedBatcher[K, T]) PutSizedContext(ctx context.Context, key K, item T, bytes uint) error {
+	kb.lock.Lock()
+	defer kb.lock.Unlock()
+
+	if kb.disposed {
+		return ErrDisposed
+	}
+	return kb.put(ctx, key, item, bytes)
+}
+
+// PutMany adds the items in order to the batch for the provided key
+// under a single acquisition of the lock. If the OverflowPolicy rejects
+// an item, the items before it remain added and the error is returned.
+// Returns ErrDisposed if the batcher has been disposed.
+func (kb *KeyedBatcher[K, T]) PutMany(key K, items ...T) error {
+	return kb.PutManyContext(context.Background(), key, items...)
+}
+
+// PutManyContext is like PutMany, but gives up when the context is
+// done if it must wait for room in the queue of completed batches,
+// leaving the items before the one waiting added. See Batcher.PutContext.
+func (kb *KeyedBatcher[K, T]) PutManyContext(ctx context.Context, key K, items ...T) error {
+	kb.lock.Lock()
+	defer kb.lock.Unlock()
+
+	if kb.disposed {
+		return ErrDisposed
+	}
+	for _, item := range items {
+		if err := kb.put(ctx, key, item, kb.size(item)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get retrieves a completed batch and the key it belongs to. This call
+// will block until a batch for any key is completed.
+// Returns ErrDisposed if the batcher is disposed and no more batches are available.
+func (kb *KeyedBatcher[K, T]) Get() (K, []T, error) {
+	key, batch, err := kb.GetBatch()
+	return key, batch.Items, err
+}
+
+// GetContext is like Get, but stops waiting once the context is done
+// and returns the context's error. See Batcher.GetContext.
+func (kb *KeyedBatcher[K, T]) GetContext(ctx context.Context) (K, []T, error) {
+	key, batch, err := kb.GetBatchContext(ctx)
+	return key, batch.Items, err
+}
+
+// GetBatch is like Get but also returns why the batch was completed,
+// its age and its size in bytes.
+func (kb *KeyedBatcher[K, T]) GetBatch() (K, Batch[T], error) {
+	return kb.GetBatchContext(context.Background())
+}
+
+// GetBatchContext is like GetBatch, but stops waiting once the context
+// is done. See Batcher.GetContext.
+func (kb *KeyedBatcher[K, T]) GetBatchContext(ctx context.Context) (K, Batch[T], error) {
+	kbatch, err := kb.batches.receive(ctx)
+	return kbatch.key, kbatch.batch, err
+}
+
+// Flush forcibly completes the batch being built for the provided key.
+// This is a no-op if the key has no items.
+// Returns ErrDisposed if the batcher has been disposed or ErrQueueFull
+// if the queue of completed batches is full and the OverflowError
+// policy is in use.
+func (kb *KeyedBatcher[K, T]) Flush(key K) error {
+	return kb.FlushContext(context.Background(), key)
+}
+
+// FlushContext is like Flush, but gives up when the context is done if
+// it must wait for room in the queue of completed batches. See
+// Batcher.FlushContext.
+func (kb *KeyedBatcher[K, T]) FlushContext(ctx context.Context, key K) error {
+	kb.lock.Lock()
+	defer kb.lock.Unlock()
+
+	if kb.disposed {
+		return ErrDisposed
+	}
+	p, ok := kb.pending[key]
+	if !ok {
+		return nil
+	}
+	if err := kb.batches.reserve(ctx); err != nil {
+		return err
+	}
+	kb.flush(key, p, ReasonFlush)
+	return nil
+}
+
+// FlushAll forcibly completes the batches being built for every key.
+// Under the OverflowError policy, flushing stops at the first batch
+// that does not fit and ErrQueueFull is returned.
+// Returns ErrDisposed if the batcher has been disposed.
+func (kb *KeyedBatcher[K, T]) FlushAll() error {
+	return kb.FlushAllContext(context.Background())
+}
+
+// FlushAllContext is like FlushAll, but gives up when the context is
+// done if it must wait for room in the queue of completed batches,
+// leaving the batches already flushed completed.
+func (kb *KeyedBatcher[K, T]) FlushAllContext(ctx context.Context) error {
+	kb.lock.Lock()
+	defer kb.lock.Unlock()
+
+	if kb.disposed {
+		return ErrDisposed
+	}
+	for key, p := range kb.pending {
+		if err := kb.batches.reserve(ctx); err != nil {
+			return err
+		}
+		kb.flush(key, p, ReasonFlush)
+	}
+	return nil
+}
+
+// Dropped returns the number of completed batches that were discarded
+// by the OverflowDropOldest policy.
+func (kb *KeyedBatcher[K, T]) Dropped() uint64 {
+	return kb.batches.droppedCount()
+}
+
+// Len returns the number of keys that currently have a batch being built.
+func (kb *KeyedBatcher[K, T]) Len() int {
+	kb.lock.Lock()
+	defer kb.lock.Unlock()
+
+	return len(kb.pending)
+}
+
+// Dispose will dispose of the batcher. Any calls to Put or Flush
+// will return ErrDisposed. Calls to Get will return an error if
+// there are no more ready batches.
+func (kb *KeyedBatcher[K, T]) Dispose() {
+	for {
+		if kb.lock.TryLock() {
+			if kb.disposed {
+				kb.lock.Unlock()
+				return
+			}
+
+			kb.disposed = true
+			for _, p := range kb.pending {
+				p.stop()
+			}
+			kb.pending = nil
+			kb.batches.discard()
+			kb.lock.Unlock()
+			return
+		} else {
+			kb.batches.drain()
+		}
+	}
+}
+
+// Close stops the batcher from accepting new items, completes the batch
+// being built for every key and closes the stream of completed batches.
+// See Batcher.Close, including why it must not be called from the
+// goroutine consuming Get while the queue may be full.
+func (kb *KeyedBatcher[K, T]) Close() {
+	kb.lock.Lock()
+	defer kb.lock.Unlock()
+
+	if kb.disposed {
+		return
+	}
+
+	kb.disposed = true
+	for key, p := range kb.pending {
+		if kb.batches.reserve(context.Background()) != nil {
+			kb.batches.wait(context.Background())
+		}
+		kb.flush(key, p, ReasonFlush)
+	}
+	kb.pending = nil
+	kb.batches.close()
+}
+
+// IsDisposed returns true if the batcher has been disposed.
+func (kb *KeyedBatcher[K, T]) IsDisposed() bool {
+	kb.lock.Lock()
+	disposed := kb.disposed
+	kb.lock.Unlock()
+	return disposed
+}
+
+// put adds an item of the given size to the batch for the key, starting
+// one if needed. Caller must hold the lock.
+func (kb *KeyedBatcher[K, T]) put(ctx context.Context, key K, item T, size uint) error {
+	p, ok := kb.pending[key]
+	if !ok {
+		limits := kb.defaults
+		if kb.limitsFor != nil {
+			limits = kb.limitsFor(key)
+		}
+		if limits.MaxBytes > 0 && kb.calculateBytes == nil {
+			return errNoCalculateBytes
+		}
+		p = &keyedPending[T]{pending: newPending[T](limits.MaxItems, 1), limits: limits}
+	}
+
+	if _, ok := p.readyWith(1, size, p.limits); ok {
+		if err := kb.batches.reserve(ctx); err != nil {
+			return err
+		}
+	}
+
+	kb.pending[key] = p
+	p.add(item, 0, size, p.limits.MaxTime, func(generation uint64) {
+		kb.timedFlush(key, p, generation)
+	})
+	if reason, ok := p.ready(p.limits); ok {
+		kb.flush(key, p, reason)
+	}
+	return nil
+}
+
+func (kb *KeyedBatcher[K, T]) size(item T) uint {
+	if kb.calculateBytes == nil {
+		return 0
+	}
+	return kb.calculateBytes(item)
+}
+
+// flush completes the batch for the key. The key is forgotten until
+// its next Put so idle keys do not accumulate. Caller must hold the lock.
+func (kb *KeyedBatcher[K, T]) flush(key K, p *keyedPending[T], reason Reason) {
+	delete(kb.pending, key)
+	batch := p.take(0, reason)
+	batch.observe(kb.batches.metrics)
+	kb.batches.send(keyedBatch[K, T]{key: key, batch: batch})
+}
+
+// timedFlush completes the batch for the key if the batch identified
+// by p and generation is still the one being built. A key's pending
+// batch is replaced after every flush, so both must match.
+func (kb *KeyedBatcher[K, T]) timedFlush(key K, p *keyedPending[T], generation uint64) {
+	kb.lock.Lock()
+	defer kb.lock.Unlock()
+
+	if kb.disposed || kb.pending[key] != p || p.generation != generation || p.len() == 0 {
+		return
+	}
+	if err := kb.batches.reserve(context.Background()); err != nil {
+		p.rearm(func(generation uint64) {
+			kb.timedFlush(key, p, generation)
+		})
+		return
+	}
+	kb.flush(key, p, ReasonMaxTime)
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2015 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyedBatcherMaxItems(t *testing.T) {
+	kb, err := NewKeyed(KeyedConfig[string, int]{
+		Config: Config[int]{MaxItems: 2},
+	})
+	require.NoError(t, err)
+	defer kb.Dispose()
+
+	kb.Put("a", 1)
+	kb.Put("b", 10)
+	kb.Put("a", 2)
+
+	key, batch, err := kb.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "a", key)
+	assert.Equal(t, []int{1, 2}, batch)
+	assert.Equal(t, 1, kb.Len())
+
+	kb.Put("b", 11)
+	key, batch, err = kb.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "b", key)
+	assert.Equal(t, []int{10, 11}, batch)
+	assert.Equal(t, 0, kb.Len())
+}
+
+func TestKeyedBatcherPerKeyLimits(t *testing.T) {
+	kb, err := NewKeyed(KeyedConfig[string, int]{
+		Config: Config[int]{MaxItems: 100},
+		LimitsFor: func(key string) Limits {
+			if key == "small" {
+				return Limits{MaxItems: 1}
+			}
+			return Limits{MaxItems: 3}
+		},
+	})
+	require.NoError(t, err)
+	defer kb.Dispose()
+
+	kb.Put("large", 1)
+	kb.Put("small", 2)
+
+	key, batch, err := kb.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "small", key)
+	assert.Equal(t, []int{2}, batch)
+}
+
+func TestKeyedBatcherMaxTime(t *testing.T) {
+	kb, err := NewKeyed(KeyedConfig[int, string]{
+		Config: Config[string]{MaxTime: 20 * time.Millisecond},
+	})
+	require.NoError(t, err)
+	defer kb.Dispose()
+
+	kb.Put(1, "x")
+	kb.Put(2, "y")
+
+	seen := map[int][]string{}
+	for range 2 {
+		key, batch, err := kb.Get()
+		require.NoError(t, err)
+		seen[key] = batch
+	}
+	assert.Equal(t, map[int][]string{1: {"x"}, 2: {"y"}}, seen)
+}
+
+func TestKeyedBatcherFlush(t *testing.T) {
+	kb, err := NewKeyed(KeyedConfig[string, int]{})
+	require.NoError(t, err)
+	defer kb.Dispose()
+
+	kb.Put("a", 1)
+	kb.Put("b", 2)
+	require.NoError(t, kb.Flush("a"))
+	require.NoError(t, kb.Flush("missing"))
+
+	key, batch, err := kb.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "a", key)
+	assert.Equal(t, []int{1}, batch)
+
+	require.NoError(t, kb.FlushAll())
+	key, batch, err = kb.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "b", key)
+	assert.Equal(t, []int{2}, batch)
+}
+
+func TestKeyedBatcherMaxBytes(t *testing.T) {
+	_, err := NewKeyed(KeyedConfig[string, string]{
+		Config: Config[string]{MaxBytes: 10},
+	})
+	assert.Error(t, err)
+
+	kb, err := NewKeyed(KeyedConfig[string, string]{
+		LimitsFor: func(string) Limits { return Limits{MaxBytes: 4} },
+	})
+	require.NoError(t, err)
+	defer kb.Dispose()
+	assert.Error(t, kb.Put("a", "abcd"))
+}
+
+func TestKeyedBatcherDispose(t *testing.T) {
+	kb, err := NewKeyed(KeyedConfig[string, int]{
+		Config: Config[int]{MaxItems: 1},
+	})
+	require.NoError(t, err)
+
+	kb.Put("a", 1)
+	kb.Dispose()
+	assert.True(t, kb.IsDisposed())
+
+	assert.Equal(t, ErrDisposed, kb.Put("a", 2))
+	assert.Equal(t, ErrDisposed, kb.Flush("a"))
+	assert.Equal(t, ErrDisposed, kb.FlushAll())
+
+	_, _, err = kb.Get()
+	assert.Equal(t, ErrDisposed, err)
+}
+
+func TestKeyedBatcherPutManyAndPutSized(t *testing.T) {
+	kb, err := NewKeyed[string, string](KeyedConfig[string, string]{
+		Config: Config[string]{
+			MaxItems:       3,
+			MaxBytes:       10,
+			CalculateBytes: func(s string) uint { return uint(len(s)) },
+		},
+	})
+	require.NoError(t, err)
+	defer kb.Dispose()
+
+	require.NoError(t, kb.PutMany("a", "x", "y", "z"))
+	key, batch, err := kb.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "a", key)
+	assert.Equal(t, []string{"x", "y", "z"}, batch)
+
+	require.NoError(t, kb.PutSized("b", "small", 10))
+	key, sized, err := kb.GetBatch()
+	require.NoError(t, err)
+	assert.Equal(t, "b", key)
+	assert.Equal(t, ReasonMaxBytes, sized.Reason)
+}
+
+func TestKeyedBatcherClose(t *testing.T) {
+	kb, err := NewKeyed[string, int](KeyedConfig[string, int]{
+		Config: Config[int]{MaxItems: 10},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, kb.Put("a", 1))
+	require.NoError(t, kb.Put("b", 2))
+	kb.Close()
+	kb.Close() // no-op
+	assert.Equal(t, ErrDisposed, kb.Put("a", 3))
+
+	got := map[string][]int{}
+	for {
+		key, batch, err := kb.Get()
+		if err != nil {
+			assert.Equal(t, ErrDisposed, err)
+			break
+		}
+		got[key] = batch
+	}
+	assert.Equal(t, map[string][]int{"a": {1}, "b": {2}}, got)
+}
+
+func TestKeyedBatcherGetContext(t *testing.T) {
+	kb, err := NewKeyed(KeyedConfig[string, int]{
+		Config: Config[int]{MaxItems: 2},
+	})
+	require.NoError(t, err)
+	defer kb.Dispose()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = kb.GetContext(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	kb.Put("a", 1)
+	kb.Put("a", 2)
+	key, batch, err := kb.GetContext(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "a", key)
+	assert.Equal(t, []int{1, 2}, batch)
+}
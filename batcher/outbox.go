@@ -0,0 +1,255 @@
+/*
+Copyright 2015 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batcher
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+// ErrQueueFull is returned when an item would complete a batch while
+// the queue of completed batches is full and the OverflowError policy
+// is in use.  It is an alias of common.ErrFull.
+var ErrQueueFull = common.ErrFull
+
+// OverflowPolicy determines what happens when a batch is completed
+// while the queue of completed batches is full.
+type OverflowPolicy uint8
+
+const (
+	// OverflowBlock blocks the producer until a consumer makes room.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest discards the oldest completed batch to make room.
+	OverflowDropOldest
+	// OverflowError rejects the item that would complete a batch with
+	// ErrQueueFull, leaving the batch being built untouched.
+	OverflowError
+)
+
+// outbox is the queue of completed batches waiting for a consumer.
+// Only goroutines holding the owning batcher's lock send to it, so
+// once reserve has made room a send will not block. Batches handed
+// back by consumers are kept separately, ahead of the queue, and do
+// not count against its size.
+type outbox[B any] struct {
+	ch         chan B
+	space      chan struct{}
+	overflow   OverflowPolicy
+	dropped    uint64
+	mu         sync.Mutex
+	front      []B
+	frontReady chan struct{}
+	discarded  bool
+	metrics    common.Metrics
+}
+
+func newOutbox[B any](size uint, overflow OverflowPolicy, metrics common.Metrics) *outbox[B] {
+	return &outbox[B]{
+		ch:         make(chan B, size),
+		space:      make(chan struct{}, 1),
+		overflow:   overflow,
+		frontReady: make(chan struct{}, 1),
+		metrics:    metrics,
+	}
+}
+
+func (o *outbox[B]) full() bool {
+	return len(o.ch) == cap(o.ch)
+}
+
+// reserve makes room for one more completed batch according to the
+// overflow policy. Caller must hold the owning batcher's lock.
+func (o *outbox[B]) reserve(ctx context.Context) error {
+	for o.full() {
+		switch o.overflow {
+		case OverflowError:
+			return ErrQueueFull
+		case OverflowDropOldest:
+			select {
+			case <-o.ch:
+				atomic.AddUint64(&o.dropped, 1)
+				if o.metrics != nil {
+					o.metrics.Count("batcher.dropped", 1)
+				}
+			default:
+			}
+		default:
+			start := time.Now()
+			err := o.wait(ctx)
+			if o.metrics != nil {
+				o.metrics.Count("batcher.waits", 1)
+				o.metrics.Time("batcher.wait", time.Since(start))
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// wait blocks until a consumer makes room or the context is done,
+// regardless of the overflow policy.
+func (o *outbox[B]) wait(ctx context.Context) error {
+	for o.full() {
+		select {
+		case <-o.space:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// send queues a completed batch. Caller must hold the owning
+// batcher's lock and should have called reserve.
+func (o *outbox[B]) send(batch B) {
+	o.ch <- batch
+	o.gauge()
+}
+
+// gauge reports the number of completed batches awaiting a consumer,
+// if there are metrics to report to.
+func (o *outbox[B]) gauge() {
+	if o.metrics != nil {
+		o.metrics.Gauge("batcher.queued", float64(len(o.ch)))
+	}
+}
+
+// receive blocks until a completed batch is available, preferring
+// batches that were handed back. Returns ErrDisposed if the outbox has
+// been closed and drained, or the context's error if it is done before
+// a batch is available.
+func (o *outbox[B]) receive(ctx context.Context) (B, error) {
+	for {
+		if batch, ok := o.popFront(); ok {
+			return batch, nil
+		}
+
+		// only wait on the context once there is nothing to receive
+		select {
+		case batch, ok := <-o.ch:
+			return o.received(batch, ok)
+		default:
+		}
+
+		select {
+		case batch, ok := <-o.ch:
+			return o.received(batch, ok)
+		case <-o.frontReady:
+		case <-ctx.Done():
+			var zero B
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// received completes a receive from the queue of completed batches.
+func (o *outbox[B]) received(batch B, ok bool) (B, error) {
+	if !ok {
+		if batch, ok := o.popFront(); ok {
+			return batch, nil
+		}
+		return batch, ErrDisposed
+	}
+	o.signal()
+	o.gauge()
+	return batch, nil
+}
+
+// pushFront queues a batch ahead of every completed batch not yet
+// received. Returns ErrDisposed if the outbox has been discarded.
+func (o *outbox[B]) pushFront(batch B) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.discarded {
+		return ErrDisposed
+	}
+	o.front = append(o.front, batch)
+	o.notifyFront()
+	return nil
+}
+
+func (o *outbox[B]) popFront() (B, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var zero B
+	if len(o.front) == 0 {
+		return zero, false
+	}
+	batch := o.front[0]
+	o.front[0] = zero
+	o.front = o.front[1:]
+	if len(o.front) > 0 {
+		o.notifyFront()
+	}
+	return batch, true
+}
+
+// notifyFront wakes a consumer waiting in receive. Caller must hold mu.
+func (o *outbox[B]) notifyFront() {
+	select {
+	case o.frontReady <- struct{}{}:
+	default:
+	}
+}
+
+// drain discards every queued batch.
+func (o *outbox[B]) drain() {
+	for {
+		select {
+		case <-o.ch:
+			o.signal()
+		default:
+			return
+		}
+	}
+}
+
+// signal wakes a producer waiting in reserve, if there is one.
+func (o *outbox[B]) signal() {
+	select {
+	case o.space <- struct{}{}:
+	default:
+	}
+}
+
+func (o *outbox[B]) close() {
+	close(o.ch)
+}
+
+// discard drops every batch, including those handed back, and closes
+// the outbox.
+func (o *outbox[B]) discard() {
+	o.mu.Lock()
+	o.discarded = true
+	o.front = nil
+	o.mu.Unlock()
+
+	o.drain()
+	o.close()
+}
+
+func (o *outbox[B]) droppedCount() uint64 {
+	return atomic.LoadUint64(&o.dropped)
+}
@@ -0,0 +1,279 @@
+/*
+Copyright 2015 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatcherOverflowBlock(t *testing.T) {
+	b, err := New[int](Config[int]{
+		MaxItems: 1,
+		QueueLen: 1,
+	})
+	require.NoError(t, err)
+	defer b.Dispose()
+
+	require.NoError(t, b.Put(1))
+
+	done := make(chan error)
+	go func() {
+		done <- b.Put(2)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("put should block while the queue is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	batch, err := b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, batch)
+	assert.NoError(t, <-done)
+
+	batch, err = b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{2}, batch)
+}
+
+func TestBatcherPutContextCancelled(t *testing.T) {
+	b, err := New[int](Config[int]{
+		MaxItems: 2,
+		QueueLen: 1,
+	})
+	require.NoError(t, err)
+	defer b.Dispose()
+
+	require.NoError(t, b.Put(1))
+	require.NoError(t, b.Put(2))
+	require.NoError(t, b.Put(3))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, b.PutContext(ctx, 4))
+
+	// the rejected item must not have been added
+	batch, err := b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, batch)
+	require.NoError(t, b.Flush())
+	batch, err = b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{3}, batch)
+}
+
+func TestBatcherContextVariantsCancelled(t *testing.T) {
+	b, err := New[int](Config[int]{
+		MaxItems:   2,
+		QueueLen:   1,
+		Priorities: []time.Duration{0},
+	})
+	require.NoError(t, err)
+	defer b.Dispose()
+
+	require.NoError(t, b.Put(1))
+	require.NoError(t, b.Put(2))
+	require.NoError(t, b.Put(3))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, b.PutPriorityContext(ctx, 1, 4))
+	assert.Equal(t, context.Canceled, b.PutSizedContext(ctx, 4, 0))
+	assert.Equal(t, context.Canceled, b.PutManyContext(ctx, 4, 5))
+	assert.Equal(t, context.Canceled, b.FlushContext(ctx))
+
+	batch, err := b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, batch)
+	require.NoError(t, b.FlushContext(ctx))
+	batch, err = b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{3}, batch)
+}
+
+func TestKeyedBatcherContextVariantsCancelled(t *testing.T) {
+	kb, err := NewKeyed[string, int](KeyedConfig[string, int]{
+		Config: Config[int]{MaxItems: 2, QueueLen: 1},
+	})
+	require.NoError(t, err)
+	defer kb.Dispose()
+
+	require.NoError(t, kb.Put("a", 1))
+	require.NoError(t, kb.Put("a", 2))
+	require.NoError(t, kb.Put("b", 3))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, kb.PutSizedContext(ctx, "b", 4, 0))
+	assert.Equal(t, context.Canceled, kb.PutManyContext(ctx, "b", 4, 5))
+	assert.Equal(t, context.Canceled, kb.FlushContext(ctx, "b"))
+	assert.Equal(t, context.Canceled, kb.FlushAllContext(ctx))
+
+	key, batch, err := kb.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "a", key)
+	assert.Equal(t, []int{1, 2}, batch)
+	require.NoError(t, kb.FlushAllContext(ctx))
+	key, batch, err = kb.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "b", key)
+	assert.Equal(t, []int{3}, batch)
+}
+
+func TestBatcherOverflowError(t *testing.T) {
+	b, err := New[int](Config[int]{
+		MaxItems: 2,
+		QueueLen: 1,
+		Overflow: OverflowError,
+	})
+	require.NoError(t, err)
+	defer b.Dispose()
+
+	require.NoError(t, b.Put(1))
+	require.NoError(t, b.Put(2))
+	require.NoError(t, b.Put(3))
+	assert.Equal(t, ErrQueueFull, b.Put(4))
+	assert.Equal(t, ErrQueueFull, b.Flush())
+
+	batch, err := b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, batch)
+
+	require.NoError(t, b.Put(4))
+	batch, err = b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 4}, batch)
+}
+
+func TestBatcherOverflowErrorPostponesMaxTime(t *testing.T) {
+	b, err := New[int](Config[int]{
+		MaxTime:  10 * time.Millisecond,
+		QueueLen: 1,
+		Overflow: OverflowError,
+	})
+	require.NoError(t, err)
+	defer b.Dispose()
+
+	require.NoError(t, b.Put(1))
+	require.NoError(t, b.Flush())
+	require.NoError(t, b.Put(2))
+
+	// the timer fires while the queue is full and must try again later
+	time.Sleep(30 * time.Millisecond)
+
+	batch, err := b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, batch)
+	batch, err = b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{2}, batch)
+}
+
+func TestBatcherOverflowDropOldest(t *testing.T) {
+	b, err := New[int](Config[int]{
+		MaxItems: 1,
+		QueueLen: 2,
+		Overflow: OverflowDropOldest,
+	})
+	require.NoError(t, err)
+	defer b.Dispose()
+
+	for i := range 5 {
+		require.NoError(t, b.Put(i))
+	}
+	assert.Equal(t, uint64(3), b.Dropped())
+
+	batch, err := b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{3}, batch)
+	batch, err = b.Get()
+	require.NoError(t, err)
+	assert.Equal(t, []int{4}, batch)
+}
+
+func TestBatcherDisposeUnblocksPut(t *testing.T) {
+	b, err := New[int](Config[int]{
+		MaxItems: 1,
+		QueueLen: 1,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, b.Put(1))
+	done := make(chan error)
+	go func() {
+		done <- b.Put(2)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	b.Dispose()
+	<-done
+	assert.Equal(t, ErrDisposed, b.Put(3))
+}
+
+func TestKeyedBatcherOverflowError(t *testing.T) {
+	kb, err := NewKeyed[string, int](KeyedConfig[string, int]{
+		Config: Config[int]{
+			MaxItems: 1,
+			QueueLen: 1,
+			Overflow: OverflowError,
+		},
+	})
+	require.NoError(t, err)
+	defer kb.Dispose()
+
+	require.NoError(t, kb.Put("a", 1))
+	assert.Equal(t, ErrQueueFull, kb.Put("b", 2))
+	assert.Equal(t, 0, kb.Len())
+
+	key, batch, err := kb.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "a", key)
+	assert.Equal(t, []int{1}, batch)
+
+	require.NoError(t, kb.Put("b", 2))
+	key, batch, err = kb.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "b", key)
+	assert.Equal(t, []int{2}, batch)
+}
+
+func TestKeyedBatcherOverflowDropOldest(t *testing.T) {
+	kb, err := NewKeyed[string, int](KeyedConfig[string, int]{
+		Config: Config[int]{
+			MaxItems: 1,
+			QueueLen: 1,
+			Overflow: OverflowDropOldest,
+		},
+	})
+	require.NoError(t, err)
+	defer kb.Dispose()
+
+	require.NoError(t, kb.Put("a", 1))
+	require.NoError(t, kb.Put("b", 2))
+	assert.Equal(t, uint64(1), kb.Dropped())
+
+	key, batch, err := kb.Get()
+	require.NoError(t, err)
+	assert.Equal(t, "b", key)
+	assert.Equal(t, []int{2}, batch)
+}
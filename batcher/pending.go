@@ -0,0 +1,145 @@
+/*
+Copyright 2015 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batcher
+
+import "time"
+
+// pending is a batch that is still being built. Items are kept in one
+// lane per priority so that higher priorities can be placed first once
+// the batch is completed.
+type pending[T any] struct {
+	lanes      [][]T
+	count      uint
+	bytes      uint
+	started    time.Time
+	deadline   time.Time
+	wait       time.Duration
+	timer      *time.Timer
+	generation uint64
+}
+
+func newPending[T any](hint uint, lanes int) *pending[T] {
+	p := &pending[T]{lanes: make([][]T, lanes)}
+	p.lanes[0] = make([]T, 0, hint)
+	return p
+}
+
+// len returns the number of items in the batch across all lanes.
+func (p *pending[T]) len() uint {
+	return p.count
+}
+
+// add appends the item to the lane for its priority. The batch is
+// completed via onTimeout once maxTime has elapsed, unless an earlier
+// item already requires it to complete sooner.
+func (p *pending[T]) add(item T, priority int, size uint, maxTime time.Duration, onTimeout func(generation uint64)) {
+	now := time.Now()
+	if p.count == 0 {
+		p.started = now
+	}
+	if maxTime > 0 {
+		if deadline := now.Add(maxTime); p.deadline.IsZero() || deadline.Before(p.deadline) {
+			p.arm(maxTime, onTimeout)
+		}
+	}
+
+	p.lanes[priority] = append(p.lanes[priority], item)
+	p.count++
+	p.bytes += size
+}
+
+// arm (re)starts the time trigger for the current generation to fire
+// after the provided duration.
+func (p *pending[T]) arm(wait time.Duration, onTimeout func(generation uint64)) {
+	p.stop()
+	p.wait = wait
+	p.deadline = time.Now().Add(wait)
+	generation := p.generation
+	p.timer = time.AfterFunc(wait, func() {
+		onTimeout(generation)
+	})
+}
+
+// rearm postpones the time trigger by the duration it was last armed
+// with.
+func (p *pending[T]) rearm(onTimeout func(generation uint64)) {
+	p.arm(p.wait, onTimeout)
+}
+
+func (p *pending[T]) ready(limits Limits) (Reason, bool) {
+	return p.readyWith(0, 0, limits)
+}
+
+// readyWith returns a bool indicating if the batch would be complete
+// after adding the given number of items and bytes, along with the
+// limit that would complete it.
+func (p *pending[T]) readyWith(items, bytes uint, limits Limits) (Reason, bool) {
+	if limits.MaxItems != 0 && p.count+items >= limits.MaxItems {
+		return ReasonMaxItems, true
+	}
+	if limits.MaxBytes != 0 && p.bytes+bytes >= limits.MaxBytes {
+		return ReasonMaxBytes, true
+	}
+	return 0, false
+}
+
+// stop disarms the time trigger, if any.
+func (p *pending[T]) stop() {
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+	p.deadline = time.Time{}
+}
+
+// take completes this batch for the given reason and resets it for the
+// next one. A time trigger that fires afterwards will find the
+// generation has moved on.
+func (p *pending[T]) take(hint uint, reason Reason) Batch[T] {
+	batch := Batch[T]{
+		Items:  p.items(hint),
+		Reason: reason,
+		Bytes:  p.bytes,
+	}
+	if p.count > 0 {
+		batch.Age = time.Since(p.started)
+	}
+
+	p.stop()
+	p.generation++
+	p.count = 0
+	p.bytes = 0
+	return batch
+}
+
+// items empties the lanes, returning their items with the highest
+// priority first.
+func (p *pending[T]) items(hint uint) []T {
+	if uint(len(p.lanes[0])) == p.count {
+		items := p.lanes[0]
+		p.lanes[0] = make([]T, 0, hint)
+		return items
+	}
+
+	items := make([]T, 0, p.count)
+	for i := len(p.lanes) - 1; i >= 0; i-- {
+		items = append(items, p.lanes[i]...)
+		clear(p.lanes[i])
+		p.lanes[i] = p.lanes[i][:0]
+	}
+	return items
+}
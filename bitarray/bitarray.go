@@ -266,6 +266,14 @@ func (ba *bitArray) Equals(other BitArray) bool {
 	var selfIndex uint64
 	for iter := other.Blocks(); iter.Next(); {
 		toIndex, otherBlock := iter.Value()
+		if toIndex >= uint64(len(ba.blocks)) {
+			// beyond our capacity, so this must be empty
+			if otherBlock > 0 {
+				return false
+			}
+			continue
+		}
+
 		if toIndex > selfIndex {
 			for i := selfIndex; i < toIndex; i++ {
 				if ba.blocks[i] > 0 {
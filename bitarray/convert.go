@@ -0,0 +1,54 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitarray
+
+// ToSparse returns a sparse bit array with the same bits set as the
+// provided bit array, which is left unchanged.  This is useful once a
+// dense bit array has few bits set across a large range.
+func ToSparse(ba BitArray) BitArray {
+	sba := newSparseBitArray()
+	for iter := ba.Blocks(); iter.Next(); {
+		index, block := iter.Value()
+		if block == 0 {
+			continue
+		}
+
+		sba.indices = append(sba.indices, index)
+		sba.blocks = append(sba.blocks, block)
+	}
+
+	return sba
+}
+
+// ToDense returns a dense bit array with the same bits set as the
+// provided bit array, which is left unchanged.  The capacity of the
+// result is the capacity of the provided bit array, which for a sparse
+// bit array is just large enough to hold its highest set bit.
+func ToDense(ba BitArray) BitArray {
+	dba := newBitArray(ba.Capacity())
+	for iter := ba.Blocks(); iter.Next(); {
+		index, block := iter.Value()
+		dba.blocks[index] = block
+	}
+
+	dba.setLowest()
+	if dba.anyset {
+		dba.setHighest()
+	}
+
+	return dba
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitarray
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToSparse(t *testing.T) {
+	ba := newBitArray(s * 100)
+	ba.SetBit(3)
+	ba.SetBit(s * 50)
+	ba.SetBit(s*99 + 1)
+
+	sba := ToSparse(ba)
+	assert.IsType(t, &sparseBitArray{}, sba)
+	assert.Len(t, sba.(*sparseBitArray).blocks, 3)
+	assert.Equal(t, ba.ToNums(), sba.ToNums())
+	assert.True(t, sba.Equals(ba))
+	assert.True(t, ba.Equals(sba))
+
+	sba.SetBit(4)
+	ok, _ := ba.GetBit(4)
+	assert.False(t, ok)
+
+	assert.True(t, ToSparse(newBitArray(s)).IsEmpty())
+}
+
+func TestToDense(t *testing.T) {
+	sba := newSparseBitArray()
+	sba.SetBit(s + 3)
+	sba.SetBit(s * 20)
+
+	ba := ToDense(sba)
+	assert.IsType(t, &bitArray{}, ba)
+	assert.Equal(t, sba.Capacity(), ba.Capacity())
+	assert.Equal(t, sba.ToNums(), ba.ToNums())
+	assert.True(t, ba.Equals(sba))
+	assert.True(t, sba.Equals(ba))
+
+	other := newSparseBitArray()
+	other.SetBit(s * 20)
+	other.SetBit(s * 30)
+	assert.Equal(t, []uint64{s * 20}, ba.And(other).ToNums())
+	assert.Equal(t, []uint64{s * 20}, other.And(ToDense(sba)).ToNums())
+
+	empty := ToDense(newSparseBitArray())
+	assert.True(t, empty.IsEmpty())
+	assert.Equal(t, uint64(0), empty.Capacity())
+}
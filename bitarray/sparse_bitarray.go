@@ -243,7 +243,8 @@ func (sba *sparseBitArray) Equals(other BitArray) bool {
 		selfIndex++
 	}
 
-	return true
+	// any blocks left over were not in the other bit array
+	return selfIndex == uint64(len(sba.indices))
 }
 
 // Count returns the number of set bits in this array.
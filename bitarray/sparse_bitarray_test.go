@@ -313,6 +313,10 @@ func TestSparseEqualsBitArray(t *testing.T) {
 
 	cba.SetBit(s + 1)
 	assert.True(t, cba.Equals(ba))
+
+	cba.SetBit(s * 10)
+	assert.False(t, cba.Equals(ba))
+	assert.False(t, ba.Equals(cba))
 }
 
 func BenchmarkCompressedEquals(b *testing.B) {
@@ -0,0 +1,120 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plus
+
+import "github.com/Workiva/go-datastructures/arena"
+
+// Arena allocates the nodes of one or more trees, and the slices of
+// keys and children they hold, from chunks, so building a large tree
+// creates a handful of allocations for the garbage collector to track
+// rather than a few per node.  Pass it to New or NewWithCompareFunc
+// with WithArena.  The slices a node outgrows on a split are not
+// returned to the arena; Reset frees everything at once, after which
+// every tree using the arena, including clones, must no longer be
+// used.  It is not safe for concurrent use.
+type Arena[K any] struct {
+	inodes arena.Arena[inode[K]]
+	lnodes arena.Arena[lnode[K]]
+	keys   arena.Arena[K]
+	nodes  arena.Arena[node[K]]
+}
+
+// NewArena returns an empty arena that allocates chunks of chunkSize
+// values, or of 1024 if chunkSize is not positive.  Chunks of keys
+// should hold many nodes' worth, so chunkSize is best several times
+// the node size of the trees using the arena.
+func NewArena[K any](chunkSize int) *Arena[K] {
+	return &Arena[K]{
+		inodes: *arena.New[inode[K]](chunkSize),
+		lnodes: *arena.New[lnode[K]](chunkSize),
+		keys:   *arena.New[K](chunkSize),
+		nodes:  *arena.New[node[K]](chunkSize),
+	}
+}
+
+// Reset frees every node allocated from the arena, keeping its chunks
+// to allocate from again.
+func (a *Arena[K]) Reset() {
+	a.inodes.Reset()
+	a.lnodes.Reset()
+	a.keys.Reset()
+	a.nodes.Reset()
+}
+
+// Release drops the arena's chunks, returning their memory to the
+// runtime once no tree built from them is referenced.
+func (a *Arena[K]) Release() {
+	a.inodes.Release()
+	a.lnodes.Release()
+	a.keys.Release()
+	a.nodes.Release()
+}
+
+// Option configures a tree created by New or NewWithCompareFunc.
+type Option[K any] func(*BTree[K])
+
+// WithArena allocates the tree's nodes from a rather than from the
+// heap.
+func WithArena[K any](a *Arena[K]) Option[K] {
+	return func(tree *BTree[K]) {
+		tree.arena = a
+	}
+}
+
+// The methods below allocate from the arena unless it is nil, in which
+// case they allocate from the heap.
+
+func (a *Arena[K]) newInode() *inode[K] {
+	if a == nil {
+		return &inode[K]{}
+	}
+	return a.inodes.New()
+}
+
+func (a *Arena[K]) newLnode() *lnode[K] {
+	if a == nil {
+		return &lnode[K]{}
+	}
+	return a.lnodes.New()
+}
+
+func (a *Arena[K]) makeKeys(n, capacity int) keySlice[K] {
+	if a == nil {
+		return make(keySlice[K], n, capacity)
+	}
+	return a.keys.Make(capacity)[:n]
+}
+
+func (a *Arena[K]) makeNodes(n, capacity int) nodes[K] {
+	if a == nil {
+		return make(nodes[K], n, capacity)
+	}
+	return a.nodes.Make(capacity)[:n]
+}
+
+func (a *Arena[K]) newInternalNode(size uint64) *inode[K] {
+	n := a.newInode()
+	n.keys = a.makeKeys(0, int(size))
+	n.nodes = a.makeNodes(0, int(size)+1)
+	return n
+}
+
+func (a *Arena[K]) newLeafNode(size uint64) *lnode[K] {
+	n := a.newLnode()
+	n.keys = a.makeKeys(0, int(size))
+	return n
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plus
+
+import (
+	"math/rand"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArena(t *testing.T) {
+	a := NewArena[*mockKey](16)
+	tree := New[*mockKey](4, WithArena(a))
+	keys := constructMockKeys(200)
+	shuffled := slices.Clone(keys)
+	rand.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	tree.Insert(shuffled...)
+
+	assert.Equal(t, []*mockKey(keys), slices.Collect(tree.All()))
+	_, found := tree.Get(keys...)
+	assert.NotContains(t, found, false)
+
+	clone := tree.Clone()
+	clone.Insert(newMockKey(500))
+	assert.Equal(t, uint64(200), tree.Len())
+	assert.Equal(t, uint64(201), clone.Len())
+
+	a.Reset()
+	tree = New[*mockKey](4, WithArena(a))
+	tree.Insert(shuffled[:50]...)
+	assert.Equal(t, uint64(50), tree.Len())
+	assert.True(t, slices.IsSortedFunc(slices.Collect(tree.All()), func(a, b *mockKey) int {
+		return a.value - b.value
+	}))
+}
+
+// BenchmarkBuildAndDiscard builds and discards a tree of 10,000 keys,
+// reporting the garbage collections it causes with and without an
+// arena.
+func BenchmarkBuildAndDiscard(b *testing.B) {
+	keys := constructRandomMockKeys(10000)
+	build := func(b *testing.B, opts func() []Option[*mockKey], reset func()) {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		b.ReportAllocs()
+		for b.Loop() {
+			tree := New[*mockKey](32, opts()...)
+			tree.Insert(keys...)
+			reset()
+		}
+		runtime.ReadMemStats(&after)
+		b.ReportMetric(float64(after.NumGC-before.NumGC)/float64(b.N), "gcs/op")
+	}
+
+	b.Run("heap", func(b *testing.B) {
+		build(b, func() []Option[*mockKey] { return nil }, func() {})
+	})
+	b.Run("arena", func(b *testing.B) {
+		a := NewArena[*mockKey](4096)
+		build(b, func() []Option[*mockKey] { return []Option[*mockKey]{WithArena(a)} }, a.Reset)
+	})
+}
@@ -45,12 +45,35 @@ BenchmarkGet-8	 			3000000	       		627 ns/op
 */
 package plus
 
-func keySearch[K Comparable[K]](keys keySlice[K], key K) int {
+import (
+	"iter"
+	"unsafe"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+// compareFunc compares two keys in the order this package has always
+// compared Comparable keys in: it returns 1 if a sorts before b, -1 if
+// it sorts after b and 0 if they are equal.
+type compareFunc[K any] func(a, b K) int
+
+// normalize clamps a comparison to -1, 0 or 1.
+func normalize(c int) int {
+	switch {
+	case c > 0:
+		return 1
+	case c < 0:
+		return -1
+	}
+	return 0
+}
+
+func keySearch[K any](keys keySlice[K], key K, compare compareFunc[K]) int {
 	low, high := 0, len(keys)-1
 	var mid int
 	for low <= high {
 		mid = (high + low) / 2
-		switch keys[mid].Compare(key) {
+		switch compare(keys[mid], key) {
 		case 1:
 			low = mid + 1
 		case -1:
@@ -63,14 +86,16 @@ func keySearch[K Comparable[K]](keys keySlice[K], key K) int {
 }
 
 // BTree is a generic B+ tree implementation.
-type BTree[K Comparable[K]] struct {
+type BTree[K any] struct {
 	root             node[K]
 	nodeSize, number uint64
+	compare          compareFunc[K]
+	arena            *Arena[K]
 }
 
 func (tree *BTree[K]) insert(key K) {
 	if tree.root == nil {
-		n := newLeafNode[K](tree.nodeSize)
+		n := tree.arena.newLeafNode(tree.nodeSize)
 		n.insert(tree, key)
 		tree.number = 1
 		return
@@ -102,17 +127,127 @@ func (tree *BTree[K]) Iter(key K) Iterator[K] {
 		return nilIterator[K]()
 	}
 
-	return tree.root.find(key)
+	return tree.root.find(key, tree.compare)
+}
+
+// All returns an iterator over the keys in the tree, in order.  The
+// tree must not be modified during iteration.
+func (tree *BTree[K]) All() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		if tree.root != nil {
+			walk(tree.root, false, yield)
+		}
+	}
+}
+
+// Backward returns an iterator over the keys in the tree in reverse
+// order.
+func (tree *BTree[K]) Backward() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		if tree.root != nil {
+			walk(tree.root, true, yield)
+		}
+	}
+}
+
+// walk visits the keys under n.  It descends through the internal
+// nodes rather than following the leaves, as leaves are only linked
+// to their siblings under the same parent.
+func walk[K any](n node[K], backward bool, yield func(K) bool) bool {
+	switch n := n.(type) {
+	case *inode[K]:
+		for i := range n.nodes {
+			if backward {
+				i = len(n.nodes) - 1 - i
+			}
+			if !walk(n.nodes[i], backward, yield) {
+				return false
+			}
+		}
+	case *lnode[K]:
+		for i := range n.keys {
+			if backward {
+				i = len(n.keys) - 1 - i
+			}
+			if !yield(n.keys[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Clone returns a copy of the tree that can be modified independently.
+// The copy keeps the shape of the original, so this is O(n) rather
+// than the O(n log n) of inserting each key again.  Keys are copied as
+// they are, so pointers are shared.
+func (tree *BTree[K]) Clone() *BTree[K] {
+	return tree.CloneWith(nil)
+}
+
+// CloneWith is like Clone, but copies each key with copyFn, which must
+// not change how the key orders against the others.  Only the keys in
+// the leaves are copied; internal nodes keep the originals for routing.
+func (tree *BTree[K]) CloneWith(copyFn func(K) K) *BTree[K] {
+	clone := &BTree[K]{
+		nodeSize: tree.nodeSize,
+		number:   tree.number,
+		compare:  tree.compare,
+		arena:    tree.arena,
+	}
+	if tree.root == nil {
+		return clone
+	}
+
+	leaves := make(map[*lnode[K]]*lnode[K])
+	clone.root = cloneNode(tree.arena, tree.root, copyFn, leaves)
+	for old, n := range leaves {
+		n.pointer = leaves[old.pointer]
+	}
+	return clone
+}
+
+// MemStats estimates the memory held by the tree.  Nodes counts both
+// internal and leaf nodes, and Slots the capacity of their key and
+// child slices.  This walks the tree, so it is O(n).
+func (tree *BTree[K]) MemStats() common.MemStats {
+	stats := common.MemStats{
+		Items: tree.number,
+		Bytes: uint64(unsafe.Sizeof(*tree)),
+	}
+	if tree.root != nil {
+		nodeMemStats(tree.root, &stats)
+	}
+	return stats
+}
+
+// nodeMemStats adds the memory held by the subtree under n to stats.
+func nodeMemStats[K any](n node[K], stats *common.MemStats) {
+	var key K
+	keySize := uint64(unsafe.Sizeof(key))
+	stats.Nodes++
+	switch n := n.(type) {
+	case *inode[K]:
+		stats.Slots += uint64(cap(n.keys) + cap(n.nodes))
+		stats.Bytes += uint64(unsafe.Sizeof(*n)) + uint64(cap(n.keys))*keySize +
+			uint64(cap(n.nodes))*uint64(unsafe.Sizeof(node[K](nil)))
+		for _, child := range n.nodes {
+			nodeMemStats(child, stats)
+		}
+	case *lnode[K]:
+		stats.Slots += uint64(cap(n.keys))
+		stats.Bytes += uint64(unsafe.Sizeof(*n)) + uint64(cap(n.keys))*keySize
+	}
 }
 
 func (tree *BTree[K]) get(key K) (K, bool) {
-	iter := tree.root.find(key)
+	iter := tree.root.find(key, tree.compare)
 	if !iter.Next() {
 		var zero K
 		return zero, false
 	}
 
-	if iter.Value().Compare(key) == 0 {
+	if tree.compare(iter.Value(), key) == 0 {
 		return iter.Value(), true
 	}
 
@@ -140,11 +275,32 @@ func (tree *BTree[K]) Len() uint64 {
 
 // New creates a new B+ tree with the specified node size.
 // The node size determines how many keys each node can hold.
-func New[K Comparable[K]](nodeSize uint64) *BTree[K] {
-	return &BTree[K]{
+func New[K Comparable[K]](nodeSize uint64, opts ...Option[K]) *BTree[K] {
+	return newTree(nodeSize, func(a, b K) int {
+		return normalize(a.Compare(b))
+	}, opts)
+}
+
+// NewWithCompareFunc is like New, but orders keys with the provided
+// function rather than requiring them to implement Comparable.  As
+// with any common.CompareFunc, compare should return a negative number
+// if a sorts before b.
+func NewWithCompareFunc[K any](compare common.CompareFunc[K], nodeSize uint64, opts ...Option[K]) *BTree[K] {
+	return newTree(nodeSize, func(a, b K) int {
+		return normalize(compare(b, a))
+	}, opts)
+}
+
+func newTree[K any](nodeSize uint64, compare compareFunc[K], opts []Option[K]) *BTree[K] {
+	tree := &BTree[K]{
 		nodeSize: nodeSize,
-		root:     newLeafNode[K](nodeSize),
+		compare:  compare,
+	}
+	for _, opt := range opts {
+		opt(tree)
 	}
+	tree.root = tree.arena.newLeafNode(nodeSize)
+	return tree
 }
 
 // Deprecated: Use New[T] instead.
@@ -18,10 +18,13 @@ package plus
 
 import (
 	"math/rand"
+	"slices"
 	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/Workiva/go-datastructures/common"
 )
 
 func constructRandomMockKeys(num int) keySlice[*mockKey] {
@@ -36,19 +39,19 @@ func TestSearchKeys(t *testing.T) {
 	keys := keySlice[*mockKey]{newMockKey(1), newMockKey(2), newMockKey(4)}
 
 	testKey := newMockKey(5)
-	assert.Equal(t, 3, keySearch(keys, testKey))
+	assert.Equal(t, 3, keySearch(keys, testKey, (*mockKey).Compare))
 
 	testKey = newMockKey(2)
-	assert.Equal(t, 1, keySearch(keys, testKey))
+	assert.Equal(t, 1, keySearch(keys, testKey, (*mockKey).Compare))
 
 	testKey = newMockKey(0)
-	assert.Equal(t, 0, keySearch(keys, testKey))
+	assert.Equal(t, 0, keySearch(keys, testKey, (*mockKey).Compare))
 
 	testKey = newMockKey(3)
-	assert.Equal(t, 2, keySearch(keys, testKey))
+	assert.Equal(t, 2, keySearch(keys, testKey, (*mockKey).Compare))
 
 	var nilKeys keySlice[*mockKey]
-	assert.Equal(t, 0, keySearch(nilKeys, testKey))
+	assert.Equal(t, 0, keySearch(nilKeys, testKey, (*mockKey).Compare))
 }
 
 func TestTreeInsert2_3_4(t *testing.T) {
@@ -431,3 +434,98 @@ func BenchmarkSimultaneousReadsAndWrites(b *testing.B) {
 		wg.Wait()
 	}
 }
+
+func TestCompareFunc(t *testing.T) {
+	tree := NewWithCompareFunc(common.Reverse(common.OrderedCompare[int]()), 4)
+	tree.Insert(6, 2, 9, 0, 3, 4, 7, 1, 8, 5)
+	assert.Equal(t, uint64(10), tree.Len())
+
+	var keys []int
+	for iter := tree.Iter(7); iter.Next(); {
+		keys = append(keys, iter.Value())
+	}
+	assert.Equal(t, []int{7, 6, 5, 4, 3, 2, 1, 0}, keys)
+
+	result, found := tree.Get(7, 30)
+	assert.Equal(t, []int{7, 0}, result)
+	assert.Equal(t, []bool{true, false}, found)
+}
+
+func TestAllBackward(t *testing.T) {
+	tree := NewWithCompareFunc(common.OrderedCompare[int](), 3)
+	assert.Empty(t, slices.Collect(tree.All()))
+
+	keys := rand.Perm(100)
+	tree.Insert(keys...)
+	slices.Sort(keys)
+	assert.Equal(t, keys, slices.Collect(tree.All()))
+	slices.Reverse(keys)
+	assert.Equal(t, keys, slices.Collect(tree.Backward()))
+	for key := range tree.Backward() {
+		assert.Equal(t, 99, key)
+		break
+	}
+}
+
+func TestClone(t *testing.T) {
+	tree := NewWithCompareFunc(common.OrderedCompare[int](), 3)
+	assert.Empty(t, slices.Collect(tree.Clone().All()))
+
+	keys := rand.Perm(100)
+	tree.Insert(keys...)
+	clone := tree.Clone()
+	assert.NoError(t, checkInvariants(clone))
+	assert.Equal(t, slices.Collect(tree.All()), slices.Collect(clone.All()))
+
+	var iterated []int
+	for iter := clone.Iter(50); iter.Next(); {
+		iterated = append(iterated, iter.Value())
+	}
+	var expected []int
+	for iter := tree.Iter(50); iter.Next(); {
+		expected = append(expected, iter.Value())
+	}
+	assert.Equal(t, expected, iterated)
+
+	clone.Insert(100, 101)
+	assert.NoError(t, checkInvariants(clone))
+	assert.NoError(t, checkInvariants(tree))
+	assert.Equal(t, uint64(100), tree.Len())
+	assert.Equal(t, uint64(102), clone.Len())
+	_, found := tree.Get(100)
+	assert.Equal(t, []bool{false}, found)
+}
+
+func TestCloneWith(t *testing.T) {
+	compare := func(a, b *int) int { return *a - *b }
+	tree := NewWithCompareFunc(compare, 3)
+	originals := make([]*int, 10)
+	for i := range originals {
+		originals[i] = &i
+		tree.Insert(originals[i])
+	}
+
+	clone := tree.CloneWith(func(p *int) *int {
+		v := *p
+		return &v
+	})
+	i := 0
+	for key := range clone.All() {
+		assert.NotSame(t, originals[i], key)
+		assert.Equal(t, i, *key)
+		i++
+	}
+	assert.Equal(t, 10, i)
+}
+
+func TestMemStats(t *testing.T) {
+	tree := NewWithCompareFunc(common.OrderedCompare[int](), 4)
+	assert.Equal(t, uint64(1), tree.MemStats().Nodes) // an empty root leaf
+
+	tree.Insert(rand.Perm(100)...)
+	stats := tree.MemStats()
+	assert.Equal(t, uint64(100), stats.Items)
+	assert.Greater(t, stats.Nodes, uint64(100/4))
+	assert.GreaterOrEqual(t, stats.Slots, uint64(100))
+	assert.Greater(t, stats.Bytes, stats.Slots*8)
+}
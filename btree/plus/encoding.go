@@ -0,0 +1,46 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plus
+
+import "github.com/Workiva/go-datastructures/codec"
+
+// MarshalBinary encodes the tree's keys, in order, with the codec
+// package's encoding for their type.
+func (tree *BTree[K]) MarshalBinary() ([]byte, error) {
+	keys, err := codec.For[K]()
+	if err != nil {
+		return nil, err
+	}
+	return codec.MarshalSeq(tree.All(), keys)
+}
+
+// UnmarshalBinary decodes keys encoded by MarshalBinary, inserting them
+// into the tree, which must have been created with New or
+// NewWithCompareFunc so that it knows how to order them.  The tree is
+// unchanged if the data is invalid.
+func (tree *BTree[K]) UnmarshalBinary(data []byte) error {
+	if tree.compare == nil {
+		return errUnmarshalUninitialized
+	}
+	keys, err := codec.For[K]()
+	if err != nil {
+		return err
+	}
+	return codec.UnmarshalSeq(data, keys, func(key K) {
+		tree.insert(key)
+	})
+}
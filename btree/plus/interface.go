@@ -16,14 +16,14 @@ limitations under the License.
 
 package plus
 
+import "github.com/Workiva/go-datastructures/common"
+
 // Comparable is a constraint for types that can be compared for ordering.
 // The Compare method should return:
 //   - negative value if receiver < other
 //   - zero if receiver == other
 //   - positive value if receiver > other
-type Comparable[T any] interface {
-	Compare(other T) int
-}
+type Comparable[T any] = common.ComparableItem[T]
 
 // Key is kept for backward compatibility.
 // Deprecated: Use Comparable[T] with the generic BTree[T] instead.
@@ -35,12 +35,5 @@ type Key interface {
 // Deprecated: Use []T with the generic BTree[T] instead.
 type Keys []Key
 
-// Iterator defines an interface for traversing tree results in order.
-type Iterator[T any] interface {
-	// Next moves the iterator to the next position and returns
-	// true if there is a value.
-	Next() bool
-	// Value returns the key at the current iterator position.
-	// Returns zero value if exhausted or not yet started.
-	Value() T
-}
+// Iterator traverses tree results in order.
+type Iterator[T any] = common.Iterator[T]
@@ -0,0 +1,145 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plus
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Workiva/go-datastructures/common"
+	"github.com/Workiva/go-datastructures/internal/check"
+)
+
+// checkInvariants returns an error if the tree's leaves are at
+// different depths, any node is over or under filled, or any key is
+// out of order or outside the bounds its parents route to it.
+func checkInvariants[K any](tree *BTree[K]) error {
+	// less reports whether a sorts before b; the tree's own compare
+	// returns 1 when it does.
+	less := func(a, b K) bool {
+		return tree.compare(a, b) > 0
+	}
+
+	var (
+		count     uint64
+		leafDepth = -1
+		leaves    []*lnode[K]
+	)
+	var walk func(n node[K], depth int, low, high *K) error
+	walk = func(n node[K], depth int, low, high *K) error {
+		var keys keySlice[K]
+		switch n := n.(type) {
+		case *inode[K]:
+			keys = n.keys
+			if len(n.nodes) != len(keys)+1 {
+				return check.Errorf(`internal node with %d keys has %d children`, len(keys), len(n.nodes))
+			}
+			for i, child := range n.nodes {
+				childLow, childHigh := low, high
+				if i > 0 {
+					childLow = &keys[i-1]
+				}
+				if i < len(keys) {
+					childHigh = &keys[i]
+				}
+				if err := walk(child, depth+1, childLow, childHigh); err != nil {
+					return err
+				}
+			}
+		case *lnode[K]:
+			keys = n.keys
+			count += uint64(len(keys))
+			leaves = append(leaves, n)
+			if leafDepth == -1 {
+				leafDepth = depth
+			} else if depth != leafDepth {
+				return check.Errorf(`leaves at depths %d and %d`, leafDepth, depth)
+			}
+		}
+
+		if depth > 0 {
+			// nodes split in half once they reach the node size
+			minKeys := int(tree.nodeSize-1) / 2
+			if _, ok := n.(*lnode[K]); ok {
+				minKeys = int(tree.nodeSize) / 2
+			}
+			if len(keys) < max(minKeys, 1) {
+				return check.Errorf(`node at depth %d has %d keys, fewer than %d`, depth, len(keys), minKeys)
+			}
+		}
+		if uint64(len(keys)) >= tree.nodeSize {
+			return check.Errorf(`node at depth %d has %d keys, more than the node size %d allows`, depth, len(keys), tree.nodeSize)
+		}
+		for i, key := range keys {
+			if i > 0 && !less(keys[i-1], key) {
+				return check.Errorf(`key %v is out of order after %v`, key, keys[i-1])
+			}
+			// a child holds keys from the one before it, inclusive,
+			// up to the one after it
+			if low != nil && less(key, *low) || high != nil && !less(key, *high) {
+				return check.Errorf(`key %v is outside the range its parent routes to it`, key)
+			}
+		}
+		return nil
+	}
+
+	if err := walk(tree.root, 0, nil, nil); err != nil {
+		return err
+	}
+	if count != tree.Len() {
+		return check.Errorf(`%d keys in a tree of length %d`, count, tree.Len())
+	}
+	return nil
+}
+
+func TestInvariants(t *testing.T) {
+	tree := NewWithCompareFunc(common.OrderedCompare[int](), 8)
+	require.NoError(t, checkInvariants(tree))
+	for i := range 1000 {
+		tree.Insert((i * 7919) % 1000)
+	}
+	require.NoError(t, checkInvariants(tree))
+
+	in := tree.root.(*inode[int])
+	in.keys[0], in.keys[1] = in.keys[1], in.keys[0]
+	assert.Error(t, checkInvariants(tree))
+}
+
+func FuzzBTree(f *testing.F) {
+	f.Add(uint8(3), []byte{0, 1, 0, 2, 0, 3, 1, 2, 0, 9, 0, 5, 0, 4, 1, 7})
+	f.Fuzz(func(t *testing.T, nodeSize uint8, data []byte) {
+		// nodes of fewer than three keys cannot split
+		tree := NewWithCompareFunc(common.OrderedCompare[int](), uint64(nodeSize%30+3))
+		model := map[int]bool{}
+		for op := range check.Ops(data, 2) {
+			switch op.Kind {
+			case 0:
+				tree.Insert(op.Key)
+				model[op.Key] = true
+			case 1:
+				_, found := tree.Get(op.Key)
+				assert.Equal(t, model[op.Key], found[0])
+			}
+			require.NoError(t, checkInvariants(tree))
+		}
+		assert.Equal(t, slices.Sorted(maps.Keys(model)), slices.Collect(tree.All()))
+	})
+}
@@ -18,7 +18,7 @@ package plus
 
 const iteratorExhausted = -2
 
-type iterator[K Comparable[K]] struct {
+type iterator[K any] struct {
 	node  *lnode[K]
 	index int
 }
@@ -61,7 +61,7 @@ func (iter *iterator[K]) exhaust() keySlice[K] {
 	return keys
 }
 
-func nilIterator[K Comparable[K]]() *iterator[K] {
+func nilIterator[K any]() *iterator[K] {
 	return &iterator[K]{
 		index: iteratorExhausted,
 	}
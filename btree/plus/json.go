@@ -0,0 +1,42 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plus
+
+import (
+	"errors"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+var errUnmarshalUninitialized = errors.New(`plus: cannot unmarshal into a BTree not created with New`)
+
+// MarshalJSON encodes the tree as a JSON array of its keys, in order.
+func (tree *BTree[K]) MarshalJSON() ([]byte, error) {
+	return common.MarshalJSONArray(tree.All(), common.DefaultJSONCodec[K]())
+}
+
+// UnmarshalJSON decodes a JSON array, inserting its keys into the tree,
+// which must have been created with New or NewWithCompareFunc so that
+// it knows how to order them.
+func (tree *BTree[K]) UnmarshalJSON(data []byte) error {
+	if tree.compare == nil {
+		return errUnmarshalUninitialized
+	}
+	return common.UnmarshalJSONArray(data, common.DefaultJSONCodec[K](), func(key K) {
+		tree.insert(key)
+	})
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plus
+
+import (
+	"encoding/json"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+func TestBTreeJSON(t *testing.T) {
+	tree := NewWithCompareFunc(common.OrderedCompare[int](), 4)
+	keys := rand.Perm(100)
+	tree.Insert(keys...)
+	b, err := json.Marshal(tree)
+	require.NoError(t, err)
+
+	var got []int
+	require.NoError(t, json.Unmarshal(b, &got))
+	slices.Sort(keys)
+	assert.Equal(t, keys, got)
+
+	decoded := NewWithCompareFunc(common.OrderedCompare[int](), 8)
+	require.NoError(t, json.Unmarshal(b, decoded))
+	assert.Equal(t, uint64(100), decoded.Len())
+	_, found := decoded.Get(42)
+	assert.True(t, found[0])
+
+	b, err = json.Marshal(NewWithCompareFunc(common.OrderedCompare[int](), 4))
+	require.NoError(t, err)
+	assert.Equal(t, `[]`, string(b))
+	assert.Error(t, json.Unmarshal(b, &BTree[int]{}))
+}
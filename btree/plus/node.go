@@ -16,14 +16,14 @@ limitations under the License.
 
 package plus
 
-func split[K Comparable[K]](tree *BTree[K], parent, child node[K]) node[K] {
+func split[K any](tree *BTree[K], parent, child node[K]) node[K] {
 	if !child.needsSplit(tree.nodeSize) {
 		return parent
 	}
 
-	key, left, right := child.split()
+	key, left, right := child.split(tree.arena)
 	if parent == nil {
-		in := newInternalNode[K](tree.nodeSize)
+		in := tree.arena.newInternalNode(tree.nodeSize)
 		in.keys = append(in.keys, key)
 		in.nodes = append(in.nodes, left)
 		in.nodes = append(in.nodes, right)
@@ -31,7 +31,7 @@ func split[K Comparable[K]](tree *BTree[K], parent, child node[K]) node[K] {
 	}
 
 	p := parent.(*inode[K])
-	i := p.search(key)
+	i := p.search(key, tree.compare)
 	// we want to ensure if the children are leaves we set
 	// the left node's left sibling to point to left
 	if cr, ok := left.(*lnode[K]); ok {
@@ -46,17 +46,17 @@ func split[K Comparable[K]](tree *BTree[K], parent, child node[K]) node[K] {
 	return parent
 }
 
-type node[K Comparable[K]] interface {
+type node[K any] interface {
 	insert(tree *BTree[K], key K) bool
 	needsSplit(nodeSize uint64) bool
 	// key is the median key while left and right nodes
 	// represent the left and right nodes respectively
-	split() (K, node[K], node[K])
-	search(key K) int
-	find(key K) *iterator[K]
+	split(a *Arena[K]) (K, node[K], node[K])
+	search(key K, compare compareFunc[K]) int
+	find(key K, compare compareFunc[K]) *iterator[K]
 }
 
-type nodes[K Comparable[K]] []node[K]
+type nodes[K any] []node[K]
 
 func (nodes *nodes[K]) insertAt(i int, n node[K]) {
 	if i == len(*nodes) {
@@ -69,46 +69,46 @@ func (nodes *nodes[K]) insertAt(i int, n node[K]) {
 	(*nodes)[i] = n
 }
 
-func (ns nodes[K]) splitAt(i int) (nodes[K], nodes[K]) {
-	left := make(nodes[K], i, cap(ns))
-	right := make(nodes[K], len(ns)-i, cap(ns))
+func (ns nodes[K]) splitAt(a *Arena[K], i int) (nodes[K], nodes[K]) {
+	left := a.makeNodes(i, cap(ns))
+	right := a.makeNodes(len(ns)-i, cap(ns))
 	copy(left, ns[:i])
 	copy(right, ns[i:])
 	return left, right
 }
 
-type inode[K Comparable[K]] struct {
+type inode[K any] struct {
 	keys  keySlice[K]
 	nodes nodes[K]
 }
 
-func (n *inode[K]) search(key K) int {
-	return n.keys.search(key)
+func (n *inode[K]) search(key K, compare compareFunc[K]) int {
+	return n.keys.search(key, compare)
 }
 
-func (n *inode[K]) find(key K) *iterator[K] {
-	i := n.search(key)
+func (n *inode[K]) find(key K, compare compareFunc[K]) *iterator[K] {
+	i := n.search(key, compare)
 	if i == len(n.keys) {
-		return n.nodes[len(n.nodes)-1].find(key)
+		return n.nodes[len(n.nodes)-1].find(key, compare)
 	}
 
 	found := n.keys[i]
-	switch found.Compare(key) {
+	switch compare(found, key) {
 	case 0, 1:
-		return n.nodes[i+1].find(key)
+		return n.nodes[i+1].find(key, compare)
 	default:
-		return n.nodes[i].find(key)
+		return n.nodes[i].find(key, compare)
 	}
 }
 
 func (n *inode[K]) insert(tree *BTree[K], key K) bool {
-	i := n.search(key)
+	i := n.search(key, tree.compare)
 	var child node[K]
 	if i == len(n.keys) { // we want the last child node in this case
 		child = n.nodes[len(n.nodes)-1]
 	} else {
 		match := n.keys[i]
-		switch match.Compare(key) {
+		switch tree.compare(match, key) {
 		case 1, 0:
 			child = n.nodes[i+1]
 		default:
@@ -132,7 +132,7 @@ func (n *inode[K]) needsSplit(nodeSize uint64) bool {
 	return uint64(len(n.keys)) >= nodeSize
 }
 
-func (n *inode[K]) split() (K, node[K], node[K]) {
+func (n *inode[K]) split(a *Arena[K]) (K, node[K], node[K]) {
 	if len(n.keys) < 3 {
 		var zero K
 		return zero, nil, nil
@@ -141,45 +141,41 @@ func (n *inode[K]) split() (K, node[K], node[K]) {
 	i := len(n.keys) / 2
 	key := n.keys[i]
 
-	ourKeys := make(keySlice[K], len(n.keys)-i-1, cap(n.keys))
-	otherKeys := make(keySlice[K], i, cap(n.keys))
+	ourKeys := a.makeKeys(len(n.keys)-i-1, cap(n.keys))
+	otherKeys := a.makeKeys(i, cap(n.keys))
 	copy(ourKeys, n.keys[i+1:])
 	copy(otherKeys, n.keys[:i])
-	left, right := n.nodes.splitAt(i + 1)
-	otherNode := &inode[K]{
-		keys:  otherKeys,
-		nodes: left,
-	}
+	left, right := n.nodes.splitAt(a, i+1)
+	otherNode := a.newInode()
+	otherNode.keys = otherKeys
+	otherNode.nodes = left
 	n.keys = ourKeys
 	n.nodes = right
 	return key, otherNode, n
 }
 
-func newInternalNode[K Comparable[K]](size uint64) *inode[K] {
-	return &inode[K]{
-		keys:  make(keySlice[K], 0, size),
-		nodes: make(nodes[K], 0, size+1),
-	}
+func newInternalNode[K any](size uint64) *inode[K] {
+	return (*Arena[K])(nil).newInternalNode(size)
 }
 
-type lnode[K Comparable[K]] struct {
+type lnode[K any] struct {
 	// points to the left leaf node is there is one
 	pointer *lnode[K]
 	keys    keySlice[K]
 }
 
-func (n *lnode[K]) search(key K) int {
-	return n.keys.search(key)
+func (n *lnode[K]) search(key K, compare compareFunc[K]) int {
+	return n.keys.search(key, compare)
 }
 
 func (n *lnode[K]) insert(tree *BTree[K], key K) bool {
-	i := keySearch(n.keys, key)
+	i := keySearch(n.keys, key, tree.compare)
 	var inserted bool
 	if i == len(n.keys) { // simple append will do
 		n.keys = append(n.keys, key)
 		inserted = true
 	} else {
-		if n.keys[i].Compare(key) == 0 {
+		if tree.compare(n.keys[i], key) == 0 {
 			n.keys[i] = key
 		} else {
 			n.keys.insertAt(i, key)
@@ -194,8 +190,8 @@ func (n *lnode[K]) insert(tree *BTree[K], key K) bool {
 	return true
 }
 
-func (n *lnode[K]) find(key K) *iterator[K] {
-	i := n.search(key)
+func (n *lnode[K]) find(key K, compare compareFunc[K]) *iterator[K] {
+	i := n.search(key, compare)
 	if i == len(n.keys) {
 		if n.pointer == nil {
 			return nilIterator[K]()
@@ -214,15 +210,15 @@ func (n *lnode[K]) find(key K) *iterator[K] {
 	return iter
 }
 
-func (n *lnode[K]) split() (K, node[K], node[K]) {
+func (n *lnode[K]) split(a *Arena[K]) (K, node[K], node[K]) {
 	if len(n.keys) < 2 {
 		var zero K
 		return zero, nil, nil
 	}
 	i := len(n.keys) / 2
 	key := n.keys[i]
-	otherKeys := make(keySlice[K], i, cap(n.keys))
-	ourKeys := make(keySlice[K], len(n.keys)-i, cap(n.keys))
+	otherKeys := a.makeKeys(i, cap(n.keys))
+	ourKeys := a.makeKeys(len(n.keys)-i, cap(n.keys))
 	// we perform these copies so these slices don't all end up
 	// pointing to the same underlying array which may make
 	// for some very difficult to debug situations later.
@@ -231,10 +227,9 @@ func (n *lnode[K]) split() (K, node[K], node[K]) {
 
 	// this should release the original array for GC
 	n.keys = ourKeys
-	otherNode := &lnode[K]{
-		keys:    otherKeys,
-		pointer: n,
-	}
+	otherNode := a.newLnode()
+	otherNode.keys = otherKeys
+	otherNode.pointer = n
 	return key, otherNode, n
 }
 
@@ -242,16 +237,42 @@ func (n *lnode[K]) needsSplit(nodeSize uint64) bool {
 	return uint64(len(n.keys)) >= nodeSize
 }
 
-func newLeafNode[K Comparable[K]](size uint64) *lnode[K] {
-	return &lnode[K]{
-		keys: make(keySlice[K], 0, size),
+func newLeafNode[K any](size uint64) *lnode[K] {
+	return (*Arena[K])(nil).newLeafNode(size)
+}
+
+// cloneNode copies the subtree under n, recording each copied leaf
+// against its original so the caller can relink the leaf pointers.
+func cloneNode[K any](a *Arena[K], n node[K], copyFn func(K) K, leaves map[*lnode[K]]*lnode[K]) node[K] {
+	switch n := n.(type) {
+	case *inode[K]:
+		clone := a.newInode()
+		clone.keys = a.makeKeys(len(n.keys), cap(n.keys))
+		clone.nodes = a.makeNodes(0, cap(n.nodes))
+		copy(clone.keys, n.keys)
+		for _, child := range n.nodes {
+			clone.nodes = append(clone.nodes, cloneNode(a, child, copyFn, leaves))
+		}
+		return clone
+	case *lnode[K]:
+		clone := a.newLnode()
+		clone.keys = a.makeKeys(len(n.keys), cap(n.keys))
+		copy(clone.keys, n.keys)
+		if copyFn != nil {
+			for i, key := range clone.keys {
+				clone.keys[i] = copyFn(key)
+			}
+		}
+		leaves[n] = clone
+		return clone
 	}
+	return nil
 }
 
-type keySlice[K Comparable[K]] []K
+type keySlice[K any] []K
 
-func (ks keySlice[K]) search(key K) int {
-	return keySearch(ks, key)
+func (ks keySlice[K]) search(key K, compare compareFunc[K]) int {
+	return keySearch(ks, key, compare)
 }
 
 func (ks *keySlice[K]) insertAt(i int, key K) {
@@ -117,7 +117,7 @@ func TestLeafNodeSplitEvenNumber(t *testing.T) {
 		keys: keys,
 	}
 
-	key, left, right := node.split()
+	key, left, right := node.split(nil)
 	assert.Equal(t, keys[2], key)
 	assert.Equal(t, left.(*lnode[*mockKey]).keys, keys[:2])
 	assert.Equal(t, right.(*lnode[*mockKey]).keys, keys[2:])
@@ -131,7 +131,7 @@ func TestLeafNodeSplitOddNumber(t *testing.T) {
 		keys: keys,
 	}
 
-	key, left, right := node.split()
+	key, left, right := node.split(nil)
 	assert.Equal(t, keys[1], key)
 	assert.Equal(t, left.(*lnode[*mockKey]).keys, keys[:1])
 	assert.Equal(t, right.(*lnode[*mockKey]).keys, keys[1:])
@@ -145,7 +145,7 @@ func TestTwoKeysLeafNodeSplit(t *testing.T) {
 		keys: keys,
 	}
 
-	key, left, right := node.split()
+	key, left, right := node.split(nil)
 	assert.Equal(t, keys[1], key)
 	assert.Equal(t, left.(*lnode[*mockKey]).keys, keys[:1])
 	assert.Equal(t, right.(*lnode[*mockKey]).keys, keys[1:])
@@ -159,7 +159,7 @@ func TestLessThanTwoKeysSplit(t *testing.T) {
 		keys: keys,
 	}
 
-	key, left, right := node.split()
+	key, left, right := node.split(nil)
 	assert.Nil(t, key)
 	assert.Nil(t, left)
 	assert.Nil(t, right)
@@ -169,7 +169,7 @@ func TestInternalNodeSplit2_3_4(t *testing.T) {
 	ns := constructMockNodes(4)
 	in := constructMockInternalNode(ns)
 
-	key, left, right := in.split()
+	key, left, right := in.split(nil)
 	assert.Equal(t, ns[3].(*lnode[*mockKey]).keys[0], key)
 	assert.Len(t, left.(*inode[*mockKey]).keys, 1)
 	assert.Len(t, right.(*inode[*mockKey]).keys, 1)
@@ -181,7 +181,7 @@ func TestInternalNodeSplit3_4_5(t *testing.T) {
 	ns := constructMockNodes(5)
 	in := constructMockInternalNode(ns)
 
-	key, left, right := in.split()
+	key, left, right := in.split(nil)
 	assert.Equal(t, ns[4].(*lnode[*mockKey]).keys[0], key)
 	assert.Len(t, left.(*inode[*mockKey]).keys, 2)
 	assert.Len(t, right.(*inode[*mockKey]).keys, 1)
@@ -193,7 +193,7 @@ func TestInternalNodeLessThan3Keys(t *testing.T) {
 	ns := constructMockNodes(2)
 	in := constructMockInternalNode(ns)
 
-	key, left, right := in.split()
+	key, left, right := in.split(nil)
 	assert.Nil(t, key)
 	assert.Nil(t, left)
 	assert.Nil(t, right)
@@ -0,0 +1,171 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plus
+
+import (
+	"iter"
+	"sync"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+// Synchronized wraps a b-tree with a read-write mutex so it can be
+// shared between goroutines.  Lookups take the read lock and inserts
+// take the write lock, so this suits light contention rather than
+// write-heavy workloads.
+//
+// The iterator returned by Iter, and the sequences returned by All
+// and Backward, work on a copy of the keys taken under the read lock,
+// so they are O(n) to start but are unaffected by later inserts and
+// may insert into the tree freely.
+type Synchronized[K any] struct {
+	lock sync.RWMutex
+	tree *BTree[K]
+}
+
+// NewSynchronized returns a Synchronized that guards the provided
+// tree.  The tree must not be used directly afterwards.
+func NewSynchronized[K any](tree *BTree[K]) *Synchronized[K] {
+	return &Synchronized[K]{tree: tree}
+}
+
+// Insert is the synchronized version of BTree.Insert.
+func (s *Synchronized[K]) Insert(keys ...K) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.tree.Insert(keys...)
+}
+
+// Get is the synchronized version of BTree.Get.
+func (s *Synchronized[K]) Get(keys ...K) ([]K, []bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.tree.Get(keys...)
+}
+
+// Len is the synchronized version of BTree.Len.
+func (s *Synchronized[K]) Len() uint64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.tree.Len()
+}
+
+// Iter returns an iterator over a copy of the keys from the provided
+// key or its successor onwards.
+func (s *Synchronized[K]) Iter(key K) Iterator[K] {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var keys keySlice[K]
+	if it, ok := s.tree.Iter(key).(*iterator[K]); ok {
+		keys = it.exhaust()
+	}
+	return &iterator[K]{node: &lnode[K]{keys: keys}, index: -1}
+}
+
+// All returns an iterator over a copy of the keys, in order.  The copy
+// is taken when iteration begins.
+func (s *Synchronized[K]) All() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for _, key := range s.snapshot() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over a copy of the keys in reverse
+// order.  The copy is taken when iteration begins.
+func (s *Synchronized[K]) Backward() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		keys := s.snapshot()
+		for i := len(keys) - 1; i >= 0; i-- {
+			if !yield(keys[i]) {
+				return
+			}
+		}
+	}
+}
+
+func (s *Synchronized[K]) snapshot() []K {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	keys := make([]K, 0, s.tree.Len())
+	for key := range s.tree.All() {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Clone returns a synchronized copy of the tree.
+func (s *Synchronized[K]) Clone() *Synchronized[K] {
+	return s.CloneWith(nil)
+}
+
+// CloneWith returns a synchronized copy of the tree, copying each key
+// with copyFn as BTree.CloneWith does.
+func (s *Synchronized[K]) CloneWith(copyFn func(K) K) *Synchronized[K] {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return NewSynchronized(s.tree.CloneWith(copyFn))
+}
+
+// MemStats is the synchronized version of BTree.MemStats.
+func (s *Synchronized[K]) MemStats() common.MemStats {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.tree.MemStats()
+}
+
+// MarshalBinary is the synchronized version of BTree.MarshalBinary.
+func (s *Synchronized[K]) MarshalBinary() ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.tree.MarshalBinary()
+}
+
+// UnmarshalBinary is the synchronized version of BTree.UnmarshalBinary.
+func (s *Synchronized[K]) UnmarshalBinary(data []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.tree.UnmarshalBinary(data)
+}
+
+// MarshalJSON is the synchronized version of BTree.MarshalJSON.
+func (s *Synchronized[K]) MarshalJSON() ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.tree.MarshalJSON()
+}
+
+// UnmarshalJSON is the synchronized version of BTree.UnmarshalJSON.
+func (s *Synchronized[K]) UnmarshalJSON(data []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.tree.UnmarshalJSON(data)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plus
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSynchronizedConcurrentInsert(t *testing.T) {
+	s := NewSynchronized(New[*mockKey](8))
+	keys := constructMockKeys(400)
+	var wg sync.WaitGroup
+	for _, part := range chunkKeys(keys, 4) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, key := range part {
+				s.Insert(key)
+				s.Get(key)
+				s.Len()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, uint64(400), s.Len())
+	assert.Equal(t, []*mockKey(keys), slices.Collect(s.All()))
+}
+
+func TestSynchronizedIterIsSnapshot(t *testing.T) {
+	s := NewSynchronized(New[*mockKey](3))
+	keys := constructMockKeys(5)
+	s.Insert(keys...)
+
+	it := s.Iter(newMockKey(2))
+	s.Insert(newMockKey(10))
+
+	var got []*mockKey
+	for it.Next() {
+		got = append(got, it.Value())
+	}
+	assert.Equal(t, []*mockKey(keys[2:]), got)
+	assert.False(t, it.Next())
+	assert.Nil(t, it.Value())
+
+	for key := range s.Backward() {
+		// inserting during iteration must not deadlock
+		s.Insert(newMockKey(key.value + 100))
+	}
+	assert.Equal(t, uint64(12), s.Len())
+
+	clone := s.Clone()
+	clone.Insert(newMockKey(50))
+	assert.Equal(t, uint64(12), s.Len())
+	assert.Equal(t, uint64(13), clone.Len())
+}
@@ -29,7 +29,13 @@ package cache
 
 import (
 	"container/list"
+	"hash/maphash"
+	"iter"
 	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/Workiva/go-datastructures/common"
 )
 
 // Sized is an interface for items that have a size.
@@ -59,21 +65,64 @@ func WithPolicy[K comparable, V Sized](policy Policy) Option[K, V] {
 	}
 }
 
+// WithTinyLFU enables a TinyLFU admission filter. Accesses are counted
+// in a count-min sketch with the given number of counters per row
+// (rounded up to a power of two, 1024 if zero), which should be on the
+// order of the number of items the cache is expected to hold. When a
+// Put of a new key would evict existing entries, the new key is only
+// admitted if it has been accessed more often than every entry it
+// would evict. This keeps one-off keys from a scan flushing the
+// frequently used entries out of the cache.
+func WithTinyLFU[K comparable, V Sized](counters uint64) Option[K, V] {
+	return WithTinyLFUHasher[K, V](counters, common.SeededHash[K](maphash.MakeSeed()))
+}
+
+// WithTinyLFUHasher is like WithTinyLFU, but the sketch hashes keys
+// with the provided hasher, such as one of the hash functions in the
+// common package, instead of the runtime's seeded hash function.
+func WithTinyLFUHasher[K comparable, V Sized](counters uint64, hasher common.Hasher[K]) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.admission = newTinyLFU[K](counters, hasher)
+	}
+}
+
+// WithMetrics reports operational measurements to m:
+//
+//	cache.hits        counter, lookups that found their key
+//	cache.misses      counter, lookups that did not
+//	cache.evictions   counter, entries removed to make room
+//	cache.rejections  counter, new keys refused by the admission filter
+//	cache.size        gauge, the total size of the items held
+//	cache.items       gauge, the number of items held
+//
+// Peek and Contains are not counted as lookups, and the gauges are
+// updated whenever the contents change.
+func WithMetrics[K comparable, V Sized](m common.Metrics) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.metrics = m
+	}
+}
+
 // cached wraps an item with its eviction list element.
 type cached[V Sized] struct {
 	item    V
 	element *list.Element
+	added   time.Time
 }
 
 // Cache is a generic bounded-size in-memory cache.
 // K must be comparable (usable as map key), V must implement Sized.
 type Cache[K comparable, V Sized] struct {
 	sync.RWMutex
-	capacity uint64
-	size     uint64
-	items    map[K]*cached[V]
-	keyList  *list.List
-	policy   Policy
+	capacity  uint64
+	size      uint64
+	items     map[K]*cached[V]
+	keyList   *list.List
+	policy    Policy
+	admission *tinyLFU[K]
+	refresher *refresher[K, V]
+	metrics   common.Metrics
+	now       func() time.Time
 }
 
 // New creates a new cache with the given capacity in bytes.
@@ -83,6 +132,7 @@ func New[K comparable, V Sized](capacity uint64, options ...Option[K, V]) *Cache
 		items:    make(map[K]*cached[V]),
 		keyList:  list.New(),
 		policy:   LeastRecentlyUsed,
+		now:      time.Now,
 	}
 
 	for _, opt := range options {
@@ -98,55 +148,101 @@ func (c *Cache[K, V]) Get(key K) (V, bool) {
 	c.Lock()
 	defer c.Unlock()
 
+	c.recordAccess(key)
 	cached, ok := c.items[key]
 	if !ok {
+		c.count("cache.misses", 1)
 		var zero V
 		return zero, false
 	}
+	c.count("cache.hits", 1)
 
 	if c.policy == LeastRecentlyUsed {
 		c.keyList.MoveToFront(cached.element)
 	}
+	c.maybeRefresh(key, cached)
 
 	return cached.item, true
 }
 
+// Peek retrieves an item from the cache without affecting its position
+// in the eviction order or its popularity with the admission filter.
+// Returns the item and true if found, zero value and false otherwise.
+func (c *Cache[K, V]) Peek(key K) (V, bool) {
+	c.RLock()
+	defer c.RUnlock()
+
+	cached, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return cached.item, true
+}
+
 // GetMultiple retrieves multiple items from the cache.
 // Returns a map of found items.
 func (c *Cache[K, V]) GetMultiple(keys ...K) map[K]V {
 	c.Lock()
 	defer c.Unlock()
 
+	result, _ := c.getMultiple(keys, false)
+	return result
+}
+
+// GetMultipleWithMisses retrieves multiple items from the cache.
+// Returns a map of found items and the keys that were not found, in
+// the order they were requested. This is useful for read-through
+// flows that need to load exactly the missing keys.
+func (c *Cache[K, V]) GetMultipleWithMisses(keys ...K) (map[K]V, []K) {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.getMultiple(keys, true)
+}
+
+// getMultiple looks up the provided keys, collecting misses if
+// requested. Caller must hold the lock.
+func (c *Cache[K, V]) getMultiple(keys []K, collectMisses bool) (map[K]V, []K) {
 	result := make(map[K]V, len(keys))
+	var misses []K
+	defer func() {
+		c.count("cache.hits", int64(len(result)))
+		c.count("cache.misses", int64(len(keys)-len(result)))
+	}()
 	for _, key := range keys {
-		if cached, ok := c.items[key]; ok {
-			if c.policy == LeastRecentlyUsed {
-				c.keyList.MoveToFront(cached.element)
+		c.recordAccess(key)
+		cached, ok := c.items[key]
+		if !ok {
+			if collectMisses {
+				misses = append(misses, key)
 			}
-			result[key] = cached.item
+			continue
+		}
+
+		if c.policy == LeastRecentlyUsed {
+			c.keyList.MoveToFront(cached.element)
 		}
+		c.maybeRefresh(key, cached)
+		result[key] = cached.item
 	}
-	return result
+	return result, misses
 }
 
 // Put adds an item to the cache, evicting items if necessary to make room.
+// If a TinyLFU admission filter is configured, a new key that would
+// require evicting more popular entries is not added.
 func (c *Cache[K, V]) Put(key K, item V) {
 	c.Lock()
 	defer c.Unlock()
 
-	// Remove existing item with this key
-	c.removeUnlocked(key)
-
-	// Ensure capacity
-	c.ensureCapacity(item.Size())
-
-	// Add new item
-	element := c.keyList.PushFront(key)
-	c.items[key] = &cached[V]{
-		item:    item,
-		element: element,
+	c.recordAccess(key)
+	if _, ok := c.items[key]; !ok && !c.admit(key, item.Size()) {
+		c.count("cache.rejections", 1)
+		return
 	}
-	c.size += item.Size()
+	c.putUnlocked(key, item)
+	c.gauge()
 }
 
 // Remove removes items with the given keys from the cache.
@@ -157,6 +253,7 @@ func (c *Cache[K, V]) Remove(keys ...K) {
 	for _, key := range keys {
 		c.removeUnlocked(key)
 	}
+	c.gauge()
 }
 
 // Size returns the current size of all items in the cache.
@@ -181,6 +278,95 @@ func (c *Cache[K, V]) Clear() {
 	c.items = make(map[K]*cached[V])
 	c.keyList = list.New()
 	c.size = 0
+	c.gauge()
+}
+
+// Clone returns a copy of the cache that can be modified independently.
+// The copy has the same capacity, policy, eviction order, admission
+// filter counts, refresh function and metrics, but no refreshes in
+// flight.  Items are copied as they are, so pointers are shared.
+func (c *Cache[K, V]) Clone() *Cache[K, V] {
+	return c.CloneWith(nil)
+}
+
+// CloneWith is like Clone, but copies each item with copyFn.  The size
+// of the copy is that of the copied items, which may exceed capacity
+// until the next Put if copyFn makes them larger.
+func (c *Cache[K, V]) CloneWith(copyFn func(V) V) *Cache[K, V] {
+	c.RLock()
+	defer c.RUnlock()
+
+	clone := &Cache[K, V]{
+		capacity: c.capacity,
+		items:    make(map[K]*cached[V], len(c.items)),
+		keyList:  list.New(),
+		policy:   c.policy,
+		metrics:  c.metrics,
+		now:      c.now,
+	}
+	if c.admission != nil {
+		clone.admission = c.admission.clone()
+	}
+	if c.refresher != nil {
+		clone.refresher = &refresher[K, V]{
+			ttl:      c.refresher.ttl,
+			fn:       c.refresher.fn,
+			inFlight: make(map[K]struct{}),
+		}
+	}
+
+	for element := c.keyList.Back(); element != nil; element = element.Prev() {
+		key := element.Value.(K)
+		entry := c.items[key]
+		item := entry.item
+		if copyFn != nil {
+			item = copyFn(item)
+		}
+		clone.items[key] = &cached[V]{
+			item:    item,
+			element: clone.keyList.PushFront(key),
+			added:   entry.added,
+		}
+		clone.size += item.Size()
+	}
+	return clone
+}
+
+// MemStats estimates the memory held by the cache.  Nodes is the number
+// of entries, each of which is a map slot, an eviction list element
+// and a record of the item; Slots is the capacity of the admission
+// filter's counters, if there is one.  Items are counted at their
+// unsafe.Sizeof size rather than the size they report, which Size
+// sums.  This is O(1).
+func (c *Cache[K, V]) MemStats() common.MemStats {
+	c.RLock()
+	defer c.RUnlock()
+
+	var (
+		key     K
+		entry   cached[V]
+		element list.Element
+		n       = uint64(len(c.items))
+	)
+	// each key is held twice, in the map and boxed in its list element
+	perEntry := uint64(unsafe.Sizeof(entry)+unsafe.Sizeof(element)+unsafe.Sizeof(&entry)) +
+		2*uint64(unsafe.Sizeof(key))
+	stats := common.MemStats{
+		Items: n,
+		Nodes: n,
+		Bytes: uint64(unsafe.Sizeof(*c)+unsafe.Sizeof(*c.keyList)) + n*perEntry,
+	}
+	if c.admission != nil {
+		stats.Bytes += uint64(unsafe.Sizeof(*c.admission) + unsafe.Sizeof(*c.admission.sketch))
+		for _, row := range c.admission.sketch.rows {
+			stats.Slots += uint64(cap(row))
+			stats.Bytes += uint64(cap(row))
+		}
+	}
+	if c.refresher != nil {
+		stats.Bytes += uint64(unsafe.Sizeof(*c.refresher)) + uint64(len(c.refresher.inFlight))*uint64(unsafe.Sizeof(key))
+	}
+	return stats
 }
 
 // Contains returns true if the key exists in the cache.
@@ -203,20 +389,155 @@ func (c *Cache[K, V]) Keys() []K {
 	return keys
 }
 
+// All returns an iterator over the keys and items in the cache, from
+// the next to be evicted to the last.  It iterates a copy taken when
+// iteration starts, so the cache may be modified meanwhile, and
+// iterating counts as no access for eviction.
+func (c *Cache[K, V]) All() iter.Seq2[K, V] {
+	return c.entries(false)
+}
+
+// Backward returns an iterator over the keys and items in the cache,
+// from the last to be evicted to the next.  Like All, it iterates a
+// copy.
+func (c *Cache[K, V]) Backward() iter.Seq2[K, V] {
+	return c.entries(true)
+}
+
+// Values returns an iterator over the items in the cache in the order
+// of All.
+func (c *Cache[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, item := range c.All() {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// entries returns an iterator over a copy of the cache in eviction
+// order or, if backward, its reverse.
+func (c *Cache[K, V]) entries(backward bool) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		c.RLock()
+		keys := make([]K, 0, len(c.items))
+		items := make([]V, 0, len(c.items))
+		element := c.keyList.Back()
+		if backward {
+			element = c.keyList.Front()
+		}
+		for element != nil {
+			key := element.Value.(K)
+			keys = append(keys, key)
+			items = append(items, c.items[key].item)
+			if backward {
+				element = element.Next()
+			} else {
+				element = element.Prev()
+			}
+		}
+		c.RUnlock()
+
+		for i, key := range keys {
+			if !yield(key, items[i]) {
+				return
+			}
+		}
+	}
+}
+
 // ensureCapacity evicts items until there's room for the given size.
 // Caller must hold the lock.
 func (c *Cache[K, V]) ensureCapacity(toAdd uint64) {
+	c.evict(toAdd, nil)
+}
+
+// evict removes items from the back of the eviction order until
+// there's room for the given size, passing over the keep element.
+// Caller must hold the lock.
+func (c *Cache[K, V]) evict(toAdd uint64, keep *list.Element) {
 	mustRemove := int64(c.size+toAdd) - int64(c.capacity)
-	for mustRemove > 0 && c.keyList.Len() > 0 {
-		element := c.keyList.Back()
-		key := element.Value.(K)
-		if cached, ok := c.items[key]; ok {
-			mustRemove -= int64(cached.item.Size())
-			c.removeUnlocked(key)
+	for element := c.keyList.Back(); mustRemove > 0 && element != nil; {
+		prev := element.Prev()
+		if element != keep {
+			key := element.Value.(K)
+			if cached, ok := c.items[key]; ok {
+				mustRemove -= int64(cached.item.Size())
+				c.removeUnlocked(key)
+				c.count("cache.evictions", 1)
+			}
 		}
+		element = prev
+	}
+}
+
+// count adds delta to the named counter, if there are metrics to
+// report to.
+func (c *Cache[K, V]) count(name string, delta int64) {
+	if c.metrics != nil && delta != 0 {
+		c.metrics.Count(name, delta)
+	}
+}
+
+// gauge reports the size and number of items held, if there are
+// metrics to report to.  Caller must hold the lock.
+func (c *Cache[K, V]) gauge() {
+	if c.metrics != nil {
+		c.metrics.Gauge("cache.size", float64(c.size))
+		c.metrics.Gauge("cache.items", float64(len(c.items)))
 	}
 }
 
+// putUnlocked adds an item without acquiring the lock or consulting
+// the admission filter. Caller must hold the lock.
+func (c *Cache[K, V]) putUnlocked(key K, item V) {
+	// Remove existing item with this key
+	c.removeUnlocked(key)
+
+	// Ensure capacity
+	c.ensureCapacity(item.Size())
+
+	// Add new item
+	element := c.keyList.PushFront(key)
+	c.items[key] = &cached[V]{
+		item:    item,
+		element: element,
+		added:   c.now(),
+	}
+	c.size += item.Size()
+}
+
+// recordAccess notes an access of the key with the admission filter,
+// if there is one. Caller must hold the lock.
+func (c *Cache[K, V]) recordAccess(key K) {
+	if c.admission != nil {
+		c.admission.record(key)
+	}
+}
+
+// admit returns a bool indicating if a new key of the given size may
+// be added, evicting whatever is needed to make room. Caller must hold
+// the lock.
+func (c *Cache[K, V]) admit(key K, toAdd uint64) bool {
+	if c.admission == nil {
+		return true
+	}
+
+	mustRemove := int64(c.size+toAdd) - int64(c.capacity)
+	if mustRemove <= 0 {
+		return true
+	}
+
+	var victims []K
+	for element := c.keyList.Back(); mustRemove > 0 && element != nil; element = element.Prev() {
+		victim := element.Value.(K)
+		victims = append(victims, victim)
+		mustRemove -= int64(c.items[victim].item.Size())
+	}
+	return c.admission.admit(key, victims)
+}
+
 // removeUnlocked removes an item without acquiring the lock.
 // Caller must hold the lock.
 func (c *Cache[K, V]) removeUnlocked(key K) {
@@ -260,6 +581,46 @@ func (c *SimpleCache[K, V]) Get(key K) (V, bool) {
 	return wrapper.value, true
 }
 
+// Peek retrieves an item from the cache without affecting eviction order.
+func (c *SimpleCache[K, V]) Peek(key K) (V, bool) {
+	wrapper, ok := c.Cache.Peek(key)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return wrapper.value, true
+}
+
+// GetMultipleWithMisses retrieves multiple items from the cache and
+// returns the found items along with the keys that were not found.
+func (c *SimpleCache[K, V]) GetMultipleWithMisses(keys ...K) (map[K]V, []K) {
+	wrappers, misses := c.Cache.GetMultipleWithMisses(keys...)
+	result := make(map[K]V, len(wrappers))
+	for key, wrapper := range wrappers {
+		result[key] = wrapper.value
+	}
+	return result, misses
+}
+
+// Clone returns a copy of the cache that can be modified independently.
+// See Cache.Clone.
+func (c *SimpleCache[K, V]) Clone() *SimpleCache[K, V] {
+	return &SimpleCache[K, V]{Cache: c.Cache.Clone()}
+}
+
+// CloneWith is like Clone, but copies each value with copyFn.  A nil
+// copyFn makes it equivalent to Clone.
+func (c *SimpleCache[K, V]) CloneWith(copyFn func(V) V) *SimpleCache[K, V] {
+	if copyFn == nil {
+		return c.Clone()
+	}
+	return &SimpleCache[K, V]{
+		Cache: c.Cache.CloneWith(func(w sizedWrapper[V]) sizedWrapper[V] {
+			return sizedWrapper[V]{value: copyFn(w.value)}
+		}),
+	}
+}
+
 // Put adds an item to the cache.
 func (c *SimpleCache[K, V]) Put(key K, value V) {
 	c.Cache.Put(key, sizedWrapper[V]{value: value})
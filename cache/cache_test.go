@@ -17,9 +17,16 @@ limitations under the License.
 package cache
 
 import (
+	"errors"
+	"slices"
+	"sync/atomic"
 	"testing"
+	"time"
+	"unsafe"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/Workiva/go-datastructures/common"
 )
 
 type testItem struct {
@@ -165,6 +172,27 @@ func TestCacheContains(t *testing.T) {
 	assert.False(t, c.Contains("key2"))
 }
 
+func TestCachePeek(t *testing.T) {
+	c := New[string, testItem](50)
+
+	c.Put("key1", testItem{"value1", 25})
+	c.Put("key2", testItem{"value2", 25})
+
+	item, ok := c.Peek("key1")
+	assert.True(t, ok)
+	assert.Equal(t, "value1", item.data)
+
+	_, ok = c.Peek("nonexistent")
+	assert.False(t, ok)
+
+	// Peek must not promote key1, so it is still the eviction candidate
+	c.Put("key3", testItem{"value3", 25})
+
+	assert.False(t, c.Contains("key1"))
+	assert.True(t, c.Contains("key2"))
+	assert.True(t, c.Contains("key3"))
+}
+
 func TestCacheGetMultiple(t *testing.T) {
 	c := New[string, testItem](100)
 
@@ -179,6 +207,34 @@ func TestCacheGetMultiple(t *testing.T) {
 	assert.Equal(t, "value2", result["key2"].data)
 }
 
+func TestCacheGetMultipleWithMisses(t *testing.T) {
+	c := New[string, testItem](100)
+
+	c.Put("key1", testItem{"value1", 10})
+	c.Put("key3", testItem{"value3", 10})
+
+	result, misses := c.GetMultipleWithMisses("key1", "key2", "key3", "key4")
+
+	assert.Len(t, result, 2)
+	assert.Equal(t, "value1", result["key1"].data)
+	assert.Equal(t, "value3", result["key3"].data)
+	assert.Equal(t, []string{"key2", "key4"}, misses)
+
+	_, misses = c.GetMultipleWithMisses("key1", "key3")
+	assert.Empty(t, misses)
+}
+
+func TestSimpleCacheGetMultipleWithMisses(t *testing.T) {
+	c := NewSimple[string, int](3)
+
+	c.Put("a", 1)
+	c.Put("b", 2)
+
+	result, misses := c.GetMultipleWithMisses("a", "b", "c")
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, result)
+	assert.Equal(t, []string{"c"}, misses)
+}
+
 func TestSimpleCache(t *testing.T) {
 	c := NewSimple[string, int](3)
 
@@ -198,4 +254,436 @@ func TestSimpleCache(t *testing.T) {
 	val, ok = c.Get("d")
 	assert.True(t, ok)
 	assert.Equal(t, 4, val)
+
+	val, ok = c.Peek("d")
+	assert.True(t, ok)
+	assert.Equal(t, 4, val)
+}
+
+func TestCacheTinyLFURejectsOneHitWonders(t *testing.T) {
+	c := New[string, testItem](30, WithTinyLFU[string, testItem](64))
+
+	for _, key := range []string{"a", "b", "c"} {
+		c.Put(key, testItem{key, 10})
+	}
+	for range 3 {
+		c.Get("a")
+		c.Get("b")
+		c.Get("c")
+	}
+
+	// a scan of keys that are never seen again
+	for i := range 50 {
+		c.Put(string(rune('A'+i)), testItem{"scan", 10})
+	}
+
+	assert.True(t, c.Contains("a"))
+	assert.True(t, c.Contains("b"))
+	assert.True(t, c.Contains("c"))
+	assert.Equal(t, 3, c.Len())
+}
+
+func TestCacheTinyLFUAdmitsPopularKeys(t *testing.T) {
+	c := New[string, testItem](20, WithTinyLFU[string, testItem](64))
+
+	c.Put("a", testItem{"a", 10})
+	c.Put("b", testItem{"b", 10})
+
+	// d is requested repeatedly while missing, so it should be more
+	// popular than either resident entry by the time it is put.
+	for range 5 {
+		c.Get("d")
+	}
+	c.Put("d", testItem{"d", 10})
+
+	assert.True(t, c.Contains("d"))
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestCacheTinyLFUHasher(t *testing.T) {
+	var hashed []string
+	hasher := common.HashFunc[string](func(key string) uint64 {
+		hashed = append(hashed, key)
+		return common.StringHash()(key)
+	})
+	c := New[string, testItem](20, WithTinyLFUHasher[string, testItem](64, hasher))
+
+	c.Put("a", testItem{"a", 10})
+	c.Put("b", testItem{"b", 10})
+	c.Put("c", testItem{"c", 10})
+	assert.Contains(t, hashed, "c")
+	assert.False(t, c.Contains("c"))
+
+	for range 5 {
+		c.Get("d")
+	}
+	c.Put("d", testItem{"d", 10})
+	assert.True(t, c.Contains("d"))
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestCacheTinyLFUUpdatesExistingKeys(t *testing.T) {
+	c := New[string, testItem](20, WithTinyLFU[string, testItem](64))
+
+	c.Put("a", testItem{"a", 10})
+	c.Put("b", testItem{"b", 10})
+	c.Put("a", testItem{"updated", 10})
+
+	item, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "updated", item.data)
+}
+
+func TestCountMinSketchReset(t *testing.T) {
+	cms := newCountMinSketch(16)
+	for range 8 {
+		cms.increment(42)
+	}
+	assert.Equal(t, uint8(8), cms.estimate(42))
+
+	cms.reset()
+	assert.Equal(t, uint8(4), cms.estimate(42))
+}
+
+func TestCacheRefreshReturnsStaleValue(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	c := New[string, testItem](100, WithRefresh[string, testItem](time.Minute,
+		func(key string) (testItem, error) {
+			atomic.AddInt32(&calls, 1)
+			<-release
+			return testItem{"fresh", 10}, nil
+		},
+	))
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	c.Put("key1", testItem{"stale", 10})
+	item, _ := c.Get("key1")
+	assert.Equal(t, "stale", item.data)
+
+	now = now.Add(2 * time.Minute)
+	for range 3 {
+		item, ok := c.Get("key1")
+		assert.True(t, ok)
+		assert.Equal(t, "stale", item.data)
+	}
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		item, _ := c.Peek("key1")
+		return item.data == "fresh"
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestCacheRefreshErrorKeepsStaleValue(t *testing.T) {
+	var calls int32
+	c := New[string, testItem](100, WithRefresh[string, testItem](time.Minute,
+		func(key string) (testItem, error) {
+			atomic.AddInt32(&calls, 1)
+			return testItem{}, errors.New("unavailable")
+		},
+	))
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	c.Put("key1", testItem{"stale", 10})
+	now = now.Add(2 * time.Minute)
+	c.Get("key1")
+
+	assert.Eventually(t, func() bool {
+		c.Lock()
+		defer c.Unlock()
+		return len(c.refresher.inFlight) == 0
+	}, time.Second, time.Millisecond)
+
+	item, ok := c.Peek("key1")
+	assert.True(t, ok)
+	assert.Equal(t, "stale", item.data)
+
+	c.Get("key1")
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&calls) == 2
+	}, time.Second, time.Millisecond)
+}
+
+func TestCacheRefreshDropsRemovedKeys(t *testing.T) {
+	release := make(chan struct{})
+	c := New[string, testItem](100, WithRefresh[string, testItem](time.Minute,
+		func(key string) (testItem, error) {
+			<-release
+			return testItem{"fresh", 10}, nil
+		},
+	))
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	c.Put("key1", testItem{"stale", 10})
+	now = now.Add(2 * time.Minute)
+	c.Get("key1")
+	c.Remove("key1")
+	close(release)
+
+	assert.Eventually(t, func() bool {
+		c.Lock()
+		defer c.Unlock()
+		return len(c.refresher.inFlight) == 0
+	}, time.Second, time.Millisecond)
+	assert.False(t, c.Contains("key1"))
+}
+
+func TestCacheRefreshKeepsNewerPut(t *testing.T) {
+	release := make(chan struct{})
+	c := New[string, testItem](100, WithRefresh[string, testItem](time.Minute,
+		func(key string) (testItem, error) {
+			<-release
+			return testItem{"fetched", 10}, nil
+		},
+	))
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	c.Put("key1", testItem{"stale", 10})
+	now = now.Add(2 * time.Minute)
+	c.Get("key1")
+	c.Put("key1", testItem{"put", 10})
+	close(release)
+
+	assert.Eventually(t, func() bool {
+		c.Lock()
+		defer c.Unlock()
+		return len(c.refresher.inFlight) == 0
+	}, time.Second, time.Millisecond)
+	item, ok := c.Peek("key1")
+	assert.True(t, ok)
+	assert.Equal(t, "put", item.data)
+}
+
+func TestCacheRefreshKeepsInsertionOrder(t *testing.T) {
+	release := make(chan struct{})
+	c := New[string, testItem](20, WithPolicy[string, testItem](LeastRecentlyAdded),
+		WithRefresh[string, testItem](time.Minute,
+			func(key string) (testItem, error) {
+				<-release
+				return testItem{"fresh", 10}, nil
+			},
+		))
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	c.Put("key1", testItem{"stale", 10})
+	c.Put("key2", testItem{"stale", 10})
+	now = now.Add(2 * time.Minute)
+	c.Get("key1")
+	close(release)
+
+	assert.Eventually(t, func() bool {
+		item, _ := c.Peek("key1")
+		return item.data == "fresh"
+	}, time.Second, time.Millisecond)
+
+	// key1 was still added first, so it is evicted first
+	c.Put("key3", testItem{"new", 10})
+	assert.False(t, c.Contains("key1"))
+	assert.True(t, c.Contains("key2"))
+	assert.Equal(t, uint64(20), c.Size())
+}
+
+func TestCacheRefreshGrowingFullCache(t *testing.T) {
+	release := make(chan struct{})
+	c := New[string, testItem](30, WithPolicy[string, testItem](LeastRecentlyAdded),
+		WithRefresh[string, testItem](time.Minute,
+			func(key string) (testItem, error) {
+				<-release
+				return testItem{"fresh", 15}, nil
+			},
+		))
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	c.Put("key1", testItem{"stale", 10})
+	c.Put("key2", testItem{"stale", 10})
+	c.Put("key3", testItem{"stale", 10})
+	now = now.Add(2 * time.Minute)
+	c.Get("key1")
+	close(release)
+
+	// key1 is at the back, but the refreshed value is kept and the
+	// entries after it are evicted to make room
+	assert.Eventually(t, func() bool {
+		item, _ := c.Peek("key1")
+		return item.data == "fresh"
+	}, time.Second, time.Millisecond)
+	assert.False(t, c.Contains("key2"))
+	assert.True(t, c.Contains("key3"))
+	assert.Equal(t, uint64(25), c.Size())
+}
+
+func TestCacheAll(t *testing.T) {
+	c := New[string, testItem](100)
+	c.Put("a", testItem{"1", 1})
+	c.Put("b", testItem{"2", 1})
+	c.Put("c", testItem{"3", 1})
+	c.Get("a")
+
+	var keys []string
+	for key, item := range c.All() {
+		keys = append(keys, key)
+		assert.Equal(t, c.items[key].item, item)
+		// the cache may be modified during iteration
+		c.Remove(key)
+	}
+	assert.Equal(t, []string{"b", "c", "a"}, keys)
+	assert.Zero(t, c.Len())
+
+	c.Put("a", testItem{"1", 1})
+	c.Put("b", testItem{"2", 1})
+	keys = keys[:0]
+	for key := range c.Backward() {
+		keys = append(keys, key)
+	}
+	assert.Equal(t, []string{"b", "a"}, keys)
+
+	var values []string
+	for item := range c.Values() {
+		values = append(values, item.data)
+	}
+	assert.Equal(t, []string{"1", "2"}, values)
+}
+
+func TestCacheMetrics(t *testing.T) {
+	counts := map[string]int64{}
+	gauges := map[string]float64{}
+	m := common.MetricsFuncs{
+		OnCount: func(name string, delta int64) { counts[name] += delta },
+		OnGauge: func(name string, value float64) { gauges[name] = value },
+	}
+	c := New[string, testItem](20, WithMetrics[string, testItem](m))
+
+	c.Put("a", testItem{"a", 10})
+	c.Put("b", testItem{"b", 5})
+	assert.Equal(t, float64(15), gauges["cache.size"])
+	assert.Equal(t, float64(2), gauges["cache.items"])
+
+	c.Get("a")
+	c.Get("missing")
+	c.GetMultiple("a", "b", "missing")
+	assert.Equal(t, int64(3), counts["cache.hits"])
+	assert.Equal(t, int64(2), counts["cache.misses"])
+
+	c.Put("c", testItem{"c", 10})
+	assert.Equal(t, int64(1), counts["cache.evictions"])
+	assert.Equal(t, float64(15), gauges["cache.size"])
+	assert.Equal(t, float64(2), gauges["cache.items"])
+
+	c.Remove("b")
+	assert.Equal(t, float64(10), gauges["cache.size"])
+	c.Clear()
+	assert.Equal(t, float64(0), gauges["cache.items"])
+}
+
+func TestCacheMetricsRejections(t *testing.T) {
+	counts := map[string]int64{}
+	m := common.MetricsFuncs{
+		OnCount: func(name string, delta int64) { counts[name] += delta },
+	}
+	c := New[string, testItem](20,
+		WithTinyLFU[string, testItem](64),
+		WithMetrics[string, testItem](m),
+	)
+
+	c.Put("a", testItem{"a", 10})
+	c.Put("b", testItem{"b", 10})
+	for range 3 {
+		c.Get("a")
+		c.Get("b")
+	}
+	c.Put("c", testItem{"c", 10})
+
+	assert.False(t, c.Contains("c"))
+	assert.Equal(t, int64(1), counts["cache.rejections"])
+	assert.Zero(t, counts["cache.evictions"])
+}
+
+func TestCacheMemStats(t *testing.T) {
+	c := New[string, testItem](100)
+	empty := c.MemStats()
+	assert.Equal(t, common.MemStats{Bytes: empty.Bytes}, empty)
+
+	c.Put("a", testItem{"a", 10})
+	c.Put("b", testItem{"b", 10})
+	stats := c.MemStats()
+	assert.Equal(t, uint64(2), stats.Items)
+	assert.Equal(t, uint64(2), stats.Nodes)
+	assert.Greater(t, stats.Bytes, empty.Bytes+2*uint64(unsafe.Sizeof(testItem{})))
+
+	filtered := New[string, testItem](100, WithTinyLFU[string, testItem](64))
+	assert.Equal(t, uint64(4*64), filtered.MemStats().Slots)
+}
+
+func TestCacheClone(t *testing.T) {
+	c := New[string, testItem](30, WithPolicy[string, testItem](LeastRecentlyUsed))
+	c.Put("a", testItem{"a", 10})
+	c.Put("b", testItem{"b", 10})
+	c.Put("c", testItem{"c", 10})
+	c.Get("a")
+
+	clone := c.Clone()
+	assert.Equal(t, c.Size(), clone.Size())
+	var keys, cloneKeys []string
+	for key := range c.All() {
+		keys = append(keys, key)
+	}
+	for key := range clone.All() {
+		cloneKeys = append(cloneKeys, key)
+	}
+	assert.Equal(t, []string{"b", "c", "a"}, keys)
+	assert.Equal(t, keys, cloneKeys)
+
+	// b is next to be evicted in both
+	clone.Put("d", testItem{"d", 10})
+	assert.False(t, clone.Contains("b"))
+	assert.True(t, c.Contains("b"))
+	assert.False(t, c.Contains("d"))
+
+	clone.Remove("a")
+	item, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, "a", item.data)
+}
+
+func TestCacheCloneWith(t *testing.T) {
+	c := New[string, testItem](30)
+	c.Put("a", testItem{"a", 10})
+
+	clone := c.CloneWith(func(item testItem) testItem {
+		item.data += "'"
+		item.size *= 2
+		return item
+	})
+	item, _ := clone.Get("a")
+	assert.Equal(t, "a'", item.data)
+	assert.Equal(t, uint64(20), clone.Size())
+	item, _ = c.Get("a")
+	assert.Equal(t, "a", item.data)
+}
+
+func TestSimpleCacheClone(t *testing.T) {
+	c := NewSimple[string, []int](2)
+	c.Put("a", []int{1})
+
+	clone := c.CloneWith(slices.Clone[[]int])
+	value, _ := clone.Get("a")
+	value[0] = 2
+	value, _ = c.Get("a")
+	assert.Equal(t, []int{1}, value)
+
+	c.Clone().Put("b", []int{3})
+	assert.False(t, c.Contains("b"))
+
+	clone = c.CloneWith(nil)
+	value, ok := clone.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, []int{1}, value)
 }
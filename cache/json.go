@@ -0,0 +1,61 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"errors"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+var errUnmarshalUninitialized = errors.New(`cache: cannot unmarshal JSON into a Cache not created with New`)
+
+// MarshalJSON encodes the cache as a JSON object of its keys and items,
+// from the next to be evicted to the last.
+func (c *Cache[K, V]) MarshalJSON() ([]byte, error) {
+	return common.MarshalJSONObject(c.All(), common.DefaultJSONCodec[V]())
+}
+
+// UnmarshalJSON decodes a JSON object, putting its keys and items in
+// the cache in order, so that the cache marshaled by MarshalJSON is
+// restored with its eviction order.  Items bypass any admission
+// filter, but may still evict others to make room.  The cache must
+// have been created with New.
+func (c *Cache[K, V]) UnmarshalJSON(data []byte) error {
+	type entry struct {
+		key  K
+		item V
+	}
+	var entries []entry
+	err := common.UnmarshalJSONObject(data, common.DefaultJSONCodec[V](), func(key K, item V) {
+		entries = append(entries, entry{key, item})
+	})
+	if err != nil {
+		return err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	if c.items == nil {
+		return errUnmarshalUninitialized
+	}
+	for _, e := range entries {
+		c.putUnlocked(e.key, e.item)
+	}
+	c.gauge()
+	return nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type jsonItem struct {
+	Data string
+	Cost uint64
+}
+
+func (j jsonItem) Size() uint64 {
+	return j.Cost
+}
+
+func TestCacheJSON(t *testing.T) {
+	c := New[string, jsonItem](100)
+	c.Put(`a`, jsonItem{`1`, 10})
+	c.Put(`b`, jsonItem{`2`, 10})
+	c.Put(`c`, jsonItem{`3`, 10})
+	c.Get(`a`)
+
+	b, err := json.Marshal(c)
+	require.NoError(t, err)
+	assert.Equal(t, `{"b":{"Data":"2","Cost":10},"c":{"Data":"3","Cost":10},"a":{"Data":"1","Cost":10}}`, string(b))
+
+	// the eviction order is restored
+	decoded := New[string, jsonItem](25)
+	require.NoError(t, json.Unmarshal(b, decoded))
+	assert.Equal(t, 2, decoded.Len())
+	assert.False(t, decoded.Contains(`b`))
+	item, ok := decoded.Get(`a`)
+	assert.True(t, ok)
+	assert.Equal(t, jsonItem{`1`, 10}, item)
+
+	assert.Error(t, json.Unmarshal(b, &Cache[string, jsonItem]{}))
+}
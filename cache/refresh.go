@@ -0,0 +1,97 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import "time"
+
+// RefreshFunc loads a fresh value for the provided key. It is called
+// from a background goroutine, never while the cache lock is held.
+type RefreshFunc[K comparable, V Sized] func(key K) (V, error)
+
+// refresher tracks the stale-while-revalidate configuration and the
+// keys that currently have a refresh in flight.
+type refresher[K comparable, V Sized] struct {
+	ttl      time.Duration
+	fn       RefreshFunc[K, V]
+	inFlight map[K]struct{}
+}
+
+// WithRefresh enables stale-while-revalidate behavior. Once an entry is
+// older than the soft ttl, Get keeps returning the cached value
+// immediately but also starts a background call to fn to replace it.
+// At most one refresh per key is in flight at a time. If fn returns an
+// error the stale value is kept and the next Get will try again. A
+// refreshed value is discarded if the key was removed, evicted or Put
+// while the refresh was running. Replacing a value by refreshing it
+// does not count as a use of the key, so it does not change the order
+// in which entries are evicted, but a larger refreshed value makes room
+// by evicting other entries, and is only evicted itself if it does not
+// fit alone.
+func WithRefresh[K comparable, V Sized](ttl time.Duration, fn RefreshFunc[K, V]) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.refresher = &refresher[K, V]{
+			ttl:      ttl,
+			fn:       fn,
+			inFlight: make(map[K]struct{}),
+		}
+	}
+}
+
+// maybeRefresh starts a background refresh of the provided entry if it
+// has outlived the soft ttl. Caller must hold the lock.
+func (c *Cache[K, V]) maybeRefresh(key K, entry *cached[V]) {
+	r := c.refresher
+	if r == nil || c.now().Sub(entry.added) < r.ttl {
+		return
+	}
+
+	if _, ok := r.inFlight[key]; ok {
+		return
+	}
+
+	r.inFlight[key] = struct{}{}
+	go c.refresh(key, entry)
+}
+
+// refresh replaces the value of the stale entry with a fresh one, as
+// long as the entry is still the one cached for the key.
+func (c *Cache[K, V]) refresh(key K, stale *cached[V]) {
+	item, err := c.refresher.fn(key)
+
+	c.Lock()
+	defer c.Unlock()
+
+	delete(c.refresher.inFlight, key)
+	if err != nil {
+		return
+	}
+
+	if c.items[key] != stale {
+		return
+	}
+	c.size -= stale.item.Size()
+	stale.item, stale.added = item, c.now()
+	c.size += item.Size()
+	// make room by evicting other entries, as the refreshed one is
+	// likely the oldest; only if it alone is too large does it go
+	c.evict(0, stale.element)
+	if c.size > c.capacity {
+		c.removeUnlocked(key)
+		c.count("cache.evictions", 1)
+	}
+	c.gauge()
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import "github.com/Workiva/go-datastructures/common"
+
+// sketchDepth is the number of rows, and therefore independent hash
+// functions, in the count-min sketch.
+const sketchDepth = 4
+
+// defaultSketchWidth is used when WithTinyLFU is given a zero width.
+const defaultSketchWidth = 1024
+
+// maxCount is the saturation value of a single sketch counter.
+const maxCount = 15
+
+// countMinSketch is an approximate frequency counter. Estimates never
+// undercount, but may overcount when keys collide. Counters are halved
+// once the number of recorded accesses reaches resetAt, which keeps the
+// sketch biased towards recent history.
+type countMinSketch struct {
+	rows      [sketchDepth][]uint8
+	mask      uint64
+	additions uint64
+	resetAt   uint64
+}
+
+func newCountMinSketch(width uint64) *countMinSketch {
+	if width == 0 {
+		width = defaultSketchWidth
+	}
+	width = roundUp(width)
+
+	cms := &countMinSketch{
+		mask:    width - 1,
+		resetAt: width * 10,
+	}
+	for i := range cms.rows {
+		cms.rows[i] = make([]uint8, width)
+	}
+	return cms
+}
+
+// index returns the counter position of the hash in the given row.
+// Double hashing is used to derive sketchDepth indices from one hash.
+func (cms *countMinSketch) index(h uint64, row int) uint64 {
+	h1, h2 := h, (h>>32)|1
+	return (h1 + uint64(row)*h2) & cms.mask
+}
+
+// increment records an access of the provided hash.
+func (cms *countMinSketch) increment(h uint64) {
+	for i := range cms.rows {
+		idx := cms.index(h, i)
+		if cms.rows[i][idx] < maxCount {
+			cms.rows[i][idx]++
+		}
+	}
+
+	cms.additions++
+	if cms.additions >= cms.resetAt {
+		cms.reset()
+	}
+}
+
+// estimate returns the approximate number of accesses of the provided hash.
+func (cms *countMinSketch) estimate(h uint64) uint8 {
+	lowest := uint8(maxCount)
+	for i := range cms.rows {
+		if c := cms.rows[i][cms.index(h, i)]; c < lowest {
+			lowest = c
+		}
+	}
+	return lowest
+}
+
+// reset halves every counter so that stale popularity decays.
+func (cms *countMinSketch) reset() {
+	for i := range cms.rows {
+		for j := range cms.rows[i] {
+			cms.rows[i][j] >>= 1
+		}
+	}
+	cms.additions /= 2
+}
+
+// tinyLFU is an admission filter. A new key is only allowed to displace
+// existing entries if it has been seen more often than all of them.
+type tinyLFU[K comparable] struct {
+	hasher common.Hasher[K]
+	sketch *countMinSketch
+}
+
+func newTinyLFU[K comparable](width uint64, hasher common.Hasher[K]) *tinyLFU[K] {
+	return &tinyLFU[K]{
+		hasher: hasher,
+		sketch: newCountMinSketch(width),
+	}
+}
+
+// clone returns a copy of the filter that counts independently.
+func (t *tinyLFU[K]) clone() *tinyLFU[K] {
+	sketch := *t.sketch
+	for i := range sketch.rows {
+		sketch.rows[i] = append([]uint8(nil), t.sketch.rows[i]...)
+	}
+	return &tinyLFU[K]{hasher: t.hasher, sketch: &sketch}
+}
+
+// hash returns the hash of the key, mixed in case the hasher leaves
+// the bits the sketch indexes by poorly distributed.
+func (t *tinyLFU[K]) hash(key K) uint64 {
+	return common.Mix64(t.hasher.Hash(key))
+}
+
+// record notes an access of the provided key.
+func (t *tinyLFU[K]) record(key K) {
+	t.sketch.increment(t.hash(key))
+}
+
+// admit returns a bool indicating if the candidate is more popular
+// than every one of the provided victims.
+func (t *tinyLFU[K]) admit(candidate K, victims []K) bool {
+	freq := t.sketch.estimate(t.hash(candidate))
+	for _, victim := range victims {
+		if t.sketch.estimate(t.hash(victim)) >= freq {
+			return false
+		}
+	}
+	return true
+}
+
+// roundUp takes a uint64 greater than 0 and rounds it up to the next
+// power of 2.
+func roundUp(v uint64) uint64 {
+	v--
+	v |= v >> 1
+	v |= v >> 2
+	v |= v >> 4
+	v |= v >> 8
+	v |= v >> 16
+	v |= v >> 32
+	v++
+	return v
+}
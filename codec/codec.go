@@ -0,0 +1,408 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package codec is the binary encoding shared by the containers in this
+module.  A container's MarshalBinary writes a version byte, the number
+of elements it holds as a uvarint and then each element in its
+iteration order, each encoded by the Codec that For returns for the
+element type.  UnmarshalBinary reads the same layout back, so every
+container persists the same way and an encoding of one ordered
+container can be loaded into another.
+
+For picks an encoding from the element type:
+
+  - fixed-size values, such as integers, floats, bools and structs or
+    arrays of them, are written little endian as encoding/binary
+    writes them
+  - int, uint and uintptr are written as varints
+  - strings and byte slices are written as a uvarint length followed
+    by their bytes
+  - types that implement encoding.BinaryMarshaler, and whose pointers
+    implement encoding.BinaryUnmarshaler, are written as the length
+    prefixed output of MarshalBinary
+
+Other element types can be encoded with a Codec of their own passed to
+MarshalSeq and UnmarshalSeq along with the container's iterator.
+*/
+package codec
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"iter"
+	"reflect"
+)
+
+// version is the first byte of every encoding written by MarshalSeq
+// and MarshalSeq2 so that the format can change without misreading
+// older data.
+const version = 1
+
+var (
+	// ErrTruncated is returned when data ends partway through a value.
+	ErrTruncated = errors.New(`codec: data is truncated`)
+	// ErrCorrupt is returned when data was not produced by the codec
+	// decoding it.
+	ErrCorrupt = errors.New(`codec: data is corrupt`)
+	// ErrUnsupported is returned by For for types it has no encoding
+	// for.
+	ErrUnsupported = errors.New(`codec: values cannot be encoded`)
+)
+
+// Encoder encodes values of type T.
+type Encoder[T any] interface {
+	// AppendBinary appends the encoding of value to data.
+	AppendBinary(data []byte, value T) ([]byte, error)
+}
+
+// Decoder decodes values of type T.
+type Decoder[T any] interface {
+	// DecodeBinary decodes a value from the front of data, returning
+	// it and the number of bytes it used.
+	DecodeBinary(data []byte) (T, int, error)
+}
+
+// Codec both encodes and decodes values of type T.
+type Codec[T any] interface {
+	Encoder[T]
+	Decoder[T]
+}
+
+// Funcs is a Codec built from a pair of functions.
+type Funcs[T any] struct {
+	// Append appends the encoding of a value to data.
+	Append func(data []byte, value T) ([]byte, error)
+	// Decode decodes a value from the front of data, returning it and
+	// the number of bytes it used.
+	Decode func(data []byte) (T, int, error)
+}
+
+// AppendBinary calls f.Append.
+func (f Funcs[T]) AppendBinary(data []byte, value T) ([]byte, error) {
+	return f.Append(data, value)
+}
+
+// DecodeBinary calls f.Decode.
+func (f Funcs[T]) DecodeBinary(data []byte) (T, int, error) {
+	return f.Decode(data)
+}
+
+// Uvarint decodes a uvarint from the front of data, returning it and
+// the number of bytes it used.
+func Uvarint(data []byte) (uint64, int, error) {
+	value, n := binary.Uvarint(data)
+	switch {
+	case n == 0:
+		return 0, 0, ErrTruncated
+	case n < 0:
+		return 0, 0, ErrCorrupt
+	}
+	return value, n, nil
+}
+
+// Varint decodes a varint from the front of data, returning it and the
+// number of bytes it used.
+func Varint(data []byte) (int64, int, error) {
+	value, n := binary.Varint(data)
+	switch {
+	case n == 0:
+		return 0, 0, ErrTruncated
+	case n < 0:
+		return 0, 0, ErrCorrupt
+	}
+	return value, n, nil
+}
+
+// AppendBytes appends b to data prefixed with its length as a uvarint.
+func AppendBytes(data, b []byte) []byte {
+	data = binary.AppendUvarint(data, uint64(len(b)))
+	return append(data, b...)
+}
+
+// Bytes decodes a length prefixed byte slice written by AppendBytes
+// from the front of data, returning it and the number of bytes used.
+// The returned slice shares memory with data.
+func Bytes(data []byte) ([]byte, int, error) {
+	length, n, err := Uvarint(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	if length > uint64(len(data)-n) {
+		return nil, 0, ErrTruncated
+	}
+	end := n + int(length)
+	return data[n:end:end], end, nil
+}
+
+// For returns the Codec used for elements of type T by the containers'
+// MarshalBinary and UnmarshalBinary methods, or ErrUnsupported if T has
+// no encoding.  See the package documentation for how each type is
+// encoded.
+func For[T any]() (Codec[T], error) {
+	var zero T
+	if size := binary.Size(zero); size > 0 {
+		return fixed[T]{size: size}, nil
+	}
+	if _, ok := any(zero).(encoding.BinaryMarshaler); ok {
+		if _, ok := any(&zero).(encoding.BinaryUnmarshaler); ok {
+			return marshaler[T]{}, nil
+		}
+	}
+
+	typ := reflect.TypeFor[T]()
+	switch typ.Kind() {
+	case reflect.Int:
+		return varint[T]{}, nil
+	case reflect.Uint, reflect.Uintptr:
+		return uvarint[T]{}, nil
+	case reflect.String:
+		return str[T]{}, nil
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return byteSlice[T]{}, nil
+		}
+	}
+	return nil, ErrUnsupported
+}
+
+type fixed[T any] struct {
+	size int
+}
+
+func (c fixed[T]) AppendBinary(data []byte, value T) ([]byte, error) {
+	return binary.Append(data, binary.LittleEndian, value)
+}
+
+func (c fixed[T]) DecodeBinary(data []byte) (T, int, error) {
+	var value T
+	if len(data) < c.size {
+		return value, 0, ErrTruncated
+	}
+	if _, err := binary.Decode(data, binary.LittleEndian, &value); err != nil {
+		return value, 0, ErrCorrupt
+	}
+	return value, c.size, nil
+}
+
+type marshaler[T any] struct{}
+
+func (marshaler[T]) AppendBinary(data []byte, value T) ([]byte, error) {
+	encoded, err := any(value).(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return AppendBytes(data, encoded), nil
+}
+
+func (marshaler[T]) DecodeBinary(data []byte) (T, int, error) {
+	var value T
+	encoded, n, err := Bytes(data)
+	if err != nil {
+		return value, 0, err
+	}
+	if err := any(&value).(encoding.BinaryUnmarshaler).UnmarshalBinary(encoded); err != nil {
+		return value, 0, err
+	}
+	return value, n, nil
+}
+
+type varint[T any] struct{}
+
+func (varint[T]) AppendBinary(data []byte, value T) ([]byte, error) {
+	return binary.AppendVarint(data, reflect.ValueOf(value).Int()), nil
+}
+
+func (varint[T]) DecodeBinary(data []byte) (T, int, error) {
+	var value T
+	v, n, err := Varint(data)
+	if err != nil {
+		return value, 0, err
+	}
+	reflect.ValueOf(&value).Elem().SetInt(v)
+	return value, n, nil
+}
+
+type uvarint[T any] struct{}
+
+func (uvarint[T]) AppendBinary(data []byte, value T) ([]byte, error) {
+	return binary.AppendUvarint(data, reflect.ValueOf(value).Uint()), nil
+}
+
+func (uvarint[T]) DecodeBinary(data []byte) (T, int, error) {
+	var value T
+	v, n, err := Uvarint(data)
+	if err != nil {
+		return value, 0, err
+	}
+	reflect.ValueOf(&value).Elem().SetUint(v)
+	return value, n, nil
+}
+
+type str[T any] struct{}
+
+func (str[T]) AppendBinary(data []byte, value T) ([]byte, error) {
+	s := reflect.ValueOf(value).String()
+	data = binary.AppendUvarint(data, uint64(len(s)))
+	return append(data, s...), nil
+}
+
+func (str[T]) DecodeBinary(data []byte) (T, int, error) {
+	var value T
+	b, n, err := Bytes(data)
+	if err != nil {
+		return value, 0, err
+	}
+	reflect.ValueOf(&value).Elem().SetString(string(b))
+	return value, n, nil
+}
+
+type byteSlice[T any] struct{}
+
+func (byteSlice[T]) AppendBinary(data []byte, value T) ([]byte, error) {
+	return AppendBytes(data, reflect.ValueOf(value).Bytes()), nil
+}
+
+func (byteSlice[T]) DecodeBinary(data []byte) (T, int, error) {
+	var value T
+	b, n, err := Bytes(data)
+	if err != nil {
+		return value, 0, err
+	}
+	reflect.ValueOf(&value).Elem().SetBytes(append([]byte(nil), b...))
+	return value, n, nil
+}
+
+// MarshalSeq encodes the items, in order, as a version byte, their
+// number as a uvarint and then each item.
+func MarshalSeq[T any](items iter.Seq[T], enc Encoder[T]) ([]byte, error) {
+	var (
+		body  []byte
+		count uint64
+		err   error
+	)
+	for item := range items {
+		if body, err = enc.AppendBinary(body, item); err != nil {
+			return nil, err
+		}
+		count++
+	}
+	return appendHeader(body, count), nil
+}
+
+// UnmarshalSeq decodes data written by MarshalSeq, calling add with
+// each item in order.  add is only called once all of the items have
+// been decoded, so a container is left unchanged by invalid data.
+func UnmarshalSeq[T any](data []byte, dec Decoder[T], add func(T)) error {
+	count, data, err := readHeader(data)
+	if err != nil {
+		return err
+	}
+	items := make([]T, 0, min(count, uint64(len(data))))
+	for range count {
+		item, n, err := dec.DecodeBinary(data)
+		if err != nil {
+			return err
+		}
+		items = append(items, item)
+		data = data[n:]
+	}
+	if len(data) != 0 {
+		return ErrCorrupt
+	}
+	for _, item := range items {
+		add(item)
+	}
+	return nil
+}
+
+// MarshalSeq2 encodes the pairs, in order, as a version byte, their
+// number as a uvarint and then each key followed by its value.
+func MarshalSeq2[K, V any](pairs iter.Seq2[K, V], keys Encoder[K], values Encoder[V]) ([]byte, error) {
+	var (
+		body  []byte
+		count uint64
+		err   error
+	)
+	for key, value := range pairs {
+		if body, err = keys.AppendBinary(body, key); err != nil {
+			return nil, err
+		}
+		if body, err = values.AppendBinary(body, value); err != nil {
+			return nil, err
+		}
+		count++
+	}
+	return appendHeader(body, count), nil
+}
+
+// UnmarshalSeq2 decodes data written by MarshalSeq2, calling put with
+// each pair in order once all of them have been decoded.
+func UnmarshalSeq2[K, V any](data []byte, keys Decoder[K], values Decoder[V], put func(K, V)) error {
+	type pair struct {
+		key   K
+		value V
+	}
+
+	count, data, err := readHeader(data)
+	if err != nil {
+		return err
+	}
+	pairs := make([]pair, 0, min(count, uint64(len(data))))
+	for range count {
+		key, n, err := keys.DecodeBinary(data)
+		if err != nil {
+			return err
+		}
+		value, m, err := values.DecodeBinary(data[n:])
+		if err != nil {
+			return err
+		}
+		pairs = append(pairs, pair{key, value})
+		data = data[n+m:]
+	}
+	if len(data) != 0 {
+		return ErrCorrupt
+	}
+	for _, p := range pairs {
+		put(p.key, p.value)
+	}
+	return nil
+}
+
+func appendHeader(body []byte, count uint64) []byte {
+	data := make([]byte, 0, len(body)+1+binary.MaxVarintLen64)
+	data = append(data, version)
+	data = binary.AppendUvarint(data, count)
+	return append(data, body...)
+}
+
+// readHeader returns the number of items data holds and the data that
+// follows the header.
+func readHeader(data []byte) (uint64, []byte, error) {
+	if len(data) == 0 {
+		return 0, nil, ErrTruncated
+	}
+	if data[0] != version {
+		return 0, nil, ErrCorrupt
+	}
+	count, n, err := Uvarint(data[1:])
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, data[1+n:], nil
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package codec
+
+import (
+	"maps"
+	"math"
+	"net/netip"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func roundTrip[T any](t *testing.T, values ...T) {
+	t.Helper()
+	c, err := For[T]()
+	require.NoError(t, err)
+
+	var data []byte
+	for _, value := range values {
+		data, err = c.AppendBinary(data, value)
+		require.NoError(t, err)
+	}
+	for _, expected := range values {
+		value, n, err := c.DecodeBinary(data)
+		require.NoError(t, err)
+		assert.Equal(t, expected, value)
+		data = data[n:]
+	}
+	assert.Empty(t, data)
+}
+
+func TestFor(t *testing.T) {
+	type point struct {
+		X, Y int32
+	}
+	type name string
+	type id int
+
+	roundTrip(t, int64(math.MinInt64), 0, math.MaxInt64)
+	roundTrip(t, uint8(0), math.MaxUint8)
+	roundTrip(t, 1.5, math.Inf(-1))
+	roundTrip(t, true, false)
+	roundTrip(t, point{1, -2}, point{})
+	roundTrip(t, [2]uint16{1, 2})
+	roundTrip(t, -1, math.MaxInt, math.MinInt)
+	roundTrip(t, id(-5), id(7))
+	roundTrip(t, uint(math.MaxUint), 0)
+	roundTrip(t, "", "hello", "wörld")
+	roundTrip(t, name("x"))
+	roundTrip(t, []byte(nil), []byte{1, 2, 3})
+	roundTrip(t, netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("::1"))
+
+	_, err := For[*int]()
+	assert.Equal(t, ErrUnsupported, err)
+	_, err = For[[]int]()
+	assert.Equal(t, ErrUnsupported, err)
+	_, err = For[map[string]int]()
+	assert.Equal(t, ErrUnsupported, err)
+}
+
+func TestDecodeTruncated(t *testing.T) {
+	strings, err := For[string]()
+	require.NoError(t, err)
+	data, err := strings.AppendBinary(nil, "hello")
+	require.NoError(t, err)
+	for i := range data {
+		_, _, err := strings.DecodeBinary(data[:i])
+		assert.Equal(t, ErrTruncated, err, "truncated at %d", i)
+	}
+
+	ints, err := For[uint32]()
+	require.NoError(t, err)
+	_, _, err = ints.DecodeBinary([]byte{1, 2, 3})
+	assert.Equal(t, ErrTruncated, err)
+
+	_, _, err = Uvarint([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01})
+	assert.Equal(t, ErrCorrupt, err)
+	_, _, err = Varint([]byte{0x80})
+	assert.Equal(t, ErrTruncated, err)
+
+	addrs, err := For[netip.Addr]()
+	require.NoError(t, err)
+	_, _, err = addrs.DecodeBinary([]byte{3, 1, 2, 3})
+	assert.Error(t, err)
+}
+
+func TestBytesDoesNotAlias(t *testing.T) {
+	c, err := For[[]byte]()
+	require.NoError(t, err)
+	data := AppendBytes(nil, []byte{1, 2})
+	value, _, err := c.DecodeBinary(data)
+	require.NoError(t, err)
+	data[1] = 9
+	assert.Equal(t, []byte{1, 2}, value)
+}
+
+func TestSeq(t *testing.T) {
+	c, err := For[string]()
+	require.NoError(t, err)
+	items := []string{"a", "bc", "", "def"}
+	data, err := MarshalSeq(slices.Values(items), c)
+	require.NoError(t, err)
+	assert.Equal(t, byte(version), data[0])
+
+	var decoded []string
+	require.NoError(t, UnmarshalSeq(data, c, func(item string) {
+		decoded = append(decoded, item)
+	}))
+	assert.Equal(t, items, decoded)
+
+	add := func(string) { t.Fatal("add called for invalid data") }
+	for i := range data {
+		assert.Error(t, UnmarshalSeq(data[:i], c, add), "truncated at %d", i)
+	}
+	assert.Equal(t, ErrCorrupt, UnmarshalSeq(append(data, 0), c, add))
+	corrupt := slices.Clone(data)
+	corrupt[0] = version + 1
+	assert.Equal(t, ErrCorrupt, UnmarshalSeq(corrupt, c, add))
+
+	// a huge count must fail on the data rather than be allocated
+	assert.Equal(t, ErrTruncated, UnmarshalSeq([]byte{version, 0xff, 0xff, 0xff, 0xff, 0x0f}, c, add))
+}
+
+func TestSeq2(t *testing.T) {
+	keys, err := For[string]()
+	require.NoError(t, err)
+	values, err := For[int]()
+	require.NoError(t, err)
+
+	m := map[string]int{"a": 1, "b": -2, "c": 3}
+	data, err := MarshalSeq2(maps.All(m), keys, values)
+	require.NoError(t, err)
+
+	decoded := map[string]int{}
+	require.NoError(t, UnmarshalSeq2(data, keys, values, func(key string, value int) {
+		decoded[key] = value
+	}))
+	assert.Equal(t, m, decoded)
+	assert.Error(t, UnmarshalSeq2(data[:len(data)-1], keys, values, func(string, int) {
+		t.Fatal("put called for invalid data")
+	}))
+}
+
+func TestFuncs(t *testing.T) {
+	// encodes booleans as the characters t and f
+	c := Funcs[bool]{
+		Append: func(data []byte, value bool) ([]byte, error) {
+			if value {
+				return append(data, 't'), nil
+			}
+			return append(data, 'f'), nil
+		},
+		Decode: func(data []byte) (bool, int, error) {
+			if len(data) == 0 {
+				return false, 0, ErrTruncated
+			}
+			return data[0] == 't', 1, nil
+		},
+	}
+	data, err := MarshalSeq(slices.Values([]bool{true, false}), Codec[bool](c))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{version, 2, 't', 'f'}, data)
+
+	var decoded []bool
+	require.NoError(t, UnmarshalSeq(data, c, func(value bool) {
+		decoded = append(decoded, value)
+	}))
+	assert.Equal(t, []bool{true, false}, decoded)
+}
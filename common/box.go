@@ -0,0 +1,57 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import "cmp"
+
+// Box wraps a value of an ordered type, such as an int or a string,
+// with a Compare method so that it can be stored directly in the
+// containers that require their items to compare themselves, such as
+// skip lists, AVL trees and B+ trees, without a wrapper type written
+// for each.
+type Box[T Ordered] struct {
+	Value T
+}
+
+// NewBox returns value in a Box.
+func NewBox[T Ordered](value T) Box[T] {
+	return Box[T]{Value: value}
+}
+
+// Compare returns a negative number if this value is less than other,
+// 0 if they are equal and a positive number if it is greater.
+func (b Box[T]) Compare(other Box[T]) int {
+	return cmp.Compare(b.Value, other.Value)
+}
+
+// Wrap returns values as a slice of Boxes.
+func Wrap[T Ordered](values []T) []Box[T] {
+	boxes := make([]Box[T], len(values))
+	for i, value := range values {
+		boxes[i].Value = value
+	}
+	return boxes
+}
+
+// Unwrap returns the values held in boxes.
+func Unwrap[T Ordered](boxes []Box[T]) []T {
+	values := make([]T, len(boxes))
+	for i, box := range boxes {
+		values[i] = box.Value
+	}
+	return values
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBox(t *testing.T) {
+	assert.Negative(t, NewBox(1).Compare(NewBox(2)))
+	assert.Zero(t, NewBox("a").Compare(NewBox("a")))
+	assert.Positive(t, NewBox(2.5).Compare(NewBox(-1.0)))
+	assert.Negative(t, NewBox(math.NaN()).Compare(NewBox(0.0)))
+}
+
+func TestWrapUnwrap(t *testing.T) {
+	values := []string{"b", "a"}
+
+	boxes := Wrap(values)
+	assert.Equal(t, []Box[string]{{"b"}, {"a"}}, boxes)
+	assert.Equal(t, values, Unwrap(boxes))
+	assert.Empty(t, Unwrap(Wrap[int](nil)))
+}
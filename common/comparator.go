@@ -17,7 +17,10 @@ limitations under the License.
 // Package common provides shared interfaces and constraints for data structures.
 package common
 
-import "cmp"
+import (
+	"cmp"
+	"iter"
+)
 
 // Ordered is a constraint that matches any ordered type.
 // This includes all integer, float, and string types.
@@ -25,11 +28,37 @@ type Ordered = cmp.Ordered
 
 // ComparableItem is a generic interface for items that can be compared.
 // Returns a positive number if this item is greater, 0 if equal,
-// negative number if less than other.
+// negative number if less than other.  The Comparable constraints of
+// the ordered containers are aliases of it, so one type satisfies them
+// all.
 type ComparableItem[T any] interface {
 	Compare(other T) int
 }
 
+// Iterator is a cursor over the results of a query, shared by the
+// ordered containers whose Iter methods return one.
+type Iterator[T any] interface {
+	// Next moves the iterator to the next value and returns a bool
+	// indicating if there is one.
+	Next() bool
+	// Value returns the value at the iterator's current position, or
+	// the zero value if it is exhausted or has not been started.
+	Value() T
+}
+
+// Seq returns an iterator over the values remaining in it, for
+// ranging over the results of an Iter method.  Like it, the sequence
+// can only be iterated once.
+func Seq[T any](it Iterator[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for it.Next() {
+			if !yield(it.Value()) {
+				return
+			}
+		}
+	}
+}
+
 // CompareFunc is a function type for comparing two values.
 // Returns negative if a < b, 0 if a == b, positive if a > b.
 type CompareFunc[T any] func(a, b T) int
@@ -41,6 +70,14 @@ func OrderedCompare[T Ordered]() CompareFunc[T] {
 	}
 }
 
+// MethodCompare returns a CompareFunc for any type that compares
+// itself with its Compare method.
+func MethodCompare[T ComparableItem[T]]() CompareFunc[T] {
+	return func(a, b T) int {
+		return a.Compare(b)
+	}
+}
+
 // Less returns true if a < b for ordered types.
 func Less[T Ordered](a, b T) bool {
 	return cmp.Less(a, b)
@@ -50,3 +87,58 @@ func Less[T Ordered](a, b T) bool {
 func Equal[T comparable](a, b T) bool {
 	return a == b
 }
+
+// Reverse returns a CompareFunc that orders values in the opposite
+// order to cmp, for sorting in descending order.
+func Reverse[T any](cmp CompareFunc[T]) CompareFunc[T] {
+	return func(a, b T) int {
+		return cmp(b, a)
+	}
+}
+
+// Chain returns a CompareFunc that compares values with each of cmps
+// in turn, returning the first result that is not 0.  This orders
+// values lexicographically by several keys, the first being the most
+// significant.  With no cmps, every value is equal.
+func Chain[T any](cmps ...CompareFunc[T]) CompareFunc[T] {
+	return func(a, b T) int {
+		for _, cmp := range cmps {
+			if c := cmp(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+}
+
+// By returns a CompareFunc that orders values by the key extracted from
+// each with fn.  Combined with Then, this orders values by their fields,
+// here by last name and then from oldest to youngest:
+//
+//	compare := common.By(func(u User) string { return u.Last }).
+//		Then(common.ByDescending(func(u User) int { return u.Age }))
+func By[T any, K Ordered](fn func(T) K) CompareFunc[T] {
+	return func(a, b T) int {
+		return cmp.Compare(fn(a), fn(b))
+	}
+}
+
+// ByDescending is like By, but orders values from the greatest key to
+// the least.
+func ByDescending[T any, K Ordered](fn func(T) K) CompareFunc[T] {
+	return func(a, b T) int {
+		return cmp.Compare(fn(b), fn(a))
+	}
+}
+
+// Then returns a CompareFunc that compares values with c and, for
+// values c considers equal, with next.
+func (c CompareFunc[T]) Then(next CompareFunc[T]) CompareFunc[T] {
+	return Chain(c, next)
+}
+
+// Reverse returns a CompareFunc that orders values in the opposite
+// order to c.
+func (c CompareFunc[T]) Reverse() CompareFunc[T] {
+	return Reverse(c)
+}
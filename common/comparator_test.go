@@ -0,0 +1,87 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type person struct {
+	name string
+	age  int
+}
+
+func TestReverse(t *testing.T) {
+	values := []int{3, 1, 2}
+	slices.SortFunc(values, Reverse(OrderedCompare[int]()))
+	assert.Equal(t, []int{3, 2, 1}, values)
+}
+
+func TestChain(t *testing.T) {
+	people := []person{{"bob", 30}, {"alice", 30}, {"carol", 25}, {"alice", 20}}
+	byAge := func(a, b person) int { return a.age - b.age }
+	byName := func(a, b person) int { return OrderedCompare[string]()(a.name, b.name) }
+
+	slices.SortFunc(people, Chain(Reverse[person](byAge), byName))
+	assert.Equal(t, []person{{"alice", 30}, {"bob", 30}, {"carol", 25}, {"alice", 20}}, people)
+
+	assert.Equal(t, 0, Chain[person]()(people[0], people[1]))
+}
+
+func TestBy(t *testing.T) {
+	people := []person{{"bob", 30}, {"alice", 30}, {"carol", 25}, {"alice", 20}}
+	name := func(p person) string { return p.name }
+	age := func(p person) int { return p.age }
+
+	slices.SortFunc(people, ByDescending(age).Then(By(name)))
+	assert.Equal(t, []person{{"alice", 30}, {"bob", 30}, {"carol", 25}, {"alice", 20}}, people)
+
+	slices.SortFunc(people, By(name).Then(By(age)).Reverse())
+	assert.Equal(t, []person{{"carol", 25}, {"bob", 30}, {"alice", 30}, {"alice", 20}}, people)
+}
+
+// sliceIterator is an Iterator over a slice.
+type sliceIterator[T any] struct {
+	values []T
+	index  int
+}
+
+func (it *sliceIterator[T]) Next() bool {
+	it.index++
+	return it.index <= len(it.values)
+}
+
+func (it *sliceIterator[T]) Value() T {
+	if it.index < 1 || it.index > len(it.values) {
+		var zero T
+		return zero
+	}
+	return it.values[it.index-1]
+}
+
+func TestSeq(t *testing.T) {
+	it := &sliceIterator[int]{values: []int{1, 2, 3, 4}}
+	for value := range Seq[int](it) {
+		assert.Equal(t, 1, value)
+		break
+	}
+	assert.Equal(t, []int{2, 3, 4}, slices.Collect(Seq[int](it)))
+	assert.Empty(t, slices.Collect(Seq[int](it)))
+}
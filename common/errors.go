@@ -0,0 +1,71 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// These errors are shared by the packages in this module, which keep
+// their own names for them, such as queue.ErrDisposed and
+// batcher.ErrDisposed, as aliases.  Errors may be returned wrapped in
+// an *OpError, so test for them with errors.Is rather than ==.
+var (
+	// ErrDisposed is returned when an operation is attempted on a
+	// structure that has been disposed.
+	ErrDisposed = errors.New(`disposed`)
+
+	// ErrTimeout is returned when a call gives up waiting because its
+	// timeout elapsed.
+	ErrTimeout = errors.New(`timeout`)
+
+	// ErrEmpty is returned when an operation requires items but the
+	// structure holds none.
+	ErrEmpty = errors.New(`empty`)
+
+	// ErrFull is returned when an item does not fit because the
+	// structure is at capacity.
+	ErrFull = errors.New(`full`)
+)
+
+// OpError records the operation that failed and the timeout it was
+// given, wrapping the underlying error so that errors.Is still
+// matches it.  Use errors.As to recover one.
+type OpError struct {
+	// Op is the type and method that failed, as in "Queue.Poll".
+	Op string
+	// Timeout is the timeout the operation was given, or zero if it
+	// had none.
+	Timeout time.Duration
+	// Err is the underlying error, usually one of the errors above.
+	Err error
+}
+
+// Error implements error.
+func (e *OpError) Error() string {
+	if e.Timeout > 0 {
+		return fmt.Sprintf(`%s: %v after %v`, e.Op, e.Err, e.Timeout)
+	}
+	return fmt.Sprintf(`%s: %v`, e.Op, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *OpError) Unwrap() error {
+	return e.Err
+}
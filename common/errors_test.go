@@ -0,0 +1,41 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpError(t *testing.T) {
+	var err error = &OpError{Op: "Queue.Poll", Timeout: 50 * time.Millisecond, Err: ErrTimeout}
+	assert.EqualError(t, err, "Queue.Poll: timeout after 50ms")
+	assert.ErrorIs(t, err, ErrTimeout)
+	assert.NotErrorIs(t, err, ErrDisposed)
+
+	var opErr *OpError
+	assert.True(t, errors.As(err, &opErr))
+	assert.Equal(t, 50*time.Millisecond, opErr.Timeout)
+
+	err = &OpError{Op: "Queue.Get", Err: context.Canceled}
+	assert.EqualError(t, err, "Queue.Get: context canceled")
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"hash/maphash"
+	"math/bits"
+)
+
+// Integer is a constraint that matches any integer type.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Hasher is implemented by anything that hashes values of type T.
+// Equal values must have equal hashes.
+type Hasher[T any] interface {
+	Hash(value T) uint64
+}
+
+// Hashable is implemented by items that hash themselves.
+type Hashable interface {
+	Hash() uint64
+}
+
+// HashFunc is a function type for hashing a value.  It implements
+// Hasher, so a plain function can be used wherever a Hasher is needed.
+type HashFunc[T any] func(value T) uint64
+
+// Hash returns the hash of value.
+func (h HashFunc[T]) Hash(value T) uint64 {
+	return h(value)
+}
+
+// MethodHash returns a HashFunc for any type that hashes itself with
+// its Hash method.
+func MethodHash[T Hashable]() HashFunc[T] {
+	return func(value T) uint64 {
+		return value.Hash()
+	}
+}
+
+// IntegerHash returns a HashFunc for any integer type.  The bits of the
+// integer are mixed with Mix64 so that consecutive keys are spread
+// evenly.
+func IntegerHash[T Integer]() HashFunc[T] {
+	return func(value T) uint64 {
+		return Mix64(uint64(value))
+	}
+}
+
+// StringHash returns a HashFunc that hashes strings with FNV-1a.
+func StringHash() HashFunc[string] {
+	return func(value string) uint64 {
+		h := uint64(fnvOffset)
+		for i := 0; i < len(value); i++ {
+			h = (h ^ uint64(value[i])) * fnvPrime
+		}
+		return h
+	}
+}
+
+// BytesHash returns a HashFunc that hashes byte slices with FNV-1a.  A
+// byte slice hashes the same as the string holding the same bytes.
+func BytesHash() HashFunc[[]byte] {
+	return func(value []byte) uint64 {
+		h := uint64(fnvOffset)
+		for _, b := range value {
+			h = (h ^ uint64(b)) * fnvPrime
+		}
+		return h
+	}
+}
+
+// SeededHash returns a HashFunc for any comparable type using the
+// runtime's hash function.  Hashes depend on seed, so they differ
+// between seeds and between runs of a program, and must not be
+// persisted.
+func SeededHash[T comparable](seed maphash.Seed) HashFunc[T] {
+	return func(value T) uint64 {
+		return maphash.Comparable(seed, value)
+	}
+}
+
+const (
+	fnvOffset = 14695981039346656037
+	fnvPrime  = 1099511628211
+)
+
+// Mix64 scrambles the bits of x with the 64-bit finalizer of Murmur3,
+// so that every bit of the result depends on every bit of x.  It is
+// useful for improving a weak hash before using its low bits.
+func Mix64(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+// CombineHashes mixes the hash h into seed, for hashing values made of
+// several parts.  The result depends on the order the parts are
+// combined in.
+func CombineHashes(seed, h uint64) uint64 {
+	return Mix64(bits.RotateLeft64(seed, 5) ^ h)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"hash/fnv"
+	"hash/maphash"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type hashedKey uint64
+
+func (k hashedKey) Hash() uint64 {
+	return uint64(k) * 31
+}
+
+func TestStringHash(t *testing.T) {
+	for _, value := range []string{"", "a", "hello, world"} {
+		h := fnv.New64a()
+		h.Write([]byte(value))
+		assert.Equal(t, h.Sum64(), StringHash()(value))
+		assert.Equal(t, h.Sum64(), BytesHash()([]byte(value)))
+	}
+}
+
+func TestIntegerHash(t *testing.T) {
+	hash := IntegerHash[int32]()
+	assert.Equal(t, hash(-1), hash(-1))
+	assert.NotEqual(t, hash(1), hash(2))
+	assert.Equal(t, Mix64(uint64(5)), IntegerHash[uint8]()(5))
+
+	buckets := make([]int, 16)
+	for i := range 1600 {
+		buckets[IntegerHash[int]()(i)&15]++
+	}
+	for _, n := range buckets {
+		assert.InDelta(t, 100, n, 50)
+	}
+}
+
+func TestHasher(t *testing.T) {
+	var hasher Hasher[hashedKey] = MethodHash[hashedKey]()
+	assert.Equal(t, uint64(62), hasher.Hash(2))
+
+	seed := maphash.MakeSeed()
+	hasher2 := SeededHash[[2]int](seed)
+	assert.Equal(t, hasher2([2]int{1, 2}), hasher2.Hash([2]int{1, 2}))
+	assert.NotEqual(t, hasher2([2]int{1, 2}), hasher2([2]int{2, 1}))
+}
+
+func TestCombineHashes(t *testing.T) {
+	a, b := StringHash()("a"), StringHash()("b")
+	assert.NotEqual(t, CombineHashes(a, b), CombineHashes(b, a))
+	assert.Equal(t, CombineHashes(a, b), CombineHashes(a, b))
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"reflect"
+	"strconv"
+)
+
+// JSONCodec converts the elements of a container to and from JSON.  The
+// containers' own MarshalJSON and UnmarshalJSON methods use
+// encoding/json for their elements, which suits any element type it
+// can handle; for other types, or to change how elements look, pass a
+// codec to MarshalJSONArray and friends along with the container's
+// iterator.
+type JSONCodec[T any] struct {
+	// Marshal encodes a single element as JSON.
+	Marshal func(T) ([]byte, error)
+	// Unmarshal decodes a single element from JSON.
+	Unmarshal func([]byte) (T, error)
+}
+
+// DefaultJSONCodec returns a JSONCodec that uses encoding/json.
+func DefaultJSONCodec[T any]() JSONCodec[T] {
+	return JSONCodec[T]{
+		Marshal: func(item T) ([]byte, error) {
+			return json.Marshal(item)
+		},
+		Unmarshal: func(data []byte) (T, error) {
+			var item T
+			err := json.Unmarshal(data, &item)
+			return item, err
+		},
+	}
+}
+
+// MarshalJSONArray encodes the items as a JSON array, in order.
+func MarshalJSONArray[T any](items iter.Seq[T], codec JSONCodec[T]) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	first := true
+	for item := range items {
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+
+		b, err := codec.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSONArray decodes a JSON array, calling add with each item
+// in order.  null is treated as an empty array.
+func UnmarshalJSONArray[T any](data []byte, codec JSONCodec[T], add func(T)) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	items := make([]T, len(raw))
+	for i, r := range raw {
+		item, err := codec.Unmarshal(r)
+		if err != nil {
+			return err
+		}
+		items[i] = item
+	}
+	// nothing is added unless everything decodes
+	for _, item := range items {
+		add(item)
+	}
+	return nil
+}
+
+// MarshalJSONObject encodes the pairs as a JSON object, in order.  Keys
+// are encoded as encoding/json encodes the keys of a built-in map:
+// strings directly, then types implementing encoding.TextMarshaler,
+// then integers as decimal strings.
+func MarshalJSONObject[K comparable, V any](pairs iter.Seq2[K, V], codec JSONCodec[V]) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	first := true
+	for key, value := range pairs {
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+
+		name, err := EncodeJSONKey(key)
+		if err != nil {
+			return nil, err
+		}
+		b, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+		buf.WriteByte(':')
+		if b, err = codec.Marshal(value); err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSONObject decodes a JSON object, calling put with each key
+// and value in the order they appear.  Keys are decoded as the reverse
+// of MarshalJSONObject.  null is treated as an empty object.
+func UnmarshalJSONObject[K comparable, V any](data []byte, codec JSONCodec[V], put func(K, V)) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if t == nil {
+		return nil
+	}
+	if t != json.Delim('{') {
+		return fmt.Errorf("common: cannot unmarshal %v into an object", t)
+	}
+
+	type pair struct {
+		key   K
+		value V
+	}
+	var pairs []pair
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, err := DecodeJSONKey[K](t.(string))
+		if err != nil {
+			return err
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		value, err := codec.Unmarshal(raw)
+		if err != nil {
+			return err
+		}
+		pairs = append(pairs, pair{key, value})
+	}
+	if _, err = dec.Token(); err != nil {
+		return err
+	}
+
+	// nothing is put unless everything decodes
+	for _, p := range pairs {
+		put(p.key, p.value)
+	}
+	return nil
+}
+
+// EncodeJSONKey returns the name a key of a JSON object is given, by
+// the rules encoding/json uses for the keys of a built-in map.
+func EncodeJSONKey[K comparable](key K) (string, error) {
+	v := reflect.ValueOf(&key).Elem()
+	if v.Kind() == reflect.String {
+		return v.String(), nil
+	}
+	if tm, ok := any(key).(encoding.TextMarshaler); ok {
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			return "", nil
+		}
+		b, err := tm.MarshalText()
+		return string(b), err
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("common: unsupported JSON key type %v", v.Type())
+}
+
+// DecodeJSONKey is the reverse of EncodeJSONKey.
+func DecodeJSONKey[K comparable](name string) (K, error) {
+	var key K
+	v := reflect.ValueOf(&key).Elem()
+	if tu, ok := any(&key).(encoding.TextUnmarshaler); ok {
+		err := tu.UnmarshalText([]byte(name))
+		return key, err
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(name)
+		return key, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(name, 10, v.Type().Bits())
+		if err != nil {
+			return key, fmt.Errorf("common: invalid JSON key %q for %v", name, v.Type())
+		}
+		v.SetInt(n)
+		return key, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(name, 10, v.Type().Bits())
+		if err != nil {
+			return key, fmt.Errorf("common: invalid JSON key %q for %v", name, v.Type())
+		}
+		v.SetUint(n)
+		return key, nil
+	}
+	return key, fmt.Errorf("common: unsupported JSON key type %v", v.Type())
+}
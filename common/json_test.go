@@ -0,0 +1,133 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONArray(t *testing.T) {
+	b, err := MarshalJSONArray(slices.Values([]int{3, 1, 2}), DefaultJSONCodec[int]())
+	require.NoError(t, err)
+	assert.Equal(t, `[3,1,2]`, string(b))
+
+	b, err = MarshalJSONArray(slices.Values([]int{}), DefaultJSONCodec[int]())
+	require.NoError(t, err)
+	assert.Equal(t, `[]`, string(b))
+
+	var got []int
+	add := func(i int) { got = append(got, i) }
+	require.NoError(t, UnmarshalJSONArray([]byte(`[3,1,2]`), DefaultJSONCodec[int](), add))
+	assert.Equal(t, []int{3, 1, 2}, got)
+	require.NoError(t, UnmarshalJSONArray([]byte(`null`), DefaultJSONCodec[int](), add))
+	assert.Len(t, got, 3)
+
+	// nothing is added if any item fails to decode
+	got = nil
+	assert.Error(t, UnmarshalJSONArray([]byte(`[1,"two"]`), DefaultJSONCodec[int](), add))
+	assert.Nil(t, got)
+	assert.Error(t, UnmarshalJSONArray([]byte(`{}`), DefaultJSONCodec[int](), add))
+}
+
+func TestJSONCodec(t *testing.T) {
+	// a channel cannot be encoded by encoding/json, so encode its
+	// capacity instead
+	codec := JSONCodec[chan int]{
+		Marshal: func(c chan int) ([]byte, error) {
+			return []byte(strconv.Itoa(cap(c))), nil
+		},
+		Unmarshal: func(data []byte) (chan int, error) {
+			n, err := strconv.Atoi(string(data))
+			return make(chan int, n), err
+		},
+	}
+	_, err := MarshalJSONArray(slices.Values([]chan int{make(chan int)}), DefaultJSONCodec[chan int]())
+	assert.Error(t, err)
+
+	b, err := MarshalJSONArray(slices.Values([]chan int{make(chan int, 2), make(chan int, 5)}), codec)
+	require.NoError(t, err)
+	assert.Equal(t, `[2,5]`, string(b))
+
+	var caps []int
+	require.NoError(t, UnmarshalJSONArray(b, codec, func(c chan int) {
+		caps = append(caps, cap(c))
+	}))
+	assert.Equal(t, []int{2, 5}, caps)
+}
+
+type point struct{ x, y int }
+
+func (p point) MarshalText() ([]byte, error) {
+	return fmt.Appendf(nil, `%d,%d`, p.x, p.y), nil
+}
+
+func (p *point) UnmarshalText(text []byte) error {
+	_, err := fmt.Sscanf(string(text), `%d,%d`, &p.x, &p.y)
+	return err
+}
+
+func TestJSONObject(t *testing.T) {
+	pairs := func(yield func(int, string) bool) {
+		_ = yield(2, `b`) && yield(-1, `a`)
+	}
+	b, err := MarshalJSONObject(pairs, DefaultJSONCodec[string]())
+	require.NoError(t, err)
+	assert.Equal(t, `{"2":"b","-1":"a"}`, string(b))
+
+	var keys []int
+	var values []string
+	put := func(k int, v string) {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	require.NoError(t, UnmarshalJSONObject(b, DefaultJSONCodec[string](), put))
+	assert.Equal(t, []int{2, -1}, keys)
+	assert.Equal(t, []string{`b`, `a`}, values)
+
+	keys = nil
+	assert.Error(t, UnmarshalJSONObject([]byte(`{"1":"a","x":"b"}`), DefaultJSONCodec[string](), put))
+	assert.Error(t, UnmarshalJSONObject([]byte(`{"1":"a","2":3}`), DefaultJSONCodec[string](), put))
+	assert.Error(t, UnmarshalJSONObject([]byte(`[]`), DefaultJSONCodec[string](), put))
+	assert.Nil(t, keys)
+	require.NoError(t, UnmarshalJSONObject([]byte(`null`), DefaultJSONCodec[string](), put))
+
+	b, err = MarshalJSONObject(func(yield func(point, bool) bool) {
+		yield(point{1, 2}, true)
+	}, DefaultJSONCodec[bool]())
+	require.NoError(t, err)
+	assert.Equal(t, `{"1,2":true}`, string(b))
+	var decoded map[string]bool
+	require.NoError(t, json.Unmarshal(b, &decoded))
+
+	var points []point
+	require.NoError(t, UnmarshalJSONObject(b, DefaultJSONCodec[bool](), func(p point, _ bool) {
+		points = append(points, p)
+	}))
+	assert.Equal(t, []point{{1, 2}}, points)
+
+	_, err = EncodeJSONKey(1.5)
+	assert.Error(t, err)
+	_, err = DecodeJSONKey[int8](`300`)
+	assert.Error(t, err)
+}
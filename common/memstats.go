@@ -0,0 +1,37 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+// MemStats is an estimate of the memory held by a container, as
+// returned by its MemStats method, for capacity planning and for
+// spotting containers that grow without bound.
+type MemStats struct {
+	// Items is the number of elements held.
+	Items uint64
+	// Nodes is the number of nodes, buckets or entries allocated to
+	// hold the elements.
+	Nodes uint64
+	// Slots is the combined capacity, in elements, of the slices the
+	// container has allocated.  Slots well beyond what Items needs
+	// suggest memory that a rebuild would release.
+	Slots uint64
+	// Bytes approximates the memory of the container itself, with
+	// each element counted at its unsafe.Sizeof size.  Memory that
+	// elements refer to, such as the contents of strings or values
+	// behind pointers, is not included, nor is allocator overhead.
+	Bytes uint64
+}
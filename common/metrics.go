@@ -0,0 +1,66 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import "time"
+
+// Metrics receives operational measurements, such as queue depths,
+// waits and evictions, from the structures in this module that accept
+// one through an option.  Names are dotted and begin with the package
+// reporting them, as in "queue.depth"; each package documents the
+// names it uses.  Measurements are reported inline, so implementations
+// must be safe for concurrent use and should return quickly.
+type Metrics interface {
+	// Count adds delta to the named counter.
+	Count(name string, delta int64)
+	// Gauge sets the named gauge to value.
+	Gauge(name string, value float64)
+	// Time records a duration for the named timer.
+	Time(name string, d time.Duration)
+}
+
+// MetricsFuncs is a Metrics built from callbacks, any of which may be
+// nil to ignore that kind of measurement.
+type MetricsFuncs struct {
+	// OnCount is called for each counter increment.
+	OnCount func(name string, delta int64)
+	// OnGauge is called for each gauge update.
+	OnGauge func(name string, value float64)
+	// OnTime is called for each duration recorded.
+	OnTime func(name string, d time.Duration)
+}
+
+// Count calls m.OnCount if it is set.
+func (m MetricsFuncs) Count(name string, delta int64) {
+	if m.OnCount != nil {
+		m.OnCount(name, delta)
+	}
+}
+
+// Gauge calls m.OnGauge if it is set.
+func (m MetricsFuncs) Gauge(name string, value float64) {
+	if m.OnGauge != nil {
+		m.OnGauge(name, value)
+	}
+}
+
+// Time calls m.OnTime if it is set.
+func (m MetricsFuncs) Time(name string, d time.Duration) {
+	if m.OnTime != nil {
+		m.OnTime(name, d)
+	}
+}
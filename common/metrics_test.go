@@ -0,0 +1,55 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsFuncs(t *testing.T) {
+	var (
+		counts = map[string]int64{}
+		gauges = map[string]float64{}
+		timers = map[string]time.Duration{}
+	)
+	var m Metrics = MetricsFuncs{
+		OnCount: func(name string, delta int64) { counts[name] += delta },
+		OnGauge: func(name string, value float64) { gauges[name] = value },
+		OnTime:  func(name string, d time.Duration) { timers[name] += d },
+	}
+
+	m.Count("a.count", 2)
+	m.Count("a.count", 3)
+	m.Gauge("a.gauge", 1.5)
+	m.Time("a.timer", time.Second)
+
+	assert.Equal(t, map[string]int64{"a.count": 5}, counts)
+	assert.Equal(t, map[string]float64{"a.gauge": 1.5}, gauges)
+	assert.Equal(t, map[string]time.Duration{"a.timer": time.Second}, timers)
+}
+
+func TestMetricsFuncsIgnoresNilCallbacks(t *testing.T) {
+	var m Metrics = MetricsFuncs{}
+	assert.NotPanics(t, func() {
+		m.Count("a.count", 1)
+		m.Gauge("a.gauge", 1)
+		m.Time("a.timer", time.Second)
+	})
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package conversions builds the containers in this module from an
+iter.Seq, so moving data from one structure to another is a single
+call.  Every container can be ranged over with All or Values, and a
+slice with slices.Values, so any of them can feed any other:
+
+	s := set.New(3, 1, 2)
+	sl := conversions.ToSkipListWithCompareFunc(s.Values(), cmp.Compare[int])
+	sorted := conversions.CollectSeq(sl.All()) // []int{1, 2, 3}
+
+Ordered containers keep one entry for each set of equal values, the
+last one the sequence yields.
+*/
+package conversions
+
+import (
+	"iter"
+	"slices"
+
+	"github.com/Workiva/go-datastructures/btree/plus"
+	"github.com/Workiva/go-datastructures/common"
+	"github.com/Workiva/go-datastructures/list"
+	"github.com/Workiva/go-datastructures/set"
+	"github.com/Workiva/go-datastructures/slice/skip"
+)
+
+// ToSet returns a set of the values in seq.
+func ToSet[T comparable](seq iter.Seq[T]) *set.Set[T] {
+	s := set.New[T]()
+	for v := range seq {
+		s.Add(v)
+	}
+	return s
+}
+
+// ToSkipList returns a skip list of the values in seq, with the
+// maximum level of one created with a uint64.
+func ToSkipList[T common.ComparableItem[T]](seq iter.Seq[T]) *skip.SkipList[T] {
+	return ToSkipListWithCompareFunc(seq, common.MethodCompare[T]())
+}
+
+// ToSkipListWithCompareFunc is like ToSkipList, but orders values with
+// the provided function.
+func ToSkipListWithCompareFunc[T any](seq iter.Seq[T], compare common.CompareFunc[T]) *skip.SkipList[T] {
+	sl := skip.NewWithCompareFunc(compare, uint64(0))
+	for v := range seq {
+		sl.Insert(v)
+	}
+	return sl
+}
+
+// ToBTree returns a B+ tree of the values in seq with nodes of the
+// given size.
+func ToBTree[K common.ComparableItem[K]](seq iter.Seq[K], nodeSize uint64) *plus.BTree[K] {
+	return ToBTreeWithCompareFunc(seq, common.MethodCompare[K](), nodeSize)
+}
+
+// ToBTreeWithCompareFunc is like ToBTree, but orders values with the
+// provided function.
+func ToBTreeWithCompareFunc[K any](seq iter.Seq[K], compare common.CompareFunc[K], nodeSize uint64) *plus.BTree[K] {
+	tree := plus.NewWithCompareFunc(compare, nodeSize)
+	for v := range seq {
+		tree.Insert(v)
+	}
+	return tree
+}
+
+// ToPersistentList returns a persistent list of the values in seq, in
+// the order they are yielded, with the first at the head.
+func ToPersistentList[T any](seq iter.Seq[T]) list.PersistentList[T] {
+	var b list.Builder[T]
+	for v := range seq {
+		b.Add(v)
+	}
+	return b.List()
+}
+
+// CollectSeq returns the values in seq as a slice, exporting any
+// container back out through its All or Values method.
+func CollectSeq[T any](seq iter.Seq[T]) []T {
+	return slices.Collect(seq)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversions
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Workiva/go-datastructures/common"
+	"github.com/Workiva/go-datastructures/set"
+)
+
+func TestToSet(t *testing.T) {
+	s := ToSet(slices.Values([]int{3, 1, 3, 2}))
+	assert.Equal(t, 3, s.Len())
+	assert.True(t, s.All(1, 2, 3))
+
+	assert.Zero(t, ToSet(slices.Values([]int(nil))).Len())
+}
+
+func TestToSkipList(t *testing.T) {
+	sl := ToSkipList(slices.Values(common.Wrap([]string{"b", "c", "a", "b"})))
+	assert.Equal(t, uint64(3), sl.Len())
+	assert.Equal(t, []string{"a", "b", "c"}, common.Unwrap(CollectSeq(sl.All())))
+
+	s := set.New(3, 1, 2)
+	sorted := ToSkipListWithCompareFunc(s.Values(), cmp.Compare[int])
+	assert.Equal(t, []int{1, 2, 3}, CollectSeq(sorted.All()))
+}
+
+func TestToBTree(t *testing.T) {
+	tree := ToBTree(slices.Values(common.Wrap([]int{5, 3, 4, 1, 2, 3})), 3)
+	assert.Equal(t, uint64(5), tree.Len())
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, common.Unwrap(CollectSeq(tree.All())))
+
+	reversed := ToBTreeWithCompareFunc(tree.All(), common.Reverse(common.MethodCompare[common.Box[int]]()), 3)
+	assert.Equal(t, []int{5, 4, 3, 2, 1}, common.Unwrap(CollectSeq(reversed.All())))
+}
+
+func TestToPersistentList(t *testing.T) {
+	l := ToPersistentList(slices.Values([]string{"a", "b", "c"}))
+	assert.Equal(t, uint(3), l.Length())
+	head, ok := l.Head()
+	assert.True(t, ok)
+	assert.Equal(t, "a", head)
+	assert.Equal(t, []string{"a", "b", "c"}, CollectSeq(l.All()))
+
+	assert.True(t, ToPersistentList(slices.Values([]int(nil))).IsEmpty())
+}
+
+func TestCollectSeq(t *testing.T) {
+	assert.Nil(t, CollectSeq(slices.Values([]int(nil))))
+	assert.Equal(t, []int{1, 2}, CollectSeq(slices.Values([]int{1, 2})))
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package deque implements a double-ended queue stored in a growable ring
+of contiguous memory.  Pushing and popping at either end are amortized
+O(1), and so is indexing, and the items sit next to each other in
+memory, which makes it faster and lighter on the garbage collector than
+container/list when used as a queue, stack or deque.
+
+The ring's capacity is kept to a power of two, doubling when full and
+halving when a quarter full.  The list package has a persistent Deque
+for when old versions must be kept.
+*/
+package deque
+
+import "iter"
+
+// minCapacity is the smallest capacity the ring is allocated or shrunk
+// to.
+const minCapacity = 16
+
+// Deque is a double-ended queue.  The zero value is an empty deque.  It
+// is not threadsafe.
+type Deque[T any] struct {
+	items []T // len is always zero or a power of two
+	head  int
+	count int
+}
+
+// New returns an empty deque with room for at least hint items before
+// it grows.
+func New[T any](hint int) *Deque[T] {
+	d := &Deque[T]{}
+	if hint > 0 {
+		d.items = make([]T, roundUp(hint))
+	}
+	return d
+}
+
+// roundUp returns the smallest power of two, at least minCapacity, that
+// is at least n.
+func roundUp(n int) int {
+	capacity := minCapacity
+	for capacity < n {
+		capacity <<= 1
+	}
+	return capacity
+}
+
+// mask returns the index into items of the ith item.
+func (d *Deque[T]) mask(i int) int {
+	return (d.head + i) & (len(d.items) - 1)
+}
+
+// Len returns the number of items in the deque.
+func (d *Deque[T]) Len() int {
+	return d.count
+}
+
+// PushBack adds item to the back of the deque.
+func (d *Deque[T]) PushBack(item T) {
+	d.grow()
+	d.items[d.mask(d.count)] = item
+	d.count++
+}
+
+// PushFront adds item to the front of the deque.
+func (d *Deque[T]) PushFront(item T) {
+	d.grow()
+	d.head = (d.head - 1) & (len(d.items) - 1)
+	d.items[d.head] = item
+	d.count++
+}
+
+// PopFront removes and returns the item at the front of the deque, and
+// whether there was one.
+func (d *Deque[T]) PopFront() (T, bool) {
+	var zero T
+	if d.count == 0 {
+		return zero, false
+	}
+	item := d.items[d.head]
+	d.items[d.head] = zero
+	d.head = d.mask(1)
+	d.count--
+	d.shrink()
+	return item, true
+}
+
+// PopBack removes and returns the item at the back of the deque, and
+// whether there was one.
+func (d *Deque[T]) PopBack() (T, bool) {
+	var zero T
+	if d.count == 0 {
+		return zero, false
+	}
+	i := d.mask(d.count - 1)
+	item := d.items[i]
+	d.items[i] = zero
+	d.count--
+	d.shrink()
+	return item, true
+}
+
+// Front returns the item at the front of the deque, and whether there
+// was one.
+func (d *Deque[T]) Front() (T, bool) {
+	if d.count == 0 {
+		var zero T
+		return zero, false
+	}
+	return d.items[d.head], true
+}
+
+// Back returns the item at the back of the deque, and whether there was
+// one.
+func (d *Deque[T]) Back() (T, bool) {
+	if d.count == 0 {
+		var zero T
+		return zero, false
+	}
+	return d.items[d.mask(d.count-1)], true
+}
+
+// At returns the ith item from the front of the deque, panicking if i
+// is out of range.
+func (d *Deque[T]) At(i int) T {
+	d.check(i)
+	return d.items[d.mask(i)]
+}
+
+// Set replaces the ith item from the front of the deque, panicking if i
+// is out of range.
+func (d *Deque[T]) Set(i int, item T) {
+	d.check(i)
+	d.items[d.mask(i)] = item
+}
+
+func (d *Deque[T]) check(i int) {
+	if i < 0 || i >= d.count {
+		panic(`deque: index out of range`)
+	}
+}
+
+// Clear removes every item from the deque, keeping its storage.
+func (d *Deque[T]) Clear() {
+	clear(d.items)
+	d.head = 0
+	d.count = 0
+}
+
+// All returns an iterator over the indices and items of the deque,
+// front to back.  The deque must not be modified while it is being
+// iterated.
+func (d *Deque[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := 0; i < d.count; i++ {
+			if !yield(i, d.items[d.mask(i)]) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the indices and items of the
+// deque, back to front.  The deque must not be modified while it is
+// being iterated.
+func (d *Deque[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := d.count - 1; i >= 0; i-- {
+			if !yield(i, d.items[d.mask(i)]) {
+				return
+			}
+		}
+	}
+}
+
+// grow doubles the ring if it is full.
+func (d *Deque[T]) grow() {
+	if d.count < len(d.items) {
+		return
+	}
+	d.resize(max(minCapacity, len(d.items)*2))
+}
+
+// shrink halves the ring if it is no more than a quarter full.
+func (d *Deque[T]) shrink() {
+	if len(d.items) > minCapacity && d.count <= len(d.items)/4 {
+		d.resize(len(d.items) / 2)
+	}
+}
+
+// resize moves the items to a new ring of the given capacity, starting
+// at its beginning.
+func (d *Deque[T]) resize(capacity int) {
+	items := make([]T, capacity)
+	if d.head+d.count <= len(d.items) {
+		copy(items, d.items[d.head:d.head+d.count])
+	} else {
+		n := copy(items, d.items[d.head:])
+		copy(items[n:], d.items[:d.count-n])
+	}
+	d.items = items
+	d.head = 0
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deque
+
+import (
+	"container/list"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func items[T any](d *Deque[T]) []T {
+	result := []T{}
+	for _, item := range d.All() {
+		result = append(result, item)
+	}
+	return result
+}
+
+func TestDeque(t *testing.T) {
+	var d Deque[int]
+	_, ok := d.PopFront()
+	assert.False(t, ok)
+	_, ok = d.PopBack()
+	assert.False(t, ok)
+	_, ok = d.Front()
+	assert.False(t, ok)
+	_, ok = d.Back()
+	assert.False(t, ok)
+
+	d.PushBack(2)
+	d.PushBack(3)
+	d.PushFront(1)
+	d.PushFront(0)
+	assert.Equal(t, []int{0, 1, 2, 3}, items(&d))
+	assert.Equal(t, 4, d.Len())
+
+	front, _ := d.Front()
+	back, _ := d.Back()
+	assert.Equal(t, 0, front)
+	assert.Equal(t, 3, back)
+	assert.Equal(t, 2, d.At(2))
+	d.Set(2, 20)
+	assert.Equal(t, 20, d.At(2))
+	assert.Panics(t, func() { d.At(4) })
+	assert.Panics(t, func() { d.Set(-1, 0) })
+
+	var backward []int
+	for i, item := range d.Backward() {
+		assert.Equal(t, d.At(i), item)
+		backward = append(backward, item)
+	}
+	assert.Equal(t, []int{3, 20, 1, 0}, backward)
+
+	item, _ := d.PopFront()
+	assert.Equal(t, 0, item)
+	item, _ = d.PopBack()
+	assert.Equal(t, 3, item)
+	assert.Equal(t, []int{1, 20}, items(&d))
+
+	d.Clear()
+	assert.Equal(t, 0, d.Len())
+	assert.Equal(t, []int{}, items(&d))
+}
+
+func TestGrowAndShrink(t *testing.T) {
+	d := New[*int](0)
+	for i := range 1000 {
+		if i%2 == 0 {
+			d.PushBack(new(int))
+		} else {
+			d.PushFront(new(int))
+		}
+	}
+	assert.Equal(t, 1024, len(d.items))
+
+	for range 990 {
+		d.PopFront()
+	}
+	assert.Equal(t, 32, len(d.items))
+	assert.Equal(t, 10, d.Len())
+	for i := d.count; i < len(d.items); i++ {
+		assert.Nil(t, d.items[d.mask(i)])
+	}
+
+	assert.Equal(t, 64, len(New[int](33).items))
+	assert.Equal(t, minCapacity, len(New[int](1).items))
+}
+
+func TestMatchesSlice(t *testing.T) {
+	d := New[int](0)
+	var expected []int
+	for i := range 10000 {
+		switch rand.Intn(4) {
+		case 0:
+			d.PushFront(i)
+			expected = append([]int{i}, expected...)
+		case 1:
+			d.PushBack(i)
+			expected = append(expected, i)
+		case 2:
+			item, ok := d.PopFront()
+			assert.Equal(t, len(expected) > 0, ok)
+			if ok {
+				assert.Equal(t, expected[0], item)
+				expected = expected[1:]
+			}
+		case 3:
+			item, ok := d.PopBack()
+			assert.Equal(t, len(expected) > 0, ok)
+			if ok {
+				assert.Equal(t, expected[len(expected)-1], item)
+				expected = expected[:len(expected)-1]
+			}
+		}
+		assert.Equal(t, len(expected), d.Len())
+	}
+	if expected == nil {
+		expected = []int{}
+	}
+	assert.Equal(t, expected, items(d))
+}
+
+func BenchmarkDeque(b *testing.B) {
+	d := New[int](0)
+	for i := 0; i < b.N; i++ {
+		for j := range 100 {
+			d.PushBack(j)
+		}
+		for range 100 {
+			d.PopFront()
+		}
+	}
+}
+
+func BenchmarkList(b *testing.B) {
+	l := list.New()
+	for i := 0; i < b.N; i++ {
+		for j := range 100 {
+			l.PushBack(j)
+		}
+		for range 100 {
+			l.Remove(l.Front())
+		}
+	}
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package cuckoo implements a cuckoo filter, a probabilistic set that
+answers whether a key has been added with no false negatives and a small
+rate of false positives.  Unlike a Bloom filter, keys can be deleted.
+
+The filter stores a short fingerprint of each key in one of two buckets
+chosen by the key's hash.  When both are full, fingerprints already in
+the filter are moved to their alternate bucket to make room, as in
+cuckoo hashing.  With the default buckets of 4 fingerprints of 16 bits,
+a filter can be filled to about 95% of its capacity and has a false
+positive rate of about 2*4/2^16, or 0.012%.
+
+Lookups and deletions are O(1).  Additions are O(1) amortized, but slow
+as the filter approaches capacity.  A Filter is not threadsafe.
+*/
+package cuckoo
+
+import "github.com/Workiva/go-datastructures/common"
+
+// maxLoad is the fraction of a filter's capacity that New plans to
+// use.  Filters sized for more are given twice as many buckets.
+const maxLoad = 0.96
+
+var hashBytes = common.BytesHash()
+
+// victim is a fingerprint that could not be placed when the filter
+// filled up.  It is kept aside so that no added key is forgotten.
+type victim struct {
+	index       uint64
+	fingerprint uint32
+	used        bool
+}
+
+// Filter is a cuckoo filter.  Fingerprints are packed into a bit array
+// so that they take no more memory than their configured length.
+type Filter struct {
+	table      []uint64
+	bucketSize uint64
+	bits       uint64
+	fpMask     uint64
+	mask       uint64
+	maxKicks   uint
+	count      uint
+	victim     victim
+	seed       uint64
+}
+
+// New returns a filter with room for at least capacity keys.
+func New(capacity uint, opts ...Option) *Filter {
+	o := newOptions(opts)
+	bucketSize := uint64(o.bucketSize)
+
+	buckets := roundUp((uint64(capacity) + bucketSize - 1) / bucketSize)
+	if float64(capacity)/float64(buckets*bucketSize) > maxLoad {
+		buckets <<= 1
+	}
+
+	bits := uint64(o.fingerprintBits)
+	return &Filter{
+		table:      make([]uint64, (buckets*bucketSize*bits+63)/64),
+		bucketSize: bucketSize,
+		bits:       bits,
+		fpMask:     1<<bits - 1,
+		mask:       buckets - 1,
+		maxKicks:   o.maxKicks,
+		seed:       0x9e3779b97f4a7c15,
+	}
+}
+
+// Add adds the key to the filter.  Returns false if the filter is
+// too full to add it, in which case the filter is unchanged.  Adding
+// the same key more than once stores it more than once, and it then
+// remains in the filter until it has been deleted as many times.
+func (f *Filter) Add(key []byte) bool {
+	if f.victim.used {
+		return false
+	}
+
+	index, fp := f.hash(key)
+	f.place(index, fp)
+	f.count++
+	return true
+}
+
+// Test returns true if the key may have been added to the filter and
+// false if it definitely has not.
+func (f *Filter) Test(key []byte) bool {
+	i1, fp := f.hash(key)
+	i2 := f.altIndex(i1, fp)
+	if f.find(i1, fp) >= 0 || f.find(i2, fp) >= 0 {
+		return true
+	}
+
+	return f.victim.used && f.victim.fingerprint == fp &&
+		(f.victim.index == i1 || f.victim.index == i2)
+}
+
+// Delete removes one copy of the key from the filter, returning false
+// if the filter does not contain it.  Only keys that have been added
+// should be deleted: as keys share fingerprints, deleting any other
+// key may remove a key that was added in its place.
+func (f *Filter) Delete(key []byte) bool {
+	i1, fp := f.hash(key)
+	i2 := f.altIndex(i1, fp)
+
+	for _, index := range [2]uint64{i1, i2} {
+		if slot := f.find(index, fp); slot >= 0 {
+			f.set(index, uint64(slot), 0)
+			f.count--
+			f.retryVictim()
+			return true
+		}
+	}
+
+	if f.victim.used && f.victim.fingerprint == fp &&
+		(f.victim.index == i1 || f.victim.index == i2) {
+		f.victim.used = false
+		f.count--
+		return true
+	}
+
+	return false
+}
+
+// Count returns the number of keys in the filter.
+func (f *Filter) Count() uint {
+	return f.count
+}
+
+// Capacity returns the number of fingerprints the filter has room for.
+// In practice, a filter is full before it reaches its capacity.
+func (f *Filter) Capacity() uint {
+	return uint((f.mask + 1) * f.bucketSize)
+}
+
+// Reset removes every key from the filter.
+func (f *Filter) Reset() {
+	clear(f.table)
+	f.count = 0
+	f.victim = victim{}
+}
+
+// hash returns the primary bucket and fingerprint of the key.  The
+// fingerprint is never 0, which marks an empty slot.
+func (f *Filter) hash(key []byte) (uint64, uint32) {
+	h := common.Mix64(hashBytes(key))
+	fp := (h >> 32) & f.fpMask
+	if fp == 0 {
+		fp = 1
+	}
+	return h & f.mask, uint32(fp)
+}
+
+// altIndex returns the other bucket a fingerprint in bucket index may
+// be stored in.  Applying it twice gives back index.
+func (f *Filter) altIndex(index uint64, fp uint32) uint64 {
+	return (index ^ common.Mix64(uint64(fp))) & f.mask
+}
+
+// place stores the fingerprint in the bucket at index or its alternate,
+// relocating fingerprints already there if both are full.  If no room
+// can be made, the last fingerprint displaced becomes the victim.
+func (f *Filter) place(index uint64, fp uint32) {
+	if f.insert(index, fp) {
+		return
+	}
+	index = f.altIndex(index, fp)
+	if f.insert(index, fp) {
+		return
+	}
+
+	for range f.maxKicks {
+		slot := f.random() % f.bucketSize
+		displaced := f.get(index, slot)
+		f.set(index, slot, fp)
+		fp = displaced
+		index = f.altIndex(index, fp)
+		if f.insert(index, fp) {
+			return
+		}
+	}
+
+	f.victim = victim{index: index, fingerprint: fp, used: true}
+}
+
+// retryVictim attempts to place the victim now that a slot has been
+// freed.
+func (f *Filter) retryVictim() {
+	if !f.victim.used {
+		return
+	}
+
+	v := f.victim
+	f.victim = victim{}
+	f.place(v.index, v.fingerprint)
+}
+
+// insert stores the fingerprint in an empty slot of the bucket at
+// index, returning false if the bucket is full.
+func (f *Filter) insert(index uint64, fp uint32) bool {
+	if slot := f.find(index, 0); slot >= 0 {
+		f.set(index, uint64(slot), fp)
+		return true
+	}
+	return false
+}
+
+// find returns the slot of the bucket at index holding the
+// fingerprint, or -1 if there is none.
+func (f *Filter) find(index uint64, fp uint32) int {
+	for slot := range f.bucketSize {
+		if f.get(index, slot) == fp {
+			return int(slot)
+		}
+	}
+	return -1
+}
+
+func (f *Filter) get(index, slot uint64) uint32 {
+	bit := (index*f.bucketSize + slot) * f.bits
+	word, offset := bit/64, bit%64
+	v := f.table[word] >> offset
+	if offset+f.bits > 64 {
+		v |= f.table[word+1] << (64 - offset)
+	}
+	return uint32(v & f.fpMask)
+}
+
+func (f *Filter) set(index, slot uint64, fp uint32) {
+	bit := (index*f.bucketSize + slot) * f.bits
+	word, offset := bit/64, bit%64
+	f.table[word] = f.table[word]&^(f.fpMask<<offset) | uint64(fp)<<offset
+	if offset+f.bits > 64 {
+		shift := 64 - offset
+		f.table[word+1] = f.table[word+1]&^(f.fpMask>>shift) | uint64(fp)>>shift
+	}
+}
+
+// random returns the next number of a xorshift generator, used to
+// choose which fingerprint to relocate.
+func (f *Filter) random() uint64 {
+	f.seed ^= f.seed << 13
+	f.seed ^= f.seed >> 7
+	f.seed ^= f.seed << 17
+	return f.seed
+}
+
+// roundUp rounds v up to the next power of 2, treating 0 as 1.
+func roundUp(v uint64) uint64 {
+	if v == 0 {
+		return 1
+	}
+	v--
+	v |= v >> 1
+	v |= v >> 2
+	v |= v >> 4
+	v |= v >> 8
+	v |= v >> 16
+	v |= v >> 32
+	v++
+	return v
+}
@@ -0,0 +1,328 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cuckoo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+func generateKeys(prefix string, num int) [][]byte {
+	keys := make([][]byte, 0, num)
+	for i := range num {
+		keys = append(keys, []byte(prefix+strconv.Itoa(i)))
+	}
+	return keys
+}
+
+func TestAddTest(t *testing.T) {
+	f := New(1000)
+	keys := generateKeys("key", 1000)
+	for _, key := range keys {
+		require.True(t, f.Add(key))
+	}
+	assert.Equal(t, uint(1000), f.Count())
+
+	for _, key := range keys {
+		assert.True(t, f.Test(key))
+	}
+
+	falsePositives := 0
+	for _, key := range generateKeys("other", 100000) {
+		if f.Test(key) {
+			falsePositives++
+		}
+	}
+	// expected rate is at most 8/2^16, or about 12 in 100000
+	assert.Less(t, falsePositives, 50)
+}
+
+func TestDelete(t *testing.T) {
+	f := New(100)
+	keys := generateKeys("key", 100)
+	for _, key := range keys {
+		f.Add(key)
+	}
+
+	for _, key := range keys[:50] {
+		assert.True(t, f.Delete(key))
+	}
+	assert.Equal(t, uint(50), f.Count())
+
+	for _, key := range keys[:50] {
+		assert.False(t, f.Test(key))
+		assert.False(t, f.Delete(key))
+	}
+	for _, key := range keys[50:] {
+		assert.True(t, f.Test(key))
+	}
+}
+
+func TestDuplicates(t *testing.T) {
+	f := New(10)
+	key := []byte("key")
+	f.Add(key)
+	f.Add(key)
+	assert.Equal(t, uint(2), f.Count())
+
+	assert.True(t, f.Delete(key))
+	assert.True(t, f.Test(key))
+	assert.True(t, f.Delete(key))
+	assert.False(t, f.Test(key))
+}
+
+func TestFull(t *testing.T) {
+	f := New(64, WithMaxKicks(50))
+	var added [][]byte
+	for _, key := range generateKeys("key", 1000) {
+		if !f.Add(key) {
+			break
+		}
+		added = append(added, key)
+	}
+
+	assert.Less(t, len(added), 1000)
+	assert.GreaterOrEqual(t, len(added), int(f.Capacity()*3/4))
+	assert.Equal(t, uint(len(added)), f.Count())
+	for _, key := range added {
+		assert.True(t, f.Test(key))
+	}
+
+	assert.True(t, f.Delete(added[0]))
+	assert.True(t, f.Add([]byte("new")))
+	for _, key := range added[1:] {
+		assert.True(t, f.Test(key))
+	}
+	assert.True(t, f.Test([]byte("new")))
+
+	f.Reset()
+	assert.Equal(t, uint(0), f.Count())
+	assert.False(t, f.Test(added[1]))
+	assert.True(t, f.Add(added[1]))
+}
+
+func TestFingerprintBits(t *testing.T) {
+	for _, bits := range []uint{4, 7, 13, 32} {
+		t.Run(strconv.Itoa(int(bits)), func(t *testing.T) {
+			f := New(500, WithFingerprintBits(bits), WithBucketSize(3))
+			keys := generateKeys("key", 500)
+			for _, key := range keys {
+				require.True(t, f.Add(key))
+			}
+			for _, key := range keys {
+				assert.True(t, f.Test(key))
+			}
+			for _, key := range keys {
+				assert.True(t, f.Delete(key))
+			}
+			assert.Equal(t, uint(0), f.Count())
+			for _, word := range f.table {
+				assert.Zero(t, word)
+			}
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	assert.Panics(t, func() { WithBucketSize(0) })
+	assert.Panics(t, func() { WithBucketSize(9) })
+	assert.Panics(t, func() { WithFingerprintBits(1) })
+	assert.Panics(t, func() { WithFingerprintBits(33) })
+
+	f := New(100, WithBucketSize(2))
+	assert.Equal(t, uint(128), f.Capacity())
+	assert.Equal(t, uint(4), New(0).Capacity())
+}
+
+func BenchmarkAdd(b *testing.B) {
+	keys := generateKeys("key", 1<<16)
+	f := New(uint(len(keys)))
+
+	for i := 0; b.Loop(); i++ {
+		if i%len(keys) == 0 {
+			f.Reset()
+		}
+		f.Add(keys[i%len(keys)])
+	}
+}
+
+// bloomFilter is a minimal Bloom filter, the filter a cuckoo filter is
+// usually chosen over, used as a baseline by the benchmarks.  It uses
+// double hashing to derive its k bit positions from a single hash.
+type bloomFilter struct {
+	bits []uint64
+	m, k uint64
+}
+
+// newBloomFilter returns a Bloom filter sized to hold n keys with the
+// given false positive rate.
+func newBloomFilter(n int, fpr float64) *bloomFilter {
+	m := uint64(math.Ceil(-float64(n) * math.Log(fpr) / (math.Ln2 * math.Ln2)))
+	k := max(uint64(math.Round(float64(m)/float64(n)*math.Ln2)), 1)
+	return &bloomFilter{bits: make([]uint64, (m+63)/64), m: m, k: k}
+}
+
+func (bf *bloomFilter) positions(key []byte, fn func(uint64) bool) bool {
+	h := common.Mix64(hashBytes(key))
+	h1, h2 := h, h>>32|h<<32|1
+	for i := range bf.k {
+		if !fn((h1 + i*h2) % bf.m) {
+			return false
+		}
+	}
+	return true
+}
+
+func (bf *bloomFilter) Add(key []byte) {
+	bf.positions(key, func(bit uint64) bool {
+		bf.bits[bit/64] |= 1 << (bit % 64)
+		return true
+	})
+}
+
+func (bf *bloomFilter) Test(key []byte) bool {
+	return bf.positions(key, func(bit uint64) bool {
+		return bf.bits[bit/64]&(1<<(bit%64)) != 0
+	})
+}
+
+// comparisons pairs the fingerprint lengths benchmarked with the false
+// positive rate of a full cuckoo filter with 4 slots per bucket, 2b/2^f,
+// for which a Bloom filter is sized to compare them at equal accuracy.
+var comparisons = []struct {
+	bits uint
+	fpr  float64
+}{
+	{8, 8.0 / (1 << 8)},
+	{12, 8.0 / (1 << 12)},
+	{16, 8.0 / (1 << 16)},
+}
+
+// BenchmarkTest compares the lookup speed and memory of cuckoo filters
+// with different fingerprint lengths against Bloom filters of the same
+// false positive rate, reporting the bits each uses per key, and against
+// a map holding the keys.  Half of the lookups are of absent keys.
+func BenchmarkTest(b *testing.B) {
+	// just under the 96% load at which a filter of 1<<16 slots is
+	// doubled, as a filter sized for its keys would be filled.
+	const numItems = 60000
+	keys := generateKeys("key", numItems)
+	absent := generateKeys("absent", numItems)
+	lookup := func(i int) []byte {
+		if i%2 == 0 {
+			return keys[i/2%numItems]
+		}
+		return absent[i/2%numItems]
+	}
+
+	for _, c := range comparisons {
+		b.Run(fmt.Sprintf("cuckoo/bits=%d", c.bits), func(b *testing.B) {
+			f := New(numItems, WithFingerprintBits(c.bits))
+			for _, key := range keys {
+				f.Add(key)
+			}
+
+			for i := 0; b.Loop(); i++ {
+				f.Test(lookup(i))
+			}
+			b.ReportMetric(float64(len(f.table)*64)/numItems, "bits/key")
+		})
+
+		b.Run(fmt.Sprintf("bloom/fpr=%.3g", c.fpr), func(b *testing.B) {
+			f := newBloomFilter(numItems, c.fpr)
+			for _, key := range keys {
+				f.Add(key)
+			}
+
+			for i := 0; b.Loop(); i++ {
+				f.Test(lookup(i))
+			}
+			b.ReportMetric(float64(len(f.bits)*64)/numItems, "bits/key")
+		})
+	}
+
+	b.Run("map", func(b *testing.B) {
+		m := make(map[string]struct{}, numItems)
+		for _, key := range keys {
+			m[string(key)] = struct{}{}
+		}
+
+		for i := 0; b.Loop(); i++ {
+			_ = m[string(lookup(i))]
+		}
+	})
+}
+
+// BenchmarkAddCompare compares the insertion speed of cuckoo and Bloom
+// filters of the same false positive rate.
+func BenchmarkAddCompare(b *testing.B) {
+	const numItems = 60000
+	keys := generateKeys("key", numItems)
+
+	for _, c := range comparisons {
+		b.Run(fmt.Sprintf("cuckoo/bits=%d", c.bits), func(b *testing.B) {
+			f := New(numItems, WithFingerprintBits(c.bits))
+			for i := 0; b.Loop(); i++ {
+				if i%numItems == 0 {
+					f.Reset()
+				}
+				f.Add(keys[i%numItems])
+			}
+		})
+
+		b.Run(fmt.Sprintf("bloom/fpr=%.3g", c.fpr), func(b *testing.B) {
+			f := newBloomFilter(numItems, c.fpr)
+			for i := 0; b.Loop(); i++ {
+				if i%numItems == 0 {
+					clear(f.bits)
+				}
+				f.Add(keys[i%numItems])
+			}
+		})
+	}
+}
+
+func TestBloomBaseline(t *testing.T) {
+	const numItems = 10000
+	for _, c := range comparisons {
+		bf := newBloomFilter(numItems, c.fpr)
+		cf := New(numItems, WithFingerprintBits(c.bits))
+		for _, key := range generateKeys("key", numItems) {
+			bf.Add(key)
+			require.True(t, cf.Add(key))
+		}
+
+		var bloomFalse, cuckooFalse int
+		for _, key := range generateKeys("absent", 100*numItems) {
+			if bf.Test(key) {
+				bloomFalse++
+			}
+			if cf.Test(key) {
+				cuckooFalse++
+			}
+		}
+		assert.InDelta(t, c.fpr, float64(bloomFalse)/(100*numItems), 2*c.fpr, "bloom, bits=%d", c.bits)
+		assert.LessOrEqual(t, float64(cuckooFalse)/(100*numItems), 2*c.fpr, "cuckoo, bits=%d", c.bits)
+	}
+}
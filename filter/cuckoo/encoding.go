@@ -0,0 +1,134 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cuckoo
+
+import (
+	"encoding/binary"
+
+	"github.com/Workiva/go-datastructures/codec"
+)
+
+// encodingVersion is written at the start of every encoded filter so
+// the format can change without misreading older data.
+const encodingVersion = 1
+
+// MarshalBinary encodes the filter, including its configuration, so
+// that UnmarshalBinary restores a filter answering exactly as this one
+// does.  The header fields are uvarints, as written by the codec
+// package, and the fingerprint table follows as little endian words.
+func (f *Filter) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, 64+8*len(f.table))
+	data = append(data, encodingVersion)
+	for _, field := range [...]uint64{
+		f.mask + 1, f.bucketSize, f.bits, uint64(f.maxKicks), uint64(f.count), f.seed,
+	} {
+		data = binary.AppendUvarint(data, field)
+	}
+
+	used := byte(0)
+	if f.victim.used {
+		used = 1
+	}
+	data = append(data, used)
+	data = binary.AppendUvarint(data, f.victim.index)
+	data = binary.AppendUvarint(data, uint64(f.victim.fingerprint))
+
+	for _, word := range f.table {
+		data = binary.LittleEndian.AppendUint64(data, word)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary replaces the filter with one encoded by
+// MarshalBinary.  The filter is unchanged if the data is invalid.
+func (f *Filter) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return codec.ErrTruncated
+	}
+	if data[0] != encodingVersion {
+		return codec.ErrCorrupt
+	}
+	data = data[1:]
+
+	var header [6]uint64
+	for i := range header {
+		value, n, err := codec.Uvarint(data)
+		if err != nil {
+			return err
+		}
+		header[i], data = value, data[n:]
+	}
+	buckets, bucketSize, bits := header[0], header[1], header[2]
+	if buckets == 0 || buckets&(buckets-1) != 0 || bucketSize < 1 || bucketSize > 8 ||
+		bits < 2 || bits > 32 || header[3] > uint64(^uint(0)) || header[4] > uint64(^uint(0)) {
+		return codec.ErrCorrupt
+	}
+	// every bucket takes at least two bits of the table, so a count
+	// of buckets beyond that must be corrupt rather than allocated.
+	if buckets > 4*uint64(len(data)) {
+		return codec.ErrTruncated
+	}
+
+	if len(data) == 0 {
+		return codec.ErrTruncated
+	}
+	used := data[0]
+	if used > 1 {
+		return codec.ErrCorrupt
+	}
+	data = data[1:]
+	index, n, err := codec.Uvarint(data)
+	if err != nil {
+		return err
+	}
+	data = data[n:]
+	fp, n, err := codec.Uvarint(data)
+	if err != nil {
+		return err
+	}
+	data = data[n:]
+	if used == 1 && (index >= buckets || fp == 0 || fp >= 1<<bits) {
+		return codec.ErrCorrupt
+	}
+
+	words := (buckets*bucketSize*bits + 63) / 64
+	if uint64(len(data)) < words*8 {
+		return codec.ErrTruncated
+	}
+	if uint64(len(data)) > words*8 {
+		return codec.ErrCorrupt
+	}
+	table := make([]uint64, words)
+	for i := range table {
+		table[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+
+	*f = Filter{
+		table:      table,
+		bucketSize: bucketSize,
+		bits:       bits,
+		fpMask:     1<<bits - 1,
+		mask:       buckets - 1,
+		maxKicks:   uint(header[3]),
+		count:      uint(header[4]),
+		seed:       header[5],
+	}
+	if used == 1 {
+		f.victim = victim{index: index, fingerprint: uint32(fp), used: true}
+	}
+	return nil
+}
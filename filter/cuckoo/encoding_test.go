@@ -0,0 +1,101 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cuckoo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	f := New(100, WithFingerprintBits(12), WithBucketSize(2))
+	keys := generateKeys("key", 100)
+	for _, key := range keys {
+		f.Add(key)
+	}
+	data, err := f.MarshalBinary()
+	require.NoError(t, err)
+
+	result := New(1)
+	require.NoError(t, result.UnmarshalBinary(data))
+	assert.Equal(t, f, result)
+	for _, key := range keys {
+		assert.True(t, result.Test(key))
+	}
+	for _, key := range generateKeys("other", 1000) {
+		assert.Equal(t, f.Test(key), result.Test(key))
+	}
+	require.True(t, result.Delete(keys[0]))
+	assert.Equal(t, f.Count()-1, result.Count())
+}
+
+func TestMarshalBinaryVictim(t *testing.T) {
+	f := New(8, WithBucketSize(1), WithMaxKicks(1))
+	for _, key := range generateKeys("key", 100) {
+		if !f.Add(key) {
+			break
+		}
+	}
+	require.True(t, f.victim.used)
+
+	data, err := f.MarshalBinary()
+	require.NoError(t, err)
+	result := New(1)
+	require.NoError(t, result.UnmarshalBinary(data))
+	assert.Equal(t, f, result)
+}
+
+func TestUnmarshalBinaryInvalid(t *testing.T) {
+	f := New(10)
+	f.Add([]byte("a"))
+	data, err := f.MarshalBinary()
+	require.NoError(t, err)
+
+	result := New(10)
+	result.Add([]byte("b"))
+	for i := range data {
+		assert.Error(t, result.UnmarshalBinary(data[:i]), "truncated at %d", i)
+	}
+	assert.Error(t, result.UnmarshalBinary(append(data, 0)))
+	assert.True(t, result.Test([]byte("b")))
+
+	// a huge bucket count must not be allocated
+	assert.Error(t, result.UnmarshalBinary([]byte{encodingVersion, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x40, 4, 16, 1, 0, 1, 0, 0, 0}))
+}
+
+func FuzzUnmarshalBinary(f *testing.F) {
+	filter := New(16)
+	for _, key := range generateKeys("key", 10) {
+		filter.Add(key)
+	}
+	data, err := filter.MarshalBinary()
+	require.NoError(f, err)
+	f.Add(data)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		filter := New(1)
+		if filter.UnmarshalBinary(data) != nil {
+			return
+		}
+		// lookups, additions and deletions must not panic
+		filter.Test([]byte("key1"))
+		filter.Add([]byte("key1"))
+		filter.Delete([]byte("key1"))
+	})
+}
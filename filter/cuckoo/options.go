@@ -0,0 +1,80 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cuckoo
+
+const (
+	defaultBucketSize      = 4
+	defaultFingerprintBits = 16
+	defaultMaxKicks        = 500
+)
+
+// options holds the settings that may be given when creating a filter.
+type options struct {
+	bucketSize      uint
+	fingerprintBits uint
+	maxKicks        uint
+}
+
+// Option configures a filter when it is created.
+type Option func(*options)
+
+// WithBucketSize sets the number of fingerprints each bucket holds,
+// which must be between 1 and 8.  Larger buckets allow a higher load
+// before the filter is full, but make lookups check more fingerprints
+// and so raise the false positive rate.  Defaults to 4.
+func WithBucketSize(size uint) Option {
+	if size < 1 || size > 8 {
+		panic(`cuckoo: bucket size must be between 1 and 8`)
+	}
+	return func(o *options) {
+		o.bucketSize = size
+	}
+}
+
+// WithFingerprintBits sets the length of the fingerprint stored for
+// each key, which must be between 2 and 32 bits.  Every extra bit
+// halves the false positive rate at the cost of memory.  Defaults to
+// 16.
+func WithFingerprintBits(bits uint) Option {
+	if bits < 2 || bits > 32 {
+		panic(`cuckoo: fingerprint bits must be between 2 and 32`)
+	}
+	return func(o *options) {
+		o.fingerprintBits = bits
+	}
+}
+
+// WithMaxKicks sets how many fingerprints Add may relocate to make
+// room for a key before giving up and reporting the filter as full.
+// Defaults to 500.
+func WithMaxKicks(kicks uint) Option {
+	return func(o *options) {
+		o.maxKicks = kicks
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		bucketSize:      defaultBucketSize,
+		fingerprintBits: defaultFingerprintBits,
+		maxKicks:        defaultMaxKicks,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
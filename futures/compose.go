@@ -0,0 +1,80 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package futures
+
+// Then returns a Future completed with the result of applying fn to the
+// value of f once it completes successfully. If f fails, fn is not called
+// and the returned Future fails with the same error. fn is called as a
+// callback of f; see OnComplete.
+func Then[T, U any](f *Future[T], fn func(T) (U, error)) *Future[U] {
+	next := newFuture[U]()
+	f.OnComplete(func(item T, err error) {
+		if err != nil {
+			var zero U
+			next.setItem(zero, err)
+			return
+		}
+		next.setItem(fn(item))
+	})
+	return next
+}
+
+// Map is like Then for transformations that cannot fail.
+func Map[T, U any](f *Future[T], fn func(T) U) *Future[U] {
+	return Then(f, func(item T) (U, error) {
+		return fn(item), nil
+	})
+}
+
+// FlatMap returns a Future completed with the result of the Future that
+// fn returns for the value of f once it completes successfully. If f
+// fails, fn is not called and the returned Future fails with the same
+// error.
+func FlatMap[T, U any](f *Future[T], fn func(T) *Future[U]) *Future[U] {
+	next := newFuture[U]()
+	f.OnComplete(func(item T, err error) {
+		if err != nil {
+			var zero U
+			next.setItem(zero, err)
+			return
+		}
+		fn(item).OnComplete(next.setItem)
+	})
+	return next
+}
+
+// Catch returns a Future that is completed with the result of f if it
+// succeeds. If f fails, fn is called with the error and its result is
+// used instead, allowing the failure to be recovered from or replaced.
+func Catch[T any](f *Future[T], fn func(error) (T, error)) *Future[T] {
+	next := newFuture[T]()
+	f.OnComplete(func(item T, err error) {
+		if err != nil {
+			next.setItem(fn(err))
+			return
+		}
+		next.setItem(item, nil)
+	})
+	return next
+}
+
+// Recover is like Catch for fallbacks that cannot fail.
+func Recover[T any](f *Future[T], fn func(error) T) *Future[T] {
+	return Catch(f, func(err error) (T, error) {
+		return fn(err), nil
+	})
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package futures
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThen(t *testing.T) {
+	promise := NewPromise[string](time.Second)
+	parsed := Then(promise.Future(), strconv.Atoi)
+	doubled := Map(parsed, func(i int) int { return i * 2 })
+
+	promise.Complete("21")
+	result, err := doubled.GetResult()
+	require.NoError(t, err)
+	assert.Equal(t, 42, result)
+
+	promise = NewPromise[string](time.Second)
+	parsed = Then(promise.Future(), strconv.Atoi)
+	promise.Complete("nope")
+	_, err = Map(parsed, func(i int) int { return i * 2 }).GetResult()
+	assert.Error(t, err)
+}
+
+func TestThenPropagatesError(t *testing.T) {
+	failure := errors.New("failed")
+	promise := NewPromise[int](time.Second)
+	called := false
+	next := Then(promise.Future(), func(i int) (string, error) {
+		called = true
+		return "", nil
+	})
+
+	promise.CompleteError(failure)
+	_, err := next.GetResult()
+	assert.Equal(t, failure, err)
+	assert.False(t, called)
+}
+
+func TestFlatMap(t *testing.T) {
+	promise := NewPromise[int](time.Second)
+	next := FlatMap(promise.Future(), func(i int) *Future[string] {
+		return Await(func() (string, error) {
+			return strconv.Itoa(i + 1), nil
+		}, time.Second)
+	})
+
+	promise.Complete(1)
+	result, err := next.GetResult()
+	require.NoError(t, err)
+	assert.Equal(t, "2", result)
+}
+
+func TestCatchAndRecover(t *testing.T) {
+	failure := errors.New("failed")
+	promise := NewPromise[int](time.Second)
+	recovered := Recover(promise.Future(), func(err error) int {
+		assert.Equal(t, failure, err)
+		return -1
+	})
+	replaced := Catch(promise.Future(), func(err error) (int, error) {
+		return 0, errors.New("replaced")
+	})
+
+	promise.CompleteError(failure)
+	result, err := recovered.GetResult()
+	require.NoError(t, err)
+	assert.Equal(t, -1, result)
+	_, err = replaced.GetResult()
+	assert.EqualError(t, err, "replaced")
+
+	promise = NewPromise[int](time.Second)
+	untouched := Recover(promise.Future(), func(err error) int { return -1 })
+	promise.Complete(3)
+	result, err = untouched.GetResult()
+	require.NoError(t, err)
+	assert.Equal(t, 3, result)
+}
@@ -40,108 +40,375 @@ Example usage:
 package futures
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/Workiva/go-datastructures/common"
 )
 
 // Completer is a channel that the future expects to receive a result on.
 type Completer[T any] <-chan T
 
+// Result is the outcome of an asynchronous task: either a value or an
+// error.
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
+// ResultCompleter is a channel that the future expects to receive a
+// Result on. Unlike a Completer, it allows a producer to fail the future
+// with its own error.
+type ResultCompleter[T any] <-chan Result[T]
+
 // Future represents an object that can be used to perform asynchronous tasks.
 // The constructor of the future will complete it, and listeners will block
 // on GetResult until a result is received. This is different from a channel
 // in that the future is only completed once, and anyone listening on the
 // future will get the result, regardless of the number of listeners.
+//
+// A future starts no goroutine or timer until its result is first asked
+// for, by a wait, HasResult, Done or OnComplete. A future fed by a
+// completer channel or a context then starts a goroutine that listens for
+// its result, so a producer sending on an unbuffered completer blocks
+// until that happens; give the completer a buffer of one if producers
+// must not wait. Every other future, such as that of a Promise, is
+// completed directly by its producer: its timeout is only watched by those
+// waiting on it or, once a callback has been registered with OnComplete or
+// Done has been called, by a timer.
 type Future[T any] struct {
-	triggered bool
 	item      T
 	err       error
+	once      sync.Once
+	done      chan struct{}
 	lock      sync.Mutex
-	wg        sync.WaitGroup
+	callbacks []func(T, error)
+	running   bool
+	watch     sync.Once
+	start     sync.Once
+
+	values   <-chan T
+	results  <-chan Result[T]
+	ctx      context.Context
+	timeout  time.Duration
+	deadline time.Time
+}
+
+func newFuture[T any]() *Future[T] {
+	return &Future[T]{done: make(chan struct{})}
+}
+
+// withTimeout fails the future if it has not completed within timeout.
+func (f *Future[T]) withTimeout(timeout time.Duration) *Future[T] {
+	f.timeout = timeout
+	f.deadline = time.Now().Add(timeout)
+	return f
 }
 
 // New creates a new Future that will be completed when a value is received
 // on the completer channel or when the timeout is reached.
 func New[T any](completer Completer[T], timeout time.Duration) *Future[T] {
-	f := &Future[T]{}
-	f.wg.Add(1)
-	var wg sync.WaitGroup
-	wg.Add(1)
-	go listenForResult(f, completer, timeout, &wg)
-	wg.Wait()
+	f := newFuture[T]().withTimeout(timeout)
+	f.values = completer
+	return f
+}
+
+// NewWithResult creates a new Future that will be completed with the
+// value and error of the Result received on the completer channel or
+// when the timeout is reached.
+func NewWithResult[T any](completer ResultCompleter[T], timeout time.Duration) *Future[T] {
+	f := newFuture[T]().withTimeout(timeout)
+	f.results = completer
+	return f
+}
+
+// NewWithContext creates a new Future that will be completed when a value
+// is received on the completer channel or, with the context's error, when
+// the context is done.
+func NewWithContext[T any](ctx context.Context, completer Completer[T]) *Future[T] {
+	f := newFuture[T]()
+	f.values = completer
+	f.ctx = ctx
 	return f
 }
 
+// Done returns a channel that is closed once the future completes. For
+// a future with a timeout but no completer channel, this arms a timer to
+// fail it once the timeout is reached.
+func (f *Future[T]) Done() <-chan struct{} {
+	if !f.isDone() {
+		f.watch.Do(f.startWatching)
+	}
+	return f.done
+}
+
 // GetResult will immediately return the result if it exists,
 // or wait until the result is ready.
 func (f *Future[T]) GetResult() (T, error) {
-	f.lock.Lock()
-	if f.triggered {
-		item, err := f.item, f.err
-		f.lock.Unlock()
-		return item, err
+	f.wait(nil)
+	return f.item, f.err
+}
+
+// GetResultContext is like GetResult, but stops waiting and returns the
+// context's error if the context is done first. The future itself is
+// unaffected and may still complete later.
+func (f *Future[T]) GetResultContext(ctx context.Context) (T, error) {
+	if !f.wait(ctx.Done()) {
+		var zero T
+		return zero, ctx.Err()
 	}
-	f.lock.Unlock()
+	return f.item, f.err
+}
 
-	f.wg.Wait()
+// GetResultTimeout is like GetResult, but stops waiting and returns a
+// timeout error if the result is not available within timeout. The
+// future itself is unaffected and may still complete later.
+func (f *Future[T]) GetResultTimeout(timeout time.Duration) (T, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if !f.wait(ctx.Done()) {
+		var zero T
+		return zero, timeoutError("Future.GetResultTimeout", timeout)
+	}
 	return f.item, f.err
 }
 
 // HasResult returns true if the result is available.
 func (f *Future[T]) HasResult() bool {
-	f.lock.Lock()
-	hasResult := f.triggered
-	f.lock.Unlock()
-	return hasResult
+	if f.isDone() {
+		return true
+	}
+	if f.listening() {
+		f.begin()
+		return f.isDone()
+	}
+	if !f.expired() {
+		return false
+	}
+	f.expire()
+	return true
 }
 
-func (f *Future[T]) setItem(item T, err error) {
+// OnComplete registers fn to be called with the result of the future.
+// Callbacks are called one at a time, in registration order, and each
+// exactly once. Those registered before the future completes are called
+// on a goroutine of their own once it does. A callback registered
+// afterwards is called immediately on the calling goroutine, unless
+// earlier callbacks are still being called, in which case it is called
+// after them.
+func (f *Future[T]) OnComplete(fn func(T, error)) {
 	f.lock.Lock()
-	f.triggered = true
-	f.item = item
-	f.err = err
+	f.callbacks = append(f.callbacks, fn)
+	if !f.isDone() {
+		f.lock.Unlock()
+		f.watch.Do(f.startWatching)
+		return
+	}
+	if f.running {
+		f.lock.Unlock()
+		return
+	}
+	f.running = true
 	f.lock.Unlock()
-	f.wg.Done()
+	f.runCallbacks()
 }
 
-func listenForResult[T any](f *Future[T], ch Completer[T], timeout time.Duration, wg *sync.WaitGroup) {
-	wg.Done()
-	t := time.NewTimer(timeout)
+// runCallbacks calls the registered callbacks in order until none are
+// left. Only one goroutine runs them at a time, the one that set running.
+func (f *Future[T]) runCallbacks() {
+	for {
+		f.lock.Lock()
+		callbacks := f.callbacks
+		f.callbacks = nil
+		if len(callbacks) == 0 {
+			f.running = false
+			f.lock.Unlock()
+			return
+		}
+		f.lock.Unlock()
+
+		for _, fn := range callbacks {
+			fn(f.item, f.err)
+		}
+	}
+}
+
+// startWatching ensures the future completes, or fails once its timeout
+// is reached, even if nobody waits on it.
+func (f *Future[T]) startWatching() {
+	if f.listening() {
+		f.begin()
+	} else if !f.deadline.IsZero() {
+		time.AfterFunc(time.Until(f.deadline), f.expire)
+	}
+}
+
+// listening returns true if the future has a source of completion that
+// needs a goroutine listening on it.
+func (f *Future[T]) listening() bool {
+	return f.values != nil || f.results != nil || f.ctx != nil
+}
+
+// begin starts the goroutine listening for the result, if the future
+// needs one and it has not already been started.
+func (f *Future[T]) begin() {
+	f.start.Do(func() {
+		if f.listening() {
+			go f.listen()
+		}
+	})
+}
+
+// listen completes the future with the first of its sources of
+// completion to be ready.
+func (f *Future[T]) listen() {
+	var expired <-chan time.Time
+	if !f.deadline.IsZero() {
+		t := time.NewTimer(time.Until(f.deadline))
+		defer t.Stop()
+		expired = t.C
+	}
+
 	select {
-	case item := <-ch:
-		f.setItem(item, nil)
-		t.Stop()
-	case <-t.C:
-		var zero T
-		f.setItem(zero, fmt.Errorf("timeout after %f seconds", timeout.Seconds()))
+	case item := <-f.values:
+		f.complete(item, nil)
+	case result := <-f.results:
+		f.complete(result.Value, result.Err)
+	case <-f.ctxDone():
+		f.cancel()
+	case <-expired:
+		f.expire()
+	}
+}
+
+// wait blocks until the future completes, returning false if cancel is
+// closed first. A future without a listener is failed by the first
+// waiter to see its timeout reached.
+func (f *Future[T]) wait(cancel <-chan struct{}) bool {
+	if f.isDone() {
+		return true
+	}
+	f.begin()
+
+	var expired <-chan time.Time
+	if !f.listening() && !f.deadline.IsZero() {
+		t := time.NewTimer(time.Until(f.deadline))
+		defer t.Stop()
+		expired = t.C
 	}
+
+	select {
+	case <-f.done:
+	case <-expired:
+		f.expire()
+	case <-cancel:
+		return false
+	}
+	return true
+}
+
+func (f *Future[T]) isDone() bool {
+	select {
+	case <-f.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (f *Future[T]) ctxDone() <-chan struct{} {
+	if f.ctx == nil {
+		return nil
+	}
+	return f.ctx.Done()
+}
+
+// expired returns true if the future's timeout has been reached.
+func (f *Future[T]) expired() bool {
+	return !f.deadline.IsZero() && !time.Now().Before(f.deadline)
+}
+
+// complete completes the future with a result from its producer, unless
+// the timeout has already been reached, in which case it fails as if the
+// result had never arrived.
+func (f *Future[T]) complete(item T, err error) {
+	if f.expired() {
+		f.expire()
+		return
+	}
+	f.setItem(item, err)
+}
+
+// cancel fails the future with the error of its context.
+func (f *Future[T]) cancel() {
+	var zero T
+	f.setItem(zero, f.ctx.Err())
+}
+
+// expire fails the future because its timeout was reached.
+func (f *Future[T]) expire() {
+	var zero T
+	f.setItem(zero, timeoutError("Future", f.timeout))
+}
+
+// timeoutError returns an error matching common.ErrTimeout that names
+// the operation and the timeout it was given.
+func timeoutError(op string, timeout time.Duration) error {
+	return &common.OpError{Op: op, Timeout: timeout, Err: common.ErrTimeout}
+}
+
+// setItem completes the future. Only the first call has any effect.
+func (f *Future[T]) setItem(item T, err error) {
+	f.once.Do(func() {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+
+		f.item = item
+		f.err = err
+		close(f.done)
+		if len(f.callbacks) > 0 {
+			f.running = true
+			go f.runCallbacks()
+		}
+	})
 }
 
 // Promise provides a way to complete a Future from the producer side.
 // It wraps a Future and provides a Complete method.
 type Promise[T any] struct {
-	future    *Future[T]
-	completer chan T
-	once      sync.Once
+	future *Future[T]
 }
 
 // NewPromise creates a new Promise with the given timeout.
 func NewPromise[T any](timeout time.Duration) *Promise[T] {
-	completer := make(chan T, 1)
-	return &Promise[T]{
-		future:    New(completer, timeout),
-		completer: completer,
-	}
+	return &Promise[T]{future: newFuture[T]().withTimeout(timeout)}
+}
+
+// NewPromiseNoTimeout creates a new Promise whose future only completes
+// when the promise is completed. Listeners may bound their own wait with
+// GetResultTimeout or GetResultContext.
+func NewPromiseNoTimeout[T any]() *Promise[T] {
+	return &Promise[T]{future: newFuture[T]()}
 }
 
 // Complete completes the promise with the given value.
-// Calling Complete multiple times has no effect after the first call.
+// Calling Complete or CompleteError multiple times has no effect after
+// the first call.
 func (p *Promise[T]) Complete(value T) {
-	p.once.Do(func() {
-		p.completer <- value
-		close(p.completer)
-	})
+	p.future.complete(value, nil)
+}
+
+// CompleteError fails the promise with the given error, which every
+// listener of the future will receive.
+// Calling Complete or CompleteError multiple times has no effect after
+// the first call.
+func (p *Promise[T]) CompleteError(err error) {
+	var zero T
+	p.future.complete(zero, err)
 }
 
 // Future returns the Future associated with this Promise.
@@ -152,20 +419,10 @@ func (p *Promise[T]) Future() *Future[T] {
 // Await is a convenience function that creates a Future from a function
 // that returns a value and an error.
 func Await[T any](fn func() (T, error), timeout time.Duration) *Future[T] {
-	completer := make(chan T, 1)
-	f := New(completer, timeout)
-
+	f := newFuture[T]().withTimeout(timeout)
 	go func() {
-		result, err := fn()
-		if err != nil {
-			var zero T
-			f.setItem(zero, err)
-		} else {
-			completer <- result
-		}
-		close(completer)
+		f.complete(fn())
 	}()
-
 	return f
 }
 
@@ -183,6 +440,34 @@ func All[T any](futures ...*Future[T]) ([]T, error) {
 	return results, nil
 }
 
+// AllWait waits for all futures to complete, even once one has failed,
+// and returns their results. Failed futures leave the zero value at
+// their position, and their errors are combined with errors.Join in
+// the order the futures were provided.
+func AllWait[T any](futures ...*Future[T]) ([]T, error) {
+	results := make([]T, len(futures))
+	var errs []error
+	for i, f := range futures {
+		result, err := f.GetResult()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		results[i] = result
+	}
+	return results, errors.Join(errs...)
+}
+
+// AllSettled waits for all futures to complete and returns the result of
+// each, including its individual error, in the order provided.
+func AllSettled[T any](futures ...*Future[T]) []Result[T] {
+	results := make([]Result[T], len(futures))
+	for i, f := range futures {
+		results[i].Value, results[i].Err = f.GetResult()
+	}
+	return results
+}
+
 // Race returns the result of the first future to complete.
 func Race[T any](futures ...*Future[T]) (T, error) {
 	if len(futures) == 0 {
@@ -190,24 +475,49 @@ func Race[T any](futures ...*Future[T]) (T, error) {
 		return zero, fmt.Errorf("no futures provided")
 	}
 
-	done := make(chan struct {
-		result T
-		err    error
-	}, 1)
+	i, _ := WaitAny(context.Background(), waiters(futures)...)
+	return futures[i].GetResult()
+}
+
+// Any returns the result of the first future to complete successfully.
+// An error is only returned if every future fails, in which case the
+// errors are combined with errors.Join in the order the futures were
+// provided.
+func Any[T any](futures ...*Future[T]) (T, error) {
+	if len(futures) == 0 {
+		var zero T
+		return zero, fmt.Errorf("no futures provided")
+	}
+
+	errs := make([]error, len(futures))
+	remaining := make([]int, len(futures))
+	for i := range remaining {
+		remaining[i] = i
+	}
+
+	for len(remaining) > 0 {
+		pending := make([]*Future[T], len(remaining))
+		for i, idx := range remaining {
+			pending[i] = futures[idx]
+		}
 
-	var once sync.Once
-	for _, f := range futures {
-		go func(f *Future[T]) {
-			result, err := f.GetResult()
-			once.Do(func() {
-				done <- struct {
-					result T
-					err    error
-				}{result, err}
-			})
-		}(f)
+		i, _ := WaitAny(context.Background(), waiters(pending)...)
+		result, err := pending[i].GetResult()
+		if err == nil {
+			return result, nil
+		}
+		errs[remaining[i]] = err
+		remaining = append(remaining[:i], remaining[i+1:]...)
 	}
 
-	r := <-done
-	return r.result, r.err
+	var zero T
+	return zero, errors.Join(errs...)
+}
+
+func waiters[T any](futures []*Future[T]) []Waiter {
+	ws := make([]Waiter, len(futures))
+	for i, f := range futures {
+		ws[i] = f
+	}
+	return ws
 }
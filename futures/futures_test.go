@@ -17,11 +17,16 @@ limitations under the License.
 package futures
 
 import (
+	"context"
+	"errors"
+	"runtime"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/Workiva/go-datastructures/common"
 )
 
 func TestFutureGetResult(t *testing.T) {
@@ -136,3 +141,376 @@ func TestRace(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "first", result)
 }
+
+func TestNewWithContext(t *testing.T) {
+	completer := make(chan string, 1)
+	future := NewWithContext[string](context.Background(), completer)
+
+	completer <- "hello"
+	result, err := future.GetResult()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", result)
+}
+
+func TestNewWithContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	completer := make(chan string, 1)
+	future := NewWithContext[string](ctx, completer)
+
+	cancel()
+	_, err := future.GetResult()
+	assert.Equal(t, context.Canceled, err)
+
+	// the recorded result does not change once completed
+	completer <- "late"
+	time.Sleep(10 * time.Millisecond)
+	_, err = future.GetResult()
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestGetResultContext(t *testing.T) {
+	completer := make(chan int, 1)
+	future := New[int](completer, time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := future.GetResultContext(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.False(t, future.HasResult())
+
+	completer <- 42
+	result, err := future.GetResultContext(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 42, result)
+}
+
+func TestNewWithResult(t *testing.T) {
+	completer := make(chan Result[int], 1)
+	future := NewWithResult[int](completer, time.Second)
+
+	failure := errors.New("failed")
+	completer <- Result[int]{Err: failure}
+	_, err := future.GetResult()
+	assert.Equal(t, failure, err)
+
+	completer = make(chan Result[int], 1)
+	future = NewWithResult[int](completer, time.Second)
+	completer <- Result[int]{Value: 7}
+	result, err := future.GetResult()
+	require.NoError(t, err)
+	assert.Equal(t, 7, result)
+}
+
+func TestPromiseCompleteError(t *testing.T) {
+	promise := NewPromise[string](time.Second)
+	failure := errors.New("failed")
+
+	results := make(chan error, 3)
+	for range 3 {
+		go func() {
+			_, err := promise.Future().GetResult()
+			results <- err
+		}()
+	}
+
+	promise.CompleteError(failure)
+	promise.Complete("ignored")
+	for range 3 {
+		assert.Equal(t, failure, <-results)
+	}
+}
+
+func TestAwaitError(t *testing.T) {
+	failure := errors.New("failed")
+	future := Await(func() (int, error) {
+		return 0, failure
+	}, time.Second)
+
+	_, err := future.GetResult()
+	assert.Equal(t, failure, err)
+
+	future = Await(func() (int, error) {
+		return 5, nil
+	}, time.Second)
+	result, err := future.GetResult()
+	require.NoError(t, err)
+	assert.Equal(t, 5, result)
+}
+
+func TestAllWait(t *testing.T) {
+	first, second := errors.New("first"), errors.New("second")
+	p1, p2, p3 := NewPromise[int](time.Second), NewPromise[int](time.Second), NewPromise[int](time.Second)
+
+	p1.CompleteError(first)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		p2.Complete(2)
+		p3.CompleteError(second)
+	}()
+
+	results, err := AllWait(p1.Future(), p2.Future(), p3.Future())
+	assert.Equal(t, []int{0, 2, 0}, results)
+	assert.ErrorIs(t, err, first)
+	assert.ErrorIs(t, err, second)
+	assert.True(t, p3.Future().HasResult())
+
+	results, err = AllWait(p2.Future())
+	require.NoError(t, err)
+	assert.Equal(t, []int{2}, results)
+}
+
+func TestAllSettled(t *testing.T) {
+	failure := errors.New("failed")
+	p1, p2 := NewPromise[string](time.Second), NewPromise[string](time.Second)
+	p1.Complete("ok")
+	p2.CompleteError(failure)
+
+	results := AllSettled(p1.Future(), p2.Future())
+	assert.Equal(t, []Result[string]{{Value: "ok"}, {Err: failure}}, results)
+	assert.Empty(t, AllSettled[string]())
+}
+
+func TestAny(t *testing.T) {
+	p1, p2, p3 := NewPromise[int](time.Second), NewPromise[int](time.Second), NewPromise[int](time.Second)
+	p1.CompleteError(errors.New("first"))
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		p3.Complete(3)
+	}()
+
+	// p2 never completes, but p3 succeeds
+	result, err := Any(p1.Future(), p2.Future(), p3.Future())
+	require.NoError(t, err)
+	assert.Equal(t, 3, result)
+}
+
+func TestAnyAllFail(t *testing.T) {
+	first, second := errors.New("first"), errors.New("second")
+	p1, p2 := NewPromise[int](time.Second), NewPromise[int](time.Second)
+	p1.CompleteError(first)
+	p2.CompleteError(second)
+
+	_, err := Any(p1.Future(), p2.Future())
+	assert.ErrorIs(t, err, first)
+	assert.ErrorIs(t, err, second)
+	assert.EqualError(t, err, "first\nsecond")
+
+	_, err = Any[int]()
+	assert.Error(t, err)
+}
+
+func TestOnComplete(t *testing.T) {
+	promise := NewPromise[int](time.Second)
+	future := promise.Future()
+
+	calls := make(chan int, 3)
+	for i := range 3 {
+		future.OnComplete(func(result int, err error) {
+			assert.NoError(t, err)
+			assert.Equal(t, 42, result)
+			calls <- i
+		})
+	}
+
+	promise.Complete(42)
+	for i := range 3 {
+		assert.Equal(t, i, <-calls)
+	}
+
+	// registered after completion and once the earlier callbacks have
+	// returned, so called immediately
+	assert.Eventually(t, func() bool {
+		future.lock.Lock()
+		defer future.lock.Unlock()
+		return !future.running
+	}, time.Second, time.Millisecond)
+	called := false
+	future.OnComplete(func(result int, err error) {
+		called = true
+	})
+	assert.True(t, called)
+}
+
+func TestOnCompleteOrderAcrossCompletion(t *testing.T) {
+	promise := NewPromise[int](time.Second)
+	future := promise.Future()
+
+	release := make(chan struct{})
+	var order []int
+	future.OnComplete(func(int, error) {
+		<-release
+		order = append(order, 0)
+	})
+	promise.Complete(1)
+
+	registered := make(chan struct{})
+	go func() {
+		future.OnComplete(func(int, error) {
+			order = append(order, 1)
+		})
+		close(registered)
+	}()
+	<-registered
+	close(release)
+
+	done := make(chan struct{})
+	future.OnComplete(func(int, error) {
+		order = append(order, 2)
+		close(done)
+	})
+	<-done
+	assert.Equal(t, []int{0, 1, 2}, order)
+}
+
+func TestOnCompleteError(t *testing.T) {
+	completer := make(chan int)
+	future := New[int](completer, 10*time.Millisecond)
+
+	errs := make(chan error, 1)
+	future.OnComplete(func(_ int, err error) {
+		errs <- err
+	})
+	assert.Error(t, <-errs)
+}
+
+func TestPromisesStartNoGoroutines(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	promises := make([]*Promise[int], 0, 1000)
+	for range cap(promises) {
+		promises = append(promises, NewPromise[int](time.Minute))
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+
+	promises[0].Complete(1)
+	result, err := promises[0].Future().GetResult()
+	require.NoError(t, err)
+	assert.Equal(t, 1, result)
+}
+
+func TestFuturesStartNoGoroutines(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	completers := make([]chan int, 1000)
+	futures := make([]*Future[int], 0, 2*len(completers))
+	for i := range completers {
+		completers[i] = make(chan int, 1)
+		futures = append(futures,
+			New[int](completers[i], time.Minute),
+			NewWithContext[int](context.Background(), completers[i]),
+		)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+
+	completers[0] <- 1
+	result, err := futures[0].GetResult()
+	require.NoError(t, err)
+	assert.Equal(t, 1, result)
+}
+
+func TestUnbufferedCompleterWaitsForListener(t *testing.T) {
+	completer := make(chan int)
+	future := New[int](completer, time.Minute)
+
+	select {
+	case completer <- 1:
+		t.Fatal("send on the completer was received without a waiter")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	assert.False(t, future.HasResult())
+	completer <- 1
+	result, err := future.GetResult()
+	require.NoError(t, err)
+	assert.Equal(t, 1, result)
+}
+
+func TestResultAfterTimeout(t *testing.T) {
+	for range 20 {
+		completer := make(chan int, 1)
+		future := New[int](completer, time.Millisecond)
+		promise := NewPromise[int](time.Millisecond)
+
+		time.Sleep(2 * time.Millisecond)
+		completer <- 1
+		promise.Complete(1)
+
+		assert.True(t, promise.Future().HasResult())
+		_, err := promise.Future().GetResult()
+		assert.Error(t, err)
+		_, err = future.GetResult()
+		assert.Error(t, err)
+	}
+}
+
+func TestOnCompleteWithoutWaiters(t *testing.T) {
+	completer := make(chan int, 1)
+	future := New[int](completer, time.Minute)
+
+	results := make(chan int, 1)
+	future.OnComplete(func(result int, _ error) {
+		results <- result
+	})
+	completer <- 5
+	assert.Equal(t, 5, <-results)
+
+	promise := NewPromise[int](10 * time.Millisecond)
+	errs := make(chan error, 1)
+	promise.Future().OnComplete(func(_ int, err error) {
+		errs <- err
+	})
+	assert.Error(t, <-errs)
+}
+
+func BenchmarkNew(b *testing.B) {
+	completer := make(chan int)
+	before := runtime.NumGoroutine()
+	b.ReportAllocs()
+	for b.Loop() {
+		New[int](completer, time.Minute)
+	}
+	b.ReportMetric(float64(runtime.NumGoroutine()-before)/float64(b.N), "goroutines/op")
+}
+
+func BenchmarkNewAndComplete(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		completer := make(chan int, 1)
+		f := New[int](completer, time.Minute)
+		completer <- 1
+		f.GetResult()
+	}
+}
+
+func BenchmarkPromise(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		p := NewPromise[int](time.Minute)
+		p.Complete(1)
+		p.Future().GetResult()
+	}
+}
+
+func TestNewPromiseNoTimeout(t *testing.T) {
+	promise := NewPromiseNoTimeout[string]()
+	future := promise.Future()
+
+	_, err := future.GetResultTimeout(10 * time.Millisecond)
+	assert.ErrorIs(t, err, common.ErrTimeout)
+	assert.Contains(t, err.Error(), "timeout")
+	assert.False(t, future.HasResult())
+
+	promise.Complete("done")
+	result, err := future.GetResultTimeout(time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "done", result)
+}
+
+func TestGetResultTimeoutReturnsFutureError(t *testing.T) {
+	completer := make(chan int)
+	future := New[int](completer, 10*time.Millisecond)
+
+	_, err := future.GetResultTimeout(time.Second)
+	assert.Error(t, err)
+	assert.True(t, future.HasResult())
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package futures
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Group runs tasks on a bounded number of worker goroutines, returning a
+// Future for each. The first task to fail cancels the context passed to
+// every other task, and Wait waits for all of them to complete.
+type Group[T any] struct {
+	ctx     context.Context
+	cancel  context.CancelCauseFunc
+	limit   int
+	workers int
+	queue   []groupTask[T]
+	wg      sync.WaitGroup
+	lock    sync.Mutex
+	tasks   []*Future[T]
+	errs    []error
+}
+
+// groupTask is a task waiting for a worker, along with its position
+// among the tasks submitted.
+type groupTask[T any] struct {
+	fn    func(ctx context.Context) (T, error)
+	index int
+}
+
+// NewGroup creates a Group whose tasks are derived from ctx and which
+// runs them on at most limit goroutines. A limit of zero or less is
+// unbounded.
+func NewGroup[T any](ctx context.Context, limit int) *Group[T] {
+	g := &Group[T]{limit: limit}
+	g.ctx, g.cancel = context.WithCancelCause(ctx)
+	return g
+}
+
+// Go submits fn to the group and returns a future of its result. Go
+// does not block: fn is queued and run once a worker is free, a worker
+// being started for it if fewer than limit are running. If the group's
+// context is done before then, fn is never called and the future fails
+// with the context's cause.
+func (g *Group[T]) Go(fn func(ctx context.Context) (T, error)) *Future[T] {
+	f := newFuture[T]()
+	g.wg.Add(1)
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	g.queue = append(g.queue, groupTask[T]{fn: fn, index: len(g.tasks)})
+	g.tasks = append(g.tasks, f)
+	g.errs = append(g.errs, nil)
+	if g.limit <= 0 || g.workers < g.limit {
+		g.workers++
+		go g.work()
+	}
+	return f
+}
+
+// work runs queued tasks until the queue is empty.
+func (g *Group[T]) work() {
+	for {
+		g.lock.Lock()
+		if len(g.queue) == 0 {
+			g.workers--
+			g.lock.Unlock()
+			return
+		}
+		task := g.queue[0]
+		g.queue[0] = groupTask[T]{}
+		g.queue = g.queue[1:]
+		f := g.tasks[task.index]
+		g.lock.Unlock()
+
+		g.run(task, f)
+	}
+}
+
+func (g *Group[T]) run(task groupTask[T], f *Future[T]) {
+	defer g.wg.Done()
+
+	if g.ctx.Err() != nil {
+		var zero T
+		f.setItem(zero, context.Cause(g.ctx))
+		return
+	}
+
+	item, err := task.fn(g.ctx)
+	if err != nil {
+		g.lock.Lock()
+		g.errs[task.index] = err
+		g.lock.Unlock()
+		g.cancel(err)
+	}
+	f.setItem(item, err)
+}
+
+// Wait waits for every task submitted so far and returns their results
+// in the order submitted. Failed tasks leave the zero value at their
+// position, and the errors they returned are combined with errors.Join
+// in the same order. If no task failed but some were never started
+// because the group's context was done, its cause is returned. Wait
+// cancels the group's context, so tasks submitted afterwards fail.
+func (g *Group[T]) Wait() ([]T, error) {
+	g.wg.Wait()
+	defer g.cancel(context.Canceled)
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	results := make([]T, len(g.tasks))
+	var cancelled bool
+	for i, f := range g.tasks {
+		result, err := f.GetResult()
+		if err != nil {
+			cancelled = cancelled || g.errs[i] == nil
+			continue
+		}
+		results[i] = result
+	}
+
+	if err := errors.Join(g.errs...); err != nil {
+		return results, err
+	}
+	if cancelled {
+		return results, context.Cause(g.ctx)
+	}
+	return results, nil
+}
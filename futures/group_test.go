@@ -0,0 +1,145 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package futures
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup(t *testing.T) {
+	g := NewGroup[int](context.Background(), 2)
+
+	var running, peak int32
+	futures := make([]*Future[int], 0, 6)
+	for i := 0; i < 6; i++ {
+		i := i
+		futures = append(futures, g.Go(func(ctx context.Context) (int, error) {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return i * i, nil
+		}))
+	}
+
+	results, err := g.Wait()
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 4, 9, 16, 25}, results)
+	assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(2))
+
+	result, err := futures[3].GetResult()
+	require.NoError(t, err)
+	assert.Equal(t, 9, result)
+}
+
+func TestGroupBoundsGoroutines(t *testing.T) {
+	g := NewGroup[int](context.Background(), 2)
+	before := runtime.NumGoroutine()
+
+	release := make(chan struct{})
+	for range 100 {
+		g.Go(func(ctx context.Context) (int, error) {
+			<-release
+			return 0, nil
+		})
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before+2)
+
+	close(release)
+	_, err := g.Wait()
+	require.NoError(t, err)
+}
+
+func TestGroupError(t *testing.T) {
+	g := NewGroup[int](context.Background(), 1)
+	failure := errors.New("failed")
+
+	first := g.Go(func(ctx context.Context) (int, error) {
+		return 0, failure
+	})
+	_, err := first.GetResult()
+	assert.Equal(t, failure, err)
+
+	second := g.Go(func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+
+	results, err := g.Wait()
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, []int{0, 0}, results)
+
+	_, err = second.GetResult()
+	assert.Equal(t, failure, err)
+}
+
+func TestGroupCancelsRunningTasks(t *testing.T) {
+	g := NewGroup[int](context.Background(), 0)
+	failure := errors.New("failed")
+
+	started := make(chan struct{})
+	g.Go(func(ctx context.Context) (int, error) {
+		close(started)
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	<-started
+	g.Go(func(ctx context.Context) (int, error) {
+		return 0, failure
+	})
+
+	_, err := g.Wait()
+	assert.ErrorIs(t, err, failure)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestGroupContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := NewGroup[int](ctx, 1)
+
+	started := make(chan struct{})
+	g.Go(func(ctx context.Context) (int, error) {
+		close(started)
+		<-ctx.Done()
+		return 1, nil
+	})
+	<-started
+	skipped := g.Go(func(ctx context.Context) (int, error) {
+		t.Error("task should not run")
+		return 0, nil
+	})
+	cancel()
+
+	results, err := g.Wait()
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, []int{1, 0}, results)
+
+	_, err = skipped.GetResult()
+	assert.Equal(t, context.Canceled, err)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package futures
+
+import "time"
+
+// Backoff returns how long to wait before the next attempt, given the
+// number of attempts that have failed so far.
+type Backoff func(failures int) time.Duration
+
+// ConstantBackoff waits the same delay before every retry.
+func ConstantBackoff(delay time.Duration) Backoff {
+	return func(int) time.Duration {
+		return delay
+	}
+}
+
+// ExponentialBackoff doubles the delay after every failure, starting at
+// base and never exceeding max.
+func ExponentialBackoff(base, max time.Duration) Backoff {
+	return func(failures int) time.Duration {
+		delay := base
+		for i := 1; i < failures && delay < max; i++ {
+			delay *= 2
+		}
+		if delay > max {
+			return max
+		}
+		return delay
+	}
+}
+
+// AwaitRetry is like Await, but calls fn up to attempts times until it
+// succeeds, waiting between attempts as given by backoff. If every
+// attempt fails, the future fails with the error of the last one. The
+// timeout covers all of the attempts: no retry is started that could
+// not begin before it, and the future times out as usual once it is
+// reached.
+func AwaitRetry[T any](fn func() (T, error), attempts int, backoff Backoff, timeout time.Duration) *Future[T] {
+	f := newFuture[T]().withTimeout(timeout)
+	go func() {
+		for failures := 1; ; failures++ {
+			item, err := fn()
+			if err == nil || failures >= attempts {
+				f.complete(item, err)
+				return
+			}
+
+			var delay time.Duration
+			if backoff != nil {
+				delay = backoff(failures)
+			}
+			if delay >= time.Until(f.deadline) {
+				f.expire()
+				return
+			}
+
+			t := time.NewTimer(delay)
+			select {
+			case <-t.C:
+			case <-f.done:
+				t.Stop()
+				return
+			}
+		}
+	}()
+	return f
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package futures
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAwaitRetry(t *testing.T) {
+	calls := 0
+	future := AwaitRetry(func() (int, error) {
+		calls++
+		if calls < 3 {
+			return 0, errors.New("not yet")
+		}
+		return calls, nil
+	}, 5, ConstantBackoff(time.Millisecond), time.Second)
+
+	result, err := future.GetResult()
+	require.NoError(t, err)
+	assert.Equal(t, 3, result)
+}
+
+func TestAwaitRetryExhausted(t *testing.T) {
+	calls := 0
+	future := AwaitRetry(func() (int, error) {
+		calls++
+		return 0, fmt.Errorf("attempt %d", calls)
+	}, 3, nil, time.Second)
+
+	_, err := future.GetResult()
+	assert.EqualError(t, err, "attempt 3")
+	assert.Equal(t, 3, calls)
+}
+
+func TestAwaitRetryTimeout(t *testing.T) {
+	calls := 0
+	future := AwaitRetry(func() (int, error) {
+		calls++
+		return 0, errors.New("failed")
+	}, 10, ConstantBackoff(time.Hour), 50*time.Millisecond)
+
+	_, err := future.GetResult()
+	assert.Contains(t, err.Error(), "timeout")
+	assert.Equal(t, 1, calls)
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(time.Millisecond, 5*time.Millisecond)
+
+	assert.Equal(t, time.Millisecond, backoff(1))
+	assert.Equal(t, 2*time.Millisecond, backoff(2))
+	assert.Equal(t, 4*time.Millisecond, backoff(3))
+	assert.Equal(t, 5*time.Millisecond, backoff(4))
+	assert.Equal(t, 5*time.Millisecond, backoff(40))
+}
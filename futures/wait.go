@@ -0,0 +1,59 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package futures
+
+import (
+	"context"
+	"reflect"
+)
+
+// Waiter is anything that signals completion by closing a channel. Every
+// *Future is a Waiter regardless of its result type.
+type Waiter interface {
+	Done() <-chan struct{}
+}
+
+// WaitAny blocks until one of the waiters completes and returns its
+// index. If several have completed, the lowest index is returned. If the
+// context is done first, -1 and the context's error are returned.
+// WaitAny starts no goroutines: it selects directly on the waiters' Done
+// channels. For futures, see Done for what obtaining one involves.
+func WaitAny(ctx context.Context, waiters ...Waiter) (int, error) {
+	cases := make([]reflect.SelectCase, 0, len(waiters)+1)
+	for i, w := range waiters {
+		done := w.Done()
+		select {
+		case <-done:
+			return i, nil
+		default:
+		}
+		cases = append(cases, reflect.SelectCase{
+			Dir:  reflect.SelectRecv,
+			Chan: reflect.ValueOf(done),
+		})
+	}
+	cases = append(cases, reflect.SelectCase{
+		Dir:  reflect.SelectRecv,
+		Chan: reflect.ValueOf(ctx.Done()),
+	})
+
+	chosen, _, _ := reflect.Select(cases)
+	if chosen == len(waiters) {
+		return -1, ctx.Err()
+	}
+	return chosen, nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package futures
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitAny(t *testing.T) {
+	strs := NewPromiseNoTimeout[string]()
+	ints := make(chan int, 1)
+	intFuture := New[int](ints, time.Minute)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		ints <- 1
+	}()
+
+	i, err := WaitAny(context.Background(), strs.Future(), intFuture)
+	require.NoError(t, err)
+	assert.Equal(t, 1, i)
+	assert.True(t, intFuture.HasResult())
+
+	strs.Complete("done")
+	i, err = WaitAny(context.Background(), strs.Future(), intFuture)
+	require.NoError(t, err)
+	assert.Equal(t, 0, i)
+}
+
+func TestWaitAnyContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	i, err := WaitAny(ctx, NewPromiseNoTimeout[int]().Future())
+	assert.Equal(t, -1, i)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	i, err = WaitAny(ctx)
+	assert.Equal(t, -1, i)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestDoneTimesOut(t *testing.T) {
+	promise := NewPromise[int](10 * time.Millisecond)
+
+	<-promise.Future().Done()
+	_, err := promise.Future().GetResult()
+	assert.Error(t, err)
+}
+
+func TestWaitAnyStartsNoGoroutines(t *testing.T) {
+	waiters := make([]Waiter, 0, 300)
+	for range 100 {
+		waiters = append(waiters,
+			NewPromise[int](time.Minute).Future(),
+			NewPromiseNoTimeout[string]().Future(),
+			New[int](make(chan int), time.Minute),
+		)
+	}
+	// futures fed by a channel start their listener on the first call
+	// to Done, which WaitAny must not add to
+	for _, w := range waiters {
+		w.Done()
+	}
+	before := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	i, err := WaitAny(ctx, waiters...)
+	assert.Equal(t, -1, i)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	// the context's timer may briefly run a goroutine of its own
+	for deadline := time.Now().Add(time.Second); runtime.NumGoroutine() > before && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+}
@@ -0,0 +1,383 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrCycle is returned when an operation that requires an acyclic graph
+// is requested on a graph with a cycle.
+var ErrCycle = errors.New("graph contains a cycle")
+
+// edge is an edge to the node at index to.
+type edge struct {
+	to     int
+	weight float64
+}
+
+// Directed is a mutable, non-persistent directed graph whose nodes are
+// values of any comparable type.  Self loops are permitted, parallel
+// edges are not.  Nodes and the edges leaving each node are kept in the
+// order they were added, so every traversal, and so the result of every
+// algorithm, is deterministic.
+type Directed[N comparable] struct {
+	mutex   sync.RWMutex
+	indices map[N]int
+	nodes   []N
+	out     [][]edge
+	in      [][]int
+	edges   map[[2]int]struct{}
+}
+
+// NewDirected creates and returns an empty Directed graph.
+func NewDirected[N comparable]() *Directed[N] {
+	return &Directed[N]{
+		indices: make(map[N]int),
+		edges:   make(map[[2]int]struct{}),
+	}
+}
+
+// V returns the number of nodes in the graph.
+func (g *Directed[N]) V() int {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	return len(g.nodes)
+}
+
+// E returns the number of edges in the graph.
+func (g *Directed[N]) E() int {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	return len(g.edges)
+}
+
+// AddNode adds the node to the graph, returning false if it was
+// already there.
+func (g *Directed[N]) AddNode(n N) bool {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	_, added := g.addNode(n)
+	return added
+}
+
+// AddEdge creates an edge of weight 1 from one node to another, adding
+// either node if it is not yet in the graph.
+func (g *Directed[N]) AddEdge(from, to N) error {
+	return g.addEdge(from, to, 1)
+}
+
+// AddWeightedEdge creates an edge of the given weight from one node to
+// another, adding either node if it is not yet in the graph.  Weights
+// must not be negative.
+func (g *Directed[N]) AddWeightedEdge(from, to N, weight float64) error {
+	if !(weight >= 0) {
+		return ErrNegativeWeight
+	}
+	return g.addEdge(from, to, weight)
+}
+
+func (g *Directed[N]) addEdge(from, to N, weight float64) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	v, _ := g.addNode(from)
+	w, _ := g.addNode(to)
+	if _, ok := g.edges[[2]int{v, w}]; ok {
+		return ErrParallelEdge
+	}
+
+	g.edges[[2]int{v, w}] = struct{}{}
+	g.out[v] = append(g.out[v], edge{to: w, weight: weight})
+	g.in[w] = append(g.in[w], v)
+	return nil
+}
+
+// HasEdge returns true if there is an edge from one node to the other.
+func (g *Directed[N]) HasEdge(from, to N) bool {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	v, ok := g.indices[from]
+	if !ok {
+		return false
+	}
+	w, ok := g.indices[to]
+	if !ok {
+		return false
+	}
+	_, ok = g.edges[[2]int{v, w}]
+	return ok
+}
+
+// Weight returns the weight of the edge from one node to the other, and
+// whether there is such an edge.
+func (g *Directed[N]) Weight(from, to N) (float64, bool) {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	v, ok := g.indices[from]
+	if !ok {
+		return 0, false
+	}
+	w, ok := g.indices[to]
+	if !ok {
+		return 0, false
+	}
+	for _, e := range g.out[v] {
+		if e.to == w {
+			return e.weight, true
+		}
+	}
+	return 0, false
+}
+
+// Nodes returns every node in the order they were added.
+func (g *Directed[N]) Nodes() []N {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	return append([]N(nil), g.nodes...)
+}
+
+// Successors returns the nodes that n has an edge to, in the order the
+// edges were added.
+func (g *Directed[N]) Successors(n N) ([]N, error) {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	v, ok := g.indices[n]
+	if !ok {
+		return nil, ErrVertexNotFound
+	}
+
+	successors := make([]N, 0, len(g.out[v]))
+	for _, e := range g.out[v] {
+		successors = append(successors, g.nodes[e.to])
+	}
+	return successors, nil
+}
+
+// Predecessors returns the nodes that have an edge to n, in the order
+// the edges were added.
+func (g *Directed[N]) Predecessors(n N) ([]N, error) {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	w, ok := g.indices[n]
+	if !ok {
+		return nil, ErrVertexNotFound
+	}
+	return g.names(g.in[w]), nil
+}
+
+// TopologicalSort returns the nodes ordered so that every edge goes
+// from an earlier node to a later one, for instance so that each item
+// comes after everything it depends on when edges point from a
+// dependency to its dependents.  Of the nodes that could come next, the
+// one added first is chosen.  ErrCycle is returned if there is no such
+// order; FindCycle returns the cycle.
+func (g *Directed[N]) TopologicalSort() ([]N, error) {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	indegree := make([]int, len(g.nodes))
+	for v := range g.nodes {
+		indegree[v] = len(g.in[v])
+	}
+
+	// ready is a queue of nodes with no remaining incoming edges. It
+	// holds each node once, so it doubles as the result.
+	ready := make([]int, 0, len(g.nodes))
+	for v, d := range indegree {
+		if d == 0 {
+			ready = append(ready, v)
+		}
+	}
+	for i := 0; i < len(ready); i++ {
+		for _, e := range g.out[ready[i]] {
+			indegree[e.to]--
+			if indegree[e.to] == 0 {
+				ready = append(ready, e.to)
+			}
+		}
+	}
+
+	if len(ready) < len(g.nodes) {
+		return nil, ErrCycle
+	}
+	return g.names(ready), nil
+}
+
+// frame is a node being visited by a depth-first search along with
+// the index of the next of its edges to follow.
+type frame struct {
+	node, next int
+}
+
+// FindCycle returns the nodes of a cycle in the graph, in order along
+// its edges, or nil if the graph is acyclic.  The cycle's last node has
+// an edge back to its first.
+func (g *Directed[N]) FindCycle() []N {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	const (
+		unvisited = iota
+		onPath
+		done
+	)
+	state := make([]uint8, len(g.nodes))
+	var path []frame
+	for root := range g.nodes {
+		if state[root] != unvisited {
+			continue
+		}
+
+		state[root] = onPath
+		path = append(path[:0], frame{node: root})
+		for len(path) > 0 {
+			f := &path[len(path)-1]
+			if f.next == len(g.out[f.node]) {
+				state[f.node] = done
+				path = path[:len(path)-1]
+				continue
+			}
+
+			w := g.out[f.node][f.next].to
+			f.next++
+			switch state[w] {
+			case unvisited:
+				state[w] = onPath
+				path = append(path, frame{node: w})
+			case onPath:
+				start := len(path) - 1
+				for path[start].node != w {
+					start--
+				}
+				cycle := make([]N, 0, len(path)-start)
+				for _, f := range path[start:] {
+					cycle = append(cycle, g.nodes[f.node])
+				}
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// HasCycle returns true if the graph contains a cycle.
+func (g *Directed[N]) HasCycle() bool {
+	return g.FindCycle() != nil
+}
+
+// StronglyConnectedComponents partitions the nodes into strongly
+// connected components, the largest sets of nodes that each have a
+// path to every other.  Components are returned in reverse topological
+// order: no component has an edge to one returned after it.  Found with
+// Tarjan's algorithm in O(V+E) time.
+func (g *Directed[N]) StronglyConnectedComponents() [][]N {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	var (
+		index      = make([]int, len(g.nodes))
+		low        = make([]int, len(g.nodes))
+		onStack    = make([]bool, len(g.nodes))
+		stack      []int
+		path       []frame
+		components [][]N
+		counter    = 1
+	)
+	visit := func(v int) {
+		index[v], low[v] = counter, counter
+		counter++
+		stack = append(stack, v)
+		onStack[v] = true
+		path = append(path, frame{node: v})
+	}
+
+	for root := range g.nodes {
+		if index[root] != 0 {
+			continue
+		}
+
+		visit(root)
+		for len(path) > 0 {
+			f := &path[len(path)-1]
+			v := f.node
+			if f.next < len(g.out[v]) {
+				w := g.out[v][f.next].to
+				f.next++
+				if index[w] == 0 {
+					visit(w)
+				} else if onStack[w] {
+					low[v] = min(low[v], index[w])
+				}
+				continue
+			}
+
+			path = path[:len(path)-1]
+			if len(path) > 0 {
+				parent := path[len(path)-1].node
+				low[parent] = min(low[parent], low[v])
+			}
+			if low[v] != index[v] {
+				continue
+			}
+
+			var component []N
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				component = append(component, g.nodes[w])
+				if w == v {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+	return components
+}
+
+func (g *Directed[N]) addNode(n N) (int, bool) {
+	if v, ok := g.indices[n]; ok {
+		return v, false
+	}
+
+	v := len(g.nodes)
+	g.indices[n] = v
+	g.nodes = append(g.nodes, n)
+	g.out = append(g.out, nil)
+	g.in = append(g.in, nil)
+	return v, true
+}
+
+// names returns the nodes at the provided indices.
+func (g *Directed[N]) names(indices []int) []N {
+	nodes := make([]N, 0, len(indices))
+	for _, v := range indices {
+		nodes = append(nodes, g.nodes[v])
+	}
+	return nodes
+}
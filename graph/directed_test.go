@@ -0,0 +1,192 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirectedAddEdge(t *testing.T) {
+	g := NewDirected[string]()
+	assert.True(t, g.AddNode("a"))
+	assert.False(t, g.AddNode("a"))
+
+	require.NoError(t, g.AddEdge("a", "b"))
+	require.NoError(t, g.AddEdge("b", "a"))
+	require.NoError(t, g.AddEdge("c", "c"))
+	assert.Equal(t, ErrParallelEdge, g.AddEdge("a", "b"))
+
+	assert.Equal(t, 3, g.V())
+	assert.Equal(t, 3, g.E())
+	assert.True(t, g.HasEdge("a", "b"))
+	assert.False(t, g.HasEdge("a", "c"))
+	assert.False(t, g.HasEdge("a", "d"))
+	assert.Equal(t, []string{"a", "b", "c"}, g.Nodes())
+
+	successors, err := g.Successors("a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, successors)
+	predecessors, err := g.Predecessors("c")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c"}, predecessors)
+	_, err = g.Successors("d")
+	assert.Equal(t, ErrVertexNotFound, err)
+	_, err = g.Predecessors("d")
+	assert.Equal(t, ErrVertexNotFound, err)
+}
+
+func TestTopologicalSort(t *testing.T) {
+	g := NewDirected[string]()
+	g.AddNode("standalone")
+	for _, e := range [][2]string{
+		{"fmt", "log"}, {"io", "fmt"}, {"errors", "io"},
+		{"errors", "fmt"}, {"io", "bufio"}, {"log", "app"}, {"bufio", "app"},
+	} {
+		require.NoError(t, g.AddEdge(e[0], e[1]))
+	}
+
+	order, err := g.TopologicalSort()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"standalone", "errors", "io", "fmt", "bufio", "log", "app"}, order)
+	assert.False(t, g.HasCycle())
+	assert.Nil(t, g.FindCycle())
+
+	require.NoError(t, g.AddEdge("app", "io"))
+	_, err = g.TopologicalSort()
+	assert.Equal(t, ErrCycle, err)
+	assert.True(t, g.HasCycle())
+}
+
+func TestFindCycle(t *testing.T) {
+	g := NewDirected[int]()
+	require.NoError(t, g.AddEdge(1, 2))
+	require.NoError(t, g.AddEdge(2, 3))
+	require.NoError(t, g.AddEdge(3, 4))
+	require.NoError(t, g.AddEdge(4, 2))
+
+	assert.Equal(t, []int{2, 3, 4}, g.FindCycle())
+
+	g = NewDirected[int]()
+	require.NoError(t, g.AddEdge(1, 1))
+	assert.Equal(t, []int{1}, g.FindCycle())
+}
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	g := NewDirected[string]()
+	for _, e := range [][2]string{
+		{"a", "b"}, {"b", "c"}, {"c", "a"},
+		{"c", "d"}, {"d", "e"}, {"e", "d"}, {"e", "f"},
+	} {
+		require.NoError(t, g.AddEdge(e[0], e[1]))
+	}
+
+	components := g.StronglyConnectedComponents()
+	for _, c := range components {
+		slices.Sort(c)
+	}
+	assert.Equal(t, [][]string{{"f"}, {"d", "e"}, {"a", "b", "c"}}, components)
+}
+
+// reachable returns every node reachable from n, including n.
+func reachable(g *Directed[int], n int) map[int]bool {
+	seen := map[int]bool{n: true}
+	queue := []int{n}
+	for len(queue) > 0 {
+		successors, _ := g.Successors(queue[0])
+		queue = queue[1:]
+		for _, s := range successors {
+			if !seen[s] {
+				seen[s] = true
+				queue = append(queue, s)
+			}
+		}
+	}
+	return seen
+}
+
+func TestDirectedRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for range 50 {
+		g := NewDirected[int]()
+		n := 1 + rng.Intn(30)
+		for v := range n {
+			g.AddNode(v)
+		}
+		for range rng.Intn(2 * n) {
+			g.AddEdge(rng.Intn(n), rng.Intn(n))
+		}
+
+		reach := make([]map[int]bool, n)
+		for v := range n {
+			reach[v] = reachable(g, v)
+		}
+
+		// nodes share a component exactly when each reaches the other
+		component := make(map[int]int)
+		components := g.StronglyConnectedComponents()
+		for i, c := range components {
+			for _, v := range c {
+				component[v] = i
+			}
+		}
+		require.Len(t, component, n)
+		acyclic := true
+		for v := range n {
+			for w := range n {
+				mutual := reach[v][w] && reach[w][v]
+				require.Equal(t, mutual, component[v] == component[w], "%d and %d", v, w)
+				// no edge to a component returned later
+				if g.HasEdge(v, w) {
+					require.GreaterOrEqual(t, component[v], component[w])
+				}
+			}
+			if len(components[component[v]]) > 1 || g.HasEdge(v, v) {
+				acyclic = false
+			}
+		}
+
+		order, err := g.TopologicalSort()
+		cycle := g.FindCycle()
+		if !acyclic {
+			require.Equal(t, ErrCycle, err)
+			require.NotEmpty(t, cycle)
+			for i, v := range cycle {
+				require.True(t, g.HasEdge(v, cycle[(i+1)%len(cycle)]))
+			}
+			continue
+		}
+
+		require.NoError(t, err)
+		require.Nil(t, cycle)
+		position := make(map[int]int)
+		for i, v := range order {
+			position[v] = i
+		}
+		require.Len(t, position, n)
+		for v := range n {
+			successors, _ := g.Successors(v)
+			for _, w := range successors {
+				require.Less(t, position[v], position[w])
+			}
+		}
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"cmp"
+	"errors"
+	"math"
+
+	"github.com/Workiva/go-datastructures/queue"
+)
+
+var (
+	// ErrNegativeWeight is returned when adding an edge whose weight is
+	// negative or not a number, which shortest paths do not support.
+	ErrNegativeWeight = errors.New("edge weights must not be negative")
+
+	// ErrNoPath is returned when there is no path between two nodes.
+	ErrNoPath = errors.New("no path between nodes")
+)
+
+// Paths holds the shortest paths from a single source node to every
+// node reachable from it.
+type Paths[N comparable] struct {
+	graph    *Directed[N]
+	distance []float64
+	previous []int
+}
+
+// Distance returns the total weight of the shortest path to the node,
+// and whether the node is reachable.
+func (p *Paths[N]) Distance(to N) (float64, bool) {
+	w, ok := p.index(to)
+	if !ok {
+		return math.Inf(1), false
+	}
+	return p.distance[w], true
+}
+
+// Path returns the nodes along the shortest path to the node, starting
+// with the source and ending with the node, or nil if it is not
+// reachable.
+func (p *Paths[N]) Path(to N) []N {
+	w, ok := p.index(to)
+	if !ok {
+		return nil
+	}
+
+	p.graph.mutex.RLock()
+	defer p.graph.mutex.RUnlock()
+	return p.graph.path(p.previous, w)
+}
+
+// index returns the index of the node if it was reachable.
+func (p *Paths[N]) index(n N) (int, bool) {
+	p.graph.mutex.RLock()
+	defer p.graph.mutex.RUnlock()
+
+	w, ok := p.graph.indices[n]
+	if !ok || w >= len(p.distance) || math.IsInf(p.distance[w], 1) {
+		return 0, false
+	}
+	return w, true
+}
+
+// ShortestPaths finds the shortest paths from the node to every node
+// reachable from it with Dijkstra's algorithm, in O((V+E) log V) time.
+// The paths reflect the graph as it was when they were found.
+func (g *Directed[N]) ShortestPaths(from N) (*Paths[N], error) {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	v, ok := g.indices[from]
+	if !ok {
+		return nil, ErrVertexNotFound
+	}
+
+	distance, previous := g.search(v, -1, nil)
+	return &Paths[N]{graph: g, distance: distance, previous: previous}, nil
+}
+
+// ShortestPath returns the nodes along the shortest path from one node
+// to another, and its total weight, using Dijkstra's algorithm.
+// ErrNoPath is returned if the target cannot be reached.
+func (g *Directed[N]) ShortestPath(from, to N) ([]N, float64, error) {
+	return g.AStar(from, to, nil)
+}
+
+// AStar is like ShortestPath, but uses the A* algorithm guided by the
+// heuristic, which estimates the remaining weight from a node to the
+// target.  For the path found to be the shortest, the heuristic must
+// never overestimate it.  A node reached again by a shorter path is
+// revisited, which a heuristic that never decreases along an edge by
+// more than the edge's weight avoids.  A nil heuristic makes this
+// Dijkstra's algorithm.
+func (g *Directed[N]) AStar(from, to N, heuristic func(N) float64) ([]N, float64, error) {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	v, ok := g.indices[from]
+	if !ok {
+		return nil, 0, ErrVertexNotFound
+	}
+	w, ok := g.indices[to]
+	if !ok {
+		return nil, 0, ErrVertexNotFound
+	}
+
+	distance, previous := g.search(v, w, heuristic)
+	if math.IsInf(distance[w], 1) {
+		return nil, 0, ErrNoPath
+	}
+	return g.path(previous, w), distance[w], nil
+}
+
+// candidate is a node waiting to be visited, with its distance from
+// the source and that plus its heuristic estimate of the distance to
+// the target.
+type candidate struct {
+	node     int
+	distance float64
+	priority float64
+}
+
+func compareCandidates(a, b candidate) int {
+	if c := cmp.Compare(a.priority, b.priority); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.node, b.node)
+}
+
+// search finds the shortest distance from the source to each node,
+// stopping early once the target, if not negative, is reached.  Along
+// with the distances, which are infinite for nodes not reached, it
+// returns the node preceding each along its path, or -1.
+func (g *Directed[N]) search(source, target int, heuristic func(N) float64) ([]float64, []int) {
+	distance := make([]float64, len(g.nodes))
+	previous := make([]int, len(g.nodes))
+	for i := range distance {
+		distance[i], previous[i] = math.Inf(1), -1
+	}
+	estimate := func(v int) float64 {
+		if heuristic == nil {
+			return 0
+		}
+		return heuristic(g.nodes[v])
+	}
+
+	// the queue may hold stale candidates for nodes whose distance has
+	// since dropped, which are skipped when they come up.  A node is
+	// visited again whenever its distance drops after a visit, which
+	// keeps the result shortest under an inconsistent heuristic.
+	pq := queue.NewPriorityQueueWithCompareFunc(compareCandidates, len(g.nodes), true)
+	distance[source] = 0
+	pq.Put(candidate{node: source, priority: estimate(source)})
+	for !pq.Empty() {
+		items, _ := pq.Get(1)
+		v := items[0].node
+		if items[0].distance > distance[v] {
+			continue
+		}
+		if v == target {
+			break
+		}
+
+		for _, e := range g.out[v] {
+			if d := distance[v] + e.weight; d < distance[e.to] {
+				distance[e.to], previous[e.to] = d, v
+				pq.Put(candidate{node: e.to, distance: d, priority: d + estimate(e.to)})
+			}
+		}
+	}
+	return distance, previous
+}
+
+// path returns the nodes along the path ending at the node, following
+// previous back to the source.
+func (g *Directed[N]) path(previous []int, to int) []N {
+	var path []N
+	for v := to; v != -1; v = previous[v] {
+		path = append(path, g.nodes[v])
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graph
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShortestPath(t *testing.T) {
+	g := NewDirected[string]()
+	for _, e := range []struct {
+		from, to string
+		weight   float64
+	}{
+		{"a", "b", 7}, {"a", "c", 9}, {"a", "f", 14}, {"b", "c", 10},
+		{"b", "d", 15}, {"c", "d", 11}, {"c", "f", 2}, {"d", "e", 6},
+		{"f", "e", 9},
+	} {
+		require.NoError(t, g.AddWeightedEdge(e.from, e.to, e.weight))
+	}
+	g.AddNode("g")
+
+	path, distance, err := g.ShortestPath("a", "e")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "c", "f", "e"}, path)
+	assert.Equal(t, 20.0, distance)
+
+	path, distance, err = g.ShortestPath("a", "a")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, path)
+	assert.Equal(t, 0.0, distance)
+
+	_, _, err = g.ShortestPath("e", "a")
+	assert.Equal(t, ErrNoPath, err)
+	_, _, err = g.ShortestPath("a", "z")
+	assert.Equal(t, ErrVertexNotFound, err)
+
+	paths, err := g.ShortestPaths("a")
+	require.NoError(t, err)
+	distance, ok := paths.Distance("d")
+	assert.True(t, ok)
+	assert.Equal(t, 20.0, distance)
+	assert.Equal(t, []string{"a", "c", "d"}, paths.Path("d"))
+	_, ok = paths.Distance("g")
+	assert.False(t, ok)
+	assert.Nil(t, paths.Path("g"))
+
+	g.AddNode("h")
+	_, ok = paths.Distance("h")
+	assert.False(t, ok)
+	_, err = g.ShortestPaths("z")
+	assert.Equal(t, ErrVertexNotFound, err)
+}
+
+func TestAddWeightedEdgeNegative(t *testing.T) {
+	g := NewDirected[int]()
+	assert.Equal(t, ErrNegativeWeight, g.AddWeightedEdge(1, 2, -1))
+	assert.Equal(t, ErrNegativeWeight, g.AddWeightedEdge(1, 2, math.NaN()))
+	assert.Equal(t, 0, g.E())
+
+	require.NoError(t, g.AddWeightedEdge(1, 2, 0.5))
+	weight, ok := g.Weight(1, 2)
+	assert.True(t, ok)
+	assert.Equal(t, 0.5, weight)
+	_, ok = g.Weight(2, 1)
+	assert.False(t, ok)
+}
+
+func TestAStarGrid(t *testing.T) {
+	type cell struct{ x, y int }
+	const size = 20
+	wall := func(c cell) bool { return c.x == size/2 && c.y < size-2 }
+
+	g := NewDirected[cell]()
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			from := cell{x, y}
+			if wall(from) {
+				continue
+			}
+			for _, to := range []cell{{x + 1, y}, {x - 1, y}, {x, y + 1}, {x, y - 1}} {
+				if to.x >= 0 && to.x < size && to.y >= 0 && to.y < size && !wall(to) {
+					require.NoError(t, g.AddEdge(from, to))
+				}
+			}
+		}
+	}
+
+	target := cell{size - 1, 0}
+	manhattan := func(c cell) float64 {
+		return math.Abs(float64(target.x-c.x)) + math.Abs(float64(target.y-c.y))
+	}
+	path, distance, err := g.AStar(cell{0, 0}, target, manhattan)
+	require.NoError(t, err)
+	_, expected, err := g.ShortestPath(cell{0, 0}, target)
+	require.NoError(t, err)
+	assert.Equal(t, expected, distance)
+	assert.Len(t, path, int(distance)+1)
+	for i := 1; i < len(path); i++ {
+		assert.True(t, g.HasEdge(path[i-1], path[i]))
+	}
+}
+
+func TestAStarInconsistentHeuristic(t *testing.T) {
+	g := NewDirected[string]()
+	require.NoError(t, g.AddWeightedEdge("s", "a", 1))
+	require.NoError(t, g.AddWeightedEdge("s", "b", 3))
+	require.NoError(t, g.AddWeightedEdge("a", "b", 1))
+	require.NoError(t, g.AddWeightedEdge("b", "t", 5))
+
+	// admissible, but b is first reached through the longer edge from
+	// s, and must be visited again once reached through a.
+	heuristic := func(n string) float64 {
+		if n == "a" {
+			return 5
+		}
+		return 0
+	}
+	path, distance, err := g.AStar("s", "t", heuristic)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s", "a", "b", "t"}, path)
+	assert.Equal(t, 7.0, distance)
+}
+
+// floydWarshall returns the shortest distance between every pair of
+// nodes, which are the integers [0, n).
+func floydWarshall(g *Directed[int], n int) [][]float64 {
+	distance := make([][]float64, n)
+	for i := range distance {
+		distance[i] = make([]float64, n)
+		for j := range distance[i] {
+			if weight, ok := g.Weight(i, j); ok {
+				distance[i][j] = weight
+			} else {
+				distance[i][j] = math.Inf(1)
+			}
+		}
+		distance[i][i] = 0
+	}
+	for k := 0; k < n; k++ {
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				distance[i][j] = min(distance[i][j], distance[i][k]+distance[k][j])
+			}
+		}
+	}
+	return distance
+}
+
+func TestShortestPathsRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	for round := 0; round < 50; round++ {
+		n := 1 + rng.Intn(15)
+		g := NewDirected[int]()
+		for i := 0; i < n; i++ {
+			g.AddNode(i)
+		}
+		for i := rng.Intn(n * n); i > 0; i-- {
+			g.AddWeightedEdge(rng.Intn(n), rng.Intn(n), float64(rng.Intn(10)))
+		}
+
+		expected := floydWarshall(g, n)
+		for from := 0; from < n; from++ {
+			paths, err := g.ShortestPaths(from)
+			require.NoError(t, err)
+			for to := 0; to < n; to++ {
+				distance, ok := paths.Distance(to)
+				if math.IsInf(expected[from][to], 1) {
+					assert.False(t, ok)
+					_, _, err := g.ShortestPath(from, to)
+					assert.Equal(t, ErrNoPath, err)
+					continue
+				}
+				require.True(t, ok)
+				assert.Equal(t, expected[from][to], distance)
+
+				path, d, err := g.ShortestPath(from, to)
+				require.NoError(t, err)
+				assert.Equal(t, expected[from][to], d)
+				assert.Equal(t, from, path[0])
+				assert.Equal(t, to, path[len(path)-1])
+				var sum float64
+				for i := 1; i < len(path); i++ {
+					weight, ok := g.Weight(path[i-1], path[i])
+					require.True(t, ok)
+					sum += weight
+				}
+				assert.Equal(t, d, sum)
+			}
+		}
+	}
+}
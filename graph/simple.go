@@ -16,7 +16,8 @@ limitations under the License.
 
 /*
 Package graph provides graph implementations. Currently, this includes an
-undirected simple graph.
+undirected simple graph and a directed graph with topological sorting,
+cycle detection and strongly connected components.
 */
 package graph
 
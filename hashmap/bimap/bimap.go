@@ -0,0 +1,138 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package bimap implements a bidirectional map, which maps keys to values
+and values back to keys, each in O(1) time.  Every value is under at
+most one key, and the forward and inverse indexes are only ever changed
+together, so they always agree.
+
+When a value is put under a key while it is already under another, the
+map's Policy decides whether the put fails or evicts the other key.  A
+BiMap is not threadsafe.
+*/
+package bimap
+
+import (
+	"errors"
+	"iter"
+)
+
+// ErrValueExists is returned when putting a value that is already
+// under another key into a map with the Reject policy.
+var ErrValueExists = errors.New("value is already under another key")
+
+// Policy decides what happens when a value is put under a key while it
+// is already under another.
+type Policy int
+
+const (
+	// Reject fails the put, leaving the map unchanged.
+	Reject Policy = iota
+	// Evict removes the other key, so the value moves to the new one.
+	Evict
+)
+
+// BiMap maps keys to values and values back to keys.
+type BiMap[K, V comparable] struct {
+	forward map[K]V
+	inverse map[V]K
+	policy  Policy
+	// inv shares the indexes, swapped.
+	inv *BiMap[V, K]
+}
+
+// New returns an empty BiMap that resolves conflicting puts with the
+// policy.
+func New[K, V comparable](policy Policy) *BiMap[K, V] {
+	b := &BiMap[K, V]{forward: make(map[K]V), inverse: make(map[V]K), policy: policy}
+	b.inv = &BiMap[V, K]{forward: b.inverse, inverse: b.forward, policy: policy, inv: b}
+	return b
+}
+
+// Inverse returns a view of the map from values to keys.  It shares
+// the map's indexes, so changes through either are seen by both.
+func (b *BiMap[K, V]) Inverse() *BiMap[V, K] {
+	return b.inv
+}
+
+// Len returns the number of keys, and so of values, in the map.
+func (b *BiMap[K, V]) Len() int {
+	return len(b.forward)
+}
+
+// Get returns the value under the key, and whether there is one.
+func (b *BiMap[K, V]) Get(key K) (V, bool) {
+	value, ok := b.forward[key]
+	return value, ok
+}
+
+// GetKey returns the key the value is under, and whether there is one.
+func (b *BiMap[K, V]) GetKey(value V) (K, bool) {
+	key, ok := b.inverse[value]
+	return key, ok
+}
+
+// Put stores the value under the key, replacing any value already
+// under it.  If the value is already under another key, the Reject
+// policy returns ErrValueExists without changing the map, and the Evict
+// policy removes the other key.
+func (b *BiMap[K, V]) Put(key K, value V) error {
+	if other, ok := b.inverse[value]; ok {
+		if other == key {
+			return nil
+		}
+		if b.policy == Reject {
+			return ErrValueExists
+		}
+		delete(b.forward, other)
+	}
+	if old, ok := b.forward[key]; ok {
+		delete(b.inverse, old)
+	}
+	b.forward[key] = value
+	b.inverse[value] = key
+	return nil
+}
+
+// Delete removes the key and its value, returning the value and
+// whether there was one.
+func (b *BiMap[K, V]) Delete(key K) (V, bool) {
+	value, ok := b.forward[key]
+	if ok {
+		delete(b.forward, key)
+		delete(b.inverse, value)
+	}
+	return value, ok
+}
+
+// DeleteValue removes the value and its key, returning the key and
+// whether there was one.
+func (b *BiMap[K, V]) DeleteValue(value V) (K, bool) {
+	return b.inv.Delete(value)
+}
+
+// All returns an iterator over the keys and values in the map, in no
+// particular order.
+func (b *BiMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for key, value := range b.forward {
+			if !yield(key, value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bimap
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBiMap(t *testing.T) {
+	b := New[string, int](Reject)
+	require.NoError(t, b.Put("one", 1))
+	require.NoError(t, b.Put("two", 2))
+	require.NoError(t, b.Put("two", 2))
+
+	value, ok := b.Get("one")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+	key, ok := b.GetKey(2)
+	assert.True(t, ok)
+	assert.Equal(t, "two", key)
+	_, ok = b.GetKey(3)
+	assert.False(t, ok)
+
+	// replacing a key's value frees the old value.
+	require.NoError(t, b.Put("two", 22))
+	_, ok = b.GetKey(2)
+	assert.False(t, ok)
+	assert.Equal(t, 2, b.Len())
+
+	assert.Equal(t, ErrValueExists, b.Put("uno", 1))
+	_, ok = b.Get("uno")
+	assert.False(t, ok)
+	key, _ = b.GetKey(1)
+	assert.Equal(t, "one", key)
+
+	value, ok = b.Delete("one")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+	key, ok = b.DeleteValue(22)
+	assert.True(t, ok)
+	assert.Equal(t, "two", key)
+	_, ok = b.Delete("one")
+	assert.False(t, ok)
+	assert.Equal(t, 0, b.Len())
+}
+
+func TestEvict(t *testing.T) {
+	b := New[string, int](Evict)
+	require.NoError(t, b.Put("one", 1))
+	require.NoError(t, b.Put("two", 2))
+	require.NoError(t, b.Put("uno", 1))
+
+	_, ok := b.Get("one")
+	assert.False(t, ok)
+	key, _ := b.GetKey(1)
+	assert.Equal(t, "uno", key)
+	assert.Equal(t, 2, b.Len())
+
+	// moving a value onto a key that has one frees both old entries.
+	require.NoError(t, b.Put("two", 1))
+	assert.Equal(t, map[string]int{"two": 1}, collect(b))
+	_, ok = b.GetKey(2)
+	assert.False(t, ok)
+}
+
+func TestInverse(t *testing.T) {
+	b := New[string, int](Reject)
+	inverse := b.Inverse()
+	assert.Same(t, b, inverse.Inverse())
+
+	require.NoError(t, inverse.Put(1, "one"))
+	value, _ := b.Get("one")
+	assert.Equal(t, 1, value)
+	assert.Equal(t, ErrValueExists, inverse.Put(2, "one"))
+
+	b.Delete("one")
+	assert.Equal(t, 0, inverse.Len())
+}
+
+func collect[K, V comparable](b *BiMap[K, V]) map[K]V {
+	m := make(map[K]V)
+	for key, value := range b.All() {
+		m[key] = value
+	}
+	return m
+}
+
+func TestRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(9))
+	for _, policy := range []Policy{Reject, Evict} {
+		b := New[int, int](policy)
+		for op := 0; op < 5000; op++ {
+			key, value := rng.Intn(20), rng.Intn(20)
+			switch rng.Intn(4) {
+			case 0:
+				b.Delete(key)
+			case 1:
+				b.Inverse().Delete(value)
+			default:
+				before := collect(b)
+				err := b.Put(key, value)
+				if other, ok := b.GetKey(value); policy == Reject && err != nil {
+					assert.NotEqual(t, key, other)
+					assert.Equal(t, before, collect(b))
+				} else {
+					require.NoError(t, err)
+					assert.True(t, ok)
+					assert.Equal(t, key, other)
+				}
+			}
+
+			forward, inverse := collect(b), collect(b.Inverse())
+			require.Equal(t, len(forward), len(inverse))
+			for key, value := range forward {
+				assert.Equal(t, key, inverse[value])
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package fastinteger
+
+import (
+	"runtime"
+	"sync"
+)
+
+// shard is a single independently locked part of a ConcurrentMap,
+// padded so that neighbouring locks do not share a cache line.
+type shard[V any] struct {
+	lock sync.RWMutex
+	m    *FastIntegerMap[V]
+	_    [40]byte
+}
+
+// ConcurrentMap is a FastIntegerMap that is safe for concurrent use.
+// Keys are spread over a number of shards, each a FastIntegerMap
+// behind its own read/write lock, so reads never wait on one another
+// and writes only wait on operations that land on the same shard.
+type ConcurrentMap[V any] struct {
+	shards []shard[V]
+	shift  uint
+}
+
+// NewConcurrentMap returns a new ConcurrentMap split into the provided
+// number of shards, rounded up to a power of two, which together have
+// room for hint items before growing.  If shards is 0, four times
+// GOMAXPROCS is used.  The options apply to every shard.
+func NewConcurrentMap[V any](shards, hint uint64, options ...Option) *ConcurrentMap[V] {
+	if shards == 0 {
+		shards = uint64(runtime.GOMAXPROCS(0)) * 4
+	}
+	shards = roundUp(shards)
+
+	cm := &ConcurrentMap[V]{
+		shards: make([]shard[V], shards),
+		shift:  64,
+	}
+	for s := shards; s > 1; s >>= 1 {
+		cm.shift--
+	}
+	for i := range cm.shards {
+		cm.shards[i].m = NewMap[V](hint/shards, options...)
+	}
+	return cm
+}
+
+// shardFor picks a shard by the top bits of the hash, as the bottom
+// bits pick the bucket within the shard.
+func (cm *ConcurrentMap[V]) shardFor(key uint64) *shard[V] {
+	if cm.shift == 64 {
+		return &cm.shards[0]
+	}
+	return &cm.shards[hash(key)>>cm.shift]
+}
+
+// Get returns an item from the map if it exists.  Otherwise,
+// returns the zero value and false for the second argument.
+func (cm *ConcurrentMap[V]) Get(key uint64) (V, bool) {
+	s := cm.shardFor(key)
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.m.Get(key)
+}
+
+// Set will set the provided key with the provided value.
+func (cm *ConcurrentMap[V]) Set(key uint64, value V) {
+	s := cm.shardFor(key)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.m.Set(key, value)
+}
+
+// Exists will return a bool indicating if the provided key
+// exists in the map.
+func (cm *ConcurrentMap[V]) Exists(key uint64) bool {
+	s := cm.shardFor(key)
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.m.Exists(key)
+}
+
+// Delete will remove the provided key from the map.  If
+// the key cannot be found, this is a no-op.
+func (cm *ConcurrentMap[V]) Delete(key uint64) {
+	s := cm.shardFor(key)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.m.Delete(key)
+}
+
+// Len returns the number of items in the map.  The shards are counted
+// one at a time, so the result may be stale if the map is being
+// modified concurrently.
+func (cm *ConcurrentMap[V]) Len() uint64 {
+	var count uint64
+	for i := range cm.shards {
+		s := &cm.shards[i]
+		s.lock.RLock()
+		count += s.m.Len()
+		s.lock.RUnlock()
+	}
+	return count
+}
@@ -0,0 +1,83 @@
+package fastinteger
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentMap(t *testing.T) {
+	m := NewConcurrentMap[string](4, 0)
+
+	m.Set(1, "a")
+	m.Set(2, "b")
+	m.Set(1, "c")
+
+	value, ok := m.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, "c", value)
+	assert.True(t, m.Exists(2))
+	assert.Equal(t, uint64(2), m.Len())
+
+	m.Delete(1)
+	assert.False(t, m.Exists(1))
+	assert.Equal(t, uint64(1), m.Len())
+}
+
+func TestConcurrentMapSingleShard(t *testing.T) {
+	m := NewConcurrentMap[int](1, 0)
+
+	for i := range uint64(100) {
+		m.Set(i, int(i))
+	}
+	for i := range uint64(100) {
+		value, ok := m.Get(i)
+		assert.True(t, ok)
+		assert.Equal(t, int(i), value)
+	}
+}
+
+func TestConcurrentMapParallel(t *testing.T) {
+	m := NewConcurrentMap[uint64](0, 0)
+	numItems := uint64(1000)
+
+	var wg sync.WaitGroup
+	for g := range uint64(8) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range numItems {
+				key := g*numItems + i
+				m.Set(key, key)
+				value, ok := m.Get(key)
+				assert.True(t, ok)
+				assert.Equal(t, key, value)
+				if i%2 == 0 {
+					m.Delete(key)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 8*numItems/2, m.Len())
+}
+
+func BenchmarkConcurrentMapGet(b *testing.B) {
+	numItems := uint64(1000)
+
+	keys := generateKeys(int(numItems))
+	m := NewConcurrentMap[uint64](0, numItems*2)
+	for _, key := range keys {
+		m.Set(key, key)
+	}
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			m.Get(keys[i%len(keys)])
+			i++
+		}
+	})
+}
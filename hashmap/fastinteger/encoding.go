@@ -0,0 +1,152 @@
+package fastinteger
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"unsafe"
+
+	"github.com/Workiva/go-datastructures/codec"
+)
+
+// encodingVersion is written at the start of every encoded map so the
+// format can change without misreading older data.
+const encodingVersion = 1
+
+var (
+	// ErrInvalidData is returned when unmarshaling data that was not
+	// produced by MarshalBinary for a map of the same key type.
+	ErrInvalidData = errors.New("fastinteger: invalid binary data")
+	// ErrUnsupportedValue is returned when marshaling a map whose values
+	// have no encoding in the codec package.
+	ErrUnsupportedValue = codec.ErrUnsupported
+)
+
+// MarshalBinary encodes the map as a header holding its size, capacity
+// and load factor followed by a stream of its keys and values.  Keys
+// are written as varints and values with the codec package's encoding
+// for their type.
+func (fm *IntegerMap[K, V]) MarshalBinary() ([]byte, error) {
+	values, err := codec.For[V]()
+	if err != nil {
+		return nil, err
+	}
+
+	var zero K
+	data := []byte{encodingVersion, byte(unsafe.Sizeof(zero))}
+	data = binary.AppendUvarint(data, fm.count)
+	data = binary.AppendUvarint(data, uint64(len(fm.buckets)))
+	data = binary.AppendUvarint(data, fm.min)
+	data = binary.LittleEndian.AppendUint64(data, math.Float64bits(fm.loadFactor))
+
+	signed := isSigned[K]()
+	for _, b := range fm.buckets {
+		if !b.used {
+			continue
+		}
+		if signed {
+			data = binary.AppendVarint(data, int64(b.key))
+		} else {
+			data = binary.AppendUvarint(data, uint64(b.key))
+		}
+		if data, err = values.AppendBinary(data, b.value); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// UnmarshalBinary replaces the contents of the map with those encoded
+// by MarshalBinary.  The map is sized as the encoded map was before
+// any key is inserted, so it never grows while loading.  So that
+// corrupt data cannot make it allocate without bound, a size beyond
+// what the keys need at the default load is only honored up to the
+// length of the data, in buckets.  A map created with a far larger
+// hint than it has keys, or with a tiny load factor, is restored
+// smaller, with its minimum size lowered to match, and grows back as
+// keys are added.
+func (fm *IntegerMap[K, V]) UnmarshalBinary(data []byte) error {
+	values, err := codec.For[V]()
+	if err != nil {
+		return err
+	}
+
+	var zero K
+	limit := roundUp(uint64(len(data)))
+	if len(data) < 2 || data[0] != encodingVersion || data[1] != byte(unsafe.Sizeof(zero)) {
+		return ErrInvalidData
+	}
+	data = data[2:]
+
+	var header [3]uint64
+	for i := range header {
+		value, n := binary.Uvarint(data)
+		if n <= 0 {
+			return ErrInvalidData
+		}
+		header[i], data = value, data[n:]
+	}
+	count, size, minSize := header[0], header[1], header[2]
+	if len(data) < 8 || size == 0 || size&(size-1) != 0 || minSize == 0 || minSize > size ||
+		count > uint64(len(data)) {
+		return ErrInvalidData
+	}
+	loadFactor := math.Float64frombits(binary.LittleEndian.Uint64(data))
+	if !(loadFactor >= 0 && loadFactor < 1) {
+		return ErrInvalidData
+	}
+	load := loadFactor
+	if load == 0 {
+		load = ratio
+	}
+	// a map grows before it exceeds its load, which also leaves it
+	// an empty bucket for probes to stop at.
+	if float64(count) > float64(size)*load {
+		return ErrInvalidData
+	}
+	data = data[8:]
+
+	size = min(size, max(sizeFor(count, ratio), limit))
+	minSize = min(minSize, size)
+	buckets := make(buckets[K, V], size)
+	signed := isSigned[K]()
+	for range count {
+		var key K
+		var n int
+		if signed {
+			var k int64
+			k, n = binary.Varint(data)
+			key = K(k)
+		} else {
+			var k uint64
+			k, n = binary.Uvarint(data)
+			key = K(k)
+		}
+		if n <= 0 {
+			return ErrInvalidData
+		}
+
+		value, m, err := values.DecodeBinary(data[n:])
+		if errors.Is(err, codec.ErrTruncated) || errors.Is(err, codec.ErrCorrupt) {
+			return ErrInvalidData
+		}
+		if err != nil {
+			return err
+		}
+		data = data[n+m:]
+
+		if !buckets.set(key, value) {
+			return ErrInvalidData
+		}
+	}
+	if len(data) != 0 {
+		return ErrInvalidData
+	}
+
+	fm.count, fm.min, fm.loadFactor, fm.buckets = count, minSize, loadFactor, buckets
+	return nil
+}
+
+func isSigned[K Key]() bool {
+	return ^K(0) < 0
+}
@@ -0,0 +1,196 @@
+package fastinteger
+
+import (
+	"encoding/binary"
+	"math"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	m := NewWithCapacity(1000, WithLoadFactor(0.5))
+	keys := generateKeys(1000)
+	m.PutMany(keys, keys)
+
+	data, err := m.MarshalBinary()
+	require.NoError(t, err)
+
+	result := New(0)
+	require.NoError(t, result.UnmarshalBinary(data))
+	assert.Equal(t, m.Len(), result.Len())
+	assert.Equal(t, m.Cap(), result.Cap())
+	assert.Equal(t, 0.5, result.maxLoad())
+	for _, key := range keys {
+		value, ok := result.Get(key)
+		assert.True(t, ok)
+		assert.Equal(t, key, value)
+	}
+}
+
+func TestMarshalBinarySignedKeys(t *testing.T) {
+	type point struct {
+		X, Y int32
+	}
+	m := NewIntegerMap[int64, point](0)
+	m.Set(-1, point{X: 1, Y: 2})
+	m.Set(math.MinInt64, point{X: -1})
+	m.Set(math.MaxInt64, point{Y: -1})
+
+	data, err := m.MarshalBinary()
+	require.NoError(t, err)
+
+	var result FastInt64Map[point]
+	require.NoError(t, result.UnmarshalBinary(data))
+	assert.Equal(t, uint64(3), result.Len())
+	for _, key := range m.Keys() {
+		expected, _ := m.Get(key)
+		value, ok := result.Get(key)
+		assert.True(t, ok)
+		assert.Equal(t, expected, value)
+	}
+
+	var wrongKeys FastUint32Map[point]
+	assert.Equal(t, ErrInvalidData, wrongKeys.UnmarshalBinary(data))
+}
+
+func TestMarshalBinaryMarshalerValues(t *testing.T) {
+	m := NewIntegerMap[uint32, netip.Addr](0)
+	m.Set(1, netip.MustParseAddr("10.0.0.1"))
+	m.Set(2, netip.MustParseAddr("::1"))
+
+	data, err := m.MarshalBinary()
+	require.NoError(t, err)
+
+	result := NewIntegerMap[uint32, netip.Addr](0)
+	require.NoError(t, result.UnmarshalBinary(data))
+	value, _ := result.Get(2)
+	assert.Equal(t, netip.MustParseAddr("::1"), value)
+}
+
+func TestMarshalBinaryStringValues(t *testing.T) {
+	m := NewIntegerMap[int64, string](0)
+	m.Set(-1, "minus one")
+	m.Set(1, "")
+
+	data, err := m.MarshalBinary()
+	require.NoError(t, err)
+
+	result := NewIntegerMap[int64, string](0)
+	require.NoError(t, result.UnmarshalBinary(data))
+	assert.Equal(t, uint64(2), result.Len())
+	value, _ := result.Get(-1)
+	assert.Equal(t, "minus one", value)
+	for i := range data[:len(data)-1] {
+		assert.Error(t, NewIntegerMap[int64, string](0).UnmarshalBinary(data[:i]))
+	}
+}
+
+func TestMarshalBinaryUnsupported(t *testing.T) {
+	m := NewMap[*user](0)
+	_, err := m.MarshalBinary()
+	assert.Equal(t, ErrUnsupportedValue, err)
+	assert.Equal(t, ErrUnsupportedValue, m.UnmarshalBinary(nil))
+}
+
+func TestUnmarshalBinaryInvalid(t *testing.T) {
+	m := New(0)
+	m.Set(1, 1)
+	m.Set(2, 2)
+	data, err := m.MarshalBinary()
+	require.NoError(t, err)
+
+	for i := range data {
+		assert.Error(t, New(0).UnmarshalBinary(data[:i]), "truncated at %d", i)
+	}
+	assert.Equal(t, ErrInvalidData, New(0).UnmarshalBinary(append(data, 0)))
+
+	result := New(0)
+	result.Set(5, 5)
+	assert.Error(t, result.UnmarshalBinary(data[:len(data)-1]))
+	assert.True(t, result.Exists(5))
+}
+
+// encodeHeader returns the encoding of a map of uint64 keys with the
+// provided header and no entries.
+func encodeHeader(count, size, minSize uint64, loadFactor float64) []byte {
+	data := []byte{encodingVersion, 8}
+	data = binary.AppendUvarint(data, count)
+	data = binary.AppendUvarint(data, size)
+	data = binary.AppendUvarint(data, minSize)
+	return binary.LittleEndian.AppendUint64(data, math.Float64bits(loadFactor))
+}
+
+func TestUnmarshalBinaryCorruptHeader(t *testing.T) {
+	// a huge size must not be allocated
+	m := New(0)
+	require.NoError(t, m.UnmarshalBinary(encodeHeader(0, 1<<62, 1<<62, 0.5)))
+	assert.LessOrEqual(t, m.Cap(), uint64(64))
+	assert.LessOrEqual(t, m.min, m.Cap())
+	_, ok := m.Get(1)
+	assert.False(t, ok)
+
+	// a table filled beyond its load would leave probes no empty
+	// bucket to stop at
+	data := encodeHeader(4, 4, 4, 0.5)
+	for key := range uint64(4) {
+		data = binary.AppendUvarint(data, key)
+		data = binary.LittleEndian.AppendUint64(data, key)
+	}
+	assert.Equal(t, ErrInvalidData, New(0).UnmarshalBinary(data))
+
+	assert.Equal(t, ErrInvalidData, New(0).UnmarshalBinary(encodeHeader(0, 4, 0, 0.5)))
+	assert.Equal(t, ErrInvalidData, New(0).UnmarshalBinary(encodeHeader(0, 4, 8, 0.5)))
+	assert.Equal(t, ErrInvalidData, New(0).UnmarshalBinary(encodeHeader(0, 6, 4, 0.5)))
+	assert.Equal(t, ErrInvalidData, New(0).UnmarshalBinary(encodeHeader(0, 4, 4, 1)))
+	assert.Equal(t, ErrInvalidData, New(0).UnmarshalBinary(encodeHeader(0, 4, 4, math.NaN())))
+}
+
+func FuzzUnmarshalBinary(f *testing.F) {
+	m := New(0)
+	for key := range uint64(10) {
+		m.Set(key, key*key)
+	}
+	data, err := m.MarshalBinary()
+	require.NoError(f, err)
+	f.Add(data)
+	f.Add(append(encodeHeader(1, 1<<62, 1, 1e-300), 1, 1, 0, 0, 0, 0, 0, 0, 0))
+	f.Add(encodeHeader(0, 1<<62, 1<<62, 0.5))
+	f.Add(encodeHeader(0, 4, 0, 0.5))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		m := New(0)
+		if m.UnmarshalBinary(data) != nil {
+			return
+		}
+		assert.Less(t, m.Len(), m.Cap())
+		assert.LessOrEqual(t, m.Cap(), max(roundUp(uint64(len(data))), sizeFor(m.Len(), ratio)))
+		assert.Len(t, m.Keys(), int(m.Len()))
+
+		// lookups, including of a key not in the map, must stop
+		for _, key := range m.Keys() {
+			assert.True(t, m.Exists(key))
+		}
+		m.Exists(math.MaxUint64)
+		for _, key := range m.Keys() {
+			m.Delete(key)
+		}
+		assert.Zero(t, m.Len())
+		assert.NotZero(t, m.Cap())
+		m.Set(1, 1)
+		assert.True(t, m.Exists(1))
+	})
+}
+
+func BenchmarkUnmarshalBinary(b *testing.B) {
+	m := NewWithCapacity(1 << 16)
+	keys := generateKeys(1 << 16)
+	m.PutMany(keys, keys)
+	data, _ := m.MarshalBinary()
+
+	for b.Loop() {
+		New(0).UnmarshalBinary(data)
+	}
+}
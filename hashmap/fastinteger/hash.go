@@ -1,5 +1,13 @@
 package fastinteger
 
+import "unsafe"
+
+// Key is the set of fixed size integer types that can be used as the
+// keys of an IntegerMap.
+type Key interface {
+	~int32 | ~int64 | ~uint32 | ~uint64
+}
+
 // hash will convert the uint64 key into a hash based on Murmur3's 64-bit
 // integer finalizer.
 // Details here: https://code.google.com/p/smhasher/wiki/MurmurHash3
@@ -11,3 +19,22 @@ func hash(key uint64) uint64 {
 	key ^= key >> 33
 	return key
 }
+
+// hash32 will convert the uint32 key into a hash based on Murmur3's
+// 32-bit integer finalizer.
+func hash32(key uint32) uint32 {
+	key ^= key >> 16
+	key *= 0x85ebca6b
+	key ^= key >> 13
+	key *= 0xc2b2ae35
+	key ^= key >> 16
+	return key
+}
+
+// hashKey hashes a key with the finalizer suited to its size.
+func hashKey[K Key](key K) uint64 {
+	if unsafe.Sizeof(key) == 4 {
+		return uint64(hash32(uint32(key)))
+	}
+	return hash(uint64(key))
+}
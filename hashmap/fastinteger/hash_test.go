@@ -17,6 +17,14 @@ func TestHash(t *testing.T) {
 	assert.NotEqual(t, key, h)
 }
 
+func TestHashKey(t *testing.T) {
+	assert.Equal(t, hash(5), hashKey(uint64(5)))
+	assert.Equal(t, hash(1<<63), hashKey(int64(-1<<63)))
+	assert.Equal(t, uint64(hash32(5)), hashKey(uint32(5)))
+	assert.Equal(t, uint64(hash32(1<<31)), hashKey(int32(-1<<31)))
+	assert.NotEqual(t, uint32(5), hash32(5))
+}
+
 func BenchmarkHash(b *testing.B) {
 	numItems := 1000
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
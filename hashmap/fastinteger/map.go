@@ -0,0 +1,326 @@
+package fastinteger
+
+import (
+	"math"
+	"unsafe"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+// bucket is a single slot of the map.  Values are stored inline so
+// that a probe touches a single cache line.
+type bucket[K Key, V any] struct {
+	key   K
+	value V
+	used  bool
+}
+
+type buckets[K Key, V any] []bucket[K, V]
+
+func (buckets buckets[K, V]) find(key K) uint64 {
+	h := hashKey(key)
+	i := h & (uint64(len(buckets)) - 1)
+	for buckets[i].used && buckets[i].key != key {
+		i = (i + 1) & (uint64(len(buckets)) - 1)
+	}
+
+	return i
+}
+
+// set stores the value under key, returning true if the key was not
+// already present.
+func (buckets buckets[K, V]) set(key K, value V) bool {
+	i := buckets.find(key)
+	added := !buckets[i].used
+	buckets[i] = bucket[K, V]{key: key, value: value, used: true}
+	return added
+}
+
+func (buckets buckets[K, V]) get(key K) (V, bool) {
+	i := buckets.find(key)
+	return buckets[i].value, buckets[i].used
+}
+
+func (buckets buckets[K, V]) delete(key K) bool {
+	i := buckets.find(key)
+	if !buckets[i].used {
+		return false
+	}
+	buckets[i] = bucket[K, V]{}
+	i = (i + 1) & (uint64(len(buckets)) - 1)
+	for buckets[i].used {
+		b := buckets[i]
+		buckets[i] = bucket[K, V]{}
+		buckets.set(b.key, b.value)
+		i = (i + 1) & (uint64(len(buckets)) - 1)
+	}
+	return true
+}
+
+func (buckets buckets[K, V]) exists(key K) bool {
+	return buckets[buckets.find(key)].used
+}
+
+// IntegerMap is FastIntegerHashMap for keys of any fixed size
+// integer type and values of any type, using the same probing.
+// Values are held in the map itself, so pointers or structs can be
+// stored without the caller maintaining a parallel slice indexed by
+// the values.
+//
+// The map shrinks by half whenever deletes leave it less than a
+// quarter as full as it is allowed to get, but never below the
+// size it was created with.
+type IntegerMap[K Key, V any] struct {
+	count      uint64
+	min        uint64
+	loadFactor float64
+	buckets    buckets[K, V]
+}
+
+// maxLoad returns the fraction of the buckets that may be used before
+// the map grows.
+func (fm *IntegerMap[K, V]) maxLoad() float64 {
+	if fm.loadFactor == 0 {
+		return ratio
+	}
+	return fm.loadFactor
+}
+
+// sizeFor returns the number of buckets needed to hold count keys
+// without exceeding the given load.
+func sizeFor(count uint64, load float64) uint64 {
+	return roundUp(max(uint64(math.Ceil(float64(count)/load)), 1))
+}
+
+// rebuild is an expensive operation which requires us to iterate
+// over the current bucket and rehash the keys for insertion into
+// the new bucket.  The new bucket is twice as large as the old
+// bucket by default.
+func (fm *IntegerMap[K, V]) rebuild() {
+	fm.resize(roundUp(uint64(len(fm.buckets)) + 1))
+}
+
+// resize rehashes every key into a new bucket of the given size,
+// which must be a power of two large enough to hold them.
+func (fm *IntegerMap[K, V]) resize(size uint64) {
+	buckets := make(buckets[K, V], size)
+	for _, b := range fm.buckets {
+		if !b.used {
+			continue
+		}
+
+		buckets.set(b.key, b.value)
+	}
+	fm.buckets = buckets
+}
+
+// Get returns an item from the map if it exists.  Otherwise,
+// returns the zero value and false for the second argument.
+func (fm *IntegerMap[K, V]) Get(key K) (V, bool) {
+	return fm.buckets.get(key)
+}
+
+// Set will set the provided key with the provided value.
+func (fm *IntegerMap[K, V]) Set(key K, value V) {
+	if float64(fm.count+1)/float64(len(fm.buckets)) > fm.maxLoad() {
+		fm.rebuild()
+	}
+
+	if fm.buckets.set(key, value) {
+		fm.count++
+	}
+}
+
+// GetMany looks up every provided key, returning the values and
+// whether each key was found in the same order as the keys.
+func (fm *IntegerMap[K, V]) GetMany(keys []K) ([]V, []bool) {
+	values := make([]V, len(keys))
+	found := make([]bool, len(keys))
+	fm.GetManyInto(keys, values, found)
+	return values, found
+}
+
+// GetManyInto is GetMany writing into the provided slices, which must
+// be at least as long as keys, so that lookups made in a loop need not
+// allocate.  The probes are made in a single tight loop with no calls
+// between them, which is faster than calling Get for each key.
+func (fm *IntegerMap[K, V]) GetManyInto(keys []K, values []V, found []bool) {
+	if len(values) < len(keys) || len(found) < len(keys) {
+		panic(`fastinteger: fewer results than keys`)
+	}
+	values, found = values[:len(keys)], found[:len(keys)]
+
+	buckets := fm.buckets
+	mask := uint64(len(buckets)) - 1
+	for i, key := range keys {
+		j := hashKey(key) & mask
+		for buckets[j].used && buckets[j].key != key {
+			j = (j + 1) & mask
+		}
+		values[i], found[i] = buckets[j].value, buckets[j].used
+	}
+}
+
+// PutMany sets each of the provided keys with the value at the same
+// index of values, which must be at least as long as keys.  The map
+// grows at most once, up front, to fit every key.
+func (fm *IntegerMap[K, V]) PutMany(keys []K, values []V) {
+	if len(values) < len(keys) {
+		panic(`fastinteger: fewer values than keys`)
+	}
+
+	needed := fm.count + uint64(len(keys))
+	if float64(needed)/float64(len(fm.buckets)) > fm.maxLoad() {
+		fm.resize(sizeFor(needed, fm.maxLoad()))
+	}
+
+	for i, key := range keys {
+		if fm.buckets.set(key, values[i]) {
+			fm.count++
+		}
+	}
+}
+
+// Exists will return a bool indicating if the provided key
+// exists in the map.
+func (fm *IntegerMap[K, V]) Exists(key K) bool {
+	return fm.buckets.exists(key)
+}
+
+// Delete will remove the provided key from the map.  If
+// the key cannot be found, this is a no-op.
+func (fm *IntegerMap[K, V]) Delete(key K) {
+	if !fm.buckets.delete(key) {
+		return
+	}
+
+	fm.count--
+	size := uint64(len(fm.buckets))
+	if size > fm.min && float64(fm.count)/float64(size) < fm.maxLoad()/4 {
+		fm.resize(size / 2)
+	}
+}
+
+// Compact shrinks the map to the smallest size that holds its keys
+// at no more than half the maximum load, rehashing them.  This may
+// go below the size the map was created with, which the map will
+// then grow back from as usual.  Deletes already shrink the map
+// gradually, so this is only needed to reclaim memory at once, for
+// instance after a bulk delete ahead of a long read-only phase.
+func (fm *IntegerMap[K, V]) Compact() {
+	size := max(sizeFor(fm.count, fm.maxLoad()/2), 2)
+	if size < uint64(len(fm.buckets)) {
+		fm.resize(size)
+		fm.min = min(fm.min, size)
+	}
+}
+
+// Keys returns every key in the map, in no particular order but in
+// the same order as Values as long as the map is not modified in
+// between.
+func (fm *IntegerMap[K, V]) Keys() []K {
+	keys := make([]K, 0, fm.count)
+	for _, b := range fm.buckets {
+		if b.used {
+			keys = append(keys, b.key)
+		}
+	}
+	return keys
+}
+
+// Values returns every value in the map, in the same order as Keys.
+func (fm *IntegerMap[K, V]) Values() []V {
+	values := make([]V, 0, fm.count)
+	for _, b := range fm.buckets {
+		if b.used {
+			values = append(values, b.value)
+		}
+	}
+	return values
+}
+
+// Clone returns a copy of the map that can be modified independently.
+// Values are copied as they are, so pointers are shared.
+func (fm *IntegerMap[K, V]) Clone() *IntegerMap[K, V] {
+	clone := *fm
+	clone.buckets = make(buckets[K, V], len(fm.buckets))
+	copy(clone.buckets, fm.buckets)
+	return &clone
+}
+
+// MemStats estimates the memory held by the map.  Nodes is the number
+// of buckets, whether used or not.  This is O(1).
+func (fm *IntegerMap[K, V]) MemStats() common.MemStats {
+	var b bucket[K, V]
+	return common.MemStats{
+		Items: fm.count,
+		Nodes: uint64(len(fm.buckets)),
+		Slots: uint64(cap(fm.buckets)),
+		Bytes: uint64(unsafe.Sizeof(*fm)) + uint64(cap(fm.buckets))*uint64(unsafe.Sizeof(b)),
+	}
+}
+
+// CloneWith is like Clone, but copies each value with copyFn.
+func (fm *IntegerMap[K, V]) CloneWith(copyFn func(V) V) *IntegerMap[K, V] {
+	clone := fm.Clone()
+	for i := range clone.buckets {
+		if clone.buckets[i].used {
+			clone.buckets[i].value = copyFn(clone.buckets[i].value)
+		}
+	}
+	return clone
+}
+
+// Len returns the number of items in the map.
+func (fm *IntegerMap[K, V]) Len() uint64 {
+	return fm.count
+}
+
+// Cap returns the capacity of the map.
+func (fm *IntegerMap[K, V]) Cap() uint64 {
+	return uint64(len(fm.buckets))
+}
+
+// NewIntegerMap returns a new IntegerMap with a bucket size specified
+// by hint.
+func NewIntegerMap[K Key, V any](hint uint64, options ...Option) *IntegerMap[K, V] {
+	if hint == 0 {
+		hint = 16
+	}
+
+	hint = roundUp(hint)
+	return &IntegerMap[K, V]{
+		min:        hint,
+		loadFactor: newOptions(options).loadFactor,
+		buckets:    make(buckets[K, V], hint),
+	}
+}
+
+// NewIntegerMapWithCapacity returns a new IntegerMap large enough to
+// hold n items without growing at the configured load factor.
+func NewIntegerMapWithCapacity[K Key, V any](n uint64, options ...Option) *IntegerMap[K, V] {
+	return NewIntegerMap[K, V](sizeFor(n, newOptions(options).loadFactor), options...)
+}
+
+// FastIntegerMap is FastIntegerHashMap for values of any type.
+type FastIntegerMap[V any] = IntegerMap[uint64, V]
+
+// FastInt64Map is FastIntegerMap for signed keys.  Negative keys are
+// hashed by their two's complement bits, so every int64 is distinct.
+type FastInt64Map[V any] = IntegerMap[int64, V]
+
+// FastUint32Map is FastIntegerMap for 32-bit keys, which are hashed
+// with a 32-bit finalizer.
+type FastUint32Map[V any] = IntegerMap[uint32, V]
+
+// NewMap returns a new FastIntegerMap with a bucket size specified
+// by hint.
+func NewMap[V any](hint uint64, options ...Option) *FastIntegerMap[V] {
+	return NewIntegerMap[uint64, V](hint, options...)
+}
+
+// NewMapWithCapacity returns a new FastIntegerMap large enough to hold
+// n items without growing at the configured load factor.
+func NewMapWithCapacity[V any](n uint64, options ...Option) *FastIntegerMap[V] {
+	return NewIntegerMapWithCapacity[uint64, V](n, options...)
+}
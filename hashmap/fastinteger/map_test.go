@@ -0,0 +1,394 @@
+package fastinteger
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type user struct {
+	name string
+}
+
+func TestMapPointers(t *testing.T) {
+	m := NewMap[*user](4)
+	alice, bob := &user{name: "alice"}, &user{name: "bob"}
+
+	m.Set(1, alice)
+	m.Set(2, bob)
+
+	value, ok := m.Get(1)
+	assert.True(t, ok)
+	assert.Same(t, alice, value)
+
+	value, ok = m.Get(3)
+	assert.False(t, ok)
+	assert.Nil(t, value)
+
+	m.Set(3, nil)
+	assert.True(t, m.Exists(3))
+	assert.Equal(t, uint64(3), m.Len())
+}
+
+func TestMapStructs(t *testing.T) {
+	m := NewMap[user](0)
+	numItems := uint64(100)
+
+	for i := range numItems {
+		m.Set(i, user{name: string(rune('a' + i%26))})
+	}
+	assert.Equal(t, numItems, m.Len())
+
+	for i := range numItems {
+		value, ok := m.Get(i)
+		assert.True(t, ok)
+		assert.Equal(t, string(rune('a'+i%26)), value.name)
+	}
+
+	for i := range numItems / 2 {
+		m.Delete(i * 2)
+	}
+	assert.Equal(t, numItems/2, m.Len())
+	for i := range numItems {
+		assert.Equal(t, i%2 == 1, m.Exists(i))
+	}
+}
+
+func TestMapOverwriteDoesNotCount(t *testing.T) {
+	m := NewMap[string](0)
+
+	m.Set(5, "a")
+	m.Set(5, "b")
+
+	value, _ := m.Get(5)
+	assert.Equal(t, "b", value)
+	assert.Equal(t, uint64(1), m.Len())
+}
+
+func BenchmarkMapInsert(b *testing.B) {
+	numItems := uint64(1000)
+
+	keys := generateKeys(int(numItems))
+
+	for b.Loop() {
+		m := NewMap[*user](numItems * 2) // so we don't rebuild
+		for _, k := range keys {
+			m.Set(k, nil)
+		}
+	}
+}
+
+func TestMapShrinksOnDelete(t *testing.T) {
+	m := NewMap[int](16)
+	numItems := uint64(1000)
+
+	for i := range numItems {
+		m.Set(i, int(i))
+	}
+	peak := m.Cap()
+
+	for i := range numItems - 10 {
+		m.Delete(i)
+	}
+	assert.Less(t, m.Cap(), peak/16)
+	assert.GreaterOrEqual(t, m.Cap(), uint64(16))
+	for i := range numItems {
+		value, ok := m.Get(i)
+		assert.Equal(t, i >= numItems-10, ok)
+		if ok {
+			assert.Equal(t, int(i), value)
+		}
+	}
+
+	for i := numItems - 10; i < numItems; i++ {
+		m.Delete(i)
+	}
+	assert.Equal(t, uint64(16), m.Cap())
+}
+
+func TestMapDoesNotShrinkBelowHint(t *testing.T) {
+	m := NewMap[int](1024)
+
+	for i := range uint64(100) {
+		m.Set(i, 0)
+	}
+	for i := range uint64(100) {
+		m.Delete(i)
+	}
+	assert.Equal(t, uint64(1024), m.Cap())
+}
+
+func TestMapCompact(t *testing.T) {
+	m := NewMap[int](1024)
+
+	for i := range uint64(10) {
+		m.Set(i, int(i))
+	}
+	m.Compact()
+	assert.Equal(t, uint64(32), m.Cap())
+	for i := range uint64(10) {
+		value, ok := m.Get(i)
+		assert.True(t, ok)
+		assert.Equal(t, int(i), value)
+	}
+
+	m.Compact()
+	assert.Equal(t, uint64(32), m.Cap())
+
+	for i := range uint64(10) {
+		m.Delete(i)
+	}
+	m.Compact()
+	assert.Equal(t, uint64(2), m.Cap())
+	m.Set(1, 1)
+	m.Set(2, 2)
+	assert.Equal(t, uint64(2), m.Len())
+}
+
+// benchmarkExistsAfterDelete measures lookups of the survivors of a
+// bulk delete, optionally compacting the map first.
+func benchmarkExistsAfterDelete(b *testing.B, compact bool) {
+	numItems := 1 << 20
+	keys := generateKeys(numItems)
+
+	m := NewMap[uint64](uint64(numItems) * 2)
+	for _, key := range keys {
+		m.Set(key, key)
+	}
+	for _, key := range keys[1000:] {
+		m.Delete(key)
+	}
+	if compact {
+		m.Compact()
+	}
+
+	b.ResetTimer()
+	for b.Loop() {
+		for _, key := range keys[:1000] {
+			m.Exists(key)
+		}
+	}
+}
+
+func BenchmarkExistsAfterDelete(b *testing.B) {
+	benchmarkExistsAfterDelete(b, false)
+}
+
+func BenchmarkExistsAfterDeleteCompacted(b *testing.B) {
+	benchmarkExistsAfterDelete(b, true)
+}
+
+func TestMapGetMany(t *testing.T) {
+	m := NewMap[string](0)
+	m.Set(1, "a")
+	m.Set(3, "c")
+
+	values, found := m.GetMany([]uint64{3, 2, 1})
+	assert.Equal(t, []string{"c", "", "a"}, values)
+	assert.Equal(t, []bool{true, false, true}, found)
+
+	values, found = m.GetMany(nil)
+	assert.Empty(t, values)
+	assert.Empty(t, found)
+
+	values, found = make([]string, 3), make([]bool, 3)
+	m.GetManyInto([]uint64{1}, values, found)
+	assert.Equal(t, []string{"a", "", ""}, values)
+	assert.Equal(t, []bool{true, false, false}, found)
+	assert.Panics(t, func() {
+		m.GetManyInto([]uint64{1, 2}, values[:1], found)
+	})
+}
+
+func TestMapPutMany(t *testing.T) {
+	m := NewMap[uint64](16)
+	keys := generateKeys(1000)
+
+	m.PutMany(keys, keys)
+	m.PutMany(keys[:10], make([]uint64, 10))
+	assert.Equal(t, uint64(1000), m.Len())
+	assert.GreaterOrEqual(t, m.maxLoad(), float64(m.Len())/float64(m.Cap()))
+
+	values, found := m.GetMany(keys)
+	for i, key := range keys {
+		assert.True(t, found[i])
+		if i < 10 {
+			assert.Zero(t, values[i])
+		} else {
+			assert.Equal(t, key, values[i])
+		}
+	}
+
+	assert.Panics(t, func() {
+		m.PutMany(keys, keys[:1])
+	})
+}
+
+func BenchmarkMapGet(b *testing.B) {
+	numItems := uint64(1000)
+
+	keys := generateKeys(int(numItems))
+	m := NewMap[uint64](numItems * 2)
+	m.PutMany(keys, keys)
+
+	for b.Loop() {
+		for _, key := range keys {
+			m.Get(key)
+		}
+	}
+}
+
+func BenchmarkMapGetManyInto(b *testing.B) {
+	numItems := uint64(1000)
+
+	keys := generateKeys(int(numItems))
+	m := NewMap[uint64](numItems * 2)
+	m.PutMany(keys, keys)
+
+	values := make([]uint64, len(keys))
+	found := make([]bool, len(keys))
+	for b.Loop() {
+		m.GetManyInto(keys, values, found)
+	}
+}
+
+func TestMapLoadFactor(t *testing.T) {
+	m := NewMap[int](16, WithLoadFactor(0.5))
+
+	for i := range uint64(8) {
+		m.Set(i, 0)
+	}
+	assert.Equal(t, uint64(16), m.Cap())
+
+	m.Set(8, 0)
+	assert.Equal(t, uint64(32), m.Cap())
+
+	assert.Panics(t, func() {
+		WithLoadFactor(1)
+	})
+	assert.Panics(t, func() {
+		WithLoadFactor(0)
+	})
+}
+
+func TestNewWithCapacity(t *testing.T) {
+	for _, n := range []uint64{0, 1, 12, 13, 1000} {
+		m := NewWithCapacity(n)
+		capacity := m.Cap()
+		for i := range n {
+			m.Set(i, i)
+		}
+		assert.Equal(t, capacity, m.Cap(), "n=%d", n)
+	}
+
+	m := NewMapWithCapacity[int](100, WithLoadFactor(0.25))
+	assert.Equal(t, uint64(512), m.Cap())
+}
+
+func TestMapKeysValues(t *testing.T) {
+	m := NewMap[string](0)
+	assert.Empty(t, m.Keys())
+	assert.Empty(t, m.Values())
+
+	m.Set(1, "a")
+	m.Set(2, "b")
+	m.Set(3, "c")
+	m.Delete(2)
+
+	keys, values := m.Keys(), m.Values()
+	assert.ElementsMatch(t, []uint64{1, 3}, keys)
+	for i, key := range keys {
+		value, _ := m.Get(key)
+		assert.Equal(t, value, values[i])
+	}
+}
+
+func TestMapClone(t *testing.T) {
+	m := NewMap[int](0, WithLoadFactor(0.5))
+	for i := range uint64(20) {
+		m.Set(i, int(i))
+	}
+
+	clone := m.Clone()
+	clone.Set(100, 100)
+	clone.Delete(0)
+	m.Set(0, -1)
+
+	assert.Equal(t, uint64(20), m.Len())
+	assert.Equal(t, uint64(20), clone.Len())
+	assert.False(t, m.Exists(100))
+	assert.False(t, clone.Exists(0))
+	value, _ := m.Get(0)
+	assert.Equal(t, -1, value)
+	assert.Equal(t, m.maxLoad(), clone.maxLoad())
+}
+
+func TestMapCloneWith(t *testing.T) {
+	m := NewMap[[]int](0)
+	m.Set(1, []int{1})
+	m.Set(2, []int{2})
+
+	clone := m.CloneWith(slices.Clone[[]int])
+	value, _ := clone.Get(1)
+	value[0] = 10
+
+	value, _ = m.Get(1)
+	assert.Equal(t, []int{1}, value)
+	value, _ = clone.Get(2)
+	assert.Equal(t, []int{2}, value)
+}
+
+func TestMapMemStats(t *testing.T) {
+	m := NewMap[int64](16)
+	for i := range uint64(5) {
+		m.Set(i, int64(i))
+	}
+
+	stats := m.MemStats()
+	assert.Equal(t, uint64(5), stats.Items)
+	assert.Equal(t, m.Cap(), stats.Nodes)
+	assert.Equal(t, m.Cap(), stats.Slots)
+	assert.Equal(t, uint64(unsafe.Sizeof(*m))+m.Cap()*uint64(unsafe.Sizeof(bucket[uint64, int64]{})), stats.Bytes)
+}
+
+func TestInt64Map(t *testing.T) {
+	m := NewIntegerMap[int64, string](0)
+
+	m.Set(-1, "minus one")
+	m.Set(1, "one")
+	m.Set(math.MinInt64, "min")
+
+	value, ok := m.Get(-1)
+	assert.True(t, ok)
+	assert.Equal(t, "minus one", value)
+	value, _ = m.Get(math.MinInt64)
+	assert.Equal(t, "min", value)
+	assert.False(t, m.Exists(math.MaxInt64))
+	assert.ElementsMatch(t, []int64{-1, 1, math.MinInt64}, m.Keys())
+
+	var _ *FastInt64Map[string] = m
+}
+
+func TestUint32Map(t *testing.T) {
+	m := NewIntegerMapWithCapacity[uint32, uint32](1000)
+	capacity := m.Cap()
+
+	for i := range uint32(1000) {
+		m.Set(i*7919, i)
+	}
+	assert.Equal(t, capacity, m.Cap())
+	for i := range uint32(1000) {
+		value, ok := m.Get(i * 7919)
+		assert.True(t, ok)
+		assert.Equal(t, i, value)
+	}
+	for i := range uint32(1000) {
+		m.Delete(i * 7919)
+	}
+	assert.Equal(t, uint64(0), m.Len())
+
+	var _ *FastUint32Map[uint32] = m
+}
@@ -0,0 +1,58 @@
+package fastinteger
+
+// Merge sets every key of other in the map.  Where a key is in both,
+// onConflict is called with the map's value and then other's, and its
+// result is kept.  If onConflict is nil, other's value is kept.  The
+// map grows at most once, up front.  other is not modified.
+func (fm *IntegerMap[K, V]) Merge(other *IntegerMap[K, V], onConflict func(a, b V) V) {
+	needed := fm.count + other.count
+	if float64(needed)/float64(len(fm.buckets)) > fm.maxLoad() {
+		fm.resize(sizeFor(needed, fm.maxLoad()))
+	}
+
+	for _, b := range other.buckets {
+		if !b.used {
+			continue
+		}
+
+		i := fm.buckets.find(b.key)
+		if !fm.buckets[i].used {
+			fm.buckets[i] = b
+			fm.count++
+			continue
+		}
+		if onConflict == nil {
+			fm.buckets[i].value = b.value
+		} else {
+			fm.buckets[i].value = onConflict(fm.buckets[i].value, b.value)
+		}
+	}
+}
+
+// IntersectKeys returns the keys that are in both maps, in no
+// particular order.  The maps may hold values of different types.
+func IntersectKeys[K Key, V1, V2 any](a *IntegerMap[K, V1], b *IntegerMap[K, V2]) []K {
+	if a.count > b.count {
+		return IntersectKeys(b, a)
+	}
+
+	keys := make([]K, 0, a.count)
+	for _, bucket := range a.buckets {
+		if bucket.used && b.Exists(bucket.key) {
+			keys = append(keys, bucket.key)
+		}
+	}
+	return keys
+}
+
+// DiffKeys returns the keys that are in a but not in b, in no
+// particular order.  The maps may hold values of different types.
+func DiffKeys[K Key, V1, V2 any](a *IntegerMap[K, V1], b *IntegerMap[K, V2]) []K {
+	keys := make([]K, 0, a.count)
+	for _, bucket := range a.buckets {
+		if bucket.used && !b.Exists(bucket.key) {
+			keys = append(keys, bucket.key)
+		}
+	}
+	return keys
+}
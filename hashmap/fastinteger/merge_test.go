@@ -0,0 +1,71 @@
+package fastinteger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	a := New(0)
+	b := New(0)
+	for i := range uint64(100) {
+		a.Set(i, i)
+		b.Set(i+50, 1)
+	}
+
+	a.Merge(b, func(x, y uint64) uint64 {
+		return x + y
+	})
+	assert.Equal(t, uint64(150), a.Len())
+	assert.Equal(t, uint64(100), b.Len())
+	for i := range uint64(150) {
+		value, ok := a.Get(i)
+		assert.True(t, ok)
+		switch {
+		case i < 50:
+			assert.Equal(t, i, value)
+		case i < 100:
+			assert.Equal(t, i+1, value)
+		default:
+			assert.Equal(t, uint64(1), value)
+		}
+	}
+
+	a.Merge(b, nil)
+	value, _ := a.Get(75)
+	assert.Equal(t, uint64(1), value)
+	assert.Equal(t, uint64(150), a.Len())
+}
+
+func TestMergeShards(t *testing.T) {
+	global := New(0)
+	for shard := range uint64(4) {
+		m := New(0)
+		for i := range uint64(100) {
+			m.Set(shard*100+i, shard)
+		}
+		global.Merge(m, nil)
+	}
+
+	assert.Equal(t, uint64(400), global.Len())
+	value, _ := global.Get(250)
+	assert.Equal(t, uint64(2), value)
+}
+
+func TestIntersectAndDiffKeys(t *testing.T) {
+	a := NewMap[string](0)
+	b := NewMap[int](0)
+	for i := range uint64(10) {
+		a.Set(i, "a")
+	}
+	for i := uint64(5); i < 20; i++ {
+		b.Set(i, 0)
+	}
+
+	assert.ElementsMatch(t, []uint64{5, 6, 7, 8, 9}, IntersectKeys(a, b))
+	assert.ElementsMatch(t, []uint64{5, 6, 7, 8, 9}, IntersectKeys(b, a))
+	assert.ElementsMatch(t, []uint64{0, 1, 2, 3, 4}, DiffKeys(a, b))
+	assert.ElementsMatch(t, []uint64{10, 11, 12, 13, 14, 15, 16, 17, 18, 19}, DiffKeys(b, a))
+	assert.Empty(t, IntersectKeys(a, NewMap[int](0)))
+}
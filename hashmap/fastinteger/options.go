@@ -0,0 +1,30 @@
+package fastinteger
+
+// options holds the settings that may be given when creating a map.
+type options struct {
+	loadFactor float64
+}
+
+// Option configures a map when it is created.
+type Option func(*options)
+
+// WithLoadFactor sets the fraction of a map's buckets that may be
+// used before it grows, which must be greater than 0 and less than 1.
+// A lower load factor uses more memory for shorter probes.  Defaults
+// to 0.75.
+func WithLoadFactor(loadFactor float64) Option {
+	if loadFactor <= 0 || loadFactor >= 1 {
+		panic(`fastinteger: load factor must be between 0 and 1`)
+	}
+	return func(o *options) {
+		o.loadFactor = loadFactor
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{loadFactor: ratio}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
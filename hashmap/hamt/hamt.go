@@ -0,0 +1,416 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package hamt implements a persistent hash map as a hash array mapped
+trie.  Each level of the trie consumes five bits of a key's hash, and a
+node stores only the children that are present, indexed by a bitmap, so
+the trie is shallow and compact: Get, Put and Delete visit O(log32 n)
+nodes.
+
+A Map is immutable.  Put and Delete return a new Map that shares every
+node the change did not touch with the old one, which remains valid, so
+old versions are cheap to keep.  For a batch of changes, a Builder
+returned by Transient edits the nodes it has already copied in place,
+and produces a Map once it is done.
+
+Maps are safe for concurrent use, since they never change.  Builders
+are not.
+*/
+package hamt
+
+import (
+	"hash/maphash"
+	"iter"
+	"math/bits"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+const (
+	bitsPerLevel = 5
+	levelMask    = 1<<bitsPerLevel - 1
+)
+
+// token marks the nodes a Builder has made, which it may edit in place.
+// It is not empty, so that every token is distinct.
+type token struct {
+	_ byte
+}
+
+// slot is either a key and value, or a child node.
+type slot[K comparable, V any] struct {
+	hash  uint64
+	key   K
+	value V
+	child *node[K, V]
+}
+
+// node is either a branch, whose slots are indexed by the bits set in
+// its bitmap, or, if collision is set, a list of keys whose full hashes
+// are the same.
+type node[K comparable, V any] struct {
+	bitmap    uint32
+	collision bool
+	slots     []slot[K, V]
+	edit      *token
+}
+
+func index(hash uint64, shift uint) uint32 {
+	return 1 << ((hash >> shift) & levelMask)
+}
+
+// position returns where the slot for bit is, or would be, in a branch.
+func (n *node[K, V]) position(bit uint32) int {
+	return bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+// editable returns the node if it may be edited in place with the
+// token, and otherwise a copy of it that may.
+func (n *node[K, V]) editable(edit *token) *node[K, V] {
+	if edit != nil && n.edit == edit {
+		return n
+	}
+	return &node[K, V]{
+		bitmap:    n.bitmap,
+		collision: n.collision,
+		slots:     append([]slot[K, V](nil), n.slots...),
+		edit:      edit,
+	}
+}
+
+func (n *node[K, V]) get(hash uint64, key K) (V, bool) {
+	for shift := uint(0); ; shift += bitsPerLevel {
+		if n.collision {
+			for _, s := range n.slots {
+				if s.key == key {
+					return s.value, true
+				}
+			}
+			break
+		}
+
+		bit := index(hash, shift)
+		if n.bitmap&bit == 0 {
+			break
+		}
+		s := &n.slots[n.position(bit)]
+		if s.child == nil {
+			if s.hash == hash && s.key == key {
+				return s.value, true
+			}
+			break
+		}
+		n = s.child
+	}
+	var zero V
+	return zero, false
+}
+
+// put returns the node with the key set to the value, reporting in
+// added whether the key is new.
+func (n *node[K, V]) put(edit *token, shift uint, leaf slot[K, V], added *bool) *node[K, V] {
+	if n.collision {
+		if n.slots[0].hash != leaf.hash {
+			// the key belongs elsewhere, so the list moves down into a
+			// branch at this level alongside it.
+			branch := &node[K, V]{bitmap: index(n.slots[0].hash, shift), edit: edit}
+			branch.slots = []slot[K, V]{{hash: n.slots[0].hash, child: n}}
+			return branch.put(edit, shift, leaf, added)
+		}
+		n = n.editable(edit)
+		for i := range n.slots {
+			if n.slots[i].key == leaf.key {
+				n.slots[i].value = leaf.value
+				return n
+			}
+		}
+		n.slots = append(n.slots, leaf)
+		*added = true
+		return n
+	}
+
+	bit := index(leaf.hash, shift)
+	pos := n.position(bit)
+	if n.bitmap&bit == 0 {
+		*added = true
+		if edit == nil || n.edit != edit {
+			// copy the slots straight into their new places.
+			slots := make([]slot[K, V], len(n.slots)+1)
+			copy(slots, n.slots[:pos])
+			slots[pos] = leaf
+			copy(slots[pos+1:], n.slots[pos:])
+			return &node[K, V]{bitmap: n.bitmap | bit, slots: slots, edit: edit}
+		}
+		n.bitmap |= bit
+		n.slots = append(n.slots, slot[K, V]{})
+		copy(n.slots[pos+1:], n.slots[pos:])
+		n.slots[pos] = leaf
+		return n
+	}
+
+	s := n.slots[pos]
+	switch {
+	case s.child != nil:
+		s.child = s.child.put(edit, shift+bitsPerLevel, leaf, added)
+	case s.hash == leaf.hash && s.key == leaf.key:
+		s.value = leaf.value
+	default:
+		s = slot[K, V]{hash: s.hash, child: pair(edit, shift+bitsPerLevel, s, leaf)}
+		*added = true
+	}
+	n = n.editable(edit)
+	n.slots[pos] = s
+	return n
+}
+
+// pair returns a node holding two keys, which share the hash bits
+// below shift.
+func pair[K comparable, V any](edit *token, shift uint, a, b slot[K, V]) *node[K, V] {
+	if a.hash == b.hash {
+		return &node[K, V]{collision: true, slots: []slot[K, V]{a, b}, edit: edit}
+	}
+	bitA, bitB := index(a.hash, shift), index(b.hash, shift)
+	if bitA == bitB {
+		child := pair(edit, shift+bitsPerLevel, a, b)
+		return &node[K, V]{bitmap: bitA, slots: []slot[K, V]{{hash: a.hash, child: child}}, edit: edit}
+	}
+	if bitA > bitB {
+		a, b = b, a
+	}
+	return &node[K, V]{bitmap: bitA | bitB, slots: []slot[K, V]{a, b}, edit: edit}
+}
+
+// delete returns the node without the key, or nil if it is left empty,
+// reporting in removed whether the key was there.
+func (n *node[K, V]) delete(edit *token, shift uint, hash uint64, key K, removed *bool) *node[K, V] {
+	if n.collision {
+		for i, s := range n.slots {
+			if s.key == key {
+				*removed = true
+				if len(n.slots) == 1 {
+					return nil
+				}
+				n = n.editable(edit)
+				n.slots = append(n.slots[:i], n.slots[i+1:]...)
+				return n
+			}
+		}
+		return n
+	}
+
+	bit := index(hash, shift)
+	if n.bitmap&bit == 0 {
+		return n
+	}
+	pos := n.position(bit)
+	s := n.slots[pos]
+	if s.child == nil {
+		if s.hash != hash || s.key != key {
+			return n
+		}
+		*removed = true
+		if len(n.slots) == 1 {
+			return nil
+		}
+		n = n.editable(edit)
+		n.bitmap &^= bit
+		n.slots = append(n.slots[:pos], n.slots[pos+1:]...)
+		return n
+	}
+
+	child := s.child.delete(edit, shift+bitsPerLevel, hash, key, removed)
+	if !*removed {
+		return n
+	}
+	switch {
+	case child == nil:
+		// only happens to a branch holding a single child, which is
+		// collapsed into its parent.
+		if len(n.slots) == 1 {
+			return nil
+		}
+		n = n.editable(edit)
+		n.bitmap &^= bit
+		n.slots = append(n.slots[:pos], n.slots[pos+1:]...)
+		return n
+	case len(child.slots) == 1 && child.slots[0].child == nil:
+		// a single key left below moves up in place of its node, and
+		// on up through any parents it is then alone in.
+		s = child.slots[0]
+	default:
+		s.child = child
+	}
+	n = n.editable(edit)
+	n.slots[pos] = s
+	return n
+}
+
+func (n *node[K, V]) all(yield func(K, V) bool) bool {
+	for i := range n.slots {
+		s := &n.slots[i]
+		if s.child != nil {
+			if !s.child.all(yield) {
+				return false
+			}
+		} else if !yield(s.key, s.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Map is a persistent hash map.  The zero Map is not usable; create
+// one with New or NewWithHasher.
+type Map[K comparable, V any] struct {
+	root   *node[K, V]
+	len    int
+	hasher common.Hasher[K]
+}
+
+// New returns an empty Map, which hashes keys with the runtime's seeded
+// hash function.  The Maps derived from it share its hash function.
+func New[K comparable, V any]() *Map[K, V] {
+	return NewWithHasher[K, V](common.SeededHash[K](maphash.MakeSeed()))
+}
+
+// NewWithHasher is like New, but hashes keys with the provided hasher,
+// such as one of the hash functions in the common package.
+func NewWithHasher[K comparable, V any](hasher common.Hasher[K]) *Map[K, V] {
+	return &Map[K, V]{hasher: hasher}
+}
+
+// Len returns the number of keys in the map.
+func (m *Map[K, V]) Len() int {
+	return m.len
+}
+
+// Get returns the value stored under the key, and whether there is one.
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	if m.root == nil {
+		var zero V
+		return zero, false
+	}
+	return m.root.get(m.hasher.Hash(key), key)
+}
+
+// Put returns a map with the value stored under the key, replacing
+// any value already under it.
+func (m *Map[K, V]) Put(key K, value V) *Map[K, V] {
+	root, added := put(m.root, nil, slot[K, V]{hash: m.hasher.Hash(key), key: key, value: value})
+	return &Map[K, V]{root: root, len: m.len + added, hasher: m.hasher}
+}
+
+// Delete returns a map without the key, which is this map if the key
+// is not in it.
+func (m *Map[K, V]) Delete(key K) *Map[K, V] {
+	root, removed := remove(m.root, nil, m.hasher.Hash(key), key)
+	if removed == 0 {
+		return m
+	}
+	return &Map[K, V]{root: root, len: m.len - removed, hasher: m.hasher}
+}
+
+// All returns an iterator over the keys and values in the map, in an
+// order that depends on their hashes.
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if m.root != nil {
+			m.root.all(yield)
+		}
+	}
+}
+
+// Transient returns a Builder that starts from this map, which it does
+// not change.
+func (m *Map[K, V]) Transient() *Builder[K, V] {
+	return &Builder[K, V]{root: m.root, len: m.len, hasher: m.hasher, edit: new(token)}
+}
+
+func put[K comparable, V any](root *node[K, V], edit *token, leaf slot[K, V]) (*node[K, V], int) {
+	if root == nil {
+		return &node[K, V]{bitmap: index(leaf.hash, 0), slots: []slot[K, V]{leaf}, edit: edit}, 1
+	}
+	var added bool
+	root = root.put(edit, 0, leaf, &added)
+	if added {
+		return root, 1
+	}
+	return root, 0
+}
+
+func remove[K comparable, V any](root *node[K, V], edit *token, hash uint64, key K) (*node[K, V], int) {
+	if root == nil {
+		return nil, 0
+	}
+	var removed bool
+	root = root.delete(edit, 0, hash, key, &removed)
+	if removed {
+		return root, 1
+	}
+	return root, 0
+}
+
+// Builder makes a batch of changes to a Map, editing in place the nodes
+// it has copied rather than copying them again for each change.  A
+// Builder is not safe for concurrent use.
+type Builder[K comparable, V any] struct {
+	root   *node[K, V]
+	len    int
+	hasher common.Hasher[K]
+	edit   *token
+}
+
+// Len returns the number of keys in the map being built.
+func (b *Builder[K, V]) Len() int {
+	return b.len
+}
+
+// Get returns the value stored under the key, and whether there is one.
+func (b *Builder[K, V]) Get(key K) (V, bool) {
+	if b.root == nil {
+		var zero V
+		return zero, false
+	}
+	return b.root.get(b.hasher.Hash(key), key)
+}
+
+// Put stores the value under the key, replacing any value already
+// under it.
+func (b *Builder[K, V]) Put(key K, value V) *Builder[K, V] {
+	var added int
+	b.root, added = put(b.root, b.edit, slot[K, V]{hash: b.hasher.Hash(key), key: key, value: value})
+	b.len += added
+	return b
+}
+
+// Delete removes the key, reporting whether it was there.
+func (b *Builder[K, V]) Delete(key K) bool {
+	var removed int
+	b.root, removed = remove(b.root, b.edit, b.hasher.Hash(key), key)
+	b.len -= removed
+	return removed == 1
+}
+
+// Map returns a Map of the changes made so far.  The builder may
+// continue to be used; later changes do not affect maps already
+// returned.
+func (b *Builder[K, V]) Map() *Map[K, V] {
+	// a new token stops later changes from editing the nodes now shared
+	// with the map.
+	b.edit = new(token)
+	return &Map[K, V]{root: b.root, len: b.len, hasher: b.hasher}
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hamt
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+func collect[K comparable, V any](m *Map[K, V]) map[K]V {
+	all := make(map[K]V)
+	for key, value := range m.All() {
+		all[key] = value
+	}
+	return all
+}
+
+// depth returns the greatest depth of the trie, checking that no node
+// is left holding a single key that should have moved up.
+func depth[K comparable, V any](t *testing.T, n *node[K, V], root bool) int {
+	if n == nil {
+		return 0
+	}
+	if !root && len(n.slots) == 1 {
+		assert.NotNil(t, n.slots[0].child, "uncollapsed node")
+	}
+	d := 0
+	for _, s := range n.slots {
+		if s.child != nil {
+			d = max(d, depth(t, s.child, false))
+		}
+	}
+	return d + 1
+}
+
+func TestMap(t *testing.T) {
+	empty := New[string, int]()
+	_, ok := empty.Get("a")
+	assert.False(t, ok)
+	assert.Same(t, empty, empty.Delete("a"))
+
+	one := empty.Put("a", 1)
+	two := one.Put("b", 2)
+	replaced := two.Put("a", 10)
+
+	assert.Equal(t, 0, empty.Len())
+	assert.Equal(t, map[string]int{"a": 1}, collect(one))
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, collect(two))
+	assert.Equal(t, map[string]int{"a": 10, "b": 2}, collect(replaced))
+	assert.Equal(t, 2, replaced.Len())
+
+	deleted := replaced.Delete("a")
+	assert.Equal(t, map[string]int{"b": 2}, collect(deleted))
+	assert.Equal(t, 1, deleted.Len())
+	assert.Equal(t, 0, deleted.Delete("b").Len())
+	value, _ := replaced.Get("a")
+	assert.Equal(t, 10, value)
+}
+
+func TestCollisions(t *testing.T) {
+	// keys hash to only a few distinct values, sharing long prefixes.
+	hasher := common.HashFunc[int](func(key int) uint64 {
+		return uint64(key%3) << 62
+	})
+	m := NewWithHasher[int, int](hasher)
+	for i := 0; i < 30; i++ {
+		m = m.Put(i, i)
+	}
+	assert.Equal(t, 30, m.Len())
+	for i := 0; i < 30; i++ {
+		value, ok := m.Get(i)
+		require.True(t, ok)
+		assert.Equal(t, i, value)
+	}
+	_, ok := m.Get(30)
+	assert.False(t, ok)
+
+	for i := 0; i < 30; i += 2 {
+		m = m.Delete(i)
+	}
+	assert.Equal(t, 15, m.Len())
+	for i := 0; i < 30; i++ {
+		_, ok := m.Get(i)
+		assert.Equal(t, i%2 == 1, ok)
+	}
+	for i := 1; i < 29; i += 2 {
+		m = m.Delete(i)
+	}
+	assert.Equal(t, map[int]int{29: 29}, collect(m))
+	assert.Equal(t, 1, depth(t, m.root, true))
+}
+
+func TestBuilder(t *testing.T) {
+	base := New[int, int]().Put(1, 1).Put(2, 2)
+	b := base.Transient()
+	b.Put(3, 3).Put(1, 10)
+	assert.True(t, b.Delete(2))
+	assert.False(t, b.Delete(2))
+	assert.Equal(t, 2, b.Len())
+	value, _ := b.Get(1)
+	assert.Equal(t, 10, value)
+
+	built := b.Map()
+	b.Put(4, 4).Put(3, 30)
+	assert.Equal(t, map[int]int{1: 1, 2: 2}, collect(base))
+	assert.Equal(t, map[int]int{1: 10, 3: 3}, collect(built))
+	assert.Equal(t, map[int]int{1: 10, 3: 30, 4: 4}, collect(b.Map()))
+}
+
+func TestRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(19))
+	// a weak hash makes collisions and deep tries common.
+	weak := common.HashFunc[int](func(key int) uint64 {
+		return common.Mix64(uint64(key)) & 0xf0000000000000ff
+	})
+	for _, hasher := range []common.Hasher[int]{common.IntegerHash[int](), weak} {
+		m := NewWithHasher[int, int](hasher)
+		expected := make(map[int]int)
+		var versions []*Map[int, int]
+		var snapshots []map[int]int
+		b := m.Transient()
+		for op := 0; op < 5000; op++ {
+			key := rng.Intn(500)
+			switch rng.Intn(6) {
+			case 0:
+				m = m.Delete(key)
+				delete(expected, key)
+			case 1:
+				// apply a batch through a builder.
+				b = m.Transient()
+				for i := 0; i < 20; i++ {
+					key := rng.Intn(500)
+					if rng.Intn(3) == 0 {
+						b.Delete(key)
+						delete(expected, key)
+					} else {
+						b.Put(key, op)
+						expected[key] = op
+					}
+				}
+				m = b.Map()
+			default:
+				m = m.Put(key, op)
+				expected[key] = op
+			}
+			require.Equal(t, len(expected), m.Len())
+			if op%250 == 0 {
+				versions = append(versions, m)
+				snapshot := make(map[int]int, len(expected))
+				for k, v := range expected {
+					snapshot[k] = v
+				}
+				snapshots = append(snapshots, snapshot)
+			}
+		}
+		assert.Equal(t, expected, collect(m))
+		for key := 0; key < 500; key++ {
+			value, ok := m.Get(key)
+			expectedValue, expectedOK := expected[key]
+			assert.Equal(t, expectedOK, ok)
+			assert.Equal(t, expectedValue, value)
+		}
+		// old versions are unaffected by later changes.
+		for i, version := range versions {
+			assert.Equal(t, snapshots[i], collect(version))
+		}
+		depth(t, m.root, true)
+	}
+}
+
+func BenchmarkPut(b *testing.B) {
+	m := New[int, int]()
+	for i := 0; i < b.N; i++ {
+		m = m.Put(i, i)
+	}
+}
+
+func BenchmarkBuilderPut(b *testing.B) {
+	builder := New[int, int]().Transient()
+	for i := 0; i < b.N; i++ {
+		builder.Put(i, i)
+	}
+}
+
+func BenchmarkGet(b *testing.B) {
+	builder := New[int, int]().Transient()
+	for i := 0; i < 1<<16; i++ {
+		builder.Put(i, i)
+	}
+	m := builder.Map()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Get(i & (1<<16 - 1))
+	}
+}
@@ -0,0 +1,38 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linked
+
+import "github.com/Workiva/go-datastructures/common"
+
+// MarshalJSON encodes the map as a JSON object with its keys in order.
+// Keys are encoded as encoding/json encodes the keys of a built-in map:
+// strings directly, then types implementing encoding.TextMarshaler,
+// then integers as decimal strings.
+func (m *Map[K, V]) MarshalJSON() ([]byte, error) {
+	return common.MarshalJSONObject(m.All(), common.DefaultJSONCodec[V]())
+}
+
+// UnmarshalJSON decodes a JSON object into the map, putting its keys in
+// the order they appear.  Keys already in the map keep their place,
+// and a key that appears more than once keeps its first place and its
+// last value.  null leaves the map unchanged, as does an object that
+// fails to decode.
+func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
+	return common.UnmarshalJSONObject(data, common.DefaultJSONCodec[V](), func(key K, value V) {
+		m.Put(key, value)
+	})
+}
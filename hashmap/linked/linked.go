@@ -0,0 +1,201 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package linked implements a hash map that remembers the order keys were
+inserted in, by threading its entries onto a doubly linked list.  Get,
+Put and Delete are O(1), and iteration visits keys from the oldest to
+the newest, or the reverse.
+
+Keys can be moved to either end of the order, so a Map can also keep
+keys in order of use: moving a key to the back whenever it is read
+leaves the least recently used key at the front, ready to be evicted.
+
+A Map encodes to a JSON object with its keys in order, and decoding one
+keeps the order they appear in.  A Map is not threadsafe, and it must
+not be modified while it is being iterated over.
+*/
+package linked
+
+import "iter"
+
+type entry[K comparable, V any] struct {
+	key        K
+	value      V
+	prev, next *entry[K, V]
+}
+
+// Map is a hash map ordered by insertion.  The zero Map is empty and
+// ready to use, and a Map must not be copied after first use.
+type Map[K comparable, V any] struct {
+	entries map[K]*entry[K, V]
+	// root is the sentinel of a circular list, whose next entry is the
+	// front and whose previous entry is the back.
+	root entry[K, V]
+}
+
+// New returns an empty Map.
+func New[K comparable, V any]() *Map[K, V] {
+	return &Map[K, V]{}
+}
+
+func (m *Map[K, V]) init() {
+	if m.entries == nil {
+		m.entries = make(map[K]*entry[K, V])
+		m.root.prev, m.root.next = &m.root, &m.root
+	}
+}
+
+// Len returns the number of keys in the map.
+func (m *Map[K, V]) Len() int {
+	return len(m.entries)
+}
+
+// Get returns the value stored under the key, and whether there is one.
+// It does not change the order of the keys.
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	if e, ok := m.entries[key]; ok {
+		return e.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Put stores the value under the key, returning the value it replaced
+// and whether there was one.  A new key goes at the back, and a key
+// that was already present keeps its place.
+func (m *Map[K, V]) Put(key K, value V) (V, bool) {
+	m.init()
+	if e, ok := m.entries[key]; ok {
+		old := e.value
+		e.value = value
+		return old, true
+	}
+	e := &entry[K, V]{key: key, value: value}
+	m.entries[key] = e
+	m.insert(e, m.root.prev)
+	var zero V
+	return zero, false
+}
+
+// Delete removes the key, returning the value stored under it and
+// whether there was one.
+func (m *Map[K, V]) Delete(key K) (V, bool) {
+	e, ok := m.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	delete(m.entries, key)
+	m.remove(e)
+	return e.value, true
+}
+
+// MoveToFront moves the key to the front of the order, reporting
+// whether it is in the map.
+func (m *Map[K, V]) MoveToFront(key K) bool {
+	e, ok := m.entries[key]
+	if ok {
+		m.remove(e)
+		m.insert(e, &m.root)
+	}
+	return ok
+}
+
+// MoveToBack moves the key to the back of the order, reporting whether
+// it is in the map.
+func (m *Map[K, V]) MoveToBack(key K) bool {
+	e, ok := m.entries[key]
+	if ok {
+		m.remove(e)
+		m.insert(e, m.root.prev)
+	}
+	return ok
+}
+
+// Front returns the first key in the order and its value, and false if
+// the map is empty.
+func (m *Map[K, V]) Front() (K, V, bool) {
+	return m.end(m.root.next)
+}
+
+// Back returns the last key in the order and its value, and false if
+// the map is empty.
+func (m *Map[K, V]) Back() (K, V, bool) {
+	return m.end(m.root.prev)
+}
+
+func (m *Map[K, V]) end(e *entry[K, V]) (K, V, bool) {
+	if len(m.entries) == 0 {
+		var key K
+		var value V
+		return key, value, false
+	}
+	return e.key, e.value, true
+}
+
+// All returns an iterator over the keys and values in the map, from
+// front to back.
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if m.entries == nil {
+			return
+		}
+		for e := m.root.next; e != &m.root; e = e.next {
+			if !yield(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the keys and values in the map,
+// from back to front.
+func (m *Map[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if m.entries == nil {
+			return
+		}
+		for e := m.root.prev; e != &m.root; e = e.prev {
+			if !yield(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns the keys in the map, from front to back.
+func (m *Map[K, V]) Keys() []K {
+	keys := make([]K, 0, len(m.entries))
+	for key := range m.All() {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// insert links e into the list after at.
+func (m *Map[K, V]) insert(e, at *entry[K, V]) {
+	e.prev, e.next = at, at.next
+	at.next.prev = e
+	at.next = e
+}
+
+// remove unlinks e from the list.
+func (m *Map[K, V]) remove(e *entry[K, V]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev, e.next = nil, nil
+}
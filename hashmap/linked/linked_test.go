@@ -0,0 +1,194 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package linked
+
+import (
+	"encoding/json"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapOrder(t *testing.T) {
+	var m Map[string, int]
+	_, ok := m.Get("a")
+	assert.False(t, ok)
+	_, _, ok = m.Front()
+	assert.False(t, ok)
+	assert.Empty(t, m.Keys())
+
+	for i, key := range []string{"c", "a", "b"} {
+		m.Put(key, i)
+	}
+	old, replaced := m.Put("a", 10)
+	assert.True(t, replaced)
+	assert.Equal(t, 1, old)
+	assert.Equal(t, []string{"c", "a", "b"}, m.Keys())
+
+	assert.True(t, m.MoveToBack("c"))
+	assert.True(t, m.MoveToFront("b"))
+	assert.False(t, m.MoveToFront("z"))
+	assert.Equal(t, []string{"b", "a", "c"}, m.Keys())
+
+	key, value, ok := m.Front()
+	assert.True(t, ok)
+	assert.Equal(t, "b", key)
+	assert.Equal(t, 2, value)
+	key, value, ok = m.Back()
+	assert.True(t, ok)
+	assert.Equal(t, "c", key)
+	assert.Equal(t, 0, value)
+
+	var backward []string
+	for key := range m.Backward() {
+		backward = append(backward, key)
+	}
+	assert.Equal(t, []string{"c", "a", "b"}, backward)
+
+	value, ok = m.Delete("a")
+	assert.True(t, ok)
+	assert.Equal(t, 10, value)
+	_, ok = m.Delete("a")
+	assert.False(t, ok)
+	assert.Equal(t, 2, m.Len())
+	assert.Equal(t, []string{"b", "c"}, m.Keys())
+}
+
+func TestMapLRU(t *testing.T) {
+	// moving keys to the back when they are used leaves the least
+	// recently used key at the front.
+	const capacity = 3
+	m := New[int, string]()
+	use := func(key int) {
+		if _, ok := m.Get(key); ok {
+			m.MoveToBack(key)
+			return
+		}
+		if m.Len() == capacity {
+			oldest, _, _ := m.Front()
+			m.Delete(oldest)
+		}
+		m.Put(key, "")
+	}
+	for _, key := range []int{1, 2, 3, 1, 4, 2, 5} {
+		use(key)
+	}
+	assert.Equal(t, []int{4, 2, 5}, m.Keys())
+}
+
+func TestMapRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	m := New[int, int]()
+	var keys []int
+	values := make(map[int]int)
+	for op := 0; op < 5000; op++ {
+		key := rng.Intn(50)
+		switch rng.Intn(4) {
+		case 0:
+			_, ok := m.Delete(key)
+			assert.Equal(t, slices.Contains(keys, key), ok)
+			keys = slices.DeleteFunc(keys, func(k int) bool { return k == key })
+			delete(values, key)
+		case 1:
+			if m.MoveToFront(key) {
+				keys = slices.DeleteFunc(keys, func(k int) bool { return k == key })
+				keys = append([]int{key}, keys...)
+			}
+		default:
+			if _, ok := values[key]; !ok {
+				keys = append(keys, key)
+			}
+			values[key] = op
+			m.Put(key, op)
+		}
+	}
+	require.Equal(t, len(keys), m.Len())
+	assert.Equal(t, keys, m.Keys())
+	for key, value := range m.All() {
+		assert.Equal(t, values[key], value)
+	}
+}
+
+type point struct{ x, y int }
+
+func (p point) MarshalText() ([]byte, error) {
+	return json.Marshal([]int{p.x, p.y})
+}
+
+func (p *point) UnmarshalText(text []byte) error {
+	var xy []int
+	if err := json.Unmarshal(text, &xy); err != nil {
+		return err
+	}
+	p.x, p.y = xy[0], xy[1]
+	return nil
+}
+
+func TestMapJSON(t *testing.T) {
+	m := New[string, []int]()
+	m.Put("zebra", []int{1})
+	m.Put("apple", nil)
+	m.Put("mango", []int{2, 3})
+
+	b, err := json.Marshal(m)
+	require.NoError(t, err)
+	assert.Equal(t, `{"zebra":[1],"apple":null,"mango":[2,3]}`, string(b))
+
+	decoded := New[string, []int]()
+	require.NoError(t, json.Unmarshal(b, decoded))
+	assert.Equal(t, m.Keys(), decoded.Keys())
+	value, _ := decoded.Get("mango")
+	assert.Equal(t, []int{2, 3}, value)
+
+	// a Map nested in a struct is decoded without being created first.
+	var wrapper struct{ M *Map[int8, bool] }
+	require.NoError(t, json.Unmarshal([]byte(`{"M":{"3":true,"-1":false,"3":false}}`), &wrapper))
+	assert.Equal(t, []int8{3, -1}, wrapper.M.Keys())
+	value3, _ := wrapper.M.Get(3)
+	assert.False(t, value3)
+
+	points := New[point, int]()
+	points.Put(point{1, 2}, 3)
+	b, err = json.Marshal(points)
+	require.NoError(t, err)
+	assert.Equal(t, `{"[1,2]":3}`, string(b))
+	decodedPoints := New[point, int]()
+	require.NoError(t, json.Unmarshal(b, decodedPoints))
+	assert.Equal(t, []point{{1, 2}}, decodedPoints.Keys())
+
+	empty, err := json.Marshal(New[uint, int]())
+	require.NoError(t, err)
+	assert.Equal(t, `{}`, string(empty))
+}
+
+func TestMapJSONErrors(t *testing.T) {
+	m := New[int8, int]()
+	assert.Error(t, json.Unmarshal([]byte(`{"300":1}`), m))
+	assert.Error(t, json.Unmarshal([]byte(`{"a":1}`), m))
+	assert.Error(t, json.Unmarshal([]byte(`[1]`), m))
+	assert.Error(t, json.Unmarshal([]byte(`{"1":"x"}`), m))
+	require.NoError(t, json.Unmarshal([]byte(`null`), m))
+	assert.Equal(t, 0, m.Len())
+
+	floats := New[float64, int]()
+	floats.Put(1.5, 1)
+	_, err := json.Marshal(floats)
+	assert.Error(t, err)
+}
@@ -0,0 +1,304 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package multimap implements a map from each key to a collection of
+values, replacing the map of slices, or map of maps, that is otherwise
+written out by hand.  The collection under every key is of one Kind: a
+List, which keeps values in the order they were added, a Set, which
+holds each value at most once, or a Bag, which allows duplicates but
+not their order.
+
+Keys are kept in the order they were added, so iteration is
+deterministic.  Adding a value and looking up a key are O(1).  Sets and
+Bags of comparable values, created with New, index the values under
+each key by the values themselves, so checking for, adding and removing
+a value are O(1) as well.  Lists, and collections created with
+NewWithEqualFunc, hold values in a slice, so checking for or removing
+a value is linear in the number of values under its key, as is adding
+one to a Set.  A Multimap is not threadsafe, and it must not be
+modified while it is being iterated over.
+*/
+package multimap
+
+import (
+	"iter"
+	"slices"
+
+	"github.com/Workiva/go-datastructures/hashmap/linked"
+)
+
+// Kind is the kind of collection holding the values under each key.
+type Kind int
+
+const (
+	// List keeps values in the order they were added, including
+	// duplicates.
+	List Kind = iota
+	// Set holds each value at most once under a key.
+	Set
+	// Bag holds duplicate values without keeping their order, so
+	// removing one does not shift the others.
+	Bag
+)
+
+// Multimap maps each key to a collection of values.
+type Multimap[K comparable, V any] struct {
+	kind  Kind
+	equal func(a, b V) bool
+	// hashed is set when values are comparable and the collections
+	// are Sets or Bags, which are then indexed by value.
+	hashed bool
+	values linked.Map[K, *collection[V]]
+	len    int
+}
+
+// collection holds the values under one key, either in a slice or,
+// for hashed Sets and Bags, counted in a map indexed by value that
+// keeps the order in which each value was first added.
+type collection[V any] struct {
+	slice  []V
+	counts *linked.Map[any, *counted[V]]
+	len    int
+}
+
+type counted[V any] struct {
+	value V
+	n     int
+}
+
+// New returns an empty Multimap holding values in collections of the
+// given kind.
+func New[K, V comparable](kind Kind) *Multimap[K, V] {
+	m := NewWithEqualFunc[K](kind, func(a, b V) bool { return a == b })
+	m.hashed = kind != List
+	return m
+}
+
+// NewWithEqualFunc is like New, but compares values with equal, so that
+// they may be of any type.  Values are then held in a slice whatever
+// the kind, so checking for or removing one is O(n) in the number of
+// values under its key, as is adding one to a Set.
+func NewWithEqualFunc[K comparable, V any](kind Kind, equal func(a, b V) bool) *Multimap[K, V] {
+	return &Multimap[K, V]{kind: kind, equal: equal}
+}
+
+// Len returns the number of values in the multimap, under all keys.
+func (m *Multimap[K, V]) Len() int {
+	return m.len
+}
+
+// KeyLen returns the number of keys in the multimap.
+func (m *Multimap[K, V]) KeyLen() int {
+	return m.values.Len()
+}
+
+// Add adds the value under the key, reporting whether it was added,
+// which it is not if the multimap holds Sets and the value is already
+// under the key.
+func (m *Multimap[K, V]) Add(key K, value V) bool {
+	c, ok := m.values.Get(key)
+	if !ok {
+		c = &collection[V]{}
+		if m.hashed {
+			c.counts = linked.New[any, *counted[V]]()
+		}
+	}
+
+	if c.counts != nil {
+		if e, ok := c.counts.Get(value); ok {
+			if m.kind == Set {
+				return false
+			}
+			e.n++
+		} else {
+			c.counts.Put(value, &counted[V]{value: value, n: 1})
+		}
+	} else {
+		if m.kind == Set && m.index(c.slice, value) >= 0 {
+			return false
+		}
+		c.slice = append(c.slice, value)
+	}
+
+	if !ok {
+		m.values.Put(key, c)
+	}
+	c.len++
+	m.len++
+	return true
+}
+
+// Get returns a copy of the values under the key, or nil if there are
+// none.
+func (m *Multimap[K, V]) Get(key K) []V {
+	c, ok := m.values.Get(key)
+	if !ok {
+		return nil
+	}
+	if c.counts != nil {
+		return c.collect()
+	}
+	return slices.Clone(c.slice)
+}
+
+// Count returns the number of values under the key.
+func (m *Multimap[K, V]) Count(key K) int {
+	c, _ := m.values.Get(key)
+	if c == nil {
+		return 0
+	}
+	return c.len
+}
+
+// Contains reports whether the value is under the key.
+func (m *Multimap[K, V]) Contains(key K, value V) bool {
+	c, ok := m.values.Get(key)
+	if !ok {
+		return false
+	}
+	if c.counts != nil {
+		_, ok := c.counts.Get(value)
+		return ok
+	}
+	return m.index(c.slice, value) >= 0
+}
+
+// RemoveValue removes one occurrence of the value from under the key,
+// reporting whether there was one.  The key is removed along with its
+// last value.
+func (m *Multimap[K, V]) RemoveValue(key K, value V) bool {
+	c, ok := m.values.Get(key)
+	if !ok {
+		return false
+	}
+
+	if c.counts != nil {
+		e, ok := c.counts.Get(value)
+		if !ok {
+			return false
+		}
+		if e.n--; e.n == 0 {
+			c.counts.Delete(value)
+		}
+	} else {
+		i := m.index(c.slice, value)
+		if i < 0 {
+			return false
+		}
+		if m.kind == Bag {
+			last := len(c.slice) - 1
+			c.slice[i] = c.slice[last]
+			c.slice = slices.Delete(c.slice, last, last+1)
+		} else {
+			c.slice = slices.Delete(c.slice, i, i+1)
+		}
+	}
+
+	m.len--
+	if c.len--; c.len == 0 {
+		m.values.Delete(key)
+	}
+	return true
+}
+
+// RemoveAll removes the key and every value under it, returning the
+// values removed.
+func (m *Multimap[K, V]) RemoveAll(key K) []V {
+	c, ok := m.values.Delete(key)
+	if !ok {
+		return nil
+	}
+	m.len -= c.len
+	if c.counts != nil {
+		return c.collect()
+	}
+	return c.slice
+}
+
+// Keys returns the keys in the multimap, in the order they were
+// added.
+func (m *Multimap[K, V]) Keys() []K {
+	return m.values.Keys()
+}
+
+// All returns an iterator over every key and value pair in the
+// multimap, visiting keys in the order they were added, and the
+// values under each key in the order of its collection.  A hashed Bag
+// yields the duplicates of a value together.
+func (m *Multimap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for key, c := range m.values.All() {
+			for value := range c.all() {
+				if !yield(key, value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Collections returns an iterator over each key and the values under
+// it.  The slices must not be modified.  Those of hashed Sets and Bags
+// are built as they are visited, so this is O(n) in the number of
+// values for them.
+func (m *Multimap[K, V]) Collections() iter.Seq2[K, []V] {
+	return func(yield func(K, []V) bool) {
+		for key, c := range m.values.All() {
+			values := c.slice
+			if c.counts != nil {
+				values = c.collect()
+			}
+			if !yield(key, values) {
+				return
+			}
+		}
+	}
+}
+
+func (m *Multimap[K, V]) index(values []V, value V) int {
+	return slices.IndexFunc(values, func(v V) bool { return m.equal(v, value) })
+}
+
+// all returns an iterator over the values in the collection.
+func (c *collection[V]) all() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		if c.counts == nil {
+			for _, value := range c.slice {
+				if !yield(value) {
+					return
+				}
+			}
+			return
+		}
+		for _, e := range c.counts.All() {
+			for range e.n {
+				if !yield(e.value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// collect returns the values in the collection in a new slice.
+func (c *collection[V]) collect() []V {
+	values := make([]V, 0, c.len)
+	for value := range c.all() {
+		values = append(values, value)
+	}
+	return values
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package multimap
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type pair struct {
+	key, value string
+}
+
+func pairs(m *Multimap[string, string]) []pair {
+	var all []pair
+	for key, value := range m.All() {
+		all = append(all, pair{key, value})
+	}
+	return all
+}
+
+func TestList(t *testing.T) {
+	m := New[string, string](List)
+	assert.True(t, m.Add("fruit", "apple"))
+	assert.True(t, m.Add("veg", "leek"))
+	assert.True(t, m.Add("fruit", "pear"))
+	assert.True(t, m.Add("fruit", "apple"))
+
+	assert.Equal(t, 4, m.Len())
+	assert.Equal(t, 2, m.KeyLen())
+	assert.Equal(t, []string{"apple", "pear", "apple"}, m.Get("fruit"))
+	assert.Nil(t, m.Get("nuts"))
+	assert.Equal(t, 3, m.Count("fruit"))
+	assert.True(t, m.Contains("veg", "leek"))
+	assert.False(t, m.Contains("veg", "apple"))
+	assert.Equal(t, []string{"fruit", "veg"}, m.Keys())
+	assert.Equal(t, []pair{
+		{"fruit", "apple"}, {"fruit", "pear"}, {"fruit", "apple"}, {"veg", "leek"},
+	}, pairs(m))
+
+	assert.True(t, m.RemoveValue("fruit", "apple"))
+	assert.Equal(t, []string{"pear", "apple"}, m.Get("fruit"))
+	assert.False(t, m.RemoveValue("fruit", "plum"))
+	assert.True(t, m.RemoveValue("veg", "leek"))
+	assert.Equal(t, []string{"fruit"}, m.Keys())
+
+	assert.Equal(t, []string{"pear", "apple"}, m.RemoveAll("fruit"))
+	assert.Nil(t, m.RemoveAll("fruit"))
+	assert.Equal(t, 0, m.Len())
+	assert.Equal(t, 0, m.KeyLen())
+}
+
+func TestSet(t *testing.T) {
+	m := New[string, string](Set)
+	assert.True(t, m.Add("a", "x"))
+	assert.False(t, m.Add("a", "x"))
+	assert.True(t, m.Add("a", "y"))
+	assert.True(t, m.Add("b", "x"))
+	assert.Equal(t, 3, m.Len())
+	assert.Equal(t, []string{"x", "y"}, m.Get("a"))
+
+	assert.True(t, m.RemoveValue("a", "x"))
+	assert.False(t, m.Contains("a", "x"))
+	assert.True(t, m.Add("a", "x"))
+	assert.Equal(t, []string{"y", "x"}, m.Get("a"))
+}
+
+func TestGetReturnsCopy(t *testing.T) {
+	m := New[string, string](List)
+	m.Add("a", "x")
+	m.Get("a")[0] = "changed"
+	assert.Equal(t, []string{"x"}, m.Get("a"))
+}
+
+func TestEqualFunc(t *testing.T) {
+	m := NewWithEqualFunc[string](Set, slices.Equal[[]int])
+	assert.True(t, m.Add("a", []int{1, 2}))
+	assert.False(t, m.Add("a", []int{1, 2}))
+	assert.True(t, m.Contains("a", []int{1, 2}))
+	assert.True(t, m.RemoveValue("a", []int{1, 2}))
+	assert.Equal(t, 0, m.Len())
+}
+
+func TestRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	constructors := []func(Kind) *Multimap[int, int]{
+		New[int, int],
+		func(kind Kind) *Multimap[int, int] {
+			return NewWithEqualFunc[int](kind, func(a, b int) bool { return a == b })
+		},
+	}
+	for i := range len(constructors) * 3 {
+		kind := []Kind{List, Set, Bag}[i%3]
+		m := constructors[i/3](kind)
+		expected := make(map[int][]int)
+		for op := 0; op < 3000; op++ {
+			key, value := rng.Intn(10), rng.Intn(10)
+			switch rng.Intn(5) {
+			case 0:
+				i := slices.Index(expected[key], value)
+				assert.Equal(t, i >= 0, m.RemoveValue(key, value))
+				if i >= 0 {
+					expected[key] = slices.Delete(expected[key], i, i+1)
+				}
+			case 1:
+				assert.ElementsMatch(t, expected[key], m.RemoveAll(key))
+				delete(expected, key)
+			default:
+				added := kind != Set || !slices.Contains(expected[key], value)
+				assert.Equal(t, added, m.Add(key, value))
+				if added {
+					expected[key] = append(expected[key], value)
+				}
+			}
+		}
+
+		var total int
+		for key, values := range expected {
+			total += len(values)
+			if len(values) == 0 {
+				assert.Zero(t, m.Count(key))
+			} else if kind == Bag {
+				assert.ElementsMatch(t, values, m.Get(key))
+			} else {
+				assert.Equal(t, values, m.Get(key))
+			}
+		}
+		assert.Equal(t, total, m.Len())
+		var count int
+		for range m.All() {
+			count++
+		}
+		assert.Equal(t, total, count)
+	}
+}
+
+func TestCollections(t *testing.T) {
+	m := New[string, string](Bag)
+	m.Add("a", "x")
+	m.Add("b", "y")
+	m.Add("a", "z")
+	m.Add("a", "x")
+
+	collections := map[string][]string{}
+	for key, values := range m.Collections() {
+		collections[key] = values
+	}
+	assert.Equal(t, map[string][]string{"a": {"x", "x", "z"}, "b": {"y"}}, collections)
+	assert.Equal(t, []pair{{"a", "x"}, {"a", "x"}, {"a", "z"}, {"b", "y"}}, pairs(m))
+	assert.ElementsMatch(t, []string{"x", "z", "x"}, m.RemoveAll("a"))
+}
+
+func BenchmarkSetAdd(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		m := New[int, int](Set)
+		for i := range 10000 {
+			m.Add(0, i)
+		}
+	}
+}
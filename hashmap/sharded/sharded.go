@@ -0,0 +1,203 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package sharded implements a concurrent hash map for keys of any
+comparable type.  Keys are spread by their hash over a number of shards,
+each a built-in map behind its own read/write lock, so reads never wait
+on one another and writes only wait on operations that land on the same
+shard.
+
+Unlike sync.Map, which is tuned for keys that are written once and read
+many times, a Map keeps up under write-heavy workloads, and its keys and
+values are typed.
+*/
+package sharded
+
+import (
+	"hash/maphash"
+	"iter"
+	"runtime"
+	"sync"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+// shard is a single independently locked part of a Map, padded so
+// that neighbouring locks do not share a cache line.
+type shard[K comparable, V any] struct {
+	lock  sync.RWMutex
+	items map[K]V
+	_     [32]byte
+}
+
+// Map is a hash map that is safe for concurrent use.  The zero Map is
+// not usable; create one with New or NewWithHasher.
+type Map[K comparable, V any] struct {
+	shards []shard[K, V]
+	hasher common.Hasher[K]
+	shift  uint
+}
+
+// New returns a Map split into the provided number of shards, rounded
+// up to a power of two, which hashes keys with the runtime's seeded
+// hash function.  If shards is 0, four times GOMAXPROCS is used.
+func New[K comparable, V any](shards int) *Map[K, V] {
+	return NewWithHasher[K, V](shards, common.SeededHash[K](maphash.MakeSeed()))
+}
+
+// NewWithHasher is like New, but hashes keys with the provided hasher,
+// such as one of the hash functions in the common package.
+func NewWithHasher[K comparable, V any](shards int, hasher common.Hasher[K]) *Map[K, V] {
+	if shards <= 0 {
+		shards = runtime.GOMAXPROCS(0) * 4
+	}
+	m := &Map[K, V]{hasher: hasher, shift: 64}
+	for n := 1; n < shards; n <<= 1 {
+		m.shift--
+	}
+	m.shards = make([]shard[K, V], 1<<(64-m.shift))
+	for i := range m.shards {
+		m.shards[i].items = make(map[K]V)
+	}
+	return m
+}
+
+// shardFor picks a shard by the top bits of the key's hash, which is
+// mixed first in case the hasher leaves them poorly distributed.
+func (m *Map[K, V]) shardFor(key K) *shard[K, V] {
+	if m.shift == 64 {
+		return &m.shards[0]
+	}
+	return &m.shards[common.Mix64(m.hasher.Hash(key))>>m.shift]
+}
+
+// Get returns the value stored under the key, and whether there is one.
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	s := m.shardFor(key)
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	value, ok := s.items[key]
+	return value, ok
+}
+
+// Put stores the value under the key, returning the value it replaced
+// and whether there was one.
+func (m *Map[K, V]) Put(key K, value V) (V, bool) {
+	s := m.shardFor(key)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	old, replaced := s.items[key]
+	s.items[key] = value
+	return old, replaced
+}
+
+// Delete removes the key, returning the value stored under it and
+// whether there was one.
+func (m *Map[K, V]) Delete(key K) (V, bool) {
+	s := m.shardFor(key)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	value, ok := s.items[key]
+	if ok {
+		delete(s.items, key)
+	}
+	return value, ok
+}
+
+// GetOrCompute returns the value stored under the key if there is one.
+// Otherwise, it calls compute and stores and returns its result.  The
+// second result reports whether the value was already stored.  compute
+// is called while holding the lock on the key's shard, so concurrent
+// callers with the same key wait for it and see its result, and it
+// must not use the map.
+func (m *Map[K, V]) GetOrCompute(key K, compute func() V) (V, bool) {
+	s := m.shardFor(key)
+	s.lock.RLock()
+	value, ok := s.items[key]
+	s.lock.RUnlock()
+	if ok {
+		return value, true
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if value, ok := s.items[key]; ok {
+		return value, true
+	}
+	value = compute()
+	s.items[key] = value
+	return value, false
+}
+
+// Len returns the number of keys in the map.  The shards are counted
+// one at a time, so the result may be stale if the map is being
+// modified concurrently.
+func (m *Map[K, V]) Len() int {
+	var count int
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.lock.RLock()
+		count += len(s.items)
+		s.lock.RUnlock()
+	}
+	return count
+}
+
+// Range calls fn with each key and value in the map, in no particular
+// order, until fn returns false.  Like sync.Map's Range, it does not
+// see a consistent snapshot of the whole map: each shard is copied in
+// turn, and fn is called without holding any lock, so it may use the
+// map.
+func (m *Map[K, V]) Range(fn func(key K, value V) bool) {
+	for key, value := range m.All() {
+		if !fn(key, value) {
+			return
+		}
+	}
+}
+
+// All returns an iterator over the keys and values in the map, with
+// the same guarantees as Range.
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		var keys []K
+		var values []V
+		for i := range m.shards {
+			keys, values = m.shards[i].copy(keys[:0], values[:0])
+			for j, key := range keys {
+				if !yield(key, values[j]) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// copy appends the keys and values in the shard to the slices.
+func (s *shard[K, V]) copy(keys []K, values []V) ([]K, []V) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for key, value := range s.items {
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+	return keys, values
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharded
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+func TestMap(t *testing.T) {
+	m := New[string, int](0)
+
+	_, replaced := m.Put("a", 1)
+	assert.False(t, replaced)
+	old, replaced := m.Put("a", 2)
+	assert.True(t, replaced)
+	assert.Equal(t, 1, old)
+	m.Put("b", 3)
+
+	value, ok := m.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+	_, ok = m.Get("c")
+	assert.False(t, ok)
+	assert.Equal(t, 2, m.Len())
+
+	value, ok = m.Delete("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+	_, ok = m.Delete("a")
+	assert.False(t, ok)
+	assert.Equal(t, 1, m.Len())
+}
+
+func TestGetOrCompute(t *testing.T) {
+	m := New[int, string](4)
+	value, loaded := m.GetOrCompute(1, func() string { return "one" })
+	assert.False(t, loaded)
+	assert.Equal(t, "one", value)
+	value, loaded = m.GetOrCompute(1, func() string { panic("computed twice") })
+	assert.True(t, loaded)
+	assert.Equal(t, "one", value)
+
+	var calls atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, _ := m.GetOrCompute(2, func() string {
+				calls.Add(1)
+				return "two"
+			})
+			assert.Equal(t, "two", value)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestRange(t *testing.T) {
+	m := NewWithHasher[int, int](8, common.IntegerHash[int]())
+	for i := 0; i < 1000; i++ {
+		m.Put(i, i*i)
+	}
+
+	seen := make(map[int]int)
+	m.Range(func(key, value int) bool {
+		seen[key] = value
+		// the map may be used while ranging over it.
+		if key < 1000 {
+			m.Put(key+1000, 0)
+		}
+		return true
+	})
+	// keys added while ranging may or may not be seen.
+	assert.GreaterOrEqual(t, len(seen), 1000)
+	assert.Equal(t, 2000, m.Len())
+	for i := 0; i < 1000; i++ {
+		assert.Equal(t, i*i, seen[i])
+	}
+
+	var count int
+	m.Range(func(int, int) bool {
+		count++
+		return count < 10
+	})
+	assert.Equal(t, 10, count)
+}
+
+func TestSingleShard(t *testing.T) {
+	m := New[int, int](1)
+	assert.Len(t, m.shards, 1)
+	for i := 0; i < 100; i++ {
+		m.Put(i, i)
+	}
+	assert.Equal(t, 100, m.Len())
+
+	assert.Len(t, New[int, int](5).shards, 8)
+}
+
+func TestParallel(t *testing.T) {
+	m := New[string, int](0)
+	const numItems = 1000
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < numItems; i++ {
+				key := strconv.Itoa(g*numItems + i)
+				m.Put(key, i)
+				value, ok := m.Get(key)
+				assert.True(t, ok)
+				assert.Equal(t, i, value)
+				if i%2 == 0 {
+					m.Delete(key)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 8*numItems/2, m.Len())
+}
+
+func BenchmarkPut(b *testing.B) {
+	m := New[int, int](0)
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			m.Put(i%4096, i)
+		}
+	})
+}
+
+func BenchmarkSyncMapStore(b *testing.B) {
+	var m sync.Map
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			m.Store(i%4096, i)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package check holds the helpers shared by the invariant checks and fuzz
+targets of the ordered structures in this module.
+
+Each structure's invariants are checked by a checkInvariants function in
+its package's tests, as only they can see its nodes; this package
+supplies the pieces those checks have in common, such as verifying an
+order or a heap, and decodes fuzz input into operations to replay
+against both the structure and a simple model of it.
+*/
+package check
+
+import (
+	"fmt"
+	"iter"
+)
+
+// Errorf returns an error reporting a broken invariant.
+func Errorf(format string, args ...any) error {
+	return fmt.Errorf(`check: `+format, args...)
+}
+
+// Sorted returns an error if items are out of order under compare, or
+// if strict and any two adjacent items are equal.
+func Sorted[T any](items iter.Seq[T], compare func(a, b T) int, strict bool) error {
+	var (
+		prev  T
+		index int
+	)
+	for item := range items {
+		if index > 0 {
+			c := compare(prev, item)
+			if c > 0 || strict && c == 0 {
+				return Errorf(`item %d (%v) is out of order after %v`, index, item, prev)
+			}
+		}
+		prev = item
+		index++
+	}
+	return nil
+}
+
+// Heap returns an error if any item of a heap with the given arity,
+// laid out level by level in items, sorts before its parent under
+// compare.
+func Heap[T any](items []T, arity int, compare func(a, b T) int) error {
+	for i := 1; i < len(items); i++ {
+		parent := (i - 1) / arity
+		if compare(items[i], items[parent]) < 0 {
+			return Errorf(`item %d (%v) sorts before its parent %d (%v)`, i, items[i], parent, items[parent])
+		}
+	}
+	return nil
+}
+
+// Op is a single operation decoded from fuzz input.
+type Op struct {
+	// Kind chooses the operation, and is less than the number of kinds
+	// passed to Ops.
+	Kind int
+	// Key is the operation's argument.  Keys are small so that fuzz
+	// input often repeats them, exercising updates and deletes.
+	Key int
+}
+
+// Ops decodes fuzz input as a sequence of operations, two bytes each:
+// the first chooses one of kinds operations and the second is its key.
+// A trailing odd byte is ignored.
+func Ops(data []byte, kinds int) iter.Seq[Op] {
+	return func(yield func(Op) bool) {
+		for i := 0; i+1 < len(data); i += 2 {
+			if !yield(Op{Kind: int(data[i]) % kinds, Key: int(data[i+1])}) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package check
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSorted(t *testing.T) {
+	assert.NoError(t, Sorted(slices.Values([]int{}), cmp.Compare[int], true))
+	assert.NoError(t, Sorted(slices.Values([]int{1, 2, 2, 3}), cmp.Compare[int], false))
+	assert.Error(t, Sorted(slices.Values([]int{1, 2, 2, 3}), cmp.Compare[int], true))
+	assert.EqualError(t, Sorted(slices.Values([]int{1, 3, 2}), cmp.Compare[int], false),
+		`check: item 2 (2) is out of order after 3`)
+}
+
+func TestHeap(t *testing.T) {
+	assert.NoError(t, Heap([]int{1, 3, 2, 4, 5}, 2, cmp.Compare[int]))
+	assert.NoError(t, Heap([]int{1, 5, 4, 3, 2}, 4, cmp.Compare[int]))
+	assert.Error(t, Heap([]int{1, 5, 4, 3, 2}, 2, cmp.Compare[int]))
+	assert.NoError(t, Heap([]int(nil), 2, cmp.Compare[int]))
+}
+
+func TestOps(t *testing.T) {
+	ops := slices.Collect(Ops([]byte{0, 7, 5, 255, 3}, 3))
+	assert.Equal(t, []Op{{Kind: 0, Key: 7}, {Kind: 2, Key: 255}}, ops)
+
+	for range Ops([]byte{1, 1, 1, 1}, 2) {
+		break
+	}
+}
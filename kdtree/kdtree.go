@@ -0,0 +1,314 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package kdtree implements a k-d tree, which partitions points in k
+dimensions by splitting on each axis in turn, for range searches and
+nearest neighbour queries.  Where the R-tree indexes rectangles, a k-d
+tree is leaner for workloads made only of points.
+
+Build constructs a balanced tree from a batch of points in O(n log n)
+time, after which searches take O(log n) time on average for nearby
+points.  Insert adds points one at a time without rebalancing, so a
+tree built mostly by insertion in sorted order degrades; Rebuild
+restores its balance.  A Tree is not threadsafe.
+*/
+package kdtree
+
+import (
+	"math"
+	"slices"
+)
+
+// Point is a point in k dimensions.
+type Point []float64
+
+// Entry is a point and the value stored with it.
+type Entry[V any] struct {
+	Point Point
+	Value V
+}
+
+// Neighbor is an entry found by a nearest neighbour query, along with
+// its distance from the query point.
+type Neighbor[V any] struct {
+	Entry[V]
+	Distance float64
+}
+
+type node[V any] struct {
+	entry       Entry[V]
+	left, right *node[V]
+}
+
+// Tree is a k-d tree of points and their values.
+type Tree[V any] struct {
+	root *node[V]
+	k    int
+	len  int
+}
+
+// New returns an empty tree of points in k dimensions.
+func New[V any](k int) *Tree[V] {
+	if k < 1 {
+		panic(`kdtree: there must be at least one dimension`)
+	}
+	return &Tree[V]{k: k}
+}
+
+// Build returns a balanced tree of the entries, whose points must have
+// k dimensions.  The entries slice is reordered, but the points are
+// not copied, so they must not be modified afterwards.
+func Build[V any](k int, entries []Entry[V]) *Tree[V] {
+	t := New[V](k)
+	for _, e := range entries {
+		t.check(e.Point)
+	}
+	t.root = build(entries, 0, k)
+	t.len = len(entries)
+	return t
+}
+
+func build[V any](entries []Entry[V], axis, k int) *node[V] {
+	if len(entries) == 0 {
+		return nil
+	}
+	mid := len(entries) / 2
+	selectNth(entries, mid, axis)
+	next := (axis + 1) % k
+	return &node[V]{
+		entry: entries[mid],
+		left:  build(entries[:mid], next, k),
+		right: build(entries[mid+1:], next, k),
+	}
+}
+
+// selectNth reorders entries so that the one at index n is the one
+// that would be there if they were sorted by the axis, with none
+// greater before it and none less after it.
+func selectNth[V any](entries []Entry[V], n, axis int) {
+	low, high := 0, len(entries)-1
+	for low < high {
+		pivot := entries[(low+high)/2].Point[axis]
+		i, j := low, high
+		for i <= j {
+			for entries[i].Point[axis] < pivot {
+				i++
+			}
+			for entries[j].Point[axis] > pivot {
+				j--
+			}
+			if i <= j {
+				entries[i], entries[j] = entries[j], entries[i]
+				i++
+				j--
+			}
+		}
+		switch {
+		case n <= j:
+			high = j
+		case n >= i:
+			low = i
+		default:
+			return
+		}
+	}
+}
+
+func (t *Tree[V]) check(p Point) {
+	if len(p) != t.k {
+		panic(`kdtree: point has the wrong number of dimensions`)
+	}
+}
+
+// Len returns the number of points in the tree.
+func (t *Tree[V]) Len() int {
+	return t.len
+}
+
+// Insert adds a point, which must have k dimensions, and its value to
+// the tree.  The same point may be added more than once.  The point is
+// not copied, so it must not be modified afterwards.
+func (t *Tree[V]) Insert(p Point, value V) {
+	t.check(p)
+	link := &t.root
+	for axis := 0; *link != nil; axis = (axis + 1) % t.k {
+		n := *link
+		// points equal on the axis go right, matching Build, where the
+		// median may be preceded by equal points but never followed by
+		// lesser ones.
+		if p[axis] < n.entry.Point[axis] {
+			link = &n.left
+		} else {
+			link = &n.right
+		}
+	}
+	*link = &node[V]{entry: Entry[V]{Point: p, Value: value}}
+	t.len++
+}
+
+// Entries returns every entry in the tree, in no particular order.
+func (t *Tree[V]) Entries() []Entry[V] {
+	entries := make([]Entry[V], 0, t.len)
+	var walk func(n *node[V])
+	walk = func(n *node[V]) {
+		if n != nil {
+			entries = append(entries, n.entry)
+			walk(n.left)
+			walk(n.right)
+		}
+	}
+	walk(t.root)
+	return entries
+}
+
+// Rebuild rebalances the tree in O(n log n) time.
+func (t *Tree[V]) Rebuild() {
+	t.root = build(t.Entries(), 0, t.k)
+}
+
+// Search returns the entries whose points lie within the box between
+// the low and high corners, inclusive.
+func (t *Tree[V]) Search(low, high Point) []Entry[V] {
+	t.check(low)
+	t.check(high)
+	var found []Entry[V]
+	var search func(n *node[V], axis int)
+	search = func(n *node[V], axis int) {
+		if n == nil {
+			return
+		}
+		p := n.entry.Point
+		if inside(p, low, high) {
+			found = append(found, n.entry)
+		}
+		next := (axis + 1) % t.k
+		// lesser points are only ever on the left, but equal points may
+		// be on either side.
+		if low[axis] <= p[axis] {
+			search(n.left, next)
+		}
+		if high[axis] >= p[axis] {
+			search(n.right, next)
+		}
+	}
+	search(t.root, 0)
+	return found
+}
+
+func inside(p, low, high Point) bool {
+	for i, x := range p {
+		if x < low[i] || x > high[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Distance returns the Euclidean distance between two points.
+func Distance(a, b Point) float64 {
+	return math.Sqrt(squaredDistance(a, b))
+}
+
+func squaredDistance(a, b Point) float64 {
+	var d float64
+	for i := range a {
+		d += (a[i] - b[i]) * (a[i] - b[i])
+	}
+	return d
+}
+
+// Nearest returns the n entries whose points are nearest to the point
+// by Euclidean distance, nearest first, or every entry if there are
+// fewer than n.
+func (t *Tree[V]) Nearest(p Point, n int) []Neighbor[V] {
+	t.check(p)
+	if n <= 0 {
+		return nil
+	}
+
+	// best holds the nearest entries found so far, as a heap with the
+	// furthest at its root, and their squared distances.
+	best := make([]Neighbor[V], 0, min(n, t.len))
+	var search func(nd *node[V], axis int)
+	search = func(nd *node[V], axis int) {
+		if nd == nil {
+			return
+		}
+		if d := squaredDistance(p, nd.entry.Point); len(best) < n {
+			best = append(best, Neighbor[V]{Entry: nd.entry, Distance: d})
+			up(best, len(best)-1)
+		} else if d < best[0].Distance {
+			best[0] = Neighbor[V]{Entry: nd.entry, Distance: d}
+			down(best, 0)
+		}
+
+		// search the side the point is on first, and the other only if
+		// the splitting plane is nearer than the furthest found.
+		diff := p[axis] - nd.entry.Point[axis]
+		near, far := nd.left, nd.right
+		if diff >= 0 {
+			near, far = far, near
+		}
+		next := (axis + 1) % t.k
+		search(near, next)
+		if len(best) < n || diff*diff <= best[0].Distance {
+			search(far, next)
+		}
+	}
+	search(t.root, 0)
+
+	slices.SortFunc(best, func(a, b Neighbor[V]) int {
+		switch {
+		case a.Distance < b.Distance:
+			return -1
+		case a.Distance > b.Distance:
+			return 1
+		}
+		return 0
+	})
+	for i := range best {
+		best[i].Distance = math.Sqrt(best[i].Distance)
+	}
+	return best
+}
+
+func up[V any](heap []Neighbor[V], i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if heap[parent].Distance >= heap[i].Distance {
+			return
+		}
+		heap[parent], heap[i] = heap[i], heap[parent]
+		i = parent
+	}
+}
+
+func down[V any](heap []Neighbor[V], i int) {
+	for {
+		furthest := i
+		for _, child := range [2]int{2*i + 1, 2*i + 2} {
+			if child < len(heap) && heap[child].Distance > heap[furthest].Distance {
+				furthest = child
+			}
+		}
+		if furthest == i {
+			return
+		}
+		heap[i], heap[furthest] = heap[furthest], heap[i]
+		i = furthest
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kdtree
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func randomEntries(rng *rand.Rand, n, k int) []Entry[int] {
+	entries := make([]Entry[int], n)
+	for i := range entries {
+		p := make(Point, k)
+		for j := range p {
+			// a coarse grid makes equal coordinates common.
+			p[j] = float64(rng.Intn(50))
+		}
+		entries[i] = Entry[int]{Point: p, Value: i}
+	}
+	return entries
+}
+
+func values(entries []Entry[int]) []int {
+	var vs []int
+	for _, e := range entries {
+		vs = append(vs, e.Value)
+	}
+	sort.Ints(vs)
+	return vs
+}
+
+// depth returns the height of the tree.
+func depth[V any](n *node[V]) int {
+	if n == nil {
+		return 0
+	}
+	return 1 + max(depth(n.left), depth(n.right))
+}
+
+func TestTree(t *testing.T) {
+	tree := New[string](2)
+	tree.Insert(Point{0, 0}, "origin")
+	tree.Insert(Point{3, 4}, "far")
+	tree.Insert(Point{1, 1}, "near")
+	tree.Insert(Point{1, 1}, "again")
+	assert.Equal(t, 4, tree.Len())
+
+	found := tree.Search(Point{0, 0}, Point{1, 1})
+	assert.Len(t, found, 3)
+
+	nearest := tree.Nearest(Point{3, 3}, 2)
+	require.Len(t, nearest, 2)
+	assert.Equal(t, "far", nearest[0].Value)
+	assert.Equal(t, 1.0, nearest[0].Distance)
+	assert.Equal(t, Distance(Point{3, 3}, Point{1, 1}), nearest[1].Distance)
+
+	assert.Len(t, tree.Nearest(Point{0, 0}, 10), 4)
+	assert.Nil(t, tree.Nearest(Point{0, 0}, 0))
+	assert.Panics(t, func() { tree.Insert(Point{1}, "") })
+	assert.Panics(t, func() { tree.Search(Point{1}, Point{1, 2}) })
+	assert.Panics(t, func() { New[int](0) })
+}
+
+func TestBuildBalanced(t *testing.T) {
+	rng := rand.New(rand.NewSource(31))
+	tree := Build(3, randomEntries(rng, 1000, 3))
+	assert.Equal(t, 1000, tree.Len())
+	assert.Equal(t, 10, depth(tree.root))
+
+	// sorted insertion degrades into a list, until rebuilt.
+	sorted := New[int](1)
+	for i := 0; i < 100; i++ {
+		sorted.Insert(Point{float64(i)}, i)
+	}
+	assert.Equal(t, 100, depth(sorted.root))
+	sorted.Rebuild()
+	assert.Equal(t, 7, depth(sorted.root))
+	assert.Equal(t, 100, sorted.Len())
+}
+
+func TestRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(37))
+	for round := 0; round < 30; round++ {
+		k := 1 + rng.Intn(4)
+		entries := randomEntries(rng, rng.Intn(300), k)
+		all := slices.Clone(entries)
+
+		var tree *Tree[int]
+		if round%2 == 0 {
+			tree = Build(k, entries)
+		} else {
+			tree = New[int](k)
+			for _, e := range entries {
+				tree.Insert(e.Point, e.Value)
+			}
+		}
+		for _, e := range randomEntries(rng, rng.Intn(100), k) {
+			e.Value += 1000
+			tree.Insert(e.Point, e.Value)
+			all = append(all, e)
+		}
+		require.Equal(t, len(all), tree.Len())
+		assert.Equal(t, values(all), values(tree.Entries()))
+
+		for q := 0; q < 20; q++ {
+			low, high := randomEntries(rng, 1, k)[0].Point, randomEntries(rng, 1, k)[0].Point
+			for i := range low {
+				low[i], high[i] = min(low[i], high[i]), max(low[i], high[i])
+			}
+			var expected []Entry[int]
+			for _, e := range all {
+				if inside(e.Point, low, high) {
+					expected = append(expected, e)
+				}
+			}
+			assert.Equal(t, values(expected), values(tree.Search(low, high)))
+
+			p := randomEntries(rng, 1, k)[0].Point
+			n := 1 + rng.Intn(10)
+			distances := make([]float64, len(all))
+			for i, e := range all {
+				distances[i] = Distance(p, e.Point)
+			}
+			slices.Sort(distances)
+			nearest := tree.Nearest(p, n)
+			require.Len(t, nearest, min(n, len(all)))
+			for i, neighbor := range nearest {
+				assert.Equal(t, distances[i], neighbor.Distance)
+				assert.Equal(t, Distance(p, neighbor.Point), neighbor.Distance)
+			}
+		}
+	}
+}
+
+func BenchmarkNearest(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	entries := make([]Entry[int], 1<<16)
+	for i := range entries {
+		entries[i] = Entry[int]{Point: Point{rng.Float64(), rng.Float64()}, Value: i}
+	}
+	tree := Build(2, entries)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Nearest(Point{rng.Float64(), rng.Float64()}, 10)
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2015 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package list
+
+// Builder accumulates items in order and produces a PersistentList of
+// them in one shot. The nodes of the list are allocated together when
+// List is called rather than one per item. The zero value is ready to
+// use. A Builder is not safe for concurrent use.
+type Builder[T any] struct {
+	items []T
+}
+
+// NewBuilder returns a Builder with room for capacity items.
+func NewBuilder[T any](capacity int) *Builder[T] {
+	return &Builder[T]{items: make([]T, 0, capacity)}
+}
+
+// Add appends the item to the end of the list being built.
+func (b *Builder[T]) Add(item T) *Builder[T] {
+	b.items = append(b.items, item)
+	return b
+}
+
+// AddAll appends the items, in order, to the end of the list being built.
+func (b *Builder[T]) AddAll(items ...T) *Builder[T] {
+	b.items = append(b.items, items...)
+	return b
+}
+
+// Len returns the number of items added so far.
+func (b *Builder[T]) Len() int {
+	return len(b.items)
+}
+
+// List returns a PersistentList of the items added so far with the first
+// item added at the head. The builder may continue to be used; later
+// additions do not affect lists already returned.
+func (b *Builder[T]) List() PersistentList[T] {
+	return buildList(b.items, Empty[T]())
+}
+
+// Reset discards the items added so far.
+func (b *Builder[T]) Reset() {
+	clear(b.items)
+	b.items = b.items[:0]
+}
+
+// buildList returns a list of the items, in order, followed by tail. The
+// nodes are allocated as a single slice, so a retained suffix keeps the
+// whole slice reachable.
+func buildList[T any](items []T, tail PersistentList[T]) PersistentList[T] {
+	if len(items) == 0 {
+		return tail
+	}
+
+	nodes := make([]list[T], len(items))
+	for i, item := range items {
+		nodes[i].head = item
+		if i+1 < len(nodes) {
+			nodes[i].tail = &nodes[i+1]
+		}
+	}
+	nodes[len(nodes)-1].tail = tail
+	return &nodes[0]
+}
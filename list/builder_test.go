@@ -0,0 +1,69 @@
+/*
+Copyright 2015 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package list
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder(t *testing.T) {
+	var b Builder[int]
+	assert.True(t, b.List().IsEmpty())
+
+	b.Add(1).Add(2).AddAll(3, 4)
+	assert.Equal(t, 4, b.Len())
+
+	l := b.List()
+	assert.Equal(t, []int{1, 2, 3, 4}, l.ToSlice())
+	assert.Equal(t, uint(4), l.Length())
+
+	// later additions do not affect lists already built
+	b.Add(5)
+	assert.Equal(t, []int{1, 2, 3, 4}, l.ToSlice())
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, b.List().ToSlice())
+
+	b.Reset()
+	assert.Equal(t, 0, b.Len())
+	assert.True(t, b.List().IsEmpty())
+	assert.Equal(t, []int{1, 2, 3, 4}, l.ToSlice())
+}
+
+func TestBuilderListIsPersistent(t *testing.T) {
+	l := NewBuilder[string](3).AddAll("a", "b", "c").List()
+
+	l2 := l.Add("z")
+	removed, err := l.Remove(1)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"a", "b", "c"}, l.ToSlice())
+	assert.Equal(t, []string{"z", "a", "b", "c"}, l2.ToSlice())
+	assert.Equal(t, []string{"a", "c"}, removed.ToSlice())
+}
+
+func BenchmarkBuilder(b *testing.B) {
+	items := make([]int, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		builder := NewBuilder[int](len(items))
+		for _, item := range items {
+			builder.Add(item)
+		}
+		builder.List()
+	}
+}
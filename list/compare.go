@@ -0,0 +1,60 @@
+/*
+Copyright 2015 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package list
+
+// Equal returns a bool indicating if the lists contain equal items in the
+// same order according to eq. Once both lists reach a node they share,
+// the remainder is known to be equal without being compared, so comparing
+// versions of a list derived from one another is cheap.
+func Equal[T any](a, b PersistentList[T], eq func(T, T) bool) bool {
+	na, nb := asNode(a), asNode(b)
+	for na != nil && nb != nil {
+		if na == nb {
+			return true
+		}
+		if !eq(na.head, nb.head) {
+			return false
+		}
+		na, nb = asNode(na.tail), asNode(nb.tail)
+	}
+	return na == nil && nb == nil
+}
+
+// Compare compares the lists lexicographically using cmp, returning a
+// negative number if a < b, zero if a == b and a positive number if
+// a > b. A list that is a prefix of the other is the lesser. As with
+// Equal, comparison stops once both lists reach a shared node.
+func Compare[T any](a, b PersistentList[T], cmp func(T, T) int) int {
+	na, nb := asNode(a), asNode(b)
+	for na != nil && nb != nil {
+		if na == nb {
+			return 0
+		}
+		if c := cmp(na.head, nb.head); c != 0 {
+			return c
+		}
+		na, nb = asNode(na.tail), asNode(nb.tail)
+	}
+
+	switch {
+	case na == nil && nb == nil:
+		return 0
+	case na == nil:
+		return -1
+	}
+	return 1
+}
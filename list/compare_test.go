@@ -0,0 +1,71 @@
+/*
+Copyright 2015 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package list
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func intsEqual(a, b int) bool {
+	return a == b
+}
+
+func TestEqual(t *testing.T) {
+	a := FromSliceReversed([]int{1, 2, 3})
+
+	assert.True(t, Equal(a, FromSliceReversed([]int{1, 2, 3}), intsEqual))
+	assert.True(t, Equal(a, a, intsEqual))
+	assert.True(t, Equal(Empty[int](), Empty[int](), intsEqual))
+	assert.False(t, Equal(a, FromSliceReversed([]int{1, 2}), intsEqual))
+	assert.False(t, Equal(a, FromSliceReversed([]int{1, 2, 4}), intsEqual))
+	assert.False(t, Equal(a, Empty[int](), intsEqual))
+}
+
+func TestEqualShortCircuitsOnSharedStructure(t *testing.T) {
+	shared := millionItemList()
+	a := shared.Add(1)
+	b := shared.Add(1)
+
+	calls := 0
+	assert.True(t, Equal(a, b, func(x, y int) bool {
+		calls++
+		return x == y
+	}))
+	assert.Equal(t, 1, calls)
+
+	calls = 0
+	assert.Equal(t, 0, Compare(a, b, func(x, y int) int {
+		calls++
+		return cmp.Compare(x, y)
+	}))
+	assert.Equal(t, 1, calls)
+}
+
+func TestCompare(t *testing.T) {
+	a := FromSliceReversed([]int{1, 2, 3})
+
+	assert.Equal(t, 0, Compare(a, FromSliceReversed([]int{1, 2, 3}), cmp.Compare[int]))
+	assert.Equal(t, -1, Compare(a, FromSliceReversed([]int{1, 3}), cmp.Compare[int]))
+	assert.Equal(t, 1, Compare(a, FromSliceReversed([]int{1, 2, 2, 9}), cmp.Compare[int]))
+	assert.Equal(t, 1, Compare(a, FromSliceReversed([]int{1, 2}), cmp.Compare[int]))
+	assert.Equal(t, -1, Compare(FromSliceReversed([]int{1, 2}), a, cmp.Compare[int]))
+	assert.Equal(t, 0, Compare(Empty[int](), Empty[int](), cmp.Compare[int]))
+	assert.Equal(t, -1, Compare(Empty[int](), a, cmp.Compare[int]))
+}
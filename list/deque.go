@@ -0,0 +1,191 @@
+/*
+Copyright 2015 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package list
+
+import "iter"
+
+// dequeBalance is the maximum factor by which one side of a Deque may
+// outgrow the other before they are rebalanced.
+const dequeBalance = 3
+
+// Deque is an immutable, persistent double-ended queue. It is a banker's
+// deque: two persistent lists hold the front half in order and the back
+// half reversed, and are rebalanced whenever one grows more than
+// dequeBalance times larger than the other. Pushes and pops are
+// amortized O(1). Like PersistentList, every operation returns a new
+// Deque sharing structure with the old one.
+type Deque[T any] struct {
+	front, back       PersistentList[T]
+	frontLen, backLen uint
+}
+
+// EmptyDeque returns an empty Deque for the given type.
+func EmptyDeque[T any]() *Deque[T] {
+	return &Deque[T]{front: Empty[T](), back: Empty[T]()}
+}
+
+// DequeFromSlice returns a Deque containing the items of the slice, with
+// the first item of the slice at the front.
+func DequeFromSlice[T any](items []T) *Deque[T] {
+	return (&Deque[T]{
+		front:    FromSliceReversed(items),
+		back:     Empty[T](),
+		frontLen: uint(len(items)),
+	}).balanced()
+}
+
+// Len returns the number of items in the deque.
+func (d *Deque[T]) Len() uint {
+	return d.frontLen + d.backLen
+}
+
+// IsEmpty indicates if the deque is empty.
+func (d *Deque[T]) IsEmpty() bool {
+	return d.Len() == 0
+}
+
+// PushFront returns a new deque with the item added at the front.
+func (d *Deque[T]) PushFront(item T) *Deque[T] {
+	return (&Deque[T]{
+		front:    d.front.Add(item),
+		back:     d.back,
+		frontLen: d.frontLen + 1,
+		backLen:  d.backLen,
+	}).balanced()
+}
+
+// PushBack returns a new deque with the item added at the back.
+func (d *Deque[T]) PushBack(item T) *Deque[T] {
+	return (&Deque[T]{
+		front:    d.front,
+		back:     d.back.Add(item),
+		frontLen: d.frontLen,
+		backLen:  d.backLen + 1,
+	}).balanced()
+}
+
+// Front returns the item at the front of the deque. The bool will be
+// false if the deque is empty.
+func (d *Deque[T]) Front() (T, bool) {
+	if d.frontLen == 0 {
+		// the balance invariant leaves at most one item in back
+		return d.back.Head()
+	}
+	return d.front.Head()
+}
+
+// Back returns the item at the back of the deque. The bool will be
+// false if the deque is empty.
+func (d *Deque[T]) Back() (T, bool) {
+	if d.backLen == 0 {
+		return d.front.Head()
+	}
+	return d.back.Head()
+}
+
+// PopFront returns the item at the front of the deque along with a new
+// deque without it. The bool will be false if the deque is empty.
+func (d *Deque[T]) PopFront() (T, *Deque[T], bool) {
+	if d.frontLen == 0 {
+		item, ok := d.back.Head()
+		if !ok {
+			return item, d, false
+		}
+		return item, EmptyDeque[T](), true
+	}
+
+	item, _ := d.front.Head()
+	tail, _ := d.front.Tail()
+	return item, (&Deque[T]{
+		front:    tail,
+		back:     d.back,
+		frontLen: d.frontLen - 1,
+		backLen:  d.backLen,
+	}).balanced(), true
+}
+
+// PopBack returns the item at the back of the deque along with a new
+// deque without it. The bool will be false if the deque is empty.
+func (d *Deque[T]) PopBack() (T, *Deque[T], bool) {
+	if d.backLen == 0 {
+		item, ok := d.front.Head()
+		if !ok {
+			return item, d, false
+		}
+		return item, EmptyDeque[T](), true
+	}
+
+	item, _ := d.back.Head()
+	tail, _ := d.back.Tail()
+	return item, (&Deque[T]{
+		front:    d.front,
+		back:     tail,
+		frontLen: d.frontLen,
+		backLen:  d.backLen - 1,
+	}).balanced(), true
+}
+
+// All returns an iterator over the items of the deque from front to
+// back.
+func (d *Deque[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for item := range d.front.All() {
+			if !yield(item) {
+				return
+			}
+		}
+		for item := range d.back.Backward() {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// ToSlice returns the items of the deque from front to back.
+func (d *Deque[T]) ToSlice() []T {
+	items := make([]T, 0, d.Len())
+	for item := range d.All() {
+		items = append(items, item)
+	}
+	return items
+}
+
+// balanced returns d, or a rebalanced copy if one side has outgrown the
+// other.
+func (d *Deque[T]) balanced() *Deque[T] {
+	n := d.Len()
+	switch {
+	case d.frontLen > dequeBalance*d.backLen+1:
+		keep := (n + 1) / 2
+		return &Deque[T]{
+			front:    d.front.Take(keep),
+			back:     d.back.Concat(d.front.Drop(keep).Reverse()),
+			frontLen: keep,
+			backLen:  n - keep,
+		}
+	case d.backLen > dequeBalance*d.frontLen+1:
+		keep := (n + 1) / 2
+		return &Deque[T]{
+			front:    d.front.Concat(d.back.Drop(keep).Reverse()),
+			back:     d.back.Take(keep),
+			frontLen: n - keep,
+			backLen:  keep,
+		}
+	}
+	return d
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2015 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package list
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmptyDeque(t *testing.T) {
+	d := EmptyDeque[int]()
+
+	assert.True(t, d.IsEmpty())
+	assert.Equal(t, uint(0), d.Len())
+	_, ok := d.Front()
+	assert.False(t, ok)
+	_, ok = d.Back()
+	assert.False(t, ok)
+	_, same, ok := d.PopFront()
+	assert.False(t, ok)
+	assert.Same(t, d, same)
+	_, _, ok = d.PopBack()
+	assert.False(t, ok)
+}
+
+func TestDequePushPop(t *testing.T) {
+	d := EmptyDeque[int]().PushBack(2).PushBack(3).PushFront(1)
+	assert.Equal(t, []int{1, 2, 3}, d.ToSlice())
+
+	front, _ := d.Front()
+	back, _ := d.Back()
+	assert.Equal(t, 1, front)
+	assert.Equal(t, 3, back)
+
+	item, rest, ok := d.PopBack()
+	require.True(t, ok)
+	assert.Equal(t, 3, item)
+	item, rest, ok = rest.PopBack()
+	require.True(t, ok)
+	assert.Equal(t, 2, item)
+	item, rest, ok = rest.PopBack()
+	require.True(t, ok)
+	assert.Equal(t, 1, item)
+	assert.True(t, rest.IsEmpty())
+
+	// d is unchanged
+	assert.Equal(t, []int{1, 2, 3}, d.ToSlice())
+}
+
+func TestDequeAsQueue(t *testing.T) {
+	d := EmptyDeque[int]()
+	for i := range 100 {
+		d = d.PushBack(i)
+	}
+	assert.Equal(t, uint(100), d.Len())
+
+	for i := range 100 {
+		var item int
+		var ok bool
+		item, d, ok = d.PopFront()
+		require.True(t, ok)
+		assert.Equal(t, i, item)
+	}
+	assert.True(t, d.IsEmpty())
+}
+
+func TestDequeFromSlice(t *testing.T) {
+	d := DequeFromSlice([]int{1, 2, 3, 4, 5})
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, d.ToSlice())
+
+	back, _ := d.Back()
+	assert.Equal(t, 5, back)
+	assert.True(t, DequeFromSlice[int](nil).IsEmpty())
+}
+
+func TestDequeMatchesSlice(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	d := EmptyDeque[int]()
+	var expected []int
+
+	for i := range 2000 {
+		switch r.Intn(4) {
+		case 0:
+			d = d.PushFront(i)
+			expected = append([]int{i}, expected...)
+		case 1:
+			d = d.PushBack(i)
+			expected = append(expected, i)
+		case 2:
+			var item int
+			var ok bool
+			item, d, ok = d.PopFront()
+			require.Equal(t, len(expected) > 0, ok)
+			if ok {
+				assert.Equal(t, expected[0], item)
+				expected = expected[1:]
+			}
+		case 3:
+			var item int
+			var ok bool
+			item, d, ok = d.PopBack()
+			require.Equal(t, len(expected) > 0, ok)
+			if ok {
+				assert.Equal(t, expected[len(expected)-1], item)
+				expected = expected[:len(expected)-1]
+			}
+		}
+		require.Equal(t, uint(len(expected)), d.Len())
+	}
+	assert.Equal(t, expected, d.ToSlice())
+}
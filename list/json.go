@@ -0,0 +1,45 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package list
+
+import "github.com/Workiva/go-datastructures/common"
+
+// MarshalJSON encodes the empty list as an empty JSON array.
+func (e *emptyList[T]) MarshalJSON() ([]byte, error) {
+	return []byte(`[]`), nil
+}
+
+// MarshalJSON encodes the list as a JSON array of its items, from the
+// head to the end.
+func (l *list[T]) MarshalJSON() ([]byte, error) {
+	return common.MarshalJSONArray(l.All(), common.DefaultJSONCodec[T]())
+}
+
+// FromJSON decodes a JSON array into a PersistentList whose head is the
+// first item of the array, the reverse of marshaling a PersistentList.
+// Since a PersistentList is an interface it cannot be unmarshaled into
+// directly.
+func FromJSON[T any](data []byte) (PersistentList[T], error) {
+	var items []T
+	err := common.UnmarshalJSONArray(data, common.DefaultJSONCodec[T](), func(item T) {
+		items = append(items, item)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return FromSliceReversed(items), nil
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package list
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistentListJSON(t *testing.T) {
+	l := Empty[int]().Add(3).Add(2).Add(1)
+	b, err := json.Marshal(l)
+	require.NoError(t, err)
+	assert.Equal(t, `[1,2,3]`, string(b))
+
+	decoded, err := FromJSON[int](b)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, decoded.ToSlice())
+
+	b, err = json.Marshal(Empty[int]())
+	require.NoError(t, err)
+	assert.Equal(t, `[]`, string(b))
+	decoded, err = FromJSON[int]([]byte(`null`))
+	require.NoError(t, err)
+	assert.True(t, decoded.IsEmpty())
+
+	_, err = FromJSON[int]([]byte(`["x"]`))
+	assert.Error(t, err)
+
+	// lists marshal inside other values too
+	wrapped, err := json.Marshal(struct{ L PersistentList[string] }{Empty[string]().Add(`a`)})
+	require.NoError(t, err)
+	assert.Equal(t, `{"L":["a"]}`, string(wrapped))
+}
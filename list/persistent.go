@@ -16,7 +16,8 @@ limitations under the License.
 
 /*
 Package list provides generic list implementations. Currently, this includes a
-persistent, immutable linked list.
+persistent, immutable linked list, a persistent double-ended queue built on it
+and a lazily-evaluated stream.
 
 The PersistentList is an immutable, persistent linked list. All write operations
 yield a new structure that preserves and reuses previous versions.
@@ -25,16 +26,21 @@ Example usage:
 
 	list := list.Empty[int]()
 	list = list.Add(1).Add(2).Add(3)
+	list = list.Append(0) // list containing [3, 2, 1, 0]
 
 	head, _ := list.Head() // 3
-	tail, _ := list.Tail() // list containing [2, 1]
+	tail, _ := list.Tail() // list containing [2, 1, 0]
 
 	// Map over elements
 	doubled := list.Map(func(x int) int { return x * 2 })
 */
 package list
 
-import "errors"
+import (
+	"cmp"
+	"errors"
+	"iter"
+)
 
 var (
 	// ErrEmptyList is returned when an invalid operation is performed on an
@@ -59,6 +65,15 @@ type PersistentList[T any] interface {
 	// Add will add the item to the list, returning the new list.
 	Add(head T) PersistentList[T]
 
+	// Append will add the item to the end of the list, returning the new
+	// list. Unlike Add, this copies every node of the list.
+	Append(item T) PersistentList[T]
+
+	// Concat returns a new list with the items of this list followed by
+	// the items of other. Nodes of this list are copied while other is
+	// shared.
+	Concat(other PersistentList[T]) PersistentList[T]
+
 	// Insert will insert the item at the given position, returning the new
 	// list or an error if the position is invalid.
 	Insert(val T, pos uint) (PersistentList[T], error)
@@ -78,9 +93,10 @@ type PersistentList[T any] interface {
 	// index of the first item which matches or -1 if there is no match.
 	FindIndex(predicate func(T) bool) int
 
-	// Map applies the function to each entry in the list and returns the
-	// resulting slice.
-	Map(fn func(T) T) []T
+	// Map applies the function to each entry in the list and returns a
+	// new list of the results in the same order. Use MapTo to change the
+	// item type.
+	Map(fn func(T) T) PersistentList[T]
 
 	// ForEach applies the function to each entry in the list.
 	ForEach(fn func(T))
@@ -96,6 +112,33 @@ type PersistentList[T any] interface {
 
 	// Reverse returns a new list with elements in reverse order.
 	Reverse() PersistentList[T]
+
+	// Take returns a new list containing the first n items. The whole
+	// list is returned, without copying, if it has no more than n items.
+	Take(n uint) PersistentList[T]
+
+	// Drop returns the list without its first n items. The result shares
+	// every node with this list.
+	Drop(n uint) PersistentList[T]
+
+	// Slice returns a new list containing the items from position from up
+	// to, but not including, position to. Positions past the end of the
+	// list are clamped and an empty list is returned if from >= to.
+	Slice(from, to uint) PersistentList[T]
+
+	// Sort returns a new list with the items ordered by cmp, which returns
+	// a negative number when a < b, zero when a == b and a positive
+	// number when a > b. The sort is stable.
+	Sort(cmp func(a, b T) int) PersistentList[T]
+
+	// All returns an iterator over the items of the list, starting at
+	// the head.
+	All() iter.Seq[T]
+
+	// Backward returns an iterator over the items of the list, starting
+	// at the last item. The items are buffered before the first is
+	// yielded.
+	Backward() iter.Seq[T]
 }
 
 // Empty returns an empty PersistentList for the given type.
@@ -113,14 +156,54 @@ func FromSlice[T any](items []T) PersistentList[T] {
 	return list
 }
 
+// MapTo applies the function to each entry in the list and returns a new
+// list of the results in the same order.
+func MapTo[T, U any](l PersistentList[T], fn func(T) U) PersistentList[U] {
+	var mapped []U
+	for item := range l.All() {
+		mapped = append(mapped, fn(item))
+	}
+	return FromSliceReversed(mapped)
+}
+
+// SortBy returns a new list with the items ordered by the key returned
+// for each. The sort is stable.
+func SortBy[T any, K cmp.Ordered](l PersistentList[T], key func(T) K) PersistentList[T] {
+	return l.Sort(func(a, b T) int {
+		return cmp.Compare(key(a), key(b))
+	})
+}
+
+// Min returns the smallest item in the list according to cmp. If several
+// items are equally small, the first is returned. The bool will be false
+// if the list is empty.
+func Min[T any](l PersistentList[T], cmp func(a, b T) int) (T, bool) {
+	lowest, ok := l.Head()
+	for item := range l.All() {
+		if cmp(item, lowest) < 0 {
+			lowest = item
+		}
+	}
+	return lowest, ok
+}
+
+// Max returns the largest item in the list according to cmp. If several
+// items are equally large, the first is returned. The bool will be false
+// if the list is empty.
+func Max[T any](l PersistentList[T], cmp func(a, b T) int) (T, bool) {
+	highest, ok := l.Head()
+	for item := range l.All() {
+		if cmp(item, highest) > 0 {
+			highest = item
+		}
+	}
+	return highest, ok
+}
+
 // FromSliceReversed creates a PersistentList from a slice.
 // Items are added in reverse order, so the first item in the slice becomes the head.
 func FromSliceReversed[T any](items []T) PersistentList[T] {
-	var list PersistentList[T] = Empty[T]()
-	for i := len(items) - 1; i >= 0; i-- {
-		list = list.Add(items[i])
-	}
-	return list
+	return buildList(items, Empty[T]())
 }
 
 type emptyList[T any] struct{}
@@ -146,6 +229,14 @@ func (e *emptyList[T]) Add(head T) PersistentList[T] {
 	return &list[T]{head: head, tail: e}
 }
 
+func (e *emptyList[T]) Append(item T) PersistentList[T] {
+	return e.Add(item)
+}
+
+func (e *emptyList[T]) Concat(other PersistentList[T]) PersistentList[T] {
+	return other
+}
+
 func (e *emptyList[T]) Insert(val T, pos uint) (PersistentList[T], error) {
 	if pos == 0 {
 		return e.Add(val), nil
@@ -171,8 +262,8 @@ func (e *emptyList[T]) FindIndex(predicate func(T) bool) int {
 	return -1
 }
 
-func (e *emptyList[T]) Map(fn func(T) T) []T {
-	return nil
+func (e *emptyList[T]) Map(fn func(T) T) PersistentList[T] {
+	return e
 }
 
 func (e *emptyList[T]) ForEach(fn func(T)) {}
@@ -193,6 +284,30 @@ func (e *emptyList[T]) Reverse() PersistentList[T] {
 	return e
 }
 
+func (e *emptyList[T]) Take(n uint) PersistentList[T] {
+	return e
+}
+
+func (e *emptyList[T]) Drop(n uint) PersistentList[T] {
+	return e
+}
+
+func (e *emptyList[T]) Slice(from, to uint) PersistentList[T] {
+	return e
+}
+
+func (e *emptyList[T]) Sort(cmp func(a, b T) int) PersistentList[T] {
+	return e
+}
+
+func (e *emptyList[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {}
+}
+
+func (e *emptyList[T]) Backward() iter.Seq[T] {
+	return e.All()
+}
+
 type list[T any] struct {
 	head T
 	tail PersistentList[T]
@@ -211,96 +326,101 @@ func (l *list[T]) IsEmpty() bool {
 }
 
 func (l *list[T]) Length() uint {
-	curr := l
 	length := uint(0)
-	for {
+	for node := l; node != nil; node = asNode(node.tail) {
 		length++
-		tail, _ := curr.Tail()
-		if tail.IsEmpty() {
-			return length
-		}
-		curr = tail.(*list[T])
 	}
+	return length
 }
 
 func (l *list[T]) Add(head T) PersistentList[T] {
 	return &list[T]{head: head, tail: l}
 }
 
+func (l *list[T]) Append(item T) PersistentList[T] {
+	return l.Concat(Empty[T]().Add(item))
+}
+
+func (l *list[T]) Concat(other PersistentList[T]) PersistentList[T] {
+	return buildList(l.ToSlice(), other)
+}
+
 func (l *list[T]) Insert(val T, pos uint) (PersistentList[T], error) {
-	if pos == 0 {
-		return l.Add(val), nil
+	prefix, rest, ok := l.split(pos)
+	if !ok {
+		return nil, ErrEmptyList
 	}
-	nl, err := l.tail.Insert(val, pos-1)
-	if err != nil {
-		return nil, err
-	}
-	return nl.Add(l.head), nil
+	return buildList(prefix, rest.Add(val)), nil
 }
 
 func (l *list[T]) Get(pos uint) (T, bool) {
-	if pos == 0 {
-		return l.head, true
+	node := l
+	for ; node != nil && pos > 0; pos-- {
+		node = asNode(node.tail)
 	}
-	return l.tail.Get(pos - 1)
+	if node == nil {
+		var zero T
+		return zero, false
+	}
+	return node.head, true
 }
 
 func (l *list[T]) Remove(pos uint) (PersistentList[T], error) {
-	if pos == 0 {
-		nl, _ := l.Tail()
-		return nl, nil
+	prefix, rest, ok := l.split(pos)
+	node := asNode(rest)
+	if !ok || node == nil {
+		return nil, ErrEmptyList
 	}
-
-	nl, err := l.tail.Remove(pos - 1)
-	if err != nil {
-		return nil, err
-	}
-	return &list[T]{head: l.head, tail: nl}, nil
+	return buildList(prefix, node.tail), nil
 }
 
 func (l *list[T]) Find(predicate func(T) bool) (T, bool) {
-	if predicate(l.head) {
-		return l.head, true
+	for node := l; node != nil; node = asNode(node.tail) {
+		if predicate(node.head) {
+			return node.head, true
+		}
 	}
-	return l.tail.Find(predicate)
+	var zero T
+	return zero, false
 }
 
 func (l *list[T]) FindIndex(predicate func(T) bool) int {
-	curr := l
 	idx := 0
-	for {
-		if predicate(curr.head) {
+	for node := l; node != nil; node = asNode(node.tail) {
+		if predicate(node.head) {
 			return idx
 		}
-		tail, _ := curr.Tail()
-		if tail.IsEmpty() {
-			return -1
-		}
-		curr = tail.(*list[T])
 		idx++
 	}
+	return -1
 }
 
-func (l *list[T]) Map(fn func(T) T) []T {
-	return append(l.tail.Map(fn), fn(l.head))
+func (l *list[T]) Map(fn func(T) T) PersistentList[T] {
+	return MapTo[T, T](l, fn)
 }
 
 func (l *list[T]) ForEach(fn func(T)) {
-	fn(l.head)
-	l.tail.ForEach(fn)
+	for node := l; node != nil; node = asNode(node.tail) {
+		fn(node.head)
+	}
 }
 
 func (l *list[T]) Filter(predicate func(T) bool) PersistentList[T] {
-	filtered := l.tail.Filter(predicate)
-	if predicate(l.head) {
-		return filtered.Add(l.head)
+	var filtered []T
+	for node := l; node != nil; node = asNode(node.tail) {
+		if predicate(node.head) {
+			filtered = append(filtered, node.head)
+		}
 	}
-	return filtered
+	return buildList(filtered, Empty[T]())
 }
 
 func (l *list[T]) Reduce(fn func(acc, item T) T, initial T) T {
-	acc := fn(initial, l.head)
-	return l.tail.Reduce(fn, acc)
+	acc := initial
+	for node := l; node != nil; node = asNode(node.tail) {
+		acc = fn(acc, node.head)
+	}
+	return acc
 }
 
 func (l *list[T]) ToSlice() []T {
@@ -318,3 +438,142 @@ func (l *list[T]) Reverse() PersistentList[T] {
 	})
 	return result
 }
+
+func (l *list[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		var curr PersistentList[T] = l
+		for !curr.IsEmpty() {
+			node := curr.(*list[T])
+			if !yield(node.head) {
+				return
+			}
+			curr = node.tail
+		}
+	}
+}
+
+func (l *list[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		items := l.ToSlice()
+		for i := len(items) - 1; i >= 0; i-- {
+			if !yield(items[i]) {
+				return
+			}
+		}
+	}
+}
+
+func (l *list[T]) Take(n uint) PersistentList[T] {
+	// find out whether the list is longer than n before copying, so
+	// the copy is only made, and sized, when it is needed.
+	var curr PersistentList[T] = l
+	length := uint(0)
+	for ; length < n && !curr.IsEmpty(); length++ {
+		curr = curr.(*list[T]).tail
+	}
+	if curr.IsEmpty() {
+		return l
+	}
+
+	items := make([]T, 0, length)
+	for node := l; uint(len(items)) < length; node = asNode(node.tail) {
+		items = append(items, node.head)
+	}
+	return FromSliceReversed(items)
+}
+
+func (l *list[T]) Drop(n uint) PersistentList[T] {
+	var curr PersistentList[T] = l
+	for ; n > 0 && !curr.IsEmpty(); n-- {
+		curr = curr.(*list[T]).tail
+	}
+	return curr
+}
+
+func (l *list[T]) Slice(from, to uint) PersistentList[T] {
+	if from >= to {
+		return Empty[T]()
+	}
+	return l.Drop(from).Take(to - from)
+}
+
+// Sort copies the nodes of the list and merge sorts the copies by
+// relinking them. The copies are not visible to anyone else until the
+// sort is complete, so they may be mutated.
+func (l *list[T]) Sort(cmp func(a, b T) int) PersistentList[T] {
+	empty := Empty[T]()
+	head := &list[T]{head: l.head, tail: empty}
+	last := head
+	for curr := asNode(l.tail); curr != nil; curr = asNode(curr.tail) {
+		node := &list[T]{head: curr.head, tail: empty}
+		last.tail = node
+		last = node
+	}
+	return mergeSort(head, cmp, empty)
+}
+
+// split returns the first pos items of the list along with the rest of
+// the list. The bool will be false if the list has fewer than pos items.
+func (l *list[T]) split(pos uint) ([]T, PersistentList[T], bool) {
+	prefix := make([]T, 0, pos)
+	var rest PersistentList[T] = l
+	for ; pos > 0; pos-- {
+		node := asNode(rest)
+		if node == nil {
+			return nil, nil, false
+		}
+		prefix = append(prefix, node.head)
+		rest = node.tail
+	}
+	return prefix, rest, true
+}
+
+// asNode returns the node at the start of the list or nil if it is empty.
+func asNode[T any](l PersistentList[T]) *list[T] {
+	node, _ := l.(*list[T])
+	return node
+}
+
+// mergeSort sorts the privately owned nodes starting at head, each of
+// which ends in empty.
+func mergeSort[T any](head *list[T], cmp func(a, b T) int, empty PersistentList[T]) *list[T] {
+	if head == nil || head.tail.IsEmpty() {
+		return head
+	}
+
+	slow, fast := head, asNode(head.tail)
+	for fast != nil && !fast.tail.IsEmpty() {
+		slow = asNode(slow.tail)
+		fast = asNode(asNode(fast.tail).tail)
+	}
+	mid := asNode(slow.tail)
+	slow.tail = empty
+
+	return merge(mergeSort(head, cmp, empty), mergeSort(mid, cmp, empty), cmp, empty)
+}
+
+// merge relinks two sorted runs of privately owned nodes into one,
+// preferring a on ties so that the sort is stable.
+func merge[T any](a, b *list[T], cmp func(a, b T) int, empty PersistentList[T]) *list[T] {
+	var sentinel list[T]
+	last := &sentinel
+	for a != nil && b != nil {
+		if cmp(b.head, a.head) < 0 {
+			last.tail = b
+			last, b = b, asNode(b.tail)
+		} else {
+			last.tail = a
+			last, a = a, asNode(a.tail)
+		}
+	}
+
+	switch {
+	case a != nil:
+		last.tail = a
+	case b != nil:
+		last.tail = b
+	default:
+		last.tail = empty
+	}
+	return asNode(sentinel.tail)
+}
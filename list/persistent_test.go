@@ -17,6 +17,11 @@ limitations under the License.
 package list
 
 import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -47,6 +52,33 @@ func TestListAdd(t *testing.T) {
 	assert.Equal(t, "c", head)
 }
 
+func TestListAppend(t *testing.T) {
+	l := Empty[int]().Append(1).Append(2).Append(3)
+	assert.Equal(t, []int{1, 2, 3}, l.ToSlice())
+
+	l2 := l.Append(4)
+	assert.Equal(t, []int{1, 2, 3}, l.ToSlice())
+	assert.Equal(t, []int{1, 2, 3, 4}, l2.ToSlice())
+}
+
+func TestListConcat(t *testing.T) {
+	a := FromSliceReversed([]int{1, 2})
+	b := FromSliceReversed([]int{3, 4})
+
+	c := a.Concat(b)
+	assert.Equal(t, []int{1, 2, 3, 4}, c.ToSlice())
+	assert.Equal(t, []int{1, 2}, a.ToSlice())
+	assert.Equal(t, []int{3, 4}, b.ToSlice())
+
+	// the second list is shared rather than copied
+	tail, _ := c.Tail()
+	tail, _ = tail.Tail()
+	assert.Same(t, b, tail)
+
+	assert.Equal(t, b, Empty[int]().Concat(b))
+	assert.Equal(t, []int{1, 2}, a.Concat(Empty[int]()).ToSlice())
+}
+
 func TestListTail(t *testing.T) {
 	l := Empty[int]().Add(1).Add(2).Add(3)
 
@@ -133,8 +165,19 @@ func TestListMap(t *testing.T) {
 	// List is [3, 2, 1]
 
 	doubled := l.Map(func(x int) int { return x * 2 })
-	// Map traverses from head, but appends, so result is [2, 4, 6]
-	assert.Equal(t, []int{2, 4, 6}, doubled)
+	assert.Equal(t, []int{6, 4, 2}, doubled.ToSlice())
+	assert.Equal(t, []int{3, 2, 1}, l.ToSlice())
+
+	assert.True(t, Empty[int]().Map(func(x int) int { return x }).IsEmpty())
+}
+
+func TestMapTo(t *testing.T) {
+	l := FromSliceReversed([]int{1, 2, 3})
+
+	strs := MapTo(l, func(x int) string { return strings.Repeat("x", x) })
+	assert.Equal(t, []string{"x", "xx", "xxx"}, strs.ToSlice())
+
+	assert.True(t, MapTo(Empty[int](), strconv.Itoa).IsEmpty())
 }
 
 func TestListForEach(t *testing.T) {
@@ -210,3 +253,204 @@ func TestListImmutability(t *testing.T) {
 	assert.Equal(t, uint(3), l2.Length())
 }
 
+func TestListAll(t *testing.T) {
+	l := FromSliceReversed([]int{1, 2, 3, 4})
+	assert.Equal(t, []int{1, 2, 3, 4}, slices.Collect(l.All()))
+
+	var seen []int
+	for item := range l.All() {
+		if item == 3 {
+			break
+		}
+		seen = append(seen, item)
+	}
+	assert.Equal(t, []int{1, 2}, seen)
+
+	assert.Empty(t, slices.Collect(Empty[int]().All()))
+}
+
+func TestListBackward(t *testing.T) {
+	l := FromSliceReversed([]int{1, 2, 3, 4})
+	assert.Equal(t, []int{4, 3, 2, 1}, slices.Collect(l.Backward()))
+
+	var seen []int
+	for item := range l.Backward() {
+		if item == 2 {
+			break
+		}
+		seen = append(seen, item)
+	}
+	assert.Equal(t, []int{4, 3}, seen)
+
+	assert.Empty(t, slices.Collect(Empty[int]().Backward()))
+}
+
+func TestListTake(t *testing.T) {
+	l := FromSliceReversed([]int{1, 2, 3, 4})
+
+	assert.Equal(t, []int{1, 2}, l.Take(2).ToSlice())
+	assert.True(t, l.Take(0).IsEmpty())
+	assert.Same(t, l, l.Take(4))
+	assert.Same(t, l, l.Take(10))
+	assert.Same(t, l, l.Take(^uint(0)))
+	assert.True(t, Empty[int]().Take(3).IsEmpty())
+}
+
+func TestListDrop(t *testing.T) {
+	l := FromSliceReversed([]int{1, 2, 3, 4})
+
+	dropped := l.Drop(2)
+	assert.Equal(t, []int{3, 4}, dropped.ToSlice())
+	tail, _ := l.Tail()
+	tail, _ = tail.Tail()
+	assert.Same(t, tail, dropped)
+
+	assert.Same(t, l, l.Drop(0))
+	assert.True(t, l.Drop(4).IsEmpty())
+	assert.True(t, l.Drop(10).IsEmpty())
+	assert.Equal(t, []int{1, 2, 3, 4}, l.ToSlice())
+}
+
+func TestListSlice(t *testing.T) {
+	l := FromSliceReversed([]int{1, 2, 3, 4, 5})
+
+	assert.Equal(t, []int{2, 3, 4}, l.Slice(1, 4).ToSlice())
+	assert.Equal(t, []int{4, 5}, l.Slice(3, 10).ToSlice())
+	assert.True(t, l.Slice(3, 3).IsEmpty())
+	assert.True(t, l.Slice(4, 2).IsEmpty())
+	assert.True(t, l.Slice(7, 9).IsEmpty())
+	assert.Equal(t, []int{2, 3, 4, 5}, l.Slice(1, ^uint(0)).ToSlice())
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, l.Slice(0, ^uint(0)).ToSlice())
+}
+
+func TestListSort(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	items := make([]int, 1000)
+	for i := range items {
+		items[i] = r.Intn(100)
+	}
+	l := FromSliceReversed(items)
+
+	sorted := l.Sort(cmp.Compare[int])
+	expected := slices.Clone(items)
+	slices.Sort(expected)
+	assert.Equal(t, expected, sorted.ToSlice())
+	assert.Equal(t, items, l.ToSlice())
+
+	assert.True(t, Empty[int]().Sort(cmp.Compare[int]).IsEmpty())
+	assert.Equal(t, []int{1}, FromSlice([]int{1}).Sort(cmp.Compare[int]).ToSlice())
+}
+
+func TestListSortIsStable(t *testing.T) {
+	type pair struct {
+		key, order int
+	}
+	l := FromSliceReversed([]pair{{2, 0}, {1, 1}, {2, 2}, {1, 3}, {0, 4}})
+
+	sorted := SortBy(l, func(p pair) int { return p.key })
+	assert.Equal(t, []pair{{0, 4}, {1, 1}, {1, 3}, {2, 0}, {2, 2}}, sorted.ToSlice())
+}
+
+func TestListMinMax(t *testing.T) {
+	l := FromSliceReversed([]int{3, 1, 4, 1, 5, 9, 2, 6})
+
+	lowest, ok := Min(l, cmp.Compare[int])
+	assert.True(t, ok)
+	assert.Equal(t, 1, lowest)
+
+	highest, ok := Max(l, cmp.Compare[int])
+	assert.True(t, ok)
+	assert.Equal(t, 9, highest)
+
+	_, ok = Min(Empty[int](), cmp.Compare[int])
+	assert.False(t, ok)
+	_, ok = Max(Empty[int](), cmp.Compare[int])
+	assert.False(t, ok)
+}
+
+func TestLongListDoesNotRecurse(t *testing.T) {
+	l := millionItemList()
+	items := l.ToSlice()
+
+	assert.Equal(t, uint(len(items)), l.Length())
+	inserted, err := l.Insert(-1, uint(len(items)))
+	require.NoError(t, err)
+	last, _ := inserted.Get(uint(len(items)))
+	assert.Equal(t, -1, last)
+
+	removed, err := l.Remove(uint(len(items) - 1))
+	require.NoError(t, err)
+	assert.Equal(t, uint(len(items)-1), removed.Length())
+
+	found, ok := l.Find(func(x int) bool { return x == len(items)-1 })
+	assert.True(t, ok)
+	assert.Equal(t, len(items)-1, found)
+	assert.Equal(t, len(items)/2, l.Filter(func(x int) bool { return x%2 == 0 }).Reduce(
+		func(acc, _ int) int { return acc + 1 }, 0))
+}
+
+func TestListInsertRemoveOutOfRange(t *testing.T) {
+	l := FromSliceReversed([]int{1, 2, 3})
+
+	_, err := l.Insert(4, 4)
+	assert.Equal(t, ErrEmptyList, err)
+	_, err = l.Remove(3)
+	assert.Equal(t, ErrEmptyList, err)
+
+	inserted, err := l.Insert(4, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4}, inserted.ToSlice())
+	_, ok := l.Get(3)
+	assert.False(t, ok)
+}
+
+func millionItemList() PersistentList[int] {
+	items := make([]int, 1000000)
+	for i := range items {
+		items[i] = i
+	}
+	return FromSliceReversed(items)
+}
+
+func BenchmarkListLength(b *testing.B) {
+	l := millionItemList()
+	for b.Loop() {
+		l.Length()
+	}
+}
+
+func BenchmarkListForEach(b *testing.B) {
+	l := millionItemList()
+	sum := 0
+	for b.Loop() {
+		l.ForEach(func(x int) { sum += x })
+	}
+}
+
+func BenchmarkListGet(b *testing.B) {
+	l := millionItemList()
+	for b.Loop() {
+		l.Get(999999)
+	}
+}
+
+func BenchmarkListFind(b *testing.B) {
+	l := millionItemList()
+	for b.Loop() {
+		l.Find(func(x int) bool { return x == 999999 })
+	}
+}
+
+func BenchmarkListInsert(b *testing.B) {
+	l := millionItemList()
+	for b.Loop() {
+		l.Insert(-1, 500000)
+	}
+}
+
+func BenchmarkListRemove(b *testing.B) {
+	l := millionItemList()
+	for b.Loop() {
+		l.Remove(500000)
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2015 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package list
+
+import (
+	"iter"
+	"sync"
+)
+
+// Stream is an immutable, lazily-evaluated linked list. Each node is
+// computed the first time it is needed and memoized, so streams may be
+// infinite and expensive elements are only computed once. Streams are
+// safe for concurrent use. A nil *Stream is an empty stream.
+type Stream[T any] struct {
+	once  sync.Once
+	thunk func() *streamCell[T]
+	cell  *streamCell[T]
+}
+
+// streamCell is an evaluated stream node. A nil cell marks the end of
+// the stream.
+type streamCell[T any] struct {
+	head T
+	tail *Stream[T]
+}
+
+func lazyStream[T any](thunk func() *streamCell[T]) *Stream[T] {
+	return &Stream[T]{thunk: thunk}
+}
+
+// EmptyStream returns an empty Stream for the given type.
+func EmptyStream[T any]() *Stream[T] {
+	return &Stream[T]{}
+}
+
+// Cons returns a stream with the provided head, followed by the stream
+// returned by tail. tail is not called until the rest of the stream is
+// needed, and is called at most once.
+func Cons[T any](head T, tail func() *Stream[T]) *Stream[T] {
+	return &Stream[T]{cell: &streamCell[T]{
+		head: head,
+		tail: lazyStream(func() *streamCell[T] {
+			return tail().force()
+		}),
+	}}
+}
+
+// Iterate returns the infinite stream seed, fn(seed), fn(fn(seed)), ...
+func Iterate[T any](seed T, fn func(T) T) *Stream[T] {
+	return Cons(seed, func() *Stream[T] {
+		return Iterate(fn(seed), fn)
+	})
+}
+
+// StreamFromSlice returns a stream over the items of the slice, with the
+// first item of the slice at the head.
+func StreamFromSlice[T any](items []T) *Stream[T] {
+	if len(items) == 0 {
+		return EmptyStream[T]()
+	}
+	return Cons(items[0], func() *Stream[T] {
+		return StreamFromSlice(items[1:])
+	})
+}
+
+// force evaluates this node of the stream, returning nil if the stream
+// is empty.
+func (s *Stream[T]) force() *streamCell[T] {
+	if s == nil {
+		return nil
+	}
+	s.once.Do(func() {
+		if s.thunk != nil {
+			s.cell = s.thunk()
+			s.thunk = nil
+		}
+	})
+	return s.cell
+}
+
+// IsEmpty indicates if the stream is empty. This evaluates the head.
+func (s *Stream[T]) IsEmpty() bool {
+	return s.force() == nil
+}
+
+// Head returns the head of the stream. The bool will be false if the
+// stream is empty.
+func (s *Stream[T]) Head() (T, bool) {
+	c := s.force()
+	if c == nil {
+		var zero T
+		return zero, false
+	}
+	return c.head, true
+}
+
+// Tail returns the tail of the stream. The bool will be false if the
+// stream is empty. The tail itself is not evaluated.
+func (s *Stream[T]) Tail() (*Stream[T], bool) {
+	c := s.force()
+	if c == nil {
+		return nil, false
+	}
+	return c.tail, true
+}
+
+// Take returns a stream of at most the first n items of this stream.
+func (s *Stream[T]) Take(n uint) *Stream[T] {
+	if n == 0 {
+		return EmptyStream[T]()
+	}
+	return lazyStream(func() *streamCell[T] {
+		c := s.force()
+		if c == nil {
+			return nil
+		}
+		return &streamCell[T]{head: c.head, tail: c.tail.Take(n - 1)}
+	})
+}
+
+// Drop returns the stream without its first n items.
+func (s *Stream[T]) Drop(n uint) *Stream[T] {
+	return lazyStream(func() *streamCell[T] {
+		curr := s
+		for ; n > 0; n-- {
+			c := curr.force()
+			if c == nil {
+				return nil
+			}
+			curr = c.tail
+		}
+		return curr.force()
+	})
+}
+
+// Map returns a stream of the results of applying fn to each item.
+// fn is applied as items are evaluated.
+func (s *Stream[T]) Map(fn func(T) T) *Stream[T] {
+	return lazyStream(func() *streamCell[T] {
+		c := s.force()
+		if c == nil {
+			return nil
+		}
+		return &streamCell[T]{head: fn(c.head), tail: c.tail.Map(fn)}
+	})
+}
+
+// Filter returns a stream of the items that satisfy the predicate.
+// Evaluating a node of the result evaluates this stream up to the next
+// matching item, so filtering an infinite stream that has no further
+// matches never returns.
+func (s *Stream[T]) Filter(predicate func(T) bool) *Stream[T] {
+	return lazyStream(func() *streamCell[T] {
+		for c := s.force(); c != nil; c = c.tail.force() {
+			if predicate(c.head) {
+				return &streamCell[T]{head: c.head, tail: c.tail.Filter(predicate)}
+			}
+		}
+		return nil
+	})
+}
+
+// All returns an iterator over the items of the stream, evaluating them
+// as they are yielded.
+func (s *Stream[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for c := s.force(); c != nil; c = c.tail.force() {
+			if !yield(c.head) {
+				return
+			}
+		}
+	}
+}
+
+// ToSlice evaluates the stream and returns its items as a slice. It
+// never returns for an infinite stream; use Take first.
+func (s *Stream[T]) ToSlice() []T {
+	var items []T
+	for item := range s.All() {
+		items = append(items, item)
+	}
+	return items
+}
+
+// ToList evaluates the stream and returns its items as a PersistentList
+// with the head of the stream at the head of the list.
+func (s *Stream[T]) ToList() PersistentList[T] {
+	return FromSliceReversed(s.ToSlice())
+}
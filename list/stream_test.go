@@ -0,0 +1,129 @@
+/*
+Copyright 2015 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package list
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func naturals() *Stream[int] {
+	return Iterate(0, func(i int) int { return i + 1 })
+}
+
+func TestEmptyStream(t *testing.T) {
+	s := EmptyStream[int]()
+
+	assert.True(t, s.IsEmpty())
+	_, ok := s.Head()
+	assert.False(t, ok)
+	_, ok = s.Tail()
+	assert.False(t, ok)
+	assert.Nil(t, s.ToSlice())
+
+	var nilStream *Stream[int]
+	assert.True(t, nilStream.IsEmpty())
+}
+
+func TestStreamInfinite(t *testing.T) {
+	s := naturals()
+
+	head, ok := s.Head()
+	require.True(t, ok)
+	assert.Equal(t, 0, head)
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, s.Take(5).ToSlice())
+	assert.Equal(t, []int{10, 11, 12}, s.Drop(10).Take(3).ToSlice())
+}
+
+func TestStreamMapFilter(t *testing.T) {
+	evens := naturals().Filter(func(i int) bool { return i%2 == 0 })
+	squares := evens.Map(func(i int) int { return i * i })
+
+	assert.Equal(t, []int{0, 4, 16, 36}, squares.Take(4).ToSlice())
+}
+
+func TestStreamMemoizes(t *testing.T) {
+	calls := 0
+	s := naturals().Map(func(i int) int {
+		calls++
+		return i * 10
+	})
+
+	assert.Equal(t, []int{0, 10, 20}, s.Take(3).ToSlice())
+	assert.Equal(t, []int{0, 10, 20}, s.Take(3).ToSlice())
+	assert.Equal(t, 3, calls)
+}
+
+func TestStreamIsLazy(t *testing.T) {
+	evaluated := false
+	s := Cons(1, func() *Stream[int] {
+		evaluated = true
+		return EmptyStream[int]()
+	})
+
+	head, _ := s.Head()
+	assert.Equal(t, 1, head)
+	tail, ok := s.Tail()
+	require.True(t, ok)
+	assert.False(t, evaluated)
+
+	assert.True(t, tail.IsEmpty())
+	assert.True(t, evaluated)
+}
+
+func TestStreamFromSlice(t *testing.T) {
+	s := StreamFromSlice([]int{1, 2, 3})
+
+	assert.Equal(t, []int{1, 2, 3}, s.ToSlice())
+	assert.Equal(t, []int{1, 2, 3}, s.ToList().ToSlice())
+	assert.Equal(t, []int{1, 2}, s.Take(10).Take(2).ToSlice())
+	assert.True(t, s.Drop(5).IsEmpty())
+
+	var seen []int
+	for item := range s.All() {
+		if item == 2 {
+			break
+		}
+		seen = append(seen, item)
+	}
+	assert.Equal(t, []int{1}, seen)
+}
+
+func TestStreamConcurrentEvaluation(t *testing.T) {
+	calls := 0
+	var lock sync.Mutex
+	s := naturals().Map(func(i int) int {
+		lock.Lock()
+		calls++
+		lock.Unlock()
+		return i
+	}).Take(100)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Len(t, s.ToSlice(), 100)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 100, calls)
+}
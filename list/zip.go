@@ -0,0 +1,58 @@
+/*
+Copyright 2015 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package list
+
+// Pair holds one item from each of two zipped lists.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Zip returns a list pairing the items of a and b by position. The result
+// is as long as the shorter of the two lists.
+func Zip[A, B any](a PersistentList[A], b PersistentList[B]) PersistentList[Pair[A, B]] {
+	return ZipWith(a, b, func(first A, second B) Pair[A, B] {
+		return Pair[A, B]{First: first, Second: second}
+	})
+}
+
+// ZipWith returns a list of the results of applying fn to the items of a
+// and b at each position. The result is as long as the shorter of the
+// two lists.
+func ZipWith[A, B, C any](a PersistentList[A], b PersistentList[B], fn func(A, B) C) PersistentList[C] {
+	var zipped []C
+	na, nb := asNode(a), asNode(b)
+	for na != nil && nb != nil {
+		zipped = append(zipped, fn(na.head, nb.head))
+		na, nb = asNode(na.tail), asNode(nb.tail)
+	}
+	return FromSliceReversed(zipped)
+}
+
+// Unzip splits a list of pairs into a list of the first items and a list
+// of the second items.
+func Unzip[A, B any](l PersistentList[Pair[A, B]]) (PersistentList[A], PersistentList[B]) {
+	var (
+		firsts  []A
+		seconds []B
+	)
+	for pair := range l.All() {
+		firsts = append(firsts, pair.First)
+		seconds = append(seconds, pair.Second)
+	}
+	return FromSliceReversed(firsts), FromSliceReversed(seconds)
+}
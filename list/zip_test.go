@@ -0,0 +1,55 @@
+/*
+Copyright 2015 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package list
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZip(t *testing.T) {
+	a := FromSliceReversed([]int{1, 2, 3})
+	b := FromSliceReversed([]string{"a", "b"})
+
+	zipped := Zip(a, b)
+	assert.Equal(t, []Pair[int, string]{{1, "a"}, {2, "b"}}, zipped.ToSlice())
+
+	assert.True(t, Zip(Empty[int](), b).IsEmpty())
+	assert.True(t, Zip(a, Empty[string]()).IsEmpty())
+}
+
+func TestZipWith(t *testing.T) {
+	a := FromSliceReversed([]int{1, 2, 3})
+	b := FromSliceReversed([]int{10, 20, 30, 40})
+
+	sums := ZipWith(a, b, func(x, y int) string { return strconv.Itoa(x + y) })
+	assert.Equal(t, []string{"11", "22", "33"}, sums.ToSlice())
+}
+
+func TestUnzip(t *testing.T) {
+	l := FromSliceReversed([]Pair[int, string]{{1, "a"}, {2, "b"}, {3, "c"}})
+
+	firsts, seconds := Unzip(l)
+	assert.Equal(t, []int{1, 2, 3}, firsts.ToSlice())
+	assert.Equal(t, []string{"a", "b", "c"}, seconds.ToSlice())
+
+	firsts, seconds = Unzip(Empty[Pair[int, string]]())
+	assert.True(t, firsts.IsEmpty())
+	assert.True(t, seconds.IsEmpty())
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package memtable implements the in-memory half of a log-structured
+merge tree: a sorted table of recent writes backed by a write-ahead log.
+
+A Memtable is a skip list of entries ordered by key, where deletes are
+recorded as tombstones so that they can shadow older values held
+elsewhere.  A Store puts a Memtable in front of a write-ahead log:
+every write is appended to the log before it is applied, so that a
+Store opened after a crash replays the log and recovers its contents.
+Once the active memtable grows past a size it is frozen, still serving
+reads while a new one takes writes, and frozen memtables are merged k
+ways, newest first, into a single sorted run handed to a flush
+function, after which their logs are removed.
+*/
+package memtable
+
+import (
+	"bytes"
+	"iter"
+
+	"github.com/Workiva/go-datastructures/slice/skip"
+)
+
+// Entry is a key and its value in a memtable.  Deleted entries are
+// tombstones, which have no value and hide the key in older tables.
+type Entry struct {
+	Key, Value []byte
+	Deleted    bool
+}
+
+func compareEntries(a, b Entry) int {
+	return bytes.Compare(a.Key, b.Key)
+}
+
+// Memtable is a sorted table of entries held in a skip list.  It is
+// not safe for concurrent use; a Store guards the memtables it keeps.
+type Memtable struct {
+	list *skip.SkipList[Entry]
+	size int
+}
+
+// New returns an empty memtable.
+func New() *Memtable {
+	return &Memtable{list: skip.NewWithCompareFunc(compareEntries, uint64(0))}
+}
+
+// Put sets the value of key, replacing any value or tombstone it had.
+// The key and value are copied.
+func (m *Memtable) Put(key, value []byte) {
+	m.insert(Entry{Key: bytes.Clone(key), Value: bytes.Clone(value)})
+}
+
+// Delete records a tombstone for key, whether or not the memtable
+// holds a value for it.
+func (m *Memtable) Delete(key []byte) {
+	m.insert(Entry{Key: bytes.Clone(key), Deleted: true})
+}
+
+func (m *Memtable) insert(entry Entry) {
+	if entry.Key == nil {
+		entry.Key = []byte{}
+	}
+	old, replaced := m.list.Insert(entry)
+	if replaced[0] {
+		m.size -= len(old[0].Value)
+	} else {
+		m.size += len(entry.Key)
+	}
+	m.size += len(entry.Value)
+}
+
+// Get returns the entry for key, which may be a tombstone, and whether
+// the memtable holds one.
+func (m *Memtable) Get(key []byte) (Entry, bool) {
+	entries, found := m.list.Get(Entry{Key: key})
+	return entries[0], found[0]
+}
+
+// Len returns the number of entries, tombstones included.
+func (m *Memtable) Len() int {
+	return int(m.list.Len())
+}
+
+// Size returns the number of bytes of keys and values held.
+func (m *Memtable) Size() int {
+	return m.size
+}
+
+// All returns an iterator over the entries, tombstones included, in
+// ascending order of key.  The memtable must not be modified while it
+// is being iterated.
+func (m *Memtable) All() iter.Seq[Entry] {
+	return m.Range(nil, nil)
+}
+
+// Range returns an iterator over the entries, tombstones included,
+// with keys from from, inclusive, to to, exclusive, in ascending order.
+// A nil to leaves the range unbounded above.  The memtable must not be
+// modified while it is being iterated.
+func (m *Memtable) Range(from, to []byte) iter.Seq[Entry] {
+	return func(yield func(Entry) bool) {
+		it := m.list.Iter(Entry{Key: from})
+		for it.Next() {
+			entry := it.Value()
+			if to != nil && bytes.Compare(entry.Key, to) >= 0 {
+				return
+			}
+			if !yield(entry) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memtable
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func keys(entries []Entry) []string {
+	result := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, string(entry.Key))
+	}
+	return result
+}
+
+func TestMemtable(t *testing.T) {
+	m := New()
+	assert.Equal(t, 0, m.Len())
+
+	key := []byte(`b`)
+	m.Put(key, []byte(`1`))
+	key[0] = 'z' // the memtable keeps its own copy
+	m.Put([]byte(`a`), []byte(`2`))
+	m.Put([]byte(`c`), []byte(`3`))
+	m.Delete([]byte(`d`))
+
+	entry, ok := m.Get([]byte(`b`))
+	assert.True(t, ok)
+	assert.Equal(t, []byte(`1`), entry.Value)
+	_, ok = m.Get([]byte(`z`))
+	assert.False(t, ok)
+	entry, ok = m.Get([]byte(`d`))
+	assert.True(t, ok)
+	assert.True(t, entry.Deleted)
+
+	assert.Equal(t, 4, m.Len())
+	assert.Equal(t, []string{`a`, `b`, `c`, `d`}, keys(slices.Collect(m.All())))
+	assert.Equal(t, []string{`b`, `c`}, keys(slices.Collect(m.Range([]byte(`b`), []byte(`d`)))))
+	assert.Equal(t, []string{`c`, `d`}, keys(slices.Collect(m.Range([]byte(`bb`), nil))))
+}
+
+func TestMemtableSize(t *testing.T) {
+	m := New()
+	m.Put([]byte(`key`), []byte(`value`))
+	assert.Equal(t, 8, m.Size())
+	m.Put([]byte(`key`), []byte(`v`))
+	assert.Equal(t, 4, m.Size())
+	m.Delete([]byte(`key`))
+	assert.Equal(t, 3, m.Size())
+	m.Delete([]byte(`other`))
+	assert.Equal(t, 8, m.Size())
+	assert.Equal(t, 2, m.Len())
+}
+
+func TestMemtableEmptyKey(t *testing.T) {
+	m := New()
+	m.Put(nil, []byte(`empty`))
+	entry, ok := m.Get([]byte{})
+	assert.True(t, ok)
+	assert.Equal(t, []byte(`empty`), entry.Value)
+	assert.Len(t, slices.Collect(m.All()), 1)
+}
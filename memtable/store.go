@@ -0,0 +1,369 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memtable
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	merge "github.com/Workiva/go-datastructures/sort"
+)
+
+// defaultMaxSize is the size in bytes at which the active memtable is
+// frozen when no size is configured.
+const defaultMaxSize = 4 << 20
+
+const logSuffix = ".wal"
+
+var (
+	// ErrNoDir is returned by Open when no directory is configured.
+	ErrNoDir = errors.New(`memtable: store requires a Dir`)
+	// ErrNoFlush is returned by Open when no Flush function is
+	// configured.
+	ErrNoFlush = errors.New(`memtable: store requires a Flush function`)
+	// ErrClosed is returned when writing to a closed Store.
+	ErrClosed = errors.New(`memtable: store is closed`)
+)
+
+// Config configures a Store.
+type Config struct {
+	// Dir is the directory the write-ahead logs are kept in.  It is
+	// created if it does not exist, and should not be shared.
+	Dir string
+	// MaxSize is the number of bytes of keys and values the active
+	// memtable holds before it is frozen.  Defaults to 4MiB.
+	MaxSize int
+	// MaxFrozen is the number of frozen memtables kept, still serving
+	// reads, before they are merged and flushed together.  Defaults
+	// to 1, flushing each memtable as soon as it is frozen.
+	MaxFrozen int
+	// Flush is handed the merged entries of the frozen memtables in
+	// ascending order of key, with only the newest entry for each key
+	// and tombstones included.  Once it returns nil the logs of the
+	// flushed memtables are removed; if it returns an error they are
+	// kept and flushed again with the next.
+	Flush func(entries iter.Seq[Entry]) error
+	// Sync syncs the log to disk after every write, which survives
+	// power loss and not just the process crashing, at a cost.
+	Sync bool
+}
+
+// table is a memtable along with the logs that hold its writes.
+type table struct {
+	mem  *Memtable
+	logs []string
+}
+
+// Store is a memtable backed by a write-ahead log, which freezes and
+// flushes its contents once they grow too large.  It is safe for
+// concurrent use; flushes happen on the goroutine whose write
+// triggered them, and block other writers while they run.  A write
+// that triggers a flush that fails is still applied, and returns the
+// flush's error.
+type Store struct {
+	mu      sync.RWMutex
+	config  Config
+	active  *table
+	frozen  []*table // newest first
+	log     *wal
+	nextLog uint64
+	closed  bool
+}
+
+// Open opens the Store kept in config.Dir, recovering any writes from
+// its logs that were not flushed before it was last closed or crashed.
+func Open(config Config) (*Store, error) {
+	if config.Dir == `` {
+		return nil, ErrNoDir
+	}
+	if config.Flush == nil {
+		return nil, ErrNoFlush
+	}
+	if config.MaxSize <= 0 {
+		config.MaxSize = defaultMaxSize
+	}
+	if config.MaxFrozen <= 0 {
+		config.MaxFrozen = 1
+	}
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	logs, next, err := findLogs(config.Dir)
+	if err != nil {
+		return nil, err
+	}
+	s := &Store{config: config, nextLog: next}
+	recovered := &table{mem: New()}
+	for _, log := range logs {
+		if err := replayWAL(log, recovered.mem); err != nil {
+			return nil, err
+		}
+		recovered.logs = append(recovered.logs, log)
+	}
+
+	if err := s.rotate(); err != nil {
+		return nil, err
+	}
+	s.active.logs = append(recovered.logs, s.active.logs...)
+	s.active.mem = recovered.mem
+	if err := s.maybeFreeze(); err != nil {
+		s.log.close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// findLogs returns the logs in dir, oldest first, and the number the
+// next log should be given.
+func findLogs(dir string) ([]string, uint64, error) {
+	names, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var logs []string
+	var next uint64
+	for _, entry := range names {
+		name := entry.Name()
+		if !strings.HasSuffix(name, logSuffix) {
+			continue
+		}
+		n, err := strconv.ParseUint(strings.TrimSuffix(name, logSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		logs = append(logs, filepath.Join(dir, name))
+		next = max(next, n+1)
+	}
+	// names are zero padded, so they sort in the order they were created
+	slices.Sort(logs)
+	return logs, next, nil
+}
+
+// rotate closes the current log, if any, and starts a new active
+// memtable with a new log.
+func (s *Store) rotate() error {
+	path := filepath.Join(s.config.Dir, fmt.Sprintf(`%020d%s`, s.nextLog, logSuffix))
+	log, err := createWAL(path, s.config.Sync)
+	if err != nil {
+		return err
+	}
+	s.nextLog++
+
+	if s.log != nil {
+		if err := s.log.close(); err != nil {
+			log.close()
+			os.Remove(path)
+			return err
+		}
+	}
+	s.log = log
+	s.active = &table{mem: New(), logs: []string{path}}
+	return nil
+}
+
+// Put sets the value of key.
+func (s *Store) Put(key, value []byte) error {
+	return s.write(kindPut, key, value)
+}
+
+// Delete removes key, recording a tombstone that hides it in flushed
+// data.
+func (s *Store) Delete(key []byte) error {
+	return s.write(kindDelete, key, nil)
+}
+
+func (s *Store) write(kind byte, key, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+	if err := s.log.append(kind, key, value); err != nil {
+		return err
+	}
+	if kind == kindPut {
+		s.active.mem.Put(key, value)
+	} else {
+		s.active.mem.Delete(key)
+	}
+	return s.maybeFreeze()
+}
+
+// maybeFreeze freezes the active memtable if it has grown too large.
+func (s *Store) maybeFreeze() error {
+	if s.active.mem.Size() < s.config.MaxSize {
+		return nil
+	}
+	if err := s.freeze(); err != nil {
+		return err
+	}
+	if len(s.frozen) < s.config.MaxFrozen {
+		return nil
+	}
+	return s.flush()
+}
+
+// freeze moves the active memtable, if it holds anything, to the front
+// of the frozen memtables.
+func (s *Store) freeze() error {
+	if s.active.mem.Len() == 0 {
+		return nil
+	}
+	active := s.active
+	if err := s.rotate(); err != nil {
+		return err
+	}
+	s.frozen = slices.Insert(s.frozen, 0, active)
+	return nil
+}
+
+// flush hands the merged frozen memtables to the flush function and
+// drops them, along with their logs, if it succeeds.
+func (s *Store) flush() error {
+	if len(s.frozen) == 0 {
+		return nil
+	}
+	seqs := make([]iter.Seq[Entry], len(s.frozen))
+	for i, t := range s.frozen {
+		seqs[i] = t.mem.All()
+	}
+	if err := s.config.Flush(newest(merge.Merge(compareEntries, seqs...))); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, t := range s.frozen {
+		for _, log := range t.logs {
+			if err := os.Remove(log); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, err)
+			}
+		}
+	}
+	s.frozen = nil
+	return errors.Join(errs...)
+}
+
+// Flush freezes the active memtable and flushes it along with any
+// other frozen memtables.
+func (s *Store) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+	if err := s.freeze(); err != nil {
+		return err
+	}
+	return s.flush()
+}
+
+// newest drops all but the first of each run of entries with equal
+// keys from a merge of memtables ordered newest first, leaving the
+// newest entry for each key.
+func newest(entries iter.Seq[Entry]) iter.Seq[Entry] {
+	return func(yield func(Entry) bool) {
+		var last []byte
+		first := true
+		for entry := range entries {
+			if !first && bytes.Equal(entry.Key, last) {
+				continue
+			}
+			first = false
+			last = entry.Key
+			if !yield(entry) {
+				return
+			}
+		}
+	}
+}
+
+// Get returns the value of key and whether the store holds one, either
+// in the active memtable or in a frozen memtable not yet flushed.
+func (s *Store) Get(key []byte) ([]byte, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if entry, ok := s.active.mem.Get(key); ok {
+		return entry.Value, !entry.Deleted
+	}
+	for _, t := range s.frozen {
+		if entry, ok := t.mem.Get(key); ok {
+			return entry.Value, !entry.Deleted
+		}
+	}
+	return nil, false
+}
+
+// All returns an iterator over the keys and values held and not yet
+// flushed, in ascending order of key.  It iterates over a snapshot of
+// the store, which may be written to meanwhile.
+func (s *Store) All() iter.Seq2[[]byte, []byte] {
+	return s.Range(nil, nil)
+}
+
+// Range returns an iterator over the keys and values held and not yet
+// flushed, with keys from from, inclusive, to to, exclusive, in
+// ascending order.  A nil to leaves the range unbounded above.  It
+// iterates over a snapshot of the store, which may be written to
+// meanwhile.
+func (s *Store) Range(from, to []byte) iter.Seq2[[]byte, []byte] {
+	return func(yield func([]byte, []byte) bool) {
+		// frozen memtables are never modified, so only the active one
+		// needs to be copied
+		s.mu.RLock()
+		seqs := make([]iter.Seq[Entry], 0, len(s.frozen)+1)
+		seqs = append(seqs, slices.Values(slices.Collect(s.active.mem.Range(from, to))))
+		for _, t := range s.frozen {
+			seqs = append(seqs, t.mem.Range(from, to))
+		}
+		s.mu.RUnlock()
+
+		for entry := range newest(merge.Merge(compareEntries, seqs...)) {
+			if entry.Deleted {
+				continue
+			}
+			if !yield(entry.Key, entry.Value) {
+				return
+			}
+		}
+	}
+}
+
+// Close closes the log.  Writes not yet flushed stay in the logs and
+// are recovered when the store is opened again.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return s.log.close()
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memtable
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sink records the entries flushed by a store.
+type sink struct {
+	flushes [][]Entry
+	err     error
+}
+
+func (s *sink) flush(entries iter.Seq[Entry]) error {
+	if s.err != nil {
+		return s.err
+	}
+	var flushed []Entry
+	for entry := range entries {
+		flushed = append(flushed, entry)
+	}
+	s.flushes = append(s.flushes, flushed)
+	return nil
+}
+
+func openStore(t *testing.T, dir string, maxSize, maxFrozen int, s *sink) *Store {
+	store, err := Open(Config{Dir: dir, MaxSize: maxSize, MaxFrozen: maxFrozen, Flush: s.flush})
+	require.NoError(t, err)
+	return store
+}
+
+func collect(seq iter.Seq2[[]byte, []byte]) map[string]string {
+	result := map[string]string{}
+	for k, v := range seq {
+		result[string(k)] = string(v)
+	}
+	return result
+}
+
+func logFiles(t *testing.T, dir string) []string {
+	logs, err := filepath.Glob(filepath.Join(dir, `*`+logSuffix))
+	require.NoError(t, err)
+	return logs
+}
+
+func TestOpenConfig(t *testing.T) {
+	_, err := Open(Config{Flush: (&sink{}).flush})
+	assert.Equal(t, ErrNoDir, err)
+	_, err = Open(Config{Dir: t.TempDir()})
+	assert.Equal(t, ErrNoFlush, err)
+}
+
+func TestStore(t *testing.T) {
+	store := openStore(t, t.TempDir(), 0, 0, &sink{})
+	defer store.Close()
+
+	require.NoError(t, store.Put([]byte(`b`), []byte(`2`)))
+	require.NoError(t, store.Put([]byte(`a`), []byte(`1`)))
+	require.NoError(t, store.Put([]byte(`c`), []byte(`3`)))
+	require.NoError(t, store.Delete([]byte(`b`)))
+
+	value, ok := store.Get([]byte(`a`))
+	assert.True(t, ok)
+	assert.Equal(t, []byte(`1`), value)
+	_, ok = store.Get([]byte(`b`))
+	assert.False(t, ok)
+
+	assert.Equal(t, map[string]string{`a`: `1`, `c`: `3`}, collect(store.All()))
+	assert.Equal(t, map[string]string{`c`: `3`}, collect(store.Range([]byte(`b`), nil)))
+
+	require.NoError(t, store.Close())
+	assert.Equal(t, ErrClosed, store.Put([]byte(`d`), nil))
+	assert.NoError(t, store.Close())
+}
+
+func TestStoreRecovery(t *testing.T) {
+	dir := t.TempDir()
+	store := openStore(t, dir, 0, 0, &sink{})
+	for i := range 100 {
+		require.NoError(t, store.Put([]byte(fmt.Sprintf(`key%03d`, i)), []byte(fmt.Sprint(i))))
+	}
+	require.NoError(t, store.Delete([]byte(`key050`)))
+	require.NoError(t, store.Close())
+
+	store = openStore(t, dir, 0, 0, &sink{})
+	defer store.Close()
+	all := collect(store.All())
+	assert.Len(t, all, 99)
+	assert.Equal(t, `42`, all[`key042`])
+	_, ok := store.Get([]byte(`key050`))
+	assert.False(t, ok)
+}
+
+func TestStoreRecoversTornLog(t *testing.T) {
+	dir := t.TempDir()
+	store := openStore(t, dir, 0, 0, &sink{})
+	require.NoError(t, store.Put([]byte(`a`), []byte(`1`)))
+	require.NoError(t, store.Put([]byte(`b`), []byte(`2`)))
+	require.NoError(t, store.Close())
+
+	// cut the last record short, as a crash in the middle of writing it
+	// would
+	logs := logFiles(t, dir)
+	require.Len(t, logs, 1)
+	info, err := os.Stat(logs[0])
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(logs[0], info.Size()-1))
+
+	store = openStore(t, dir, 0, 0, &sink{})
+	assert.Equal(t, map[string]string{`a`: `1`}, collect(store.All()))
+	require.NoError(t, store.Put([]byte(`c`), []byte(`3`)))
+	require.NoError(t, store.Close())
+
+	// garbage at the end of a log is ignored too
+	f, err := os.OpenFile(logs[0], os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	store = openStore(t, dir, 0, 0, &sink{})
+	defer store.Close()
+	assert.Equal(t, map[string]string{`a`: `1`, `c`: `3`}, collect(store.All()))
+}
+
+func TestStoreFreezeAndFlush(t *testing.T) {
+	dir := t.TempDir()
+	s := &sink{}
+	store := openStore(t, dir, 10, 2, s)
+	defer store.Close()
+
+	require.NoError(t, store.Put([]byte(`a`), []byte(`1234`)))
+	require.NoError(t, store.Put([]byte(`b`), []byte(`1234`)))
+	// the first memtable is frozen but kept
+	assert.Empty(t, s.flushes)
+	assert.Len(t, logFiles(t, dir), 2)
+
+	require.NoError(t, store.Delete([]byte(`a`)))
+	require.NoError(t, store.Put([]byte(`b`), []byte(`5`)))
+	require.NoError(t, store.Put([]byte(`c`), []byte(`12345678`)))
+	// the second is frozen and both are merged, newest first
+	require.Len(t, s.flushes, 1)
+	assert.Equal(t, []Entry{
+		{Key: []byte(`a`), Deleted: true},
+		{Key: []byte(`b`), Value: []byte(`5`)},
+		{Key: []byte(`c`), Value: []byte(`12345678`)},
+	}, s.flushes[0])
+	assert.Len(t, logFiles(t, dir), 1)
+	assert.Empty(t, collect(store.All()))
+
+	require.NoError(t, store.Put([]byte(`d`), []byte(`4`)))
+	require.NoError(t, store.Flush())
+	require.Len(t, s.flushes, 2)
+	assert.Equal(t, []Entry{{Key: []byte(`d`), Value: []byte(`4`)}}, s.flushes[1])
+
+	// flushing nothing does not call the flush function
+	require.NoError(t, store.Flush())
+	assert.Len(t, s.flushes, 2)
+}
+
+func TestStoreFlushError(t *testing.T) {
+	dir := t.TempDir()
+	failure := errors.New(`failed`)
+	s := &sink{err: failure}
+	store := openStore(t, dir, 4, 1, s)
+
+	assert.Equal(t, failure, store.Put([]byte(`a`), []byte(`123`)))
+	// the write is still applied and readable from the frozen memtable
+	value, ok := store.Get([]byte(`a`))
+	assert.True(t, ok)
+	assert.Equal(t, []byte(`123`), value)
+
+	assert.Equal(t, failure, store.Put([]byte(`b`), []byte(`456`)))
+	assert.Equal(t, map[string]string{`a`: `123`, `b`: `456`}, collect(store.All()))
+	require.NoError(t, store.Close())
+
+	// the logs survive the failed flushes
+	s.err = nil
+	store = openStore(t, dir, 100, 1, s)
+	defer store.Close()
+	require.NoError(t, store.Flush())
+	require.Len(t, s.flushes, 1)
+	assert.Equal(t, []string{`a`, `b`}, keys(s.flushes[0]))
+	assert.Len(t, logFiles(t, dir), 1)
+}
+
+func TestStoreMatchesMap(t *testing.T) {
+	dir := t.TempDir()
+	s := &sink{}
+	store := openStore(t, dir, 256, 3, s)
+	expected := map[string]string{}
+	flushed := map[string]string{}
+	apply := func(entries []Entry) {
+		for _, entry := range entries {
+			if entry.Deleted {
+				delete(flushed, string(entry.Key))
+			} else {
+				flushed[string(entry.Key)] = string(entry.Value)
+			}
+		}
+	}
+
+	for i := range 2000 {
+		key := fmt.Sprintf(`%02d`, rand.Intn(100))
+		if rand.Intn(4) == 0 {
+			require.NoError(t, store.Delete([]byte(key)))
+			delete(expected, key)
+		} else {
+			value := fmt.Sprint(i)
+			require.NoError(t, store.Put([]byte(key), []byte(value)))
+			expected[key] = value
+		}
+
+		if i%500 == 499 {
+			require.NoError(t, store.Close())
+			store = openStore(t, dir, 256, 3, s)
+		}
+	}
+	defer store.Close()
+
+	for _, flush := range s.flushes {
+		assert.True(t, slices.IsSortedFunc(flush, compareEntries))
+		apply(flush)
+	}
+	// unflushed writes shadow flushed ones
+	for k, v := range store.All() {
+		flushed[string(k)] = string(v)
+	}
+	for k := range maps.Keys(flushed) {
+		if _, ok := expected[k]; !ok {
+			_, live := store.Get([]byte(k))
+			assert.False(t, live)
+			// only a tombstone in the store can explain the difference
+			delete(flushed, k)
+		}
+	}
+	assert.Equal(t, expected, flushed)
+
+	require.NoError(t, store.Flush())
+	flushed = map[string]string{}
+	for _, flush := range s.flushes {
+		apply(flush)
+	}
+	assert.Equal(t, expected, flushed)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memtable
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"os"
+)
+
+// Each record in a log is a header of a checksum and the length of the
+// payload, both little-endian uint32s, followed by the payload: a kind
+// byte, the length of the key as a uvarint, the key and the value.
+const headerSize = 8
+
+const (
+	kindPut byte = iota + 1
+	kindDelete
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// errTorn is returned internally when a log ends with a record that
+// was not completely written.
+var errTorn = errors.New(`memtable: torn record`)
+
+// wal is a write-ahead log of the writes applied to a memtable.
+type wal struct {
+	file *os.File
+	sync bool
+	buf  []byte
+}
+
+// createWAL creates a new, empty log at path.
+func createWAL(path string, sync bool) (*wal, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &wal{file: file, sync: sync}, nil
+}
+
+// append writes a record in a single write, so that a crash leaves at
+// most the last record torn, and syncs it to disk if configured to.
+func (w *wal) append(kind byte, key, value []byte) error {
+	buf := append(w.buf[:0], make([]byte, headerSize)...)
+	buf = append(buf, kind)
+	buf = binary.AppendUvarint(buf, uint64(len(key)))
+	buf = append(buf, key...)
+	buf = append(buf, value...)
+	payload := buf[headerSize:]
+	binary.LittleEndian.PutUint32(buf, crc32.Checksum(payload, castagnoli))
+	binary.LittleEndian.PutUint32(buf[4:], uint32(len(payload)))
+	w.buf = buf
+
+	if _, err := w.file.Write(buf); err != nil {
+		return err
+	}
+	if w.sync {
+		return w.file.Sync()
+	}
+	return nil
+}
+
+// close syncs the log to disk and closes it.
+func (w *wal) close() error {
+	err := w.file.Sync()
+	if cerr := w.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// replayWAL applies the records in the log at path to m.  Replay stops
+// quietly at the first record that is incomplete or fails its
+// checksum, which is what a crash in the middle of a write leaves
+// behind.
+func replayWAL(path string, m *Memtable) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	remaining := info.Size()
+
+	r := bufio.NewReader(file)
+	header := make([]byte, headerSize)
+	var payload []byte
+	for {
+		kind, key, value, n, err := readRecord(r, header, &payload, remaining)
+		if err == io.EOF || err == errTorn {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		remaining -= n
+
+		switch kind {
+		case kindPut:
+			m.Put(key, value)
+		case kindDelete:
+			m.Delete(key)
+		default:
+			return nil
+		}
+	}
+}
+
+// readRecord reads the next record from r, of which at most remaining
+// bytes are left, returning its fields and its size in bytes.
+func readRecord(r io.Reader, header []byte, payload *[]byte, remaining int64) (byte, []byte, []byte, int64, error) {
+	if _, err := io.ReadFull(r, header); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = errTorn
+		}
+		return 0, nil, nil, 0, err
+	}
+
+	sum := binary.LittleEndian.Uint32(header)
+	length := int64(binary.LittleEndian.Uint32(header[4:]))
+	if length == 0 || length > remaining-headerSize {
+		return 0, nil, nil, 0, errTorn
+	}
+	if int64(cap(*payload)) < length {
+		*payload = make([]byte, length)
+	}
+	buf := (*payload)[:length]
+	if _, err := io.ReadFull(r, buf); err != nil {
+		if err == io.ErrUnexpectedEOF || err == io.EOF {
+			err = errTorn
+		}
+		return 0, nil, nil, 0, err
+	}
+	if crc32.Checksum(buf, castagnoli) != sum {
+		return 0, nil, nil, 0, errTorn
+	}
+
+	keyLen, n := binary.Uvarint(buf[1:])
+	if n <= 0 || keyLen > uint64(len(buf)-1-n) {
+		return 0, nil, nil, 0, errTorn
+	}
+	key := buf[1+n : 1+n+int(keyLen)]
+	value := buf[1+n+int(keyLen):]
+	return buf[0], key, value, headerSize + length, nil
+}
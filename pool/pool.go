@@ -0,0 +1,403 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package pool implements a typed, bounded pool of objects that are
+expensive to create, such as connections or large buffers.
+
+Unlike sync.Pool, objects are only dropped when the pool decides to:
+when more than a number of them are idle, when one has been idle for
+too long, or when the caller says it is broken.  Hooks run as objects
+are created, returned and destroyed, the number of objects in use can
+be capped, with callers waiting for one to be returned, and counters
+report how the pool is doing.
+
+The pool runs no goroutines or timers of its own: idle objects are
+evicted as the pool is used, or when Evict is called.
+*/
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned by Get once the pool is closed.
+var ErrClosed = errors.New(`pool: closed`)
+
+// options holds the settings that may be given when creating a pool.
+type options[T any] struct {
+	reset       func(T) error
+	destroy     func(T)
+	maxSize     int
+	maxIdle     int
+	idleTimeout time.Duration
+	now         func() time.Time
+}
+
+// Option configures a pool when it is created.
+type Option[T any] func(*options[T])
+
+// WithReset sets a function called on each object returned to the
+// pool, to make it ready for reuse.  Objects it returns an error for
+// are destroyed instead.
+func WithReset[T any](reset func(T) error) Option[T] {
+	return func(o *options[T]) {
+		o.reset = reset
+	}
+}
+
+// WithDestroy sets a function called on each object the pool drops,
+// to release what it holds.
+func WithDestroy[T any](destroy func(T)) Option[T] {
+	return func(o *options[T]) {
+		o.destroy = destroy
+	}
+}
+
+// WithMaxSize caps the number of objects, in use or idle, at n, with
+// Get waiting for one to be returned once it is reached.  By default
+// there is no cap.
+func WithMaxSize[T any](n int) Option[T] {
+	return func(o *options[T]) {
+		o.maxSize = n
+	}
+}
+
+// WithMaxIdle caps the number of idle objects kept at n, destroying
+// the longest idle when more are returned.  By default there is no
+// cap.
+func WithMaxIdle[T any](n int) Option[T] {
+	return func(o *options[T]) {
+		o.maxIdle = n
+	}
+}
+
+// WithIdleTimeout destroys objects that have been idle for at least d.
+// By default idle objects are kept indefinitely.
+func WithIdleTimeout[T any](d time.Duration) Option[T] {
+	return func(o *options[T]) {
+		o.idleTimeout = d
+	}
+}
+
+// WithClock sets the function the pool reads the current time from.
+// It defaults to time.Now, and is usually replaced to control time in
+// tests.
+func WithClock[T any](now func() time.Time) Option[T] {
+	return func(o *options[T]) {
+		o.now = now
+	}
+}
+
+// Stats counts what a pool has done since it was created.
+type Stats struct {
+	// Hits is the number of Gets served by an idle object.
+	Hits uint64
+	// Misses is the number of Gets that created an object.
+	Misses uint64
+	// Waits is the number of Gets that waited for an object, because
+	// the pool was at its maximum size.
+	Waits uint64
+	// Created and Destroyed are the numbers of objects created and
+	// destroyed.  Failed creations are not counted.
+	Created, Destroyed uint64
+	// Evicted is the number of objects destroyed for being idle too
+	// long.
+	Evicted uint64
+	// InUse and Idle are the numbers of objects currently handed out
+	// and waiting in the pool.
+	InUse, Idle int
+}
+
+// idleObject is an object in the pool along with when it was returned.
+type idleObject[T any] struct {
+	value T
+	since time.Time
+}
+
+// handoff passes a returned object, or a free slot to create one in,
+// straight to a waiting Get.
+type handoff[T any] struct {
+	value  T
+	object bool
+	closed bool
+}
+
+// Pool is a pool of objects of type T.  It is safe for concurrent use.
+type Pool[T any] struct {
+	mu      sync.Mutex
+	create  func() (T, error)
+	options options[T]
+	idle    []idleObject[T] // longest idle first
+	waiters []chan handoff[T]
+	live    int // objects in use or idle
+	closed  bool
+	stats   Stats
+}
+
+// New returns an empty pool that creates objects with create.
+func New[T any](create func() (T, error), opts ...Option[T]) *Pool[T] {
+	o := options[T]{now: time.Now}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Pool[T]{create: create, options: o}
+}
+
+// Get returns the most recently returned idle object, or creates one.
+// If the pool is at its maximum size, Get waits until an object is
+// returned or discarded, or ctx is done.
+func (p *Pool[T]) Get(ctx context.Context) (T, error) {
+	var zero T
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return zero, ErrClosed
+	}
+	expired := p.expire()
+	if len(p.idle) > 0 {
+		last := len(p.idle) - 1
+		value := p.idle[last].value
+		p.idle[last] = idleObject[T]{}
+		p.idle = p.idle[:last]
+		p.stats.Hits++
+		p.mu.Unlock()
+		p.destroyAll(expired)
+		return value, nil
+	}
+	if p.options.maxSize <= 0 || p.live < p.options.maxSize {
+		p.live++
+		p.stats.Misses++
+		p.mu.Unlock()
+		p.destroyAll(expired)
+		return p.newObject()
+	}
+
+	wait := make(chan handoff[T], 1)
+	p.waiters = append(p.waiters, wait)
+	p.stats.Waits++
+	p.mu.Unlock()
+	p.destroyAll(expired)
+
+	select {
+	case h := <-wait:
+		return p.received(h)
+	case <-ctx.Done():
+	}
+
+	p.mu.Lock()
+	for i, waiter := range p.waiters {
+		if waiter == wait {
+			p.waiters = append(p.waiters[:i], p.waiters[i+1:]...)
+			p.mu.Unlock()
+			return zero, ctx.Err()
+		}
+	}
+	p.mu.Unlock()
+
+	// something was handed off as ctx finished, so pass it on
+	h := <-wait
+	if h.object {
+		p.Put(h.value)
+	} else if !h.closed {
+		p.release()
+	}
+	return zero, ctx.Err()
+}
+
+// received returns what was handed off to a waiting Get.
+func (p *Pool[T]) received(h handoff[T]) (T, error) {
+	switch {
+	case h.closed:
+		var zero T
+		return zero, ErrClosed
+	case h.object:
+		return h.value, nil
+	default:
+		return p.newObject()
+	}
+}
+
+// newObject creates an object in a slot already counted as live,
+// freeing the slot if creation fails.
+func (p *Pool[T]) newObject() (T, error) {
+	value, err := p.create()
+	if err != nil {
+		p.release()
+		return value, err
+	}
+
+	p.mu.Lock()
+	p.stats.Created++
+	p.mu.Unlock()
+	return value, nil
+}
+
+// Put returns an object taken from the pool with Get.  It is reset, and
+// then either handed to a waiting Get or kept idle.
+func (p *Pool[T]) Put(value T) {
+	if p.options.reset != nil {
+		if err := p.options.reset(value); err != nil {
+			p.Discard(value)
+			return
+		}
+	}
+
+	p.mu.Lock()
+	if p.closed {
+		p.live--
+		p.stats.Destroyed++
+		p.mu.Unlock()
+		p.destroy(value)
+		return
+	}
+	if len(p.waiters) > 0 {
+		wait := p.popWaiter()
+		p.stats.Hits++
+		p.mu.Unlock()
+		wait <- handoff[T]{value: value, object: true}
+		return
+	}
+
+	p.idle = append(p.idle, idleObject[T]{value: value, since: p.options.now()})
+	var dropped []T
+	if p.options.maxIdle > 0 {
+		for len(p.idle) > p.options.maxIdle {
+			dropped = append(dropped, p.idle[0].value)
+			p.removeOldest()
+		}
+	}
+	p.mu.Unlock()
+	p.destroyAll(dropped)
+}
+
+// Discard destroys an object taken from the pool with Get instead of
+// returning it, for when it is broken, and frees its place for another.
+func (p *Pool[T]) Discard(value T) {
+	p.mu.Lock()
+	p.stats.Destroyed++
+	p.mu.Unlock()
+	p.release()
+	p.destroy(value)
+}
+
+// release frees the place of an object that no longer exists, letting
+// a waiting Get create another in its place.
+func (p *Pool[T]) release() {
+	p.mu.Lock()
+	if len(p.waiters) > 0 && !p.closed {
+		wait := p.popWaiter()
+		p.stats.Misses++
+		p.mu.Unlock()
+		wait <- handoff[T]{}
+		return
+	}
+	p.live--
+	p.mu.Unlock()
+}
+
+func (p *Pool[T]) popWaiter() chan handoff[T] {
+	wait := p.waiters[0]
+	p.waiters[0] = nil
+	p.waiters = p.waiters[1:]
+	return wait
+}
+
+// removeOldest drops the longest idle object, which the caller
+// destroys.
+func (p *Pool[T]) removeOldest() {
+	copy(p.idle, p.idle[1:])
+	p.idle[len(p.idle)-1] = idleObject[T]{}
+	p.idle = p.idle[:len(p.idle)-1]
+	p.live--
+	p.stats.Destroyed++
+}
+
+// expire removes the objects that have been idle too long, returning
+// them for the caller to destroy once the lock is released.
+func (p *Pool[T]) expire() []T {
+	if p.options.idleTimeout <= 0 || len(p.idle) == 0 {
+		return nil
+	}
+	var expired []T
+	now := p.options.now()
+	for len(p.idle) > 0 && now.Sub(p.idle[0].since) >= p.options.idleTimeout {
+		expired = append(expired, p.idle[0].value)
+		p.removeOldest()
+		p.stats.Evicted++
+	}
+	return expired
+}
+
+// Evict destroys the objects that have been idle too long, which the
+// pool otherwise only does as objects are taken from it.
+func (p *Pool[T]) Evict() {
+	p.mu.Lock()
+	expired := p.expire()
+	p.mu.Unlock()
+	p.destroyAll(expired)
+}
+
+func (p *Pool[T]) destroy(value T) {
+	if p.options.destroy != nil {
+		p.options.destroy(value)
+	}
+}
+
+func (p *Pool[T]) destroyAll(values []T) {
+	for _, value := range values {
+		p.destroy(value)
+	}
+}
+
+// Stats returns the pool's counters.
+func (p *Pool[T]) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := p.stats
+	stats.Idle = len(p.idle)
+	stats.InUse = p.live - len(p.idle)
+	return stats
+}
+
+// Close destroys the idle objects and fails any waiting or later Gets.
+// Objects still in use are destroyed as they are returned.
+func (p *Pool[T]) Close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	var dropped []T
+	for len(p.idle) > 0 {
+		dropped = append(dropped, p.idle[0].value)
+		p.removeOldest()
+	}
+	waiters := p.waiters
+	p.waiters = nil
+	p.mu.Unlock()
+
+	for _, wait := range waiters {
+		wait <- handoff[T]{closed: true}
+	}
+	p.destroyAll(dropped)
+}
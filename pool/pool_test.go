@@ -0,0 +1,276 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resource is an object that records what the pool did with it.
+type resource struct {
+	id        int
+	resets    int
+	destroyed bool
+	broken    bool
+}
+
+type factory struct {
+	mu      sync.Mutex
+	created int
+	err     error
+}
+
+func (f *factory) create() (*resource, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.created++
+	return &resource{id: f.created}, nil
+}
+
+func reset(r *resource) error {
+	if r.broken {
+		return errors.New(`broken`)
+	}
+	r.resets++
+	return nil
+}
+
+func destroy(r *resource) {
+	r.destroyed = true
+}
+
+func newPool(f *factory, opts ...Option[*resource]) *Pool[*resource] {
+	opts = append([]Option[*resource]{WithReset(reset), WithDestroy(destroy)}, opts...)
+	return New(f.create, opts...)
+}
+
+func TestGetPut(t *testing.T) {
+	p := newPool(&factory{})
+	ctx := context.Background()
+
+	a, err := p.Get(ctx)
+	require.NoError(t, err)
+	b, err := p.Get(ctx)
+	require.NoError(t, err)
+	assert.NotEqual(t, a.id, b.id)
+
+	p.Put(a)
+	p.Put(b)
+	assert.Equal(t, 1, a.resets)
+
+	// the most recently returned object is reused first
+	c, err := p.Get(ctx)
+	require.NoError(t, err)
+	assert.Same(t, b, c)
+
+	assert.Equal(t, Stats{Hits: 1, Misses: 2, Created: 2, InUse: 1, Idle: 1}, p.Stats())
+}
+
+func TestResetFailure(t *testing.T) {
+	p := newPool(&factory{})
+	a, err := p.Get(context.Background())
+	require.NoError(t, err)
+
+	a.broken = true
+	p.Put(a)
+	assert.True(t, a.destroyed)
+	stats := p.Stats()
+	assert.Equal(t, 0, stats.Idle)
+	assert.Equal(t, 0, stats.InUse)
+	assert.Equal(t, uint64(1), stats.Destroyed)
+}
+
+func TestCreateFailure(t *testing.T) {
+	f := &factory{err: errors.New(`unavailable`)}
+	p := newPool(f, WithMaxSize[*resource](1))
+	_, err := p.Get(context.Background())
+	assert.Equal(t, f.err, err)
+
+	// the failed creation does not use up the only place
+	f.err = nil
+	a, err := p.Get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, a.id)
+}
+
+func TestMaxIdle(t *testing.T) {
+	p := newPool(&factory{}, WithMaxIdle[*resource](2))
+	ctx := context.Background()
+	var taken []*resource
+	for range 4 {
+		r, err := p.Get(ctx)
+		require.NoError(t, err)
+		taken = append(taken, r)
+	}
+	for _, r := range taken {
+		p.Put(r)
+	}
+
+	// the longest idle are destroyed
+	assert.True(t, taken[0].destroyed)
+	assert.True(t, taken[1].destroyed)
+	assert.False(t, taken[2].destroyed)
+	assert.False(t, taken[3].destroyed)
+	assert.Equal(t, 2, p.Stats().Idle)
+}
+
+func TestIdleTimeout(t *testing.T) {
+	now := time.Unix(0, 0)
+	p := newPool(&factory{},
+		WithIdleTimeout[*resource](time.Minute),
+		WithClock[*resource](func() time.Time { return now }),
+	)
+	ctx := context.Background()
+	a, _ := p.Get(ctx)
+	b, _ := p.Get(ctx)
+	p.Put(a)
+	now = now.Add(30 * time.Second)
+	p.Put(b)
+
+	now = now.Add(30 * time.Second)
+	p.Evict()
+	assert.True(t, a.destroyed)
+	assert.False(t, b.destroyed)
+
+	// Get evicts too
+	now = now.Add(30 * time.Second)
+	c, err := p.Get(ctx)
+	require.NoError(t, err)
+	assert.True(t, b.destroyed)
+	assert.Equal(t, 3, c.id)
+
+	stats := p.Stats()
+	assert.Equal(t, uint64(2), stats.Evicted)
+	assert.Equal(t, uint64(2), stats.Destroyed)
+}
+
+func TestMaxSize(t *testing.T) {
+	p := newPool(&factory{}, WithMaxSize[*resource](1))
+	a, err := p.Get(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = p.Get(ctx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	got := make(chan *resource)
+	go func() {
+		r, err := p.Get(context.Background())
+		assert.NoError(t, err)
+		got <- r
+	}()
+	for p.Stats().Waits < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	p.Put(a)
+	assert.Same(t, a, <-got)
+
+	// discarding frees the place for a new object
+	go func() {
+		r, err := p.Get(context.Background())
+		assert.NoError(t, err)
+		got <- r
+	}()
+	for p.Stats().Waits < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	p.Discard(a)
+	b := <-got
+	assert.Equal(t, 2, b.id)
+	assert.True(t, a.destroyed)
+	assert.Equal(t, 1, p.Stats().InUse)
+}
+
+func TestClose(t *testing.T) {
+	p := newPool(&factory{}, WithMaxSize[*resource](2))
+	ctx := context.Background()
+	a, _ := p.Get(ctx)
+	b, _ := p.Get(ctx)
+	p.Put(a)
+
+	p.Close()
+	assert.True(t, a.destroyed)
+	_, err := p.Get(ctx)
+	assert.Equal(t, ErrClosed, err)
+
+	p.Put(b)
+	assert.True(t, b.destroyed)
+	assert.Equal(t, Stats{Misses: 2, Created: 2, Destroyed: 2}, p.Stats())
+	p.Close()
+}
+
+func TestCloseWakesWaiters(t *testing.T) {
+	p := newPool(&factory{}, WithMaxSize[*resource](1))
+	_, err := p.Get(context.Background())
+	require.NoError(t, err)
+
+	done := make(chan error)
+	go func() {
+		_, err := p.Get(context.Background())
+		done <- err
+	}()
+	for p.Stats().Waits < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	p.Close()
+	assert.Equal(t, ErrClosed, <-done)
+}
+
+func TestConcurrentUse(t *testing.T) {
+	f := &factory{}
+	p := newPool(f, WithMaxSize[*resource](4), WithMaxIdle[*resource](2))
+	var inUse sync.Map
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 200 {
+				r, err := p.Get(context.Background())
+				if !assert.NoError(t, err) {
+					return
+				}
+				_, loaded := inUse.LoadOrStore(r, true)
+				assert.False(t, loaded, `object handed out twice`)
+				inUse.Delete(r)
+				if i%10 == 0 {
+					p.Discard(r)
+				} else {
+					p.Put(r)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := p.Stats()
+	assert.Equal(t, 0, stats.InUse)
+	assert.LessOrEqual(t, stats.Idle, 2)
+	assert.Equal(t, stats.Created, stats.Destroyed+uint64(stats.Idle))
+	assert.Equal(t, uint64(1600), stats.Hits+stats.Misses)
+}
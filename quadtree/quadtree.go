@@ -0,0 +1,350 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package quadtree implements a quadtree, which partitions a region of
+the plane into four quadrants, and each quadrant in turn, to find the
+items near a point or within a region without looking at the rest.
+It suits collision detection and map viewports, where an R-tree is more
+than is needed.
+
+Items have rectangular bounds, and each is kept in the smallest node
+whose region contains it.  A node splits into quadrants once it holds
+more than the bucket size of items, up to the maximum depth, and
+quadrants merge back into their parent once few enough items are left
+in them.
+
+In a plain quadtree, a small item that straddles the line between two
+quadrants stays in their parent however small it is.  In a loose
+quadtree, each node's region is enlarged by a looseness factor, usually
+2, so that its quadrants overlap, and such an item fits in a quadrant
+after all.  Searches then visit more nodes, but each holds fewer items
+that do not match.
+
+A Tree is not threadsafe.
+*/
+package quadtree
+
+// Rect is an axis-aligned rectangle, including its edges.
+type Rect struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+// Point returns a Rect holding only the point.
+func Point(x, y float64) Rect {
+	return Rect{MinX: x, MinY: y, MaxX: x, MaxY: y}
+}
+
+// Contains reports whether other lies within the rectangle.
+func (r Rect) Contains(other Rect) bool {
+	return r.MinX <= other.MinX && other.MaxX <= r.MaxX &&
+		r.MinY <= other.MinY && other.MaxY <= r.MaxY
+}
+
+// Intersects reports whether the rectangles overlap, or touch.
+func (r Rect) Intersects(other Rect) bool {
+	return r.MinX <= other.MaxX && other.MinX <= r.MaxX &&
+		r.MinY <= other.MaxY && other.MinY <= r.MaxY
+}
+
+func (r Rect) center() (float64, float64) {
+	return (r.MinX + r.MaxX) / 2, (r.MinY + r.MaxY) / 2
+}
+
+// options holds the settings that may be given when creating a tree.
+type options struct {
+	maxDepth   int
+	bucketSize int
+	looseness  float64
+}
+
+// Option configures a tree when it is created.
+type Option func(*options)
+
+// WithMaxDepth sets how many levels of quadrants there may be below the
+// root.  Defaults to 8.
+func WithMaxDepth(depth int) Option {
+	if depth < 0 {
+		panic(`quadtree: max depth must not be negative`)
+	}
+	return func(o *options) {
+		o.maxDepth = depth
+	}
+}
+
+// WithBucketSize sets how many items a node holds before it splits.
+// Defaults to 8.
+func WithBucketSize(size int) Option {
+	if size < 1 {
+		panic(`quadtree: bucket size must be at least 1`)
+	}
+	return func(o *options) {
+		o.bucketSize = size
+	}
+}
+
+// WithLooseness enlarges each node's region by the factor, making a
+// loose quadtree.  Defaults to 1, a plain quadtree.
+func WithLooseness(factor float64) Option {
+	if !(factor >= 1) {
+		panic(`quadtree: looseness must be at least 1`)
+	}
+	return func(o *options) {
+		o.looseness = factor
+	}
+}
+
+type entry[T comparable] struct {
+	item   T
+	bounds Rect
+	node   *node[T]
+	// index is the entry's place in its node's entries.
+	index int
+}
+
+type node[T comparable] struct {
+	// region is the node's quadrant, and loose is the region items in
+	// it must lie within, which is larger in a loose quadtree.
+	region, loose Rect
+	entries       []*entry[T]
+	children      *[4]node[T]
+	parent        *node[T]
+	depth         int
+	// count is the number of entries in the node and below it.
+	count int
+}
+
+// Tree is a quadtree of items of type T, each of which may be in the
+// tree at most once.
+type Tree[T comparable] struct {
+	root    node[T]
+	entries map[T]*entry[T]
+	options options
+}
+
+// New returns an empty tree covering the bounds.  Items outside of the
+// bounds may still be added, but they are all kept at the root.
+func New[T comparable](bounds Rect, opts ...Option) *Tree[T] {
+	o := options{maxDepth: 8, bucketSize: 8, looseness: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	t := &Tree[T]{entries: make(map[T]*entry[T]), options: o}
+	t.root.region, t.root.loose = bounds, bounds
+	return t
+}
+
+// Len returns the number of items in the tree.
+func (t *Tree[T]) Len() int {
+	return len(t.entries)
+}
+
+// Bounds returns the bounds of the item, and whether it is in the tree.
+func (t *Tree[T]) Bounds(item T) (Rect, bool) {
+	e, ok := t.entries[item]
+	if !ok {
+		return Rect{}, false
+	}
+	return e.bounds, true
+}
+
+// Insert adds the item with the bounds, or moves it there if it is
+// already in the tree.
+func (t *Tree[T]) Insert(item T, bounds Rect) {
+	if t.Move(item, bounds) {
+		return
+	}
+	e := &entry[T]{item: item, bounds: bounds}
+	t.entries[item] = e
+	t.insert(&t.root, e)
+}
+
+func (t *Tree[T]) insert(n *node[T], e *entry[T]) {
+	for {
+		n.count++
+		if n.children == nil {
+			break
+		}
+		child := n.child(e.bounds)
+		if child == nil {
+			break
+		}
+		n = child
+	}
+	t.add(n, e)
+	if n.children == nil && len(n.entries) > t.options.bucketSize && n.depth < t.options.maxDepth {
+		t.split(n)
+	}
+}
+
+// child returns the quadrant the bounds fit in, or nil if none.
+func (n *node[T]) child(bounds Rect) *node[T] {
+	x, y := n.region.center()
+	cx, cy := bounds.center()
+	i := 0
+	if cx >= x {
+		i |= 1
+	}
+	if cy >= y {
+		i |= 2
+	}
+	if child := &n.children[i]; child.loose.Contains(bounds) {
+		return child
+	}
+	return nil
+}
+
+func (t *Tree[T]) add(n *node[T], e *entry[T]) {
+	e.node, e.index = n, len(n.entries)
+	n.entries = append(n.entries, e)
+}
+
+func (t *Tree[T]) unlink(e *entry[T]) {
+	n := e.node
+	last := n.entries[len(n.entries)-1]
+	n.entries[e.index], last.index = last, e.index
+	n.entries[len(n.entries)-1] = nil
+	n.entries = n.entries[:len(n.entries)-1]
+	e.node = nil
+}
+
+// split divides the node into quadrants, moving down the entries that
+// fit in one.
+func (t *Tree[T]) split(n *node[T]) {
+	n.children = new([4]node[T])
+	x, y := n.region.center()
+	for i := range n.children {
+		child := &n.children[i]
+		child.parent, child.depth = n, n.depth+1
+		child.region = n.region
+		if i&1 == 0 {
+			child.region.MaxX = x
+		} else {
+			child.region.MinX = x
+		}
+		if i&2 == 0 {
+			child.region.MaxY = y
+		} else {
+			child.region.MinY = y
+		}
+		child.loose = loosen(child.region, t.options.looseness)
+	}
+
+	entries := n.entries
+	n.entries = nil
+	for _, e := range entries {
+		if child := n.child(e.bounds); child != nil {
+			// the child may split in turn if every entry lands in it.
+			t.insert(child, e)
+		} else {
+			t.add(n, e)
+		}
+	}
+}
+
+func loosen(r Rect, factor float64) Rect {
+	if factor == 1 {
+		return r
+	}
+	dx, dy := (r.MaxX-r.MinX)*(factor-1)/2, (r.MaxY-r.MinY)*(factor-1)/2
+	return Rect{MinX: r.MinX - dx, MinY: r.MinY - dy, MaxX: r.MaxX + dx, MaxY: r.MaxY + dy}
+}
+
+// Remove removes the item from the tree, reporting whether it was
+// there.
+func (t *Tree[T]) Remove(item T) bool {
+	e, ok := t.entries[item]
+	if !ok {
+		return false
+	}
+	delete(t.entries, item)
+	t.remove(e)
+	return true
+}
+
+func (t *Tree[T]) remove(e *entry[T]) {
+	n := e.node
+	t.unlink(e)
+	// merge the highest node left with few enough entries below it.
+	var merge *node[T]
+	for ; n != nil; n = n.parent {
+		n.count--
+		if n.children != nil && n.count <= t.options.bucketSize {
+			merge = n
+		}
+	}
+	if merge != nil {
+		t.merge(merge, merge)
+	}
+}
+
+// merge moves every entry below the node up into into, and removes the
+// quadrants below it.
+func (t *Tree[T]) merge(n, into *node[T]) {
+	if n.children == nil {
+		return
+	}
+	for i := range n.children {
+		child := &n.children[i]
+		for _, e := range child.entries {
+			t.add(into, e)
+		}
+		t.merge(child, into)
+	}
+	n.children = nil
+}
+
+// Move changes the bounds of the item, reporting whether it is in the
+// tree.  An item that stays within its node is updated in place.
+func (t *Tree[T]) Move(item T, bounds Rect) bool {
+	e, ok := t.entries[item]
+	if !ok {
+		return false
+	}
+	n := e.node
+	fits := n == &t.root || n.loose.Contains(bounds)
+	if fits && (n.children == nil || n.child(bounds) == nil) {
+		e.bounds = bounds
+		return true
+	}
+	t.remove(e)
+	e.bounds = bounds
+	t.insert(&t.root, e)
+	return true
+}
+
+// Search returns the items whose bounds intersect the region.
+func (t *Tree[T]) Search(region Rect) []T {
+	var found []T
+	t.search(&t.root, region, &found)
+	return found
+}
+
+func (t *Tree[T]) search(n *node[T], region Rect, found *[]T) {
+	for _, e := range n.entries {
+		if e.bounds.Intersects(region) {
+			*found = append(*found, e.item)
+		}
+	}
+	if n.children == nil {
+		return
+	}
+	for i := range n.children {
+		if child := &n.children[i]; child.count > 0 && child.loose.Intersects(region) {
+			t.search(child, region, found)
+		}
+	}
+}
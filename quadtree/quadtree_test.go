@@ -0,0 +1,182 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quadtree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// check verifies that every entry lies within its node, and that the
+// counts and indexes are right.
+func check[T comparable](t *testing.T, tree *Tree[T]) {
+	var walk func(n *node[T]) int
+	walk = func(n *node[T]) int {
+		count := len(n.entries)
+		for i, e := range n.entries {
+			require.Same(t, n, e.node)
+			require.Equal(t, i, e.index)
+			require.Same(t, e, tree.entries[e.item])
+			if n != &tree.root {
+				require.True(t, n.loose.Contains(e.bounds))
+			}
+		}
+		if n.children != nil {
+			require.Greater(t, n.count, tree.options.bucketSize)
+			for i := range n.children {
+				count += walk(&n.children[i])
+			}
+		}
+		require.Equal(t, count, n.count)
+		return count
+	}
+	require.Equal(t, tree.Len(), walk(&tree.root))
+}
+
+func TestTree(t *testing.T) {
+	tree := New[string](Rect{0, 0, 100, 100}, WithBucketSize(1))
+	tree.Insert("a", Point(10, 10))
+	tree.Insert("b", Point(90, 90))
+	tree.Insert("c", Rect{40, 40, 60, 60})
+	tree.Insert("outside", Point(200, 200))
+	check(t, tree)
+	assert.Equal(t, 4, tree.Len())
+	require.NotNil(t, tree.root.children)
+
+	found := tree.Search(Rect{0, 0, 50, 50})
+	sort.Strings(found)
+	assert.Equal(t, []string{"a", "c"}, found)
+	assert.Equal(t, []string{"outside"}, tree.Search(Rect{150, 150, 250, 250}))
+	assert.Empty(t, tree.Search(Rect{20, 20, 30, 30}))
+
+	assert.True(t, tree.Move("a", Point(95, 95)))
+	bounds, ok := tree.Bounds("a")
+	assert.True(t, ok)
+	assert.Equal(t, Point(95, 95), bounds)
+	found = tree.Search(Rect{80, 80, 100, 100})
+	sort.Strings(found)
+	assert.Equal(t, []string{"a", "b"}, found)
+	assert.False(t, tree.Move("z", Point(0, 0)))
+
+	assert.True(t, tree.Remove("b"))
+	assert.False(t, tree.Remove("b"))
+	assert.True(t, tree.Remove("c"))
+	check(t, tree)
+	assert.NotNil(t, tree.root.children)
+	assert.True(t, tree.Remove("outside"))
+	check(t, tree)
+	assert.Nil(t, tree.root.children)
+	assert.Equal(t, []string{"a"}, tree.Search(Rect{0, 0, 100, 100}))
+}
+
+func TestMaxDepth(t *testing.T) {
+	tree := New[int](Rect{0, 0, 1, 1}, WithBucketSize(1), WithMaxDepth(3))
+	for i := 0; i < 10; i++ {
+		tree.Insert(i, Point(0.01, 0.01))
+	}
+	check(t, tree)
+	n := &tree.root
+	for n.children != nil {
+		n = &n.children[0]
+	}
+	assert.Equal(t, 3, n.depth)
+	assert.Len(t, n.entries, 10)
+}
+
+func TestLooseness(t *testing.T) {
+	// a small item on the centre line stays at the root of a plain
+	// quadtree, but fits in a quadrant of a loose one.
+	item := Rect{49, 10, 51, 12}
+	plain := New[int](Rect{0, 0, 100, 100}, WithBucketSize(1))
+	loose := New[int](Rect{0, 0, 100, 100}, WithBucketSize(1), WithLooseness(2))
+	for _, tree := range []*Tree[int]{plain, loose} {
+		tree.Insert(1, Point(10, 10))
+		tree.Insert(2, Point(90, 90))
+		tree.Insert(3, item)
+		check(t, tree)
+	}
+	assert.Same(t, &plain.root, plain.entries[3].node)
+	assert.NotSame(t, &loose.root, loose.entries[3].node)
+}
+
+func TestRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(41))
+	randomRect := func() Rect {
+		x, y := rng.Float64()*120-10, rng.Float64()*120-10
+		w, h := rng.Float64()*rng.Float64()*30, rng.Float64()*rng.Float64()*30
+		return Rect{x, y, x + w, y + h}
+	}
+	for _, looseness := range []float64{1, 2} {
+		tree := New[int](Rect{0, 0, 100, 100}, WithBucketSize(4), WithMaxDepth(5), WithLooseness(looseness))
+		expected := make(map[int]Rect)
+		for op := 0; op < 4000; op++ {
+			item := rng.Intn(300)
+			switch rng.Intn(4) {
+			case 0:
+				_, ok := expected[item]
+				assert.Equal(t, ok, tree.Remove(item))
+				delete(expected, item)
+			case 1:
+				// small moves, as of a moving object.
+				if bounds, ok := expected[item]; ok {
+					dx, dy := rng.Float64()*4-2, rng.Float64()*4-2
+					bounds = Rect{bounds.MinX + dx, bounds.MinY + dy, bounds.MaxX + dx, bounds.MaxY + dy}
+					assert.True(t, tree.Move(item, bounds))
+					expected[item] = bounds
+				}
+			default:
+				bounds := randomRect()
+				tree.Insert(item, bounds)
+				expected[item] = bounds
+			}
+
+			if op%100 == 0 {
+				check(t, tree)
+				region := randomRect()
+				var want []int
+				for item, bounds := range expected {
+					if bounds.Intersects(region) {
+						want = append(want, item)
+					}
+				}
+				got := tree.Search(region)
+				sort.Ints(want)
+				sort.Ints(got)
+				assert.Equal(t, want, got)
+			}
+		}
+		assert.Equal(t, len(expected), tree.Len())
+	}
+}
+
+func BenchmarkSearch(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	tree := New[int](Rect{0, 0, 1000, 1000}, WithLooseness(2))
+	for i := 0; i < 1<<14; i++ {
+		x, y := rng.Float64()*1000, rng.Float64()*1000
+		tree.Insert(i, Rect{x, y, x + 5, y + 5})
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x, y := rng.Float64()*1000, rng.Float64()*1000
+		tree.Search(Rect{x, y, x + 20, y + 20})
+	}
+}
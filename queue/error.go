@@ -16,15 +16,19 @@ limitations under the License.
 
 package queue
 
-import "errors"
+import "github.com/Workiva/go-datastructures/common"
 
+// These are aliases of the errors shared through common, so errors.Is
+// matches them against the same errors from other packages.  Timeouts
+// are returned wrapped in a *common.OpError naming the call, so test
+// for them with errors.Is.
 var (
 	// ErrDisposed is returned when an operation is attempted on a disposed queue.
-	ErrDisposed = errors.New("queue: disposed")
+	ErrDisposed = common.ErrDisposed
 
 	// ErrTimeout is returned when a poll operation times out.
-	ErrTimeout = errors.New("queue: poll timed out")
+	ErrTimeout = common.ErrTimeout
 
 	// ErrEmptyQueue is returned when an operation requires items but the queue is empty.
-	ErrEmptyQueue = errors.New("queue: empty")
+	ErrEmptyQueue = common.ErrEmpty
 )
@@ -0,0 +1,128 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Workiva/go-datastructures/internal/check"
+)
+
+// checkPriorityQueue returns an error if the queue's items break the
+// heap property.
+func checkPriorityQueue[T any](pq *PriorityQueue[T]) error {
+	return check.Heap(pq.items, pq.arity, pq.compare)
+}
+
+// checkMinMaxHeap returns an error if any item sorts before an
+// ancestor on a min level or after one on a max level.  Checking each
+// item against its parent and grandparent covers every ancestor.
+func checkMinMaxHeap[T any](h *MinMaxHeap[T]) error {
+	for i := 1; i < len(h.items); i++ {
+		parent := (i - 1) / 2
+		ancestors := []int{parent}
+		if parent > 0 {
+			ancestors = append(ancestors, (parent-1)/2)
+		}
+		for _, ancestor := range ancestors {
+			c := h.compare(h.items[i], h.items[ancestor])
+			if isMinLevel(ancestor) && c < 0 || !isMinLevel(ancestor) && c > 0 {
+				return check.Errorf(`item %d (%v) is on the wrong side of its ancestor %d (%v)`,
+					i, h.items[i], ancestor, h.items[ancestor])
+			}
+		}
+	}
+	return nil
+}
+
+func TestInvariants(t *testing.T) {
+	pq := NewPriorityQueueWithArity(cmp.Compare[int], 3, 0, true)
+	h := NewMinMaxHeapWithCompareFunc(cmp.Compare[int], 0)
+	for i := range 1000 {
+		pq.Put((i * 7919) % 1000)
+		h.Push((i * 7919) % 1000)
+	}
+	require.NoError(t, checkPriorityQueue(pq))
+	require.NoError(t, checkMinMaxHeap(h))
+
+	pq.items[0], pq.items[1] = pq.items[1], pq.items[0]
+	assert.Error(t, checkPriorityQueue(pq))
+	h.items[0], h.items[1] = h.items[1], h.items[0]
+	assert.Error(t, checkMinMaxHeap(h))
+}
+
+func FuzzPriorityQueue(f *testing.F) {
+	f.Add(uint8(2), []byte{0, 5, 0, 3, 0, 9, 1, 0, 0, 1, 1, 0})
+	f.Fuzz(func(t *testing.T, arity uint8, data []byte) {
+		pq := NewPriorityQueueWithArity(cmp.Compare[int], int(arity%8)+2, 0, true)
+		var model []int
+		for op := range check.Ops(data, 2) {
+			switch op.Kind {
+			case 0:
+				require.NoError(t, pq.Put(op.Key))
+				model = append(model, op.Key)
+				slices.Sort(model)
+			case 1:
+				if len(model) == 0 {
+					continue
+				}
+				items, err := pq.Get(op.Key%4 + 1)
+				require.NoError(t, err)
+				n := min(op.Key%4+1, len(model))
+				assert.Equal(t, model[:n], items)
+				model = model[n:]
+			}
+			require.NoError(t, checkPriorityQueue(pq))
+		}
+		assert.Equal(t, len(model), pq.Len())
+	})
+}
+
+func FuzzMinMaxHeap(f *testing.F) {
+	f.Add([]byte{0, 5, 0, 3, 0, 9, 1, 0, 0, 1, 2, 0, 0, 7})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		h := NewMinMaxHeapWithCompareFunc(cmp.Compare[int], 0)
+		var model []int
+		for op := range check.Ops(data, 3) {
+			switch op.Kind {
+			case 0:
+				h.Push(op.Key)
+				model = append(model, op.Key)
+				slices.Sort(model)
+			case 1:
+				item, ok := h.PopMin()
+				if assert.Equal(t, len(model) > 0, ok) && ok {
+					assert.Equal(t, model[0], item)
+					model = model[1:]
+				}
+			case 2:
+				item, ok := h.PopMax()
+				if assert.Equal(t, len(model) > 0, ok) && ok {
+					assert.Equal(t, model[len(model)-1], item)
+					model = model[:len(model)-1]
+				}
+			}
+			require.NoError(t, checkMinMaxHeap(h))
+		}
+		assert.Equal(t, len(model), h.Len())
+	})
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+var errUnmarshalUninitialized = errors.New(`queue: cannot unmarshal JSON into a PriorityQueue not created with NewPriorityQueue`)
+
+// MarshalJSON encodes the queue as a JSON array of its items, from the
+// front of the queue to the back.
+func (q *Queue[T]) MarshalJSON() ([]byte, error) {
+	q.lock.Lock()
+	items := slices.Clone(q.items)
+	q.lock.Unlock()
+
+	return common.MarshalJSONArray(slices.Values(items), common.DefaultJSONCodec[T]())
+}
+
+// UnmarshalJSON decodes a JSON array, putting its items on the queue in
+// order.  Returns ErrDisposed if the queue has been disposed.
+func (q *Queue[T]) UnmarshalJSON(data []byte) error {
+	items, err := unmarshalItems[T](data)
+	if err != nil || len(items) == 0 {
+		return err
+	}
+	return q.Put(items...)
+}
+
+// MarshalJSON encodes the queue as a JSON array of its items, in the
+// order they would be retrieved.
+func (pq *PriorityQueue[T]) MarshalJSON() ([]byte, error) {
+	pq.lock.Lock()
+	items := slices.Clone(pq.items)
+	pq.lock.Unlock()
+
+	if pq.compare != nil {
+		slices.SortStableFunc(items, pq.compare)
+	}
+	return common.MarshalJSONArray(slices.Values(items), common.DefaultJSONCodec[T]())
+}
+
+// UnmarshalJSON decodes a JSON array, putting its items on the queue,
+// which must have been created with one of the NewPriorityQueue
+// functions so that it knows how to order them.  Returns ErrDisposed
+// if the queue has been disposed.
+func (pq *PriorityQueue[T]) UnmarshalJSON(data []byte) error {
+	if pq.compare == nil {
+		return errUnmarshalUninitialized
+	}
+	items, err := unmarshalItems[T](data)
+	if err != nil || len(items) == 0 {
+		return err
+	}
+	return pq.Put(items...)
+}
+
+func unmarshalItems[T any](data []byte) ([]T, error) {
+	var items []T
+	err := common.UnmarshalJSONArray(data, common.DefaultJSONCodec[T](), func(item T) {
+		items = append(items, item)
+	})
+	return items, err
+}
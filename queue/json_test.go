@@ -0,0 +1,62 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"cmp"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueueJSON(t *testing.T) {
+	q := New[string](10)
+	q.Put(`a`, `b`, `c`)
+	b, err := json.Marshal(q)
+	require.NoError(t, err)
+	assert.Equal(t, `["a","b","c"]`, string(b))
+	assert.Equal(t, int64(3), q.Len())
+
+	decoded := New[string](0)
+	decoded.Put(`z`)
+	require.NoError(t, json.Unmarshal(b, decoded))
+	items, err := decoded.Get(10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{`z`, `a`, `b`, `c`}, items)
+
+	decoded.Dispose()
+	assert.Equal(t, ErrDisposed, json.Unmarshal(b, decoded))
+}
+
+func TestPriorityQueueJSON(t *testing.T) {
+	pq := NewPriorityQueueWithCompareFunc(cmp.Compare[int], 10, true)
+	pq.Put(5, 1, 4, 2, 3)
+	b, err := json.Marshal(pq)
+	require.NoError(t, err)
+	assert.Equal(t, `[1,2,3,4,5]`, string(b))
+	assert.Equal(t, 5, pq.Len())
+
+	decoded := NewPriorityQueueWithCompareFunc(cmp.Compare[int], 0, true)
+	require.NoError(t, json.Unmarshal([]byte(`[9,0,7]`), decoded))
+	items, err := decoded.Get(3)
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 7, 9}, items)
+
+	assert.Error(t, json.Unmarshal(b, &PriorityQueue[int]{}))
+}
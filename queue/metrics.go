@@ -0,0 +1,109 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"time"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+// Option configures a Queue, PriorityQueue or RingBuffer at construction.
+type Option func(*options)
+
+type options struct {
+	metrics common.Metrics
+}
+
+// WithMetrics reports operational measurements to m.  A Queue reports
+// the following, and a PriorityQueue and RingBuffer report the same
+// measurements prefixed with "priority_queue." and "ring_buffer."
+// instead of "queue.":
+//
+//	queue.depth  gauge, the number of items held after each put or get
+//	queue.puts   counter, items added
+//	queue.gets   counter, items removed
+//	queue.waits  counter, operations that had to block
+//	queue.wait   timer, how long each blocked operation waited
+//
+// Only gets block on a Queue or PriorityQueue.  A RingBuffer counts
+// any put or get that had to yield, whether for room, for an item or
+// for a slot another goroutine was claiming.
+func WithMetrics(m common.Metrics) Option {
+	return func(o *options) {
+		o.metrics = m
+	}
+}
+
+// observer reports measurements under a fixed prefix and does nothing
+// when no Metrics was configured.
+type observer struct {
+	metrics                      common.Metrics
+	depth, puts, gets, waits, wt string
+}
+
+func newObserver(prefix string, opts []Option) observer {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.metrics == nil {
+		return observer{}
+	}
+	return observer{
+		metrics: o.metrics,
+		depth:   prefix + ".depth",
+		puts:    prefix + ".puts",
+		gets:    prefix + ".gets",
+		waits:   prefix + ".waits",
+		wt:      prefix + ".wait",
+	}
+}
+
+func (o observer) put(n, depth int) {
+	if o.metrics == nil {
+		return
+	}
+	o.metrics.Count(o.puts, int64(n))
+	o.metrics.Gauge(o.depth, float64(depth))
+}
+
+func (o observer) get(n, depth int) {
+	if o.metrics == nil || n == 0 {
+		return
+	}
+	o.metrics.Count(o.gets, int64(n))
+	o.metrics.Gauge(o.depth, float64(depth))
+}
+
+// waited records an operation that blocked from start until now.
+func (o observer) waited(start time.Time) {
+	if o.metrics == nil {
+		return
+	}
+	o.metrics.Count(o.waits, 1)
+	o.metrics.Time(o.wt, time.Since(start))
+}
+
+// waitStart returns the time a blocking operation began, or the zero
+// time when nothing is observing.
+func (o observer) waitStart() time.Time {
+	if o.metrics == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
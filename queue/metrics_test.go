@@ -0,0 +1,176 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+// recorder is a Metrics that remembers what it was told.
+type recorder struct {
+	mu     sync.Mutex
+	counts map[string]int64
+	gauges map[string]float64
+	timers map[string]int
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		counts: map[string]int64{},
+		gauges: map[string]float64{},
+		timers: map[string]int{},
+	}
+}
+
+func (r *recorder) metrics() common.Metrics {
+	return common.MetricsFuncs{
+		OnCount: func(name string, delta int64) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.counts[name] += delta
+		},
+		OnGauge: func(name string, value float64) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.gauges[name] = value
+		},
+		OnTime: func(name string, d time.Duration) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.timers[name]++
+		},
+	}
+}
+
+func (r *recorder) count(name string) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.counts[name]
+}
+
+func (r *recorder) gauge(name string) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.gauges[name]
+}
+
+func (r *recorder) timed(name string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.timers[name]
+}
+
+func TestQueueMetrics(t *testing.T) {
+	r := newRecorder()
+	q := New[int](10, WithMetrics(r.metrics()))
+
+	require.NoError(t, q.Put(1, 2, 3))
+	assert.Equal(t, int64(3), r.count("queue.puts"))
+	assert.Equal(t, float64(3), r.gauge("queue.depth"))
+
+	_, err := q.Get(2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), r.count("queue.gets"))
+	assert.Equal(t, float64(1), r.gauge("queue.depth"))
+
+	_, err = q.TakeUntil(func(int) bool { return true })
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), r.count("queue.gets"))
+	assert.Equal(t, float64(0), r.gauge("queue.depth"))
+	assert.Zero(t, r.count("queue.waits"))
+
+	_, err = q.Poll(1, time.Millisecond)
+	assert.ErrorIs(t, err, ErrTimeout)
+	assert.Equal(t, int64(1), r.count("queue.waits"))
+	assert.Equal(t, 1, r.timed("queue.wait"))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		items, err := q.Get(1)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{4}, items)
+	}()
+	for {
+		q.lock.Lock()
+		waiting := len(q.waiters) > 0
+		q.lock.Unlock()
+		if waiting {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	require.NoError(t, q.Put(4))
+	<-done
+
+	assert.Equal(t, int64(2), r.count("queue.waits"))
+	assert.Equal(t, 2, r.timed("queue.wait"))
+	assert.Equal(t, int64(4), r.count("queue.gets"))
+}
+
+func TestPriorityQueueMetrics(t *testing.T) {
+	r := newRecorder()
+	pq := NewPriorityQueue[testPriorityItem](10, true, WithMetrics(r.metrics()))
+
+	require.NoError(t, pq.Put(
+		testPriorityItem{priority: 3},
+		testPriorityItem{priority: 1},
+		testPriorityItem{priority: 2},
+	))
+	assert.Equal(t, int64(3), r.count("priority_queue.puts"))
+	assert.Equal(t, float64(3), r.gauge("priority_queue.depth"))
+
+	_, err := pq.Get(1)
+	require.NoError(t, err)
+	for range pq.Drain() {
+	}
+	assert.Equal(t, int64(3), r.count("priority_queue.gets"))
+	assert.Equal(t, float64(0), r.gauge("priority_queue.depth"))
+	assert.Zero(t, r.count("priority_queue.waits"))
+}
+
+func TestRingBufferMetrics(t *testing.T) {
+	r := newRecorder()
+	rb := NewRingBuffer[int](2, WithMetrics(r.metrics()))
+
+	require.NoError(t, rb.Put(1))
+	require.NoError(t, rb.Put(2))
+	ok, err := rb.Offer(3)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, int64(2), r.count("ring_buffer.puts"))
+	assert.Equal(t, float64(2), r.gauge("ring_buffer.depth"))
+
+	_, err = rb.Get()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), r.count("ring_buffer.gets"))
+	assert.Equal(t, float64(1), r.gauge("ring_buffer.depth"))
+
+	_, err = rb.Get()
+	require.NoError(t, err)
+	_, err = rb.Poll(time.Millisecond)
+	assert.ErrorIs(t, err, ErrTimeout)
+	assert.Equal(t, int64(1), r.count("ring_buffer.waits"))
+	assert.Equal(t, 1, r.timed("ring_buffer.wait"))
+}
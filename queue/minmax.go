@@ -0,0 +1,212 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"math/bits"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+// MinMaxHeap is a double-ended priority queue: both the lowest and the
+// highest item can be read or removed in O(log n) time.  This suits a
+// bounded "keep the best N" window, which must evict its worst item as
+// better ones arrive while still handing out its best.
+//
+// It is a min-max heap after Atkinson et al: a binary heap whose even
+// levels, starting with the root, are ordered as a min-heap and whose
+// odd levels are ordered as a max-heap, so the lowest item is the root
+// and the highest is one of its children.  It is not threadsafe.
+type MinMaxHeap[T any] struct {
+	items   []T
+	compare common.CompareFunc[T]
+}
+
+// NewMinMaxHeap returns an empty heap with room for hint items before
+// it grows, ordering items by their Comparable implementation.
+func NewMinMaxHeap[T Comparable[T]](hint int) *MinMaxHeap[T] {
+	return NewMinMaxHeapWithCompareFunc(common.MethodCompare[T](), hint)
+}
+
+// NewMinMaxHeapWithCompareFunc is like NewMinMaxHeap, but orders items
+// with the provided function rather than requiring them to implement
+// Comparable.
+func NewMinMaxHeapWithCompareFunc[T any](compare common.CompareFunc[T], hint int) *MinMaxHeap[T] {
+	return &MinMaxHeap[T]{items: make([]T, 0, hint), compare: compare}
+}
+
+// Len returns the number of items in the heap.
+func (h *MinMaxHeap[T]) Len() int {
+	return len(h.items)
+}
+
+// Empty returns true if the heap has no items.
+func (h *MinMaxHeap[T]) Empty() bool {
+	return len(h.items) == 0
+}
+
+// Push adds items to the heap.
+func (h *MinMaxHeap[T]) Push(items ...T) {
+	for _, item := range items {
+		h.items = append(h.items, item)
+		h.up(len(h.items) - 1)
+	}
+}
+
+// PeekMin returns the lowest item without removing it, and whether
+// there was one.
+func (h *MinMaxHeap[T]) PeekMin() (T, bool) {
+	if len(h.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return h.items[0], true
+}
+
+// PeekMax returns the highest item without removing it, and whether
+// there was one.
+func (h *MinMaxHeap[T]) PeekMax() (T, bool) {
+	if len(h.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return h.items[h.maxIndex()], true
+}
+
+// PopMin removes and returns the lowest item, and whether there was
+// one.
+func (h *MinMaxHeap[T]) PopMin() (T, bool) {
+	if len(h.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return h.remove(0), true
+}
+
+// PopMax removes and returns the highest item, and whether there was
+// one.
+func (h *MinMaxHeap[T]) PopMax() (T, bool) {
+	if len(h.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return h.remove(h.maxIndex()), true
+}
+
+// maxIndex returns the index of the highest item in a non-empty heap,
+// which is the root or one of its children.
+func (h *MinMaxHeap[T]) maxIndex() int {
+	switch {
+	case len(h.items) == 1:
+		return 0
+	case len(h.items) == 2 || h.compare(h.items[1], h.items[2]) >= 0:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// remove takes out the item at i, replacing it with the last item and
+// moving that down to its place.
+func (h *MinMaxHeap[T]) remove(i int) T {
+	item := h.items[i]
+	last := len(h.items) - 1
+	h.items[i] = h.items[last]
+	var zero T
+	h.items[last] = zero
+	h.items = h.items[:last]
+	if i < last {
+		h.down(i)
+	}
+	return item
+}
+
+// isMinLevel returns whether i is on an even level of the heap.
+func isMinLevel(i int) bool {
+	return bits.Len(uint(i+1))%2 == 1
+}
+
+// before returns whether the item at i belongs above the item at j on
+// a min level, or on a max level if onMax is set.
+func (h *MinMaxHeap[T]) before(i, j int, onMax bool) bool {
+	c := h.compare(h.items[i], h.items[j])
+	if onMax {
+		return c > 0
+	}
+	return c < 0
+}
+
+func (h *MinMaxHeap[T]) swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+}
+
+// up moves the item at i up to its place.
+func (h *MinMaxHeap[T]) up(i int) {
+	if i == 0 {
+		return
+	}
+	onMax := !isMinLevel(i)
+	parent := (i - 1) / 2
+	// an item belonging above its parent on the other kind of level
+	// belongs among that level's ancestors instead
+	if h.before(parent, i, onMax) {
+		h.swap(i, parent)
+		i = parent
+		onMax = !onMax
+	}
+	for i > 2 {
+		grandparent := ((i-1)/2 - 1) / 2
+		if !h.before(i, grandparent, onMax) {
+			return
+		}
+		h.swap(i, grandparent)
+		i = grandparent
+	}
+}
+
+// down moves the item at i down to its place.
+func (h *MinMaxHeap[T]) down(i int) {
+	onMax := !isMinLevel(i)
+	for {
+		// find the best of the children and grandchildren
+		first := 2*i + 1
+		if first >= len(h.items) {
+			return
+		}
+		best := first
+		for _, j := range [...]int{first + 1, 2*first + 1, 2*first + 2, 2*first + 3, 2*first + 4} {
+			if j < len(h.items) && h.before(j, best, onMax) {
+				best = j
+			}
+		}
+
+		if !h.before(best, i, onMax) {
+			return
+		}
+		h.swap(i, best)
+		if best <= first+1 {
+			// a child is on the other kind of level, and has no
+			// descendants the item could belong among
+			return
+		}
+		// a grandchild may now belong above its parent
+		if parent := (best - 1) / 2; h.before(parent, best, onMax) {
+			h.swap(best, parent)
+		}
+		i = best
+	}
+}
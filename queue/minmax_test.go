@@ -0,0 +1,126 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queue
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// checkMinMax asserts that every item is within the bounds set by its
+// ancestors on each kind of level.
+func checkMinMax[T any](t *testing.T, h *MinMaxHeap[T]) {
+	for i := 1; i < len(h.items); i++ {
+		for a := (i - 1) / 2; ; a = (a - 1) / 2 {
+			c := h.compare(h.items[i], h.items[a])
+			if isMinLevel(a) {
+				assert.GreaterOrEqual(t, c, 0)
+			} else {
+				assert.LessOrEqual(t, c, 0)
+			}
+			if a == 0 {
+				break
+			}
+		}
+	}
+}
+
+func TestMinMaxHeap(t *testing.T) {
+	h := NewMinMaxHeap[testPriorityItem](0)
+	_, ok := h.PeekMin()
+	assert.False(t, ok)
+	_, ok = h.PopMax()
+	assert.False(t, ok)
+	assert.True(t, h.Empty())
+
+	for _, priority := range []int{5, 1, 9, 3, 7} {
+		h.Push(testPriorityItem{priority: priority})
+	}
+	assert.Equal(t, 5, h.Len())
+	min, _ := h.PeekMin()
+	max, _ := h.PeekMax()
+	assert.Equal(t, 1, min.priority)
+	assert.Equal(t, 9, max.priority)
+
+	max, _ = h.PopMax()
+	assert.Equal(t, 9, max.priority)
+	min, _ = h.PopMin()
+	assert.Equal(t, 1, min.priority)
+	max, _ = h.PopMax()
+	assert.Equal(t, 7, max.priority)
+	assert.Equal(t, 2, h.Len())
+
+	h.PopMin()
+	last, ok := h.PopMax()
+	assert.True(t, ok)
+	assert.Equal(t, 5, last.priority)
+	assert.True(t, h.Empty())
+}
+
+func TestMinMaxHeapMatchesSortedSlice(t *testing.T) {
+	h := NewMinMaxHeapWithCompareFunc(cmp.Compare[int], 0)
+	var expected []int
+	for range 5000 {
+		switch rand.Intn(3) {
+		case 0:
+			min, ok := h.PopMin()
+			assert.Equal(t, len(expected) > 0, ok)
+			if ok {
+				assert.Equal(t, expected[0], min)
+				expected = expected[1:]
+			}
+		case 1:
+			max, ok := h.PopMax()
+			assert.Equal(t, len(expected) > 0, ok)
+			if ok {
+				assert.Equal(t, expected[len(expected)-1], max)
+				expected = expected[:len(expected)-1]
+			}
+		default:
+			v := rand.Intn(100)
+			h.Push(v)
+			i, _ := slices.BinarySearch(expected, v)
+			expected = slices.Insert(expected, i, v)
+		}
+		assert.Equal(t, len(expected), h.Len())
+	}
+	checkMinMax(t, h)
+}
+
+func TestMinMaxHeapKeepBest(t *testing.T) {
+	// keep the five largest values seen, evicting the smallest
+	h := NewMinMaxHeapWithCompareFunc(cmp.Compare[int], 6)
+	values := rand.Perm(100)
+	for _, v := range values {
+		h.Push(v)
+		if h.Len() > 5 {
+			h.PopMin()
+		}
+	}
+	checkMinMax(t, h)
+
+	var best []int
+	for !h.Empty() {
+		max, _ := h.PopMax()
+		best = append(best, max)
+	}
+	assert.Equal(t, []int{99, 98, 97, 96, 95}, best)
+}
@@ -16,15 +16,18 @@ limitations under the License.
 
 package queue
 
-import "sync"
+import (
+	"context"
+	"iter"
+	"sync"
+
+	"github.com/Workiva/go-datastructures/common"
+)
 
 // Comparable is an interface for items that can be compared for ordering.
 // Items implementing this interface can be used with PriorityQueue.
-type Comparable[T any] interface {
-	// Compare returns a value indicating the relationship between this item
-	// and the other. Return 1 if this > other, 0 if equal, -1 if this < other.
-	Compare(other T) int
-}
+// Compare returns 1 if this > other, 0 if equal, -1 if this < other.
+type Comparable[T any] = common.ComparableItem[T]
 
 // PriorityItem wraps a value with a priority for use in OrderedPriorityQueue.
 type PriorityItem[T any] struct {
@@ -43,13 +46,23 @@ func (p PriorityItem[T]) Compare(other PriorityItem[T]) int {
 	return 0
 }
 
-type priorityItems[T Comparable[T]] []T
+// defaultArity is the number of children of each node in a priority
+// queue's heap when none is given.  Wider heaps are shallower, so
+// pushes make fewer comparisons and swaps, while the children of a
+// node share cache lines, so pops pay little for comparing more of
+// them; four children make pushes markedly cheaper than two for about
+// the same cost per pop.
+const defaultArity = 4
+
+// priorityItems is a d-ary heap, in which the children of the item at
+// index i are at indices arity*i+1 through arity*i+arity.
+type priorityItems[T any] []T
 
 func (items *priorityItems[T]) swap(i, j int) {
 	(*items)[i], (*items)[j] = (*items)[j], (*items)[i]
 }
 
-func (items *priorityItems[T]) pop() T {
+func (items *priorityItems[T]) pop(compare common.CompareFunc[T], arity int) T {
 	size := len(*items)
 
 	items.swap(size-1, 0)
@@ -58,18 +71,20 @@ func (items *priorityItems[T]) pop() T {
 	(*items)[size-1], *items = zero, (*items)[:size-1]
 
 	index := 0
-	childL, childR := 2*index+1, 2*index+2
-	for len(*items) > childL {
-		child := childL
-		if len(*items) > childR && (*items)[childR].Compare((*items)[childL]) < 0 {
-			child = childR
+	first := arity*index + 1
+	for len(*items) > first {
+		child := first
+		for i := first + 1; i < first+arity && i < len(*items); i++ {
+			if compare((*items)[i], (*items)[child]) < 0 {
+				child = i
+			}
 		}
 
-		if (*items)[child].Compare((*items)[index]) < 0 {
+		if compare((*items)[child], (*items)[index]) < 0 {
 			items.swap(index, child)
 
 			index = child
-			childL, childR = 2*index+1, 2*index+2
+			first = arity*index + 1
 		} else {
 			break
 		}
@@ -78,50 +93,79 @@ func (items *priorityItems[T]) pop() T {
 	return item
 }
 
-func (items *priorityItems[T]) get(number int) []T {
+func (items *priorityItems[T]) get(number int, compare common.CompareFunc[T], arity int) []T {
 	returnItems := make([]T, 0, number)
 	for range number {
 		if len(*items) == 0 {
 			break
 		}
 
-		returnItems = append(returnItems, items.pop())
+		returnItems = append(returnItems, items.pop(compare, arity))
 	}
 
 	return returnItems
 }
 
-func (items *priorityItems[T]) push(item T) {
+func (items *priorityItems[T]) push(item T, compare common.CompareFunc[T], arity int) {
 	*items = append(*items, item)
 
 	index := len(*items) - 1
-	parent := (index - 1) / 2
-	for parent >= 0 && (*items)[parent].Compare(item) > 0 {
+	for index > 0 {
+		parent := (index - 1) / arity
+		if compare((*items)[parent], item) <= 0 {
+			break
+		}
 		items.swap(index, parent)
-
 		index = parent
-		parent = (index - 1) / 2
 	}
 }
 
 // PriorityQueue is a generic thread-safe priority queue.
-// Items must implement the Comparable interface for ordering.
-type PriorityQueue[T Comparable[T]] struct {
+// Items are ordered by their Comparable implementation or by the
+// function given to NewPriorityQueueWithCompareFunc.  It is a d-ary
+// heap, with four children per node unless created with
+// NewPriorityQueueWithArity.
+type PriorityQueue[T any] struct {
 	waiters         waiters
 	items           priorityItems[T]
+	compare         common.CompareFunc[T]
+	arity           int
 	lock            sync.Mutex
 	disposeLock     sync.Mutex
 	disposed        bool
 	allowDuplicates bool
+	obs             observer
 }
 
 // NewPriorityQueue creates a new priority queue with the given capacity hint.
 // If allowDuplicates is false, duplicate items (as determined by pointer equality
 // or value equality for comparable types) will not be added.
-func NewPriorityQueue[T Comparable[T]](hint int, allowDuplicates bool) *PriorityQueue[T] {
+func NewPriorityQueue[T Comparable[T]](hint int, allowDuplicates bool, opts ...Option) *PriorityQueue[T] {
+	return NewPriorityQueueWithCompareFunc(common.MethodCompare[T](), hint, allowDuplicates, opts...)
+}
+
+// NewPriorityQueueWithCompareFunc is like NewPriorityQueue, but orders
+// items with the provided function rather than requiring them to
+// implement Comparable.  Items that compare lowest are retrieved first.
+func NewPriorityQueueWithCompareFunc[T any](compare common.CompareFunc[T], hint int, allowDuplicates bool, opts ...Option) *PriorityQueue[T] {
+	return NewPriorityQueueWithArity(compare, defaultArity, hint, allowDuplicates, opts...)
+}
+
+// NewPriorityQueueWithArity is like NewPriorityQueueWithCompareFunc, but
+// gives each node of the heap arity children rather than four.  Two
+// makes a binary heap; larger arities favour pushes over pops, and
+// suit workloads that push many more items than they get.  It panics
+// if arity is less than two.
+func NewPriorityQueueWithArity[T any](compare common.CompareFunc[T], arity, hint int, allowDuplicates bool, opts ...Option) *PriorityQueue[T] {
+	if arity < 2 {
+		panic(`queue: priority queue arity must be at least 2`)
+	}
 	return &PriorityQueue[T]{
 		items:           make(priorityItems[T], 0, hint),
+		compare:         compare,
+		arity:           arity,
 		allowDuplicates: allowDuplicates,
+		obs:             newObserver("priority_queue", opts),
 	}
 }
 
@@ -140,7 +184,7 @@ func (pq *PriorityQueue[T]) Put(items ...T) error {
 	}
 
 	for _, item := range items {
-		pq.items.push(item)
+		pq.items.push(item, pq.compare, pq.arity)
 	}
 
 	for {
@@ -150,19 +194,30 @@ func (pq *PriorityQueue[T]) Put(items ...T) error {
 		}
 
 		sema.response.Add(1)
-		sema.ready <- true
-		sema.response.Wait()
+		select {
+		case sema.ready <- true:
+			sema.response.Wait()
+		default:
+			// This getter's context was done.
+		}
 		if len(pq.items) == 0 {
 			break
 		}
 	}
 
+	pq.obs.put(len(items), len(pq.items))
 	return nil
 }
 
 // Get retrieves items from the queue in priority order.
 // If the queue is empty, this call blocks until items are added.
 func (pq *PriorityQueue[T]) Get(number int) ([]T, error) {
+	return pq.GetContext(context.Background(), number)
+}
+
+// GetContext is like Get, but stops waiting for items once the context
+// is done and returns the context's error.
+func (pq *PriorityQueue[T]) GetContext(ctx context.Context, number int) ([]T, error) {
 	if number < 1 {
 		return nil, nil
 	}
@@ -181,18 +236,35 @@ func (pq *PriorityQueue[T]) Get(number int) ([]T, error) {
 		pq.waiters.put(sema)
 		pq.lock.Unlock()
 
-		<-sema.ready
+		start := pq.obs.waitStart()
+		select {
+		case <-sema.ready:
+		case <-ctx.Done():
+			pq.obs.waited(start)
+			select {
+			case sema.ready <- true:
+				pq.lock.Lock()
+				pq.waiters.remove(sema)
+				pq.lock.Unlock()
+			default:
+				sema.response.Done()
+			}
+			return nil, ctx.Err()
+		}
+		pq.obs.waited(start)
 
 		if pq.Disposed() {
 			return nil, ErrDisposed
 		}
 
-		items = pq.items.get(number)
+		items = pq.items.get(number, pq.compare, pq.arity)
+		pq.obs.get(len(items), len(pq.items))
 		sema.response.Done()
 		return items, nil
 	}
 
-	items = pq.items.get(number)
+	items = pq.items.get(number, pq.compare, pq.arity)
+	pq.obs.get(len(items), len(pq.items))
 	pq.lock.Unlock()
 	return items, nil
 }
@@ -209,6 +281,52 @@ func (pq *PriorityQueue[T]) Peek() (T, bool) {
 	return zero, false
 }
 
+// Drain returns an iterator that removes and yields items in priority
+// order until the queue is empty or disposed.  It never blocks waiting
+// for items, and stopping early leaves the rest queued.
+func (pq *PriorityQueue[T]) Drain() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			pq.lock.Lock()
+			if pq.disposed || len(pq.items) == 0 {
+				pq.lock.Unlock()
+				return
+			}
+			item := pq.items.pop(pq.compare, pq.arity)
+			pq.obs.get(1, len(pq.items))
+			pq.lock.Unlock()
+
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// Clone returns a copy of the priority queue holding the same items,
+// with none of the goroutines waiting in Get.  Items are copied as they
+// are, so pointers are shared.  A clone of a disposed queue is itself
+// disposed.
+func (pq *PriorityQueue[T]) Clone() *PriorityQueue[T] {
+	return pq.CloneWith(nil)
+}
+
+// CloneWith is like Clone, but copies each item with copyFn, which
+// must not change how the item orders against the others.
+func (pq *PriorityQueue[T]) CloneWith(copyFn func(T) T) *PriorityQueue[T] {
+	pq.lock.Lock()
+	defer pq.lock.Unlock()
+
+	return &PriorityQueue[T]{
+		items:           cloneItems(pq.items, copyFn),
+		compare:         pq.compare,
+		arity:           pq.arity,
+		disposed:        pq.disposed,
+		allowDuplicates: pq.allowDuplicates,
+		obs:             pq.obs,
+	}
+}
+
 // Empty returns true if the queue has no items.
 func (pq *PriorityQueue[T]) Empty() bool {
 	pq.lock.Lock()
@@ -244,7 +362,10 @@ func (pq *PriorityQueue[T]) Dispose() {
 	pq.disposed = true
 	for _, waiter := range pq.waiters {
 		waiter.response.Add(1)
-		waiter.ready <- true
+		select {
+		case waiter.ready <- true:
+		default:
+		}
 	}
 
 	pq.items = nil
@@ -259,9 +380,9 @@ type OrderedPriorityQueue[T any] struct {
 
 // NewOrderedPriorityQueue creates a priority queue that uses integer priorities.
 // Lower priority values are dequeued first (min-heap behavior).
-func NewOrderedPriorityQueue[T any](hint int, allowDuplicates bool) *OrderedPriorityQueue[T] {
+func NewOrderedPriorityQueue[T any](hint int, allowDuplicates bool, opts ...Option) *OrderedPriorityQueue[T] {
 	return &OrderedPriorityQueue[T]{
-		PriorityQueue: NewPriorityQueue[PriorityItem[T]](hint, allowDuplicates),
+		PriorityQueue: NewPriorityQueue[PriorityItem[T]](hint, allowDuplicates, opts...),
 	}
 }
 
@@ -283,3 +404,18 @@ func (opq *OrderedPriorityQueue[T]) Dequeue() (T, int, error) {
 	}
 	return items[0].Value, items[0].Priority, nil
 }
+
+// Clone returns a copy of the queue.  See PriorityQueue.Clone.
+func (opq *OrderedPriorityQueue[T]) Clone() *OrderedPriorityQueue[T] {
+	return &OrderedPriorityQueue[T]{PriorityQueue: opq.PriorityQueue.Clone()}
+}
+
+// CloneWith is like Clone, but copies each value with copyFn.
+func (opq *OrderedPriorityQueue[T]) CloneWith(copyFn func(T) T) *OrderedPriorityQueue[T] {
+	return &OrderedPriorityQueue[T]{
+		PriorityQueue: opq.PriorityQueue.CloneWith(func(item PriorityItem[T]) PriorityItem[T] {
+			item.Value = copyFn(item.Value)
+			return item
+		}),
+	}
+}
@@ -23,6 +23,13 @@ Both implementations return errors instead of panicking when operations are
 attempted on disposed queues. The priority queue maintains items in priority
 order using a heap.
 
+The blocking calls have Context variants, such as GetContext, that stop
+waiting once the context is done and return its error.  A context is
+only consulted while a call would otherwise block, so items that are
+already available are returned even if it is done, and a queue
+disposed while a call waits returns ErrDisposed rather than the
+context's error.
+
 Example usage:
 
 	q := queue.New[string](10)
@@ -33,10 +40,14 @@ Example usage:
 package queue
 
 import (
+	"context"
+	"iter"
 	"runtime"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/Workiva/go-datastructures/common"
 )
 
 type waiters []*sema
@@ -145,13 +156,15 @@ type Queue[T any] struct {
 	items    items[T]
 	lock     sync.Mutex
 	disposed bool
+	obs      observer
 }
 
 // New creates a new Queue with the given initial capacity hint.
 // The hint is used to pre-allocate the underlying storage for better performance.
-func New[T any](hint int64) *Queue[T] {
+func New[T any](hint int64, opts ...Option) *Queue[T] {
 	return &Queue[T]{
 		items: make([]T, 0, hint),
+		obs:   newObserver("queue", opts),
 	}
 }
 
@@ -187,6 +200,7 @@ func (q *Queue[T]) Put(items ...T) error {
 		}
 	}
 
+	q.obs.put(len(items), len(q.items))
 	q.lock.Unlock()
 	return nil
 }
@@ -203,8 +217,21 @@ func (q *Queue[T]) Get(number int64) ([]T, error) {
 // Poll will return a number UP TO the number passed in as a parameter. If no
 // items are in the queue, this method will pause until items are added to the
 // queue or the provided timeout is reached. A non-positive timeout will block
-// until items are added. If a timeout occurs, ErrTimeout is returned.
+// until items are added. If a timeout occurs, an error matching ErrTimeout
+// is returned.
 func (q *Queue[T]) Poll(number int64, timeout time.Duration) ([]T, error) {
+	return q.poll(context.Background(), number, timeout)
+}
+
+// GetContext is like Get, but stops waiting for items once the context
+// is done and returns the context's error.
+func (q *Queue[T]) GetContext(ctx context.Context, number int64) ([]T, error) {
+	return q.poll(ctx, number, 0)
+}
+
+// poll waits for items until the timeout, if it is positive, or the
+// context ends the wait.
+func (q *Queue[T]) poll(ctx context.Context, number int64, timeout time.Duration) ([]T, error) {
 	if number < 1 {
 		return []T{}, nil
 	}
@@ -223,32 +250,43 @@ func (q *Queue[T]) Poll(number int64, timeout time.Duration) ([]T, error) {
 		q.waiters.put(sema)
 		q.lock.Unlock()
 
-		var timeoutC <-chan time.Time
+		var (
+			timeoutC <-chan time.Time
+			err      error = &common.OpError{Op: "Queue.Poll", Timeout: timeout, Err: ErrTimeout}
+		)
 		if timeout > 0 {
 			timeoutC = time.After(timeout)
 		}
+		start := q.obs.waitStart()
 		select {
 		case <-sema.ready:
+			q.obs.waited(start)
 			if q.disposed {
 				return nil, ErrDisposed
 			}
 			items = q.items.get(number)
+			q.obs.get(len(items), len(q.items))
 			sema.response.Done()
 			return items, nil
 		case <-timeoutC:
-			select {
-			case sema.ready <- true:
-				q.lock.Lock()
-				q.waiters.remove(sema)
-				q.lock.Unlock()
-			default:
-				sema.response.Done()
-			}
-			return nil, ErrTimeout
+		case <-ctx.Done():
+			err = ctx.Err()
 		}
+
+		q.obs.waited(start)
+		select {
+		case sema.ready <- true:
+			q.lock.Lock()
+			q.waiters.remove(sema)
+			q.lock.Unlock()
+		default:
+			sema.response.Done()
+		}
+		return nil, err
 	}
 
 	items = q.items.get(number)
+	q.obs.get(len(items), len(q.items))
 	q.lock.Unlock()
 	return items, nil
 }
@@ -289,10 +327,70 @@ func (q *Queue[T]) TakeUntil(checker func(item T) bool) ([]T, error) {
 	}
 
 	result := q.items.getUntil(checker)
+	q.obs.get(len(result), len(q.items))
 	q.lock.Unlock()
 	return result, nil
 }
 
+// Drain returns an iterator that removes and yields items from the
+// front of the queue until it is empty or disposed.  It never blocks
+// waiting for items, and an item is only removed once the previous
+// one has been yielded, so stopping early leaves the rest queued.
+func (q *Queue[T]) Drain() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			q.lock.Lock()
+			if q.disposed || len(q.items) == 0 {
+				q.lock.Unlock()
+				return
+			}
+			item := q.items.get(1)[0]
+			q.obs.get(1, len(q.items))
+			q.lock.Unlock()
+
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// Clone returns a copy of the queue holding the same items in the same
+// order, with none of the goroutines waiting in Get.  Items are copied
+// as they are, so pointers are shared.  A clone of a disposed queue is
+// itself disposed.
+func (q *Queue[T]) Clone() *Queue[T] {
+	return q.CloneWith(nil)
+}
+
+// CloneWith is like Clone, but copies each item with copyFn.
+func (q *Queue[T]) CloneWith(copyFn func(T) T) *Queue[T] {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return &Queue[T]{
+		items:    cloneItems(q.items, copyFn),
+		disposed: q.disposed,
+		obs:      q.obs,
+	}
+}
+
+// cloneItems returns a copy of items with the same capacity, copying
+// each item with copyFn if it is not nil.
+func cloneItems[S ~[]T, T any](items S, copyFn func(T) T) S {
+	if items == nil {
+		return nil
+	}
+	clone := make(S, len(items), cap(items))
+	copy(clone, items)
+	if copyFn != nil {
+		for i, item := range clone {
+			clone[i] = copyFn(item)
+		}
+	}
+	return clone
+}
+
 // Empty returns a bool indicating if the queue is empty.
 func (q *Queue[T]) Empty() bool {
 	q.lock.Lock()
@@ -17,11 +17,18 @@ limitations under the License.
 package queue
 
 import (
+	"cmp"
+	"context"
+	"math/rand"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/Workiva/go-datastructures/common"
 )
 
 func TestQueuePut(t *testing.T) {
@@ -72,7 +79,11 @@ func TestQueuePoll(t *testing.T) {
 
 	// Test timeout on empty queue
 	_, err := q.Poll(1, 50*time.Millisecond)
-	assert.Equal(t, ErrTimeout, err)
+	assert.ErrorIs(t, err, ErrTimeout)
+	var opErr *common.OpError
+	require.ErrorAs(t, err, &opErr)
+	assert.Equal(t, "Queue.Poll", opErr.Op)
+	assert.Equal(t, 50*time.Millisecond, opErr.Timeout)
 
 	// Add item and get it
 	q.Put(42)
@@ -183,6 +194,65 @@ func TestPriorityQueuePeek(t *testing.T) {
 	assert.Equal(t, 2, pq.Len())
 }
 
+func TestPriorityQueueCompareFunc(t *testing.T) {
+	pq := NewPriorityQueueWithCompareFunc(func(a, b string) int {
+		return len(a) - len(b)
+	}, 10, true)
+
+	pq.Put("medium", "low", "highest")
+
+	items, err := pq.Get(3)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"low", "medium", "highest"}, items)
+}
+
+func TestPriorityQueueArity(t *testing.T) {
+	for _, arity := range []int{2, 3, 4, 8} {
+		pq := NewPriorityQueueWithArity(cmp.Compare[int], arity, 0, true)
+		values := make([]int, 1000)
+		for i := range values {
+			values[i] = rand.Intn(100)
+		}
+		pq.Put(values...)
+		for i := 0; i < 500; i++ {
+			pq.Put(rand.Intn(100))
+		}
+
+		items, err := pq.Get(pq.Len())
+		require.NoError(t, err)
+		assert.Len(t, items, 1500)
+		assert.True(t, slices.IsSorted(items), `arity %d`, arity)
+	}
+
+	assert.Panics(t, func() {
+		NewPriorityQueueWithArity(cmp.Compare[int], 1, 0, true)
+	})
+}
+
+// benchmarkPriorityQueueArity pushes many more items than it gets, as
+// when only the best few of a large batch are wanted.
+func benchmarkPriorityQueueArity(b *testing.B, arity int) {
+	values := rand.Perm(100000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pq := NewPriorityQueueWithArity(cmp.Compare[int], arity, len(values), true)
+		pq.Put(values...)
+		pq.Get(1000)
+	}
+}
+
+func BenchmarkPriorityQueueArity2(b *testing.B) {
+	benchmarkPriorityQueueArity(b, 2)
+}
+
+func BenchmarkPriorityQueueArity4(b *testing.B) {
+	benchmarkPriorityQueueArity(b, 4)
+}
+
+func BenchmarkPriorityQueueArity8(b *testing.B) {
+	benchmarkPriorityQueueArity(b, 8)
+}
+
 func TestOrderedPriorityQueue(t *testing.T) {
 	opq := NewOrderedPriorityQueue[string](10, true)
 
@@ -266,7 +336,10 @@ func TestRingBufferPoll(t *testing.T) {
 
 	// Test timeout
 	_, err := rb.Poll(50 * time.Millisecond)
-	assert.Equal(t, ErrTimeout, err)
+	assert.ErrorIs(t, err, ErrTimeout)
+	var opErr *common.OpError
+	require.ErrorAs(t, err, &opErr)
+	assert.Equal(t, "RingBuffer.Poll", opErr.Op)
 }
 
 func TestExecuteInParallel(t *testing.T) {
@@ -291,3 +364,190 @@ func TestExecuteInParallel(t *testing.T) {
 	// Sum of 0..99 = 4950
 	assert.Equal(t, 4950, sum)
 }
+
+func TestDrain(t *testing.T) {
+	q := New[int](10)
+	q.Put(1, 2, 3, 4)
+
+	var items []int
+	for item := range q.Drain() {
+		items = append(items, item)
+		if item == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []int{1, 2}, items)
+	assert.Equal(t, int64(2), q.Len())
+
+	assert.Equal(t, []int{3, 4}, slices.Collect(q.Drain()))
+	assert.True(t, q.Empty())
+
+	q.Put(5)
+	q.Dispose()
+	assert.Empty(t, slices.Collect(q.Drain()))
+}
+
+func TestPriorityQueueDrain(t *testing.T) {
+	pq := NewPriorityQueueWithCompareFunc(cmp.Compare[int], 10, true)
+	pq.Put(3, 1, 4, 1, 5)
+
+	for item := range pq.Drain() {
+		assert.Equal(t, 1, item)
+		break
+	}
+	assert.Equal(t, 4, pq.Len())
+	assert.Equal(t, []int{1, 3, 4, 5}, slices.Collect(pq.Drain()))
+	assert.True(t, pq.Empty())
+
+	pq.Put(1)
+	pq.Dispose()
+	assert.Empty(t, slices.Collect(pq.Drain()))
+}
+
+func TestQueueClone(t *testing.T) {
+	q := New[[]int](10)
+	q.Put([]int{1}, []int{2})
+
+	clone := q.Clone()
+	clone.Put([]int{3})
+	assert.Equal(t, int64(2), q.Len())
+	assert.Equal(t, [][]int{{1}, {2}, {3}}, slices.Collect(clone.Drain()))
+	assert.Equal(t, int64(2), q.Len())
+
+	deep := q.CloneWith(slices.Clone[[]int])
+	items, err := deep.Get(1)
+	require.NoError(t, err)
+	items[0][0] = 10
+	items, err = q.Get(1)
+	require.NoError(t, err)
+	assert.Equal(t, [][]int{{1}}, items)
+
+	q.Dispose()
+	assert.True(t, q.Clone().Disposed())
+}
+
+func TestPriorityQueueClone(t *testing.T) {
+	pq := NewPriorityQueueWithCompareFunc(cmp.Compare[int], 10, true)
+	pq.Put(3, 1, 4, 1, 5)
+
+	clone := pq.Clone()
+	clone.Put(0)
+	assert.Equal(t, []int{0, 1, 1, 3, 4, 5}, slices.Collect(clone.Drain()))
+	assert.Equal(t, 5, pq.Len())
+
+	doubled := pq.CloneWith(func(i int) int { return i * 2 })
+	assert.Equal(t, []int{2, 2, 6, 8, 10}, slices.Collect(doubled.Drain()))
+	assert.Equal(t, []int{1, 1, 3, 4, 5}, slices.Collect(pq.Drain()))
+
+	opq := NewOrderedPriorityQueue[string](10, false)
+	opq.Enqueue("b", 2)
+	opq.Enqueue("a", 1)
+	upper := opq.CloneWith(strings.ToUpper)
+	value, priority, err := upper.Dequeue()
+	require.NoError(t, err)
+	assert.Equal(t, "A", value)
+	assert.Equal(t, 1, priority)
+	assert.Equal(t, 2, opq.Len())
+	assert.Equal(t, 2, opq.Clone().Len())
+}
+
+func TestQueueGetContext(t *testing.T) {
+	q := New[int](10)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	_, err := q.GetContext(ctx, 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// items already queued are returned even if the context is done
+	require.NoError(t, q.Put(1, 2))
+	items, err := q.GetContext(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, items)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	require.NoError(t, q.Put(3))
+	q.Get(2)
+	go func() {
+		_, err := q.GetContext(ctx, 1)
+		result <- err
+	}()
+	time.Sleep(5 * time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-result, context.Canceled)
+
+	// the canceled getter no longer waits for items
+	require.NoError(t, q.Put(4))
+	items, err = q.Get(1)
+	require.NoError(t, err)
+	assert.Equal(t, []int{4}, items)
+
+	go func() {
+		_, err := q.GetContext(context.Background(), 1)
+		result <- err
+	}()
+	time.Sleep(5 * time.Millisecond)
+	q.Dispose()
+	assert.ErrorIs(t, <-result, ErrDisposed)
+}
+
+func TestPriorityQueueGetContext(t *testing.T) {
+	pq := NewPriorityQueueWithCompareFunc(cmp.Compare[int], 10, true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := pq.GetContext(ctx, 1)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	require.NoError(t, pq.Put(2, 1))
+	items, err := pq.GetContext(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, items)
+	pq.Get(1)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		_, err := pq.GetContext(ctx, 1)
+		result <- err
+	}()
+	time.Sleep(5 * time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-result, context.Canceled)
+
+	require.NoError(t, pq.Put(3))
+	items, err = pq.Get(1)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3}, items)
+
+	go func() {
+		_, err := pq.GetContext(context.Background(), 1)
+		result <- err
+	}()
+	time.Sleep(5 * time.Millisecond)
+	pq.Dispose()
+	assert.ErrorIs(t, <-result, ErrDisposed)
+}
+
+func TestRingBufferContext(t *testing.T) {
+	rb := NewRingBuffer[int](2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	_, err := rb.PollContext(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	require.NoError(t, rb.PutContext(ctx, 1))
+	require.NoError(t, rb.PutContext(ctx, 2))
+	assert.ErrorIs(t, rb.PutContext(ctx, 3), context.DeadlineExceeded)
+
+	item, err := rb.PollContext(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, item)
+
+	rb.Dispose()
+	_, err = rb.PollContext(ctx)
+	assert.ErrorIs(t, err, ErrDisposed)
+	assert.ErrorIs(t, rb.PutContext(ctx, 3), ErrDisposed)
+}
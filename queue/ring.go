@@ -17,9 +17,12 @@ limitations under the License.
 package queue
 
 import (
+	"context"
 	"runtime"
 	"sync/atomic"
 	"time"
+
+	"github.com/Workiva/go-datastructures/common"
 )
 
 // roundUp takes a uint64 greater than 0 and rounds it up to the next
@@ -59,6 +62,7 @@ type RingBuffer[T any] struct {
 	mask, disposed uint64
 	_padding3      [8]uint64
 	nodes          []ringNode[T]
+	obs            observer
 }
 
 func (rb *RingBuffer[T]) init(size uint64) {
@@ -74,7 +78,13 @@ func (rb *RingBuffer[T]) init(size uint64) {
 // call will block until an item is added to the queue or Dispose is called
 // on the queue. An error will be returned if the queue is disposed.
 func (rb *RingBuffer[T]) Put(item T) error {
-	_, err := rb.put(item, false)
+	return rb.PutContext(context.Background(), item)
+}
+
+// PutContext is like Put, but stops waiting for space once the context
+// is done and returns the context's error.
+func (rb *RingBuffer[T]) PutContext(ctx context.Context, item T) error {
+	_, err := rb.put(ctx, item, false)
 	return err
 }
 
@@ -82,12 +92,16 @@ func (rb *RingBuffer[T]) Put(item T) error {
 // is full, this call will return false. An error will be returned if the
 // queue is disposed.
 func (rb *RingBuffer[T]) Offer(item T) (bool, error) {
-	return rb.put(item, true)
+	return rb.put(context.Background(), item, true)
 }
 
-func (rb *RingBuffer[T]) put(item T, offer bool) (bool, error) {
-	var n *ringNode[T]
-	pos := atomic.LoadUint64(&rb.queue)
+func (rb *RingBuffer[T]) put(ctx context.Context, item T, offer bool) (bool, error) {
+	var (
+		n       *ringNode[T]
+		pos     = atomic.LoadUint64(&rb.queue)
+		waiting bool
+		waited  time.Time
+	)
 L:
 	for {
 		if atomic.LoadUint64(&rb.disposed) == 1 {
@@ -111,12 +125,26 @@ L:
 			return false, nil
 		}
 
+		if err := ctx.Err(); err != nil {
+			if waiting {
+				rb.obs.waited(waited)
+			}
+			return false, err
+		}
+
+		if !waiting {
+			waiting, waited = true, rb.obs.waitStart()
+		}
 		runtime.Gosched()
 	}
 
 	n.data = item
 	n.hasData = true
 	atomic.StoreUint64(&n.position, pos+1)
+	if waiting {
+		rb.obs.waited(waited)
+	}
+	rb.obs.put(1, int(rb.Len()))
 	return true, nil
 }
 
@@ -134,11 +162,23 @@ func (rb *RingBuffer[T]) Get() (T, error) {
 // An error will be returned if the queue is disposed or a timeout occurs.
 // A non-positive timeout will block indefinitely.
 func (rb *RingBuffer[T]) Poll(timeout time.Duration) (T, error) {
+	return rb.poll(context.Background(), timeout)
+}
+
+// PollContext is like Get, but stops waiting for an item once the
+// context is done and returns the context's error.
+func (rb *RingBuffer[T]) PollContext(ctx context.Context) (T, error) {
+	return rb.poll(ctx, 0)
+}
+
+func (rb *RingBuffer[T]) poll(ctx context.Context, timeout time.Duration) (T, error) {
 	var (
-		n     *ringNode[T]
-		pos   = atomic.LoadUint64(&rb.dequeue)
-		start time.Time
-		zero  T
+		n       *ringNode[T]
+		pos     = atomic.LoadUint64(&rb.dequeue)
+		start   time.Time
+		zero    T
+		waiting bool
+		waited  time.Time
 	)
 	if timeout > 0 {
 		start = time.Now()
@@ -162,16 +202,30 @@ L:
 			pos = atomic.LoadUint64(&rb.dequeue)
 		}
 
-		if timeout > 0 && time.Since(start) >= timeout {
-			return zero, ErrTimeout
+		err := ctx.Err()
+		if err == nil && timeout > 0 && time.Since(start) >= timeout {
+			err = &common.OpError{Op: "RingBuffer.Poll", Timeout: timeout, Err: ErrTimeout}
+		}
+		if err != nil {
+			if waiting {
+				rb.obs.waited(waited)
+			}
+			return zero, err
 		}
 
+		if !waiting {
+			waiting, waited = true, rb.obs.waitStart()
+		}
 		runtime.Gosched()
 	}
 	data := n.data
 	n.data = zero
 	n.hasData = false
 	atomic.StoreUint64(&n.position, pos+rb.mask+1)
+	if waiting {
+		rb.obs.waited(waited)
+	}
+	rb.obs.get(1, int(rb.Len()))
 	return data, nil
 }
 
@@ -199,8 +253,8 @@ func (rb *RingBuffer[T]) IsDisposed() bool {
 
 // NewRingBuffer will allocate, initialize, and return a ring buffer
 // with the specified size.
-func NewRingBuffer[T any](size uint64) *RingBuffer[T] {
-	rb := &RingBuffer[T]{}
+func NewRingBuffer[T any](size uint64, opts ...Option) *RingBuffer[T] {
+	rb := &RingBuffer[T]{obs: newObserver("ring_buffer", opts)}
 	rb.init(size)
 	return rb
 }
@@ -0,0 +1,64 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import "time"
+
+// LeakyBucket is a rate limiter that lets events through one at a
+// time, evenly spaced at a steady rate, with no bursts.  Events that
+// arrive early can queue to wait their turn, up to a fixed number at a
+// time.  A LeakyBucket is safe for concurrent use.
+type LeakyBucket struct {
+	clock    clock
+	interval int64
+	capacity int64
+	next     schedule
+}
+
+// NewLeakyBucket returns a LeakyBucket that lets through rate events
+// per second, and queues up to capacity events waiting their turn.
+func NewLeakyBucket(rate float64, capacity int, opts ...Option) *LeakyBucket {
+	if capacity < 0 {
+		panic(`ratelimit: capacity must not be negative`)
+	}
+	o := newOptions(opts)
+	b := &LeakyBucket{clock: newClock(o.now), interval: interval(rate)}
+	b.capacity = int64(capacity) * b.interval
+	return b
+}
+
+// Allow lets an event through if it is its turn now, without waiting,
+// reporting whether it did.
+func (b *LeakyBucket) Allow() bool {
+	_, _, ok := b.next.advance(b.clock, func(next, now int64) (int64, bool) {
+		return now + b.interval, next <= now
+	})
+	return ok
+}
+
+// Reserve queues an event, and returns how long it must wait for its
+// turn.  It returns false, queueing nothing, if the queue is full.
+func (b *LeakyBucket) Reserve() (time.Duration, bool) {
+	next, now, ok := b.next.advance(b.clock, func(next, now int64) (int64, bool) {
+		turn := max(next, now)
+		return turn + b.interval, turn-now <= b.capacity
+	})
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(max(next, now) - now), true
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package ratelimit implements rate limiters as small, lock-free data
+structures, with no goroutines or timers of their own, so that one can
+be kept per client, per key or per connection as cheaply as a counter.
+
+A TokenBucket allows bursts: it holds up to a fixed number of tokens,
+refilled at a steady rate, and each event spends one.  A LeakyBucket
+smooths events out instead, letting them through one at a time at a
+steady rate and queueing a bounded number of them to wait their turn.
+
+Both keep their state in a single atomic word, updated with
+compare-and-swap, and read the time from a clock that can be replaced
+with WithClock for testing.
+*/
+package ratelimit
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// options holds the settings that may be given when creating a
+// limiter.
+type options struct {
+	now func() time.Time
+}
+
+// Option configures a limiter when it is created.
+type Option func(*options)
+
+// WithClock sets the function a limiter reads the current time from.
+// It defaults to time.Now, and is usually replaced to control time in
+// tests.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{now: time.Now}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// clock measures time in nanoseconds since a limiter was created.
+type clock struct {
+	now   func() time.Time
+	start time.Time
+}
+
+func newClock(now func() time.Time) clock {
+	return clock{now: now, start: now()}
+}
+
+func (c clock) elapsed() int64 {
+	return int64(c.now().Sub(c.start))
+}
+
+// interval returns the time between events at the rate, which must be
+// positive.
+func interval(rate float64) int64 {
+	if !(rate > 0) {
+		panic(`ratelimit: rate must be positive`)
+	}
+	return max(int64(float64(time.Second)/rate), 1)
+}
+
+// schedule is the time, in nanoseconds since a limiter was created,
+// that its next event is due, which is advanced by compare-and-swap.
+type schedule struct {
+	atomic.Int64
+}
+
+// advance sets the schedule to next(due, now) for the current due time
+// and the current time, unless it returns false.  It returns the due
+// time and current time the update was based on.
+func (s *schedule) advance(c clock, next func(due, now int64) (int64, bool)) (int64, int64, bool) {
+	for {
+		now := c.elapsed()
+		due := s.Load()
+		n, ok := next(due, now)
+		if !ok {
+			return due, now, false
+		}
+		if s.CompareAndSwap(due, n) {
+			return due, now, true
+		}
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock is a clock that only moves when told to.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(0, 0)}
+}
+
+func TestTokenBucket(t *testing.T) {
+	clock := newFakeClock()
+	b := NewTokenBucket(10, 3, WithClock(clock.Now))
+	assert.Equal(t, 3.0, b.Tokens())
+	assert.True(t, b.Allow())
+	assert.True(t, b.AllowN(2))
+	assert.False(t, b.Allow())
+	assert.Equal(t, 0.0, b.Tokens())
+
+	clock.Sleep(150 * time.Millisecond)
+	assert.Equal(t, 1.5, b.Tokens())
+	// n tokens are spent together or not at all.
+	assert.False(t, b.AllowN(2))
+	assert.True(t, b.Allow())
+	assert.False(t, b.Allow())
+
+	// the bucket holds no more than burst tokens.
+	clock.Sleep(time.Hour)
+	assert.Equal(t, 3.0, b.Tokens())
+	assert.False(t, b.AllowN(4))
+	assert.True(t, b.AllowN(3))
+	assert.True(t, b.AllowN(0))
+}
+
+func TestTokenBucketReserve(t *testing.T) {
+	clock := newFakeClock()
+	b := NewTokenBucket(10, 2, WithClock(clock.Now))
+
+	delay, ok := b.Reserve(2)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), delay)
+	delay, ok = b.Reserve(1)
+	assert.True(t, ok)
+	assert.Equal(t, 100*time.Millisecond, delay)
+	delay, ok = b.Reserve(2)
+	assert.True(t, ok)
+	assert.Equal(t, 300*time.Millisecond, delay)
+	assert.Equal(t, -3.0, b.Tokens())
+	assert.False(t, b.Allow())
+
+	_, ok = b.Reserve(3)
+	assert.False(t, ok)
+	assert.Equal(t, -3.0, b.Tokens())
+
+	clock.Sleep(300 * time.Millisecond)
+	assert.Equal(t, 0.0, b.Tokens())
+	clock.Sleep(100 * time.Millisecond)
+	assert.True(t, b.Allow())
+}
+
+func TestTokenBucketConcurrent(t *testing.T) {
+	clock := newFakeClock()
+	b := NewTokenBucket(1, 100, WithClock(clock.Now))
+
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if b.Allow() {
+					allowed.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(100), allowed.Load())
+}
+
+func TestLeakyBucket(t *testing.T) {
+	clock := newFakeClock()
+	b := NewLeakyBucket(10, 2, WithClock(clock.Now))
+
+	// events are spaced out, without bursts.
+	assert.True(t, b.Allow())
+	assert.False(t, b.Allow())
+	clock.Sleep(100 * time.Millisecond)
+	assert.True(t, b.Allow())
+
+	for _, expected := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond} {
+		delay, ok := b.Reserve()
+		assert.True(t, ok)
+		assert.Equal(t, expected, delay)
+	}
+	_, ok := b.Reserve()
+	assert.False(t, ok)
+
+	clock.Sleep(150 * time.Millisecond)
+	delay, ok := b.Reserve()
+	assert.True(t, ok)
+	assert.Equal(t, 150*time.Millisecond, delay)
+
+	// an idle bucket does not save up turns.
+	clock.Sleep(time.Hour)
+	assert.True(t, b.Allow())
+	assert.False(t, b.Allow())
+}
+
+func TestLeakyBucketNoQueue(t *testing.T) {
+	clock := newFakeClock()
+	b := NewLeakyBucket(1, 0, WithClock(clock.Now))
+	delay, ok := b.Reserve()
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), delay)
+	_, ok = b.Reserve()
+	assert.False(t, ok)
+}
+
+func TestInvalidConfig(t *testing.T) {
+	assert.Panics(t, func() { NewTokenBucket(0, 1) })
+	assert.Panics(t, func() { NewTokenBucket(1, 0) })
+	assert.Panics(t, func() { NewLeakyBucket(-1, 0) })
+	assert.Panics(t, func() { NewLeakyBucket(1, -1) })
+}
+
+func BenchmarkTokenBucketAllow(b *testing.B) {
+	bucket := NewTokenBucket(1e9, 1000)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			bucket.Allow()
+		}
+	})
+}
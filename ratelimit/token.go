@@ -0,0 +1,101 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import "time"
+
+// TokenBucket is a rate limiter that holds up to burst tokens, refilled
+// at a steady rate, and allows an event for each token spent.  It
+// starts full.  A TokenBucket is safe for concurrent use.
+//
+// Rather than counting tokens, it tracks the time at which the bucket
+// would be full again had it been refilled continuously, as in the
+// generic cell rate algorithm, which needs only that one timestamp.
+type TokenBucket struct {
+	clock    clock
+	interval int64
+	capacity int64
+	full     schedule
+}
+
+// NewTokenBucket returns a TokenBucket that is refilled with rate
+// tokens per second, and holds up to burst of them.
+func NewTokenBucket(rate float64, burst int, opts ...Option) *TokenBucket {
+	if burst < 1 {
+		panic(`ratelimit: burst must be at least 1`)
+	}
+	o := newOptions(opts)
+	b := &TokenBucket{clock: newClock(o.now), interval: interval(rate)}
+	b.capacity = int64(burst) * b.interval
+	return b
+}
+
+// Allow spends a token if there is one, reporting whether it did.
+func (b *TokenBucket) Allow() bool {
+	return b.AllowN(1)
+}
+
+// AllowN spends n tokens if there are that many, reporting whether it
+// did.  It never spends some of them without the rest.
+func (b *TokenBucket) AllowN(n int) bool {
+	if n <= 0 {
+		return true
+	}
+	cost, ok := b.cost(n)
+	if !ok {
+		return false
+	}
+	_, _, ok = b.full.advance(b.clock, func(full, now int64) (int64, bool) {
+		next := max(full, now) + cost
+		return next, next-now <= b.capacity
+	})
+	return ok
+}
+
+// Reserve spends n tokens whether or not there are that many, going
+// into debt for the rest, and returns how long to wait before acting
+// as though they had been available.  It returns false, spending
+// nothing, if n is more than the bucket holds.
+func (b *TokenBucket) Reserve(n int) (time.Duration, bool) {
+	if n <= 0 {
+		return 0, true
+	}
+	cost, ok := b.cost(n)
+	if !ok {
+		return 0, false
+	}
+	full, now, _ := b.full.advance(b.clock, func(full, now int64) (int64, bool) {
+		return max(full, now) + cost, true
+	})
+	return time.Duration(max(max(full, now)+cost-b.capacity-now, 0)), true
+}
+
+// Tokens returns the number of tokens in the bucket, which is negative
+// while it is in debt.
+func (b *TokenBucket) Tokens() float64 {
+	now := b.clock.elapsed()
+	return float64(b.capacity-(max(b.full.Load(), now)-now)) / float64(b.interval)
+}
+
+// cost returns the time it takes to refill n tokens, and false if n is
+// more than the bucket holds.
+func (b *TokenBucket) cost(n int) (int64, bool) {
+	if int64(n) > b.capacity/b.interval {
+		return 0, false
+	}
+	return int64(n) * b.interval, true
+}
@@ -0,0 +1,338 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package rope implements ropes, immutable strings stored as balanced
+binary trees of chunks of text, for editing large texts.  Inserting,
+deleting, slicing and concatenating take O(log n) time however long the
+text is, and the result shares all but O(log n) of its nodes with the
+ropes it was made from, so the versions of a text an editor's undo
+history keeps are cheap.
+
+Positions are byte offsets, as in strings.  A rope is not validated as
+UTF-8, and it may be split in the middle of a rune, but Runes decodes
+runes across the chunks they are split between.
+
+The zero Rope is empty and ready to use.  Ropes are safe for
+concurrent use, since they never change.
+*/
+package rope
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxLeaf is the most bytes a leaf made by New, or by joining two
+// small leaves, holds.
+const maxLeaf = 512
+
+// node is either a leaf of text, or a branch of two non-nil children.
+type node struct {
+	left, right *node
+	text        string
+	length      int
+	height      int
+}
+
+func leaf(text string) *node {
+	if text == "" {
+		return nil
+	}
+	return &node{text: text, length: len(text), height: 1}
+}
+
+func branch(left, right *node) *node {
+	return &node{left: left, right: right, length: left.length + right.length, height: max(left.height, right.height) + 1}
+}
+
+func height(n *node) int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// concat returns a balanced tree of the text of left followed by right,
+// in time proportional to the difference of their heights.
+func concat(left, right *node) *node {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	case left.left == nil && right.left == nil && left.length+right.length <= maxLeaf:
+		return leaf(left.text + right.text)
+	case left.height > right.height+1:
+		return rebalance(left.left, concat(left.right, right))
+	case right.height > left.height+1:
+		return rebalance(concat(left, right.left), right.right)
+	}
+	return branch(left, right)
+}
+
+// rebalance returns a branch of the two trees, whose heights differ by
+// at most two, rotating them if they differ by two.
+func rebalance(left, right *node) *node {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	case left.height > right.height+1:
+		if height(left.left) >= height(left.right) {
+			return branch(left.left, branch(left.right, right))
+		}
+		return branch(branch(left.left, left.right.left), branch(left.right.right, right))
+	case right.height > left.height+1:
+		if height(right.right) >= height(right.left) {
+			return branch(branch(left, right.left), right.right)
+		}
+		return branch(branch(left, right.left.left), branch(right.left.right, right.right))
+	}
+	return branch(left, right)
+}
+
+// split returns trees of the text before and after the offset.
+func split(n *node, i int) (*node, *node) {
+	switch {
+	case n == nil:
+		return nil, nil
+	case i <= 0:
+		return nil, n
+	case i >= n.length:
+		return n, nil
+	case n.left == nil:
+		return leaf(n.text[:i]), leaf(n.text[i:])
+	case i <= n.left.length:
+		before, after := split(n.left, i)
+		return before, concat(after, n.right)
+	}
+	before, after := split(n.right, i-n.left.length)
+	return concat(n.left, before), after
+}
+
+// build returns a balanced tree of the text in leaves of up to
+// maxLeaf bytes.
+func build(text string) *node {
+	if len(text) <= maxLeaf {
+		return leaf(text)
+	}
+	// split on a leaf boundary, so that only the last leaf is short.
+	mid := (len(text)/maxLeaf + 1) / 2 * maxLeaf
+	return branch(build(text[:mid]), build(text[mid:]))
+}
+
+// Rope is an immutable string stored as a balanced tree.
+type Rope struct {
+	root *node
+}
+
+// New returns a rope of the text.
+func New(text string) Rope {
+	return Rope{root: build(text)}
+}
+
+// Len returns the length of the rope in bytes.
+func (r Rope) Len() int {
+	if r.root == nil {
+		return 0
+	}
+	return r.root.length
+}
+
+// String returns the text of the rope, in O(n) time.
+func (r Rope) String() string {
+	var b strings.Builder
+	b.Grow(r.Len())
+	for chunk := range r.Chunks() {
+		b.WriteString(chunk)
+	}
+	return b.String()
+}
+
+// Index returns the byte at offset i.
+func (r Rope) Index(i int) byte {
+	r.check(i, r.Len()-1)
+	n := r.root
+	for n.left != nil {
+		if i < n.left.length {
+			n = n.left
+		} else {
+			i -= n.left.length
+			n = n.right
+		}
+	}
+	return n.text[i]
+}
+
+// Concat returns a rope of this rope followed by the other.
+func (r Rope) Concat(other Rope) Rope {
+	return Rope{root: concat(r.root, other.root)}
+}
+
+// Split returns ropes of the text before and after offset i.
+func (r Rope) Split(i int) (Rope, Rope) {
+	r.check(i, r.Len())
+	before, after := split(r.root, i)
+	return Rope{root: before}, Rope{root: after}
+}
+
+// Insert returns a rope with the text inserted at offset i.
+func (r Rope) Insert(i int, text string) Rope {
+	before, after := r.Split(i)
+	return before.Concat(New(text)).Concat(after)
+}
+
+// Delete returns a rope without the bytes in [from, to).
+func (r Rope) Delete(from, to int) Rope {
+	r.checkRange(from, to)
+	before, rest := split(r.root, from)
+	_, after := split(rest, to-from)
+	return Rope{root: concat(before, after)}
+}
+
+// Slice returns a rope of the bytes in [from, to).
+func (r Rope) Slice(from, to int) Rope {
+	r.checkRange(from, to)
+	_, rest := split(r.root, from)
+	slice, _ := split(rest, to-from)
+	return Rope{root: slice}
+}
+
+func (r Rope) check(i, max int) {
+	if i < 0 || i > max {
+		panic(fmt.Sprintf("rope: offset %d out of range [0:%d]", i, r.Len()))
+	}
+}
+
+func (r Rope) checkRange(from, to int) {
+	if from < 0 || to < from || to > r.Len() {
+		panic(fmt.Sprintf("rope: range [%d:%d] out of range [0:%d]", from, to, r.Len()))
+	}
+}
+
+// Chunks returns an iterator over the chunks of text the rope is made
+// of, in order.
+func (r Rope) Chunks() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		chunks(r.root, yield)
+	}
+}
+
+func chunks(n *node, yield func(string) bool) bool {
+	switch {
+	case n == nil:
+		return true
+	case n.left == nil:
+		return yield(n.text)
+	}
+	return chunks(n.left, yield) && chunks(n.right, yield)
+}
+
+// Runes returns an iterator over the runes in the rope and their byte
+// offsets, decoding runes split between chunks.  Invalid UTF-8 yields
+// utf8.RuneError for each byte, as ranging over a string does.
+func (r Rope) Runes() iter.Seq2[int, rune] {
+	return func(yield func(int, rune) bool) {
+		// pending holds the start of a rune cut off by the end of the
+		// previous chunk.
+		var pending []byte
+		offset := 0
+		for chunk := range r.Chunks() {
+			for len(chunk) > 0 {
+				if len(pending) > 0 {
+					pending = append(pending, chunk[0])
+					chunk = chunk[1:]
+					if !utf8.FullRune(pending) {
+						continue
+					}
+					c, size := utf8.DecodeRune(pending)
+					if !yield(offset, c) {
+						return
+					}
+					offset += size
+					// bytes after an invalid first byte are decoded
+					// afresh.
+					chunk = string(pending[size:]) + chunk
+					pending = pending[:0]
+					continue
+				}
+				if !utf8.FullRuneInString(chunk) {
+					pending = append(pending, chunk...)
+					break
+				}
+				c, size := utf8.DecodeRuneInString(chunk)
+				if !yield(offset, c) {
+					return
+				}
+				offset += size
+				chunk = chunk[size:]
+			}
+		}
+		for len(pending) > 0 {
+			c, size := utf8.DecodeRune(pending)
+			if !yield(offset, c) {
+				return
+			}
+			offset += size
+			pending = pending[size:]
+		}
+	}
+}
+
+// Reader returns a reader of the text of the rope.
+func (r Rope) Reader() io.Reader {
+	return &reader{stack: []*node{r.root}}
+}
+
+type reader struct {
+	// stack holds the subtrees left to read, the next on top, and chunk
+	// the rest of the leaf being read.
+	stack []*node
+	chunk string
+}
+
+func (rd *reader) Read(p []byte) (int, error) {
+	total := 0
+	for len(p) > 0 {
+		for rd.chunk == "" {
+			if len(rd.stack) == 0 {
+				if total == 0 {
+					return 0, io.EOF
+				}
+				return total, nil
+			}
+			n := rd.stack[len(rd.stack)-1]
+			rd.stack = rd.stack[:len(rd.stack)-1]
+			switch {
+			case n == nil:
+			case n.left == nil:
+				rd.chunk = n.text
+			default:
+				rd.stack = append(rd.stack, n.right, n.left)
+			}
+		}
+		copied := copy(p, rd.chunk)
+		rd.chunk = rd.chunk[copied:]
+		p = p[copied:]
+		total += copied
+	}
+	return total, nil
+}
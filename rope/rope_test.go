@@ -0,0 +1,190 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rope
+
+import (
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// check verifies the lengths and heights of the tree, and that it is
+// balanced like an AVL tree.
+func check(t *testing.T, n *node) {
+	if n == nil || n.left == nil {
+		if n != nil {
+			require.Equal(t, len(n.text), n.length)
+			require.Equal(t, 1, n.height)
+			require.NotEmpty(t, n.text)
+		}
+		return
+	}
+	check(t, n.left)
+	check(t, n.right)
+	require.Equal(t, n.left.length+n.right.length, n.length)
+	require.Equal(t, max(n.left.height, n.right.height)+1, n.height)
+	require.LessOrEqual(t, n.left.height-n.right.height, 1)
+	require.LessOrEqual(t, n.right.height-n.left.height, 1)
+}
+
+func TestRope(t *testing.T) {
+	var empty Rope
+	assert.Equal(t, 0, empty.Len())
+	assert.Equal(t, "", empty.String())
+
+	r := New("hello world")
+	r = r.Insert(5, ",").Insert(12, "!")
+	assert.Equal(t, "hello, world!", r.String())
+	assert.Equal(t, byte('w'), r.Index(7))
+	assert.Equal(t, "world", r.Slice(7, 12).String())
+	assert.Equal(t, "hello!", r.Delete(5, 12).String())
+	assert.Equal(t, "hello, world!hello, world!", r.Concat(r).String())
+
+	before, after := r.Split(6)
+	assert.Equal(t, "hello,", before.String())
+	assert.Equal(t, " world!", after.String())
+
+	assert.Panics(t, func() { r.Index(13) })
+	assert.Panics(t, func() { r.Split(14) })
+	assert.Panics(t, func() { r.Slice(3, 2) })
+	assert.Panics(t, func() { r.Delete(-1, 2) })
+}
+
+func TestLarge(t *testing.T) {
+	text := strings.Repeat("abcdefghij", 10000)
+	r := New(text)
+	check(t, r.root)
+	assert.Equal(t, len(text), r.Len())
+	assert.LessOrEqual(t, r.root.height, 10)
+
+	var count int
+	for chunk := range r.Chunks() {
+		assert.LessOrEqual(t, len(chunk), maxLeaf)
+		count++
+	}
+	assert.Equal(t, (len(text)+maxLeaf-1)/maxLeaf, count)
+}
+
+func TestRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(43))
+	alphabet := []string{"a", "b", "é", "世", "🙂"}
+	randomText := func() string {
+		var b strings.Builder
+		for i := rng.Intn(1 << rng.Intn(11)); i > 0; i-- {
+			b.WriteString(alphabet[rng.Intn(len(alphabet))])
+		}
+		return b.String()
+	}
+
+	r, expected := New(""), ""
+	for op := 0; op < 2000; op++ {
+		from := rng.Intn(len(expected) + 1)
+		to := from + rng.Intn(len(expected)-from+1)
+		switch rng.Intn(5) {
+		case 0:
+			r, expected = r.Delete(from, to), expected[:from]+expected[to:]
+		case 1:
+			slice := r.Slice(from, to)
+			check(t, slice.root)
+			require.Equal(t, expected[from:to], slice.String())
+		case 2:
+			text := randomText()
+			r, expected = r.Concat(New(text)), expected+text
+		default:
+			text := randomText()
+			r, expected = r.Insert(from, text), expected[:from]+text+expected[from:]
+		}
+		check(t, r.root)
+		require.Equal(t, len(expected), r.Len())
+		if op%50 == 0 {
+			require.Equal(t, expected, r.String())
+			if len(expected) > 0 {
+				i := rng.Intn(len(expected))
+				require.Equal(t, expected[i], r.Index(i))
+			}
+		}
+	}
+	assert.Equal(t, expected, r.String())
+}
+
+func TestRunes(t *testing.T) {
+	text := strings.Repeat("a世🙂é", 300) + "\xff\xe4\xb8" + "z"
+	// build the rope from short pieces, so that runes are cut between
+	// chunks.
+	var r Rope
+	for i := 0; i < len(text); i += 7 {
+		r = r.Concat(New(text[i:min(i+7, len(text))]))
+	}
+	require.Equal(t, text, r.String())
+	var cut int
+	for chunk := range r.Chunks() {
+		if !utf8.ValidString(chunk) {
+			cut++
+		}
+	}
+	require.Greater(t, cut, 1)
+
+	type decoded struct {
+		offset int
+		c      rune
+	}
+	var expected, actual []decoded
+	for i, c := range text {
+		expected = append(expected, decoded{i, c})
+	}
+	for i, c := range r.Runes() {
+		actual = append(actual, decoded{i, c})
+	}
+	assert.Equal(t, expected, actual)
+
+	var count int
+	for range r.Runes() {
+		count++
+		if count == 10 {
+			break
+		}
+	}
+	assert.Equal(t, 10, count)
+}
+
+func TestReader(t *testing.T) {
+	text := strings.Repeat("the quick brown fox ", 200)
+	r := New(text[:1000]).Concat(New(text[1000:]))
+	b, err := io.ReadAll(r.Reader())
+	require.NoError(t, err)
+	assert.Equal(t, text, string(b))
+	assert.NoError(t, iotest.TestReader(r.Reader(), []byte(text)))
+
+	b, err = io.ReadAll(Rope{}.Reader())
+	require.NoError(t, err)
+	assert.Empty(t, b)
+}
+
+func BenchmarkInsert(b *testing.B) {
+	r := New(strings.Repeat("x", 1<<20))
+	rng := rand.New(rand.NewSource(1))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r = r.Insert(rng.Intn(r.Len()), "y")
+	}
+}
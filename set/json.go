@@ -0,0 +1,51 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package set
+
+import (
+	"slices"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+// MarshalJSON encodes the set as a JSON array of its items, in no
+// particular order.
+func (s *Set[T]) MarshalJSON() ([]byte, error) {
+	return common.MarshalJSONArray(slices.Values(s.ToSlice()), common.DefaultJSONCodec[T]())
+}
+
+// UnmarshalJSON decodes a JSON array, adding its items to the set.
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+	err := common.UnmarshalJSONArray(data, common.DefaultJSONCodec[T](), func(item T) {
+		items = append(items, item)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.items == nil {
+		s.items = make(map[T]struct{}, len(items))
+	}
+	for _, item := range items {
+		s.items[item] = struct{}{}
+	}
+	s.flattened = nil
+	return nil
+}
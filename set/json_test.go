@@ -0,0 +1,46 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package set
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetJSON(t *testing.T) {
+	s := New(3, 1, 2)
+	b, err := json.Marshal(s)
+	require.NoError(t, err)
+
+	var items []int
+	require.NoError(t, json.Unmarshal(b, &items))
+	sort.Ints(items)
+	assert.Equal(t, []int{1, 2, 3}, items)
+
+	decoded := New(4)
+	require.NoError(t, json.Unmarshal(b, decoded))
+	assert.True(t, decoded.Equal(New(1, 2, 3, 4)))
+
+	// the zero value can be decoded into too
+	var zero Set[int]
+	require.NoError(t, json.Unmarshal(b, &zero))
+	assert.Equal(t, 3, zero.Len())
+}
@@ -35,6 +35,7 @@ Example usage:
 package set
 
 import (
+	"iter"
 	"sync"
 )
 
@@ -147,6 +148,20 @@ func (s *Set[T]) Clear() {
 	s.flattened = nil
 }
 
+// Values returns an iterator over the items in the set, in no
+// particular order.  It iterates a copy taken when iteration starts,
+// so the set may be modified meanwhile.  All, by contrast, reports
+// whether items are in the set.
+func (s *Set[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, item := range s.ToSlice() {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
 // All returns true if all of the supplied items exist in the set.
 func (s *Set[T]) All(items ...T) bool {
 	s.lock.RLock()
@@ -207,3 +207,16 @@ func TestSetWithStruct(t *testing.T) {
 	assert.True(t, s.Exists(point{1, 2}))
 	assert.False(t, s.Exists(point{1, 3}))
 }
+
+func TestValues(t *testing.T) {
+	s := New("a", "b", "c")
+	var values []string
+	for value := range s.Values() {
+		values = append(values, value)
+		// the set may be modified during iteration
+		s.Remove(value)
+	}
+	sort.Strings(values)
+	assert.Equal(t, []string{"a", "b", "c"}, values)
+	assert.Zero(t, s.Len())
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skip
+
+import "github.com/Workiva/go-datastructures/arena"
+
+// Arena allocates the nodes of one or more skiplists from chunks, so
+// building a large list creates a handful of allocations for the
+// garbage collector to track rather than three per entry.  Pass it to
+// New or NewWithCompareFunc with WithArena.  Nodes are not returned
+// to the arena when entries are deleted; Reset frees them all at once,
+// after which every list using the arena, including clones and the
+// right halves of splits, must no longer be used.  It is not safe for
+// concurrent use.
+type Arena[T any] struct {
+	nodes   arena.Arena[node[T]]
+	forward arena.Arena[*node[T]]
+	widths  arena.Arena[uint64]
+}
+
+// NewArena returns an empty arena that allocates chunks of chunkSize
+// nodes, or of 1024 if chunkSize is not positive.
+func NewArena[T any](chunkSize int) *Arena[T] {
+	return &Arena[T]{
+		nodes:   *arena.New[node[T]](chunkSize),
+		forward: *arena.New[*node[T]](chunkSize),
+		widths:  *arena.New[uint64](chunkSize),
+	}
+}
+
+// Reset frees every node allocated from the arena, keeping its chunks
+// to allocate from again.
+func (a *Arena[T]) Reset() {
+	a.nodes.Reset()
+	a.forward.Reset()
+	a.widths.Reset()
+}
+
+// Release drops the arena's chunks, returning their memory to the
+// runtime once no list built from them is referenced.
+func (a *Arena[T]) Release() {
+	a.nodes.Release()
+	a.forward.Release()
+	a.widths.Release()
+}
+
+// Option configures a skiplist created by New or NewWithCompareFunc.
+type Option[T any] func(*SkipList[T])
+
+// WithArena allocates the list's nodes from a rather than from the
+// heap.
+func WithArena[T any](a *Arena[T]) Option[T] {
+	return func(sl *SkipList[T]) {
+		sl.arena = a
+	}
+}
+
+// newNode is like the package's newNode, but allocates from the arena
+// unless it is nil.
+func (a *Arena[T]) newNode(cmp T, hasEntry bool, maxLevels uint8) *node[T] {
+	if a == nil {
+		return newNode(cmp, hasEntry, maxLevels)
+	}
+	n := a.nodes.New()
+	n.entry = cmp
+	n.hasEntry = hasEntry
+	n.forward = a.forward.Make(int(maxLevels))
+	n.widths = a.widths.Make(int(maxLevels))
+	return n
+}
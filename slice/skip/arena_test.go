@@ -0,0 +1,75 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skip
+
+import (
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArena(t *testing.T) {
+	a := NewArena[mockEntry](16)
+	sl := New[mockEntry](uint8(0), WithArena(a))
+	entries := generateRandomMockEntries(100)
+	sl.Insert(entries...)
+	sl.Delete(entries[:10]...)
+
+	expected := New[mockEntry](uint8(0))
+	expected.Insert(entries[10:]...)
+	assert.Equal(t, slices.Collect(expected.All()), slices.Collect(sl.All()))
+
+	left, right := sl.Clone().SplitAt(44)
+	assert.Equal(t, uint64(45), left.Len())
+	assert.Equal(t, uint64(45), right.Len())
+	assert.Equal(t, uint64(90), sl.Len())
+
+	a.Reset()
+	sl = New[mockEntry](uint8(0), WithArena(a))
+	sl.Insert(entries...)
+	assert.Equal(t, uint64(100), sl.Len())
+	assert.True(t, slices.IsSorted(slices.Collect(sl.All())))
+}
+
+// BenchmarkBuildAndDiscard builds and discards a list of 10,000
+// entries, reporting the garbage collections it causes with and
+// without an arena.
+func BenchmarkBuildAndDiscard(b *testing.B) {
+	entries := generateRandomMockEntries(10000)
+	build := func(b *testing.B, opts func() []Option[mockEntry], reset func()) {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		b.ReportAllocs()
+		for b.Loop() {
+			sl := New[mockEntry](uint64(0), opts()...)
+			sl.Insert(entries...)
+			reset()
+		}
+		runtime.ReadMemStats(&after)
+		b.ReportMetric(float64(after.NumGC-before.NumGC)/float64(b.N), "gcs/op")
+	}
+
+	b.Run("heap", func(b *testing.B) {
+		build(b, func() []Option[mockEntry] { return nil }, func() {})
+	})
+	b.Run("arena", func(b *testing.B) {
+		a := NewArena[mockEntry](4096)
+		build(b, func() []Option[mockEntry] { return []Option[mockEntry]{WithArena(a)} }, a.Reset)
+	})
+}
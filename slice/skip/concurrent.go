@@ -0,0 +1,326 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skip
+
+import (
+	"iter"
+	"math/bits"
+	"math/rand/v2"
+	"runtime"
+	"sync/atomic"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+// concurrentMaxLevel is the number of levels in a ConcurrentMap, enough
+// for 2^32 entries at p = .5.
+const concurrentMaxLevel = 32
+
+// ref is a link from a node to its successor on one level, along with
+// the node's mark for removal on that level.  Links are replaced
+// rather than modified, so that the successor and the mark are swapped
+// together in a single compare-and-swap.
+type ref[K, V any] struct {
+	node   *mapNode[K, V]
+	marked bool
+}
+
+// mapNode holds an entry.  Its value is nil once a Delete has claimed
+// it, after which the entry is absent though the node may still be
+// linked.
+type mapNode[K, V any] struct {
+	key   K
+	value atomic.Pointer[V]
+	next  []atomic.Pointer[ref[K, V]]
+}
+
+func newMapNode[K, V any](key K, level int) *mapNode[K, V] {
+	return &mapNode[K, V]{key: key, next: make([]atomic.Pointer[ref[K, V]], level)}
+}
+
+// ConcurrentMap is an ordered map that is safe for concurrent use
+// without locks, the equivalent of Java's ConcurrentSkipListMap.  It is
+// a lock-free skip list after Herlihy and Shavit: entries are removed
+// by first claiming their value, then marking their links, top level
+// down, so that no link can be added after them, and marked entries
+// are unlinked by whichever operation next passes them.  Gets never
+// retry, and Puts and Deletes only retry when they race another write
+// next to the same entry.
+//
+// Iteration is weakly consistent: it never yields an entry twice or
+// out of order, and sees every entry that is present throughout, but
+// may or may not see changes made while it runs.
+type ConcurrentMap[K, V any] struct {
+	compare common.CompareFunc[K]
+	head    *mapNode[K, V]
+	len     atomic.Int64
+}
+
+// NewConcurrentMap returns an empty ConcurrentMap of keys in their
+// natural order.
+func NewConcurrentMap[K common.Ordered, V any]() *ConcurrentMap[K, V] {
+	return NewConcurrentMapWithCompareFunc[K, V](common.OrderedCompare[K]())
+}
+
+// NewConcurrentMapWithCompareFunc returns an empty ConcurrentMap of keys
+// ordered by compare.
+func NewConcurrentMapWithCompareFunc[K, V any](compare common.CompareFunc[K]) *ConcurrentMap[K, V] {
+	var zero K
+	head := newMapNode[K, V](zero, concurrentMaxLevel)
+	for i := range head.next {
+		head.next[i].Store(&ref[K, V]{})
+	}
+	return &ConcurrentMap[K, V]{compare: compare, head: head}
+}
+
+// randomLevel returns the number of levels for a new node, each level
+// above the first being half as likely as the one below.
+func randomLevel() int {
+	return min(bits.TrailingZeros64(rand.Uint64())+1, concurrentMaxLevel)
+}
+
+// find fills preds and succs with the nodes either side of key on
+// every level, unlinking marked nodes on the way, and returns whether
+// succs[0] holds key.
+func (m *ConcurrentMap[K, V]) find(key K, preds, succs []*mapNode[K, V]) bool {
+retry:
+	pred := m.head
+	for level := concurrentMaxLevel - 1; level >= 0; level-- {
+		predRef := pred.next[level].Load()
+		if predRef.marked {
+			goto retry
+		}
+		curr := predRef.node
+		for curr != nil {
+			currRef := curr.next[level].Load()
+			for currRef.marked {
+				unlinked := &ref[K, V]{node: currRef.node}
+				if !pred.next[level].CompareAndSwap(predRef, unlinked) {
+					goto retry
+				}
+				predRef = unlinked
+				curr = currRef.node
+				if curr == nil {
+					break
+				}
+				currRef = curr.next[level].Load()
+			}
+			if curr == nil || m.compare(curr.key, key) >= 0 {
+				break
+			}
+			pred, predRef, curr = curr, currRef, currRef.node
+		}
+		preds[level], succs[level] = pred, curr
+	}
+	return succs[0] != nil && m.compare(succs[0].key, key) == 0
+}
+
+// search returns the first node whose key is at least key and that is
+// not marked for removal, without modifying the list.
+func (m *ConcurrentMap[K, V]) search(key K) *mapNode[K, V] {
+	pred := m.head
+	var curr *mapNode[K, V]
+	for level := concurrentMaxLevel - 1; level >= 0; level-- {
+		curr = pred.next[level].Load().node
+		for curr != nil {
+			currRef := curr.next[level].Load()
+			if currRef.marked {
+				curr = currRef.node
+				continue
+			}
+			if m.compare(curr.key, key) >= 0 {
+				break
+			}
+			pred, curr = curr, currRef.node
+		}
+	}
+	return curr
+}
+
+// Get returns the value of key and whether the map holds it.
+func (m *ConcurrentMap[K, V]) Get(key K) (V, bool) {
+	n := m.search(key)
+	if n != nil && m.compare(n.key, key) == 0 {
+		if value := n.value.Load(); value != nil {
+			return *value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Put sets the value of key, returning the value it replaced and
+// whether there was one.
+func (m *ConcurrentMap[K, V]) Put(key K, value V) (V, bool) {
+	var preds, succs [concurrentMaxLevel]*mapNode[K, V]
+	level := randomLevel()
+	for {
+		if m.find(key, preds[:], succs[:]) {
+			n := succs[0]
+			old := n.value.Load()
+			if old == nil {
+				// claimed by a Delete, so wait for it to be unlinked
+				runtime.Gosched()
+				continue
+			}
+			if !n.value.CompareAndSwap(old, &value) {
+				continue
+			}
+			return *old, true
+		}
+
+		n := newMapNode[K, V](key, level)
+		n.value.Store(&value)
+		for i := range level {
+			n.next[i].Store(&ref[K, V]{node: succs[i]})
+		}
+
+		// the node is in the map once it is linked on the bottom level
+		pred := preds[0]
+		predRef := pred.next[0].Load()
+		if predRef.marked || predRef.node != succs[0] ||
+			!pred.next[0].CompareAndSwap(predRef, &ref[K, V]{node: n}) {
+			continue
+		}
+		m.len.Add(1)
+		m.link(n, level, preds[:], succs[:])
+		var zero V
+		return zero, false
+	}
+}
+
+// link adds n to the levels above the bottom, giving up if it is
+// removed meanwhile.
+func (m *ConcurrentMap[K, V]) link(n *mapNode[K, V], level int, preds, succs []*mapNode[K, V]) {
+	for i := 1; i < level; i++ {
+		for {
+			nRef := n.next[i].Load()
+			if nRef.marked {
+				return
+			}
+			if nRef.node != succs[i] && !n.next[i].CompareAndSwap(nRef, &ref[K, V]{node: succs[i]}) {
+				continue
+			}
+
+			pred := preds[i]
+			predRef := pred.next[i].Load()
+			if !predRef.marked && predRef.node == succs[i] &&
+				pred.next[i].CompareAndSwap(predRef, &ref[K, V]{node: n}) {
+				break
+			}
+			if !m.find(n.key, preds, succs) || succs[0] != n {
+				// removed, and perhaps replaced by a new entry
+				return
+			}
+		}
+	}
+}
+
+// Delete removes key, returning its value and whether the map held it.
+func (m *ConcurrentMap[K, V]) Delete(key K) (V, bool) {
+	var preds, succs [concurrentMaxLevel]*mapNode[K, V]
+	var zero V
+	if !m.find(key, preds[:], succs[:]) {
+		return zero, false
+	}
+	n := succs[0]
+
+	// whoever claims the value removes the entry, so a Put replacing
+	// the value either lands before the claim or sees it and retries
+	var value *V
+	for {
+		value = n.value.Load()
+		if value == nil {
+			return zero, false
+		}
+		if n.value.CompareAndSwap(value, nil) {
+			break
+		}
+	}
+
+	for i := len(n.next) - 1; i >= 0; i-- {
+		for {
+			nRef := n.next[i].Load()
+			if nRef.marked || n.next[i].CompareAndSwap(nRef, &ref[K, V]{node: nRef.node, marked: true}) {
+				break
+			}
+		}
+	}
+	m.len.Add(-1)
+	m.find(key, preds[:], succs[:])
+	return *value, true
+}
+
+// Len returns the number of entries in the map.  With concurrent
+// writers it is only a moment's estimate.
+func (m *ConcurrentMap[K, V]) Len() int {
+	return int(m.len.Load())
+}
+
+// All returns a weakly consistent iterator over the entries in
+// ascending order of key.
+func (m *ConcurrentMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.iterate(m.head.next[0].Load().node, nil)(yield)
+	}
+}
+
+// Range returns a weakly consistent iterator over the entries with keys
+// from start, inclusive, to stop, exclusive, in ascending order.
+func (m *ConcurrentMap[K, V]) Range(start, stop K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.iterate(m.search(start), &stop)(yield)
+	}
+}
+
+func (m *ConcurrentMap[K, V]) iterate(n *mapNode[K, V], stop *K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for ; n != nil; n = n.next[0].Load().node {
+			if stop != nil && m.compare(n.key, *stop) >= 0 {
+				return
+			}
+			value := n.value.Load()
+			if value == nil || n.next[0].Load().marked {
+				continue
+			}
+			if !yield(n.key, *value) {
+				return
+			}
+		}
+	}
+}
+
+// Snapshot copies the entries into a slice, in ascending order of key,
+// in a single weakly consistent pass, and returns an iterator over the
+// copy that later writes cannot affect.
+func (m *ConcurrentMap[K, V]) Snapshot() iter.Seq2[K, V] {
+	type entry struct {
+		key   K
+		value V
+	}
+	var entries []entry
+	for k, v := range m.All() {
+		entries = append(entries, entry{k, v})
+	}
+	return func(yield func(K, V) bool) {
+		for _, e := range entries {
+			if !yield(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,269 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skip
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func collectMap[K comparable, V any](seq func(func(K, V) bool)) ([]K, map[K]V) {
+	var keys []K
+	values := map[K]V{}
+	for k, v := range seq {
+		keys = append(keys, k)
+		values[k] = v
+	}
+	return keys, values
+}
+
+func TestConcurrentMap(t *testing.T) {
+	m := NewConcurrentMap[int, string]()
+	_, ok := m.Get(1)
+	assert.False(t, ok)
+
+	for _, k := range []int{5, 1, 9, 3, 7} {
+		_, replaced := m.Put(k, `v`)
+		assert.False(t, replaced)
+	}
+	old, replaced := m.Put(3, `three`)
+	assert.True(t, replaced)
+	assert.Equal(t, `v`, old)
+	assert.Equal(t, 5, m.Len())
+
+	value, ok := m.Get(3)
+	assert.True(t, ok)
+	assert.Equal(t, `three`, value)
+
+	keys, _ := collectMap(m.All())
+	assert.Equal(t, []int{1, 3, 5, 7, 9}, keys)
+	keys, _ = collectMap(m.Range(2, 7))
+	assert.Equal(t, []int{3, 5}, keys)
+	keys, _ = collectMap(m.Range(10, 20))
+	assert.Nil(t, keys)
+
+	value, ok = m.Delete(3)
+	assert.True(t, ok)
+	assert.Equal(t, `three`, value)
+	_, ok = m.Delete(3)
+	assert.False(t, ok)
+	_, ok = m.Get(3)
+	assert.False(t, ok)
+	assert.Equal(t, 4, m.Len())
+
+	snapshot := m.Snapshot()
+	m.Put(4, `four`)
+	m.Delete(1)
+	keys, _ = collectMap(snapshot)
+	assert.Equal(t, []int{1, 5, 7, 9}, keys)
+	keys, _ = collectMap(m.All())
+	assert.Equal(t, []int{4, 5, 7, 9}, keys)
+}
+
+func TestConcurrentMapCompareFunc(t *testing.T) {
+	m := NewConcurrentMapWithCompareFunc[int, int](func(a, b int) int { return b - a })
+	for i := range 10 {
+		m.Put(i, i)
+	}
+	keys, _ := collectMap(m.All())
+	assert.Equal(t, []int{9, 8, 7, 6, 5, 4, 3, 2, 1, 0}, keys)
+}
+
+func TestConcurrentMapMatchesMap(t *testing.T) {
+	m := NewConcurrentMap[int, int]()
+	expected := map[int]int{}
+	for i := range 10000 {
+		k := rand.Intn(500)
+		if rand.Intn(3) == 0 {
+			_, ok := m.Delete(k)
+			_, had := expected[k]
+			assert.Equal(t, had, ok)
+			delete(expected, k)
+		} else {
+			m.Put(k, i)
+			expected[k] = i
+		}
+	}
+
+	keys, values := collectMap(m.All())
+	assert.Equal(t, expected, values)
+	assert.Equal(t, len(expected), m.Len())
+	for i := 1; i < len(keys); i++ {
+		assert.Less(t, keys[i-1], keys[i])
+	}
+}
+
+func TestConcurrentMapConcurrentWriters(t *testing.T) {
+	m := NewConcurrentMap[int, int]()
+	var wg sync.WaitGroup
+	// each writer owns the keys equal to its number mod 8, so the
+	// final contents are known, while all of them contend for links
+	for w := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 2000 {
+				k := (i%250)*8 + w
+				if i%3 == 2 {
+					m.Delete(k)
+				} else {
+					m.Put(k, i)
+				}
+			}
+		}()
+	}
+	// readers iterate meanwhile, and must always see ascending keys
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				last := -1
+				for k := range m.All() {
+					assert.Less(t, last, k)
+					last = k
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	expected := map[int]int{}
+	for w := range 8 {
+		for i := range 2000 {
+			k := (i%250)*8 + w
+			if i%3 == 2 {
+				delete(expected, k)
+			} else {
+				expected[k] = i
+			}
+		}
+	}
+	_, values := collectMap(m.All())
+	assert.Equal(t, expected, values)
+	assert.Equal(t, len(expected), m.Len())
+}
+
+func TestConcurrentMapContendedKeys(t *testing.T) {
+	m := NewConcurrentMap[int, int]()
+	var puts, deletes [8]int
+	var wg sync.WaitGroup
+	for w := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(w)))
+			for range 5000 {
+				k := r.Intn(16)
+				if r.Intn(2) == 0 {
+					if _, replaced := m.Put(k, w); !replaced {
+						puts[w]++
+					}
+				} else if _, ok := m.Delete(k); ok {
+					deletes[w]++
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// every entry added was either removed or is still there
+	added, removed := 0, 0
+	for w := range 8 {
+		added += puts[w]
+		removed += deletes[w]
+	}
+	keys, _ := collectMap(m.All())
+	assert.Equal(t, added-removed, len(keys))
+	assert.Equal(t, len(keys), m.Len())
+}
+
+func TestConcurrentMapConservesValues(t *testing.T) {
+	m := NewConcurrentMap[int, int]()
+	const writers, ops = 8, 5000
+	// every value put is unique, and must come back exactly once: as
+	// the value a Put replaced, from a Delete, or left in the map
+	var returned [writers][]int
+	var wg sync.WaitGroup
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(int64(w)))
+			for i := range ops {
+				k := r.Intn(4)
+				if r.Intn(2) == 0 {
+					if old, replaced := m.Put(k, w*ops+i); replaced {
+						returned[w] = append(returned[w], old)
+					}
+				} else if old, ok := m.Delete(k); ok {
+					returned[w] = append(returned[w], old)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	seen := map[int]int{}
+	for w := range writers {
+		for _, v := range returned[w] {
+			seen[v]++
+		}
+	}
+	for _, v := range m.All() {
+		seen[v]++
+	}
+	put := 0
+	for w := range writers {
+		r := rand.New(rand.NewSource(int64(w)))
+		for i := range ops {
+			r.Intn(4)
+			if r.Intn(2) == 0 {
+				put++
+				assert.Equal(t, 1, seen[w*ops+i], "value %d", w*ops+i)
+			}
+		}
+	}
+	assert.Equal(t, put, len(seen))
+}
+
+func BenchmarkConcurrentMapPut(b *testing.B) {
+	m := NewConcurrentMap[int, int]()
+	b.RunParallel(func(pb *testing.PB) {
+		r := rand.New(rand.NewSource(rand.Int63()))
+		for pb.Next() {
+			m.Put(r.Intn(1<<16), 0)
+		}
+	})
+}
+
+func BenchmarkConcurrentMapGet(b *testing.B) {
+	m := NewConcurrentMap[int, int]()
+	for i := range 1 << 16 {
+		m.Put(i, i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		r := rand.New(rand.NewSource(rand.Int63()))
+		for pb.Next() {
+			m.Get(r.Intn(1 << 16))
+		}
+	})
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skip
+
+import "github.com/Workiva/go-datastructures/codec"
+
+// MarshalBinary encodes the skiplist's entries, in order, with the
+// codec package's encoding for their type.
+func (sl *SkipList[T]) MarshalBinary() ([]byte, error) {
+	entries, err := codec.For[T]()
+	if err != nil {
+		return nil, err
+	}
+	return codec.MarshalSeq(sl.All(), entries)
+}
+
+// UnmarshalBinary decodes entries encoded by MarshalBinary, inserting
+// them into the skiplist, which must have been created with New or
+// NewWithCompareFunc so that it knows how to order them.  Entries equal
+// to ones already in the list replace them.  The list is unchanged if
+// the data is invalid.
+func (sl *SkipList[T]) UnmarshalBinary(data []byte) error {
+	if sl.compare == nil {
+		return errUnmarshalUninitialized
+	}
+	entries, err := codec.For[T]()
+	if err != nil {
+		return err
+	}
+	return codec.UnmarshalSeq(data, entries, func(entry T) {
+		sl.insert(entry)
+	})
+}
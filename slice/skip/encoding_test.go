@@ -0,0 +1,47 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Workiva/go-datastructures/codec"
+	"github.com/Workiva/go-datastructures/common"
+)
+
+func TestSkipListBinary(t *testing.T) {
+	sl := NewWithCompareFunc(common.OrderedCompare[string](), uint64(0))
+	sl.Insert(`c`, `a`, `b`)
+	data, err := sl.MarshalBinary()
+	require.NoError(t, err)
+
+	decoded := NewWithCompareFunc(common.OrderedCompare[string](), uint64(0))
+	decoded.Insert(`d`)
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, uint64(4), decoded.Len())
+	assert.Equal(t, []string{`a`, `b`, `c`, `d`}, decoded.IterAtPosition(0).(*iterator[string]).exhaust())
+
+	assert.Error(t, decoded.UnmarshalBinary(data[:len(data)-1]))
+	assert.Equal(t, uint64(4), decoded.Len())
+	assert.Equal(t, errUnmarshalUninitialized, (&SkipList[string]{}).UnmarshalBinary(data))
+
+	_, err = NewWithCompareFunc(func(a, b *int) int { return *a - *b }, uint64(0)).MarshalBinary()
+	assert.Equal(t, codec.ErrUnsupported, err)
+}
@@ -23,20 +23,11 @@ import "github.com/Workiva/go-datastructures/common"
 //   - negative value if receiver < other
 //   - zero if receiver == other
 //   - positive value if receiver > other
-type Comparable[T any] interface {
-	Compare(other T) int
-}
+type Comparable[T any] = common.ComparableItem[T]
 
-// Iterator defines a generic interface that allows a consumer to iterate
-// all results of a query. All values will be visited in-order.
-type Iterator[T any] interface {
-	// Next returns a bool indicating if there is future value
-	// in the iterator and moves the iterator to that value.
-	Next() bool
-	// Value returns a value representing the iterator's current
-	// position. Returns zero value if exhausted.
-	Value() T
-}
+// Iterator allows a consumer to iterate all results of a query. All
+// values will be visited in-order.
+type Iterator[T any] = common.Iterator[T]
 
 // ComparatorWrapper wraps common.Comparator to implement Comparable[ComparatorWrapper].
 // This allows using the generic SkipList with the old common.Comparator interface.
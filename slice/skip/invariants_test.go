@@ -0,0 +1,115 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skip
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Workiva/go-datastructures/common"
+	"github.com/Workiva/go-datastructures/internal/check"
+)
+
+// checkInvariants returns an error if the skiplist's links or widths
+// are inconsistent or its entries are out of order.
+func checkInvariants[T any](sl *SkipList[T]) error {
+	positions := map[*node[T]]uint64{sl.head: 0}
+	var count uint64
+	for n := sl.head.forward[0]; n != nil; n = n.forward[0] {
+		count++
+		if !n.hasEntry {
+			return check.Errorf(`node %d has no entry`, count)
+		}
+		positions[n] = count
+	}
+	if count != sl.Len() {
+		return check.Errorf(`%d nodes in a list of length %d`, count, sl.Len())
+	}
+
+	for level := range len(sl.head.forward) {
+		if level >= int(sl.level) && sl.head.forward[level] != nil {
+			return check.Errorf(`level %d is linked above the list's level %d`, level, sl.level)
+		}
+		for n := sl.head; n.forward[level] != nil; n = n.forward[level] {
+			next := n.forward[level]
+			pos, ok := positions[next]
+			switch {
+			case !ok:
+				return check.Errorf(`level %d links to a node missing from level 0`, level)
+			case pos <= positions[n]:
+				return check.Errorf(`level %d links backward from position %d to %d`, level, positions[n], pos)
+			case len(next.forward) <= level:
+				return check.Errorf(`level %d links to position %d of height %d`, level, pos, len(next.forward))
+			case n.widths[level] != pos-positions[n]:
+				return check.Errorf(`level %d width from position %d is %d, but the next node is %d on`,
+					level, positions[n], n.widths[level], pos-positions[n])
+			}
+		}
+	}
+
+	return check.Sorted(sl.All(), sl.compare, true)
+}
+
+func TestInvariants(t *testing.T) {
+	sl := NewWithCompareFunc(common.OrderedCompare[int](), uint8(0))
+	require.NoError(t, checkInvariants(sl))
+	for i := range 1000 {
+		sl.Insert((i * 7919) % 1000)
+	}
+	require.NoError(t, checkInvariants(sl))
+	for i := range 500 {
+		sl.Delete(i * 2)
+	}
+	require.NoError(t, checkInvariants(sl))
+
+	sl.head.forward[0].forward[0], sl.head.forward[0] = nil, sl.head.forward[0].forward[0]
+	assert.Error(t, checkInvariants(sl))
+}
+
+func FuzzSkipList(f *testing.F) {
+	f.Add([]byte{0, 1, 0, 2, 0, 3, 1, 2, 2, 3, 3, 1})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		sl := NewWithCompareFunc(common.OrderedCompare[int](), uint8(0))
+		model := map[int]bool{}
+		for op := range check.Ops(data, 4) {
+			switch op.Kind {
+			case 0:
+				sl.Insert(op.Key)
+				model[op.Key] = true
+			case 1:
+				_, deleted := sl.Delete(op.Key)
+				assert.Equal(t, model[op.Key], deleted[0])
+				delete(model, op.Key)
+			case 2:
+				_, found := sl.Get(op.Key)
+				assert.Equal(t, model[op.Key], found[0])
+			case 3:
+				keys := slices.Sorted(maps.Keys(model))
+				entry, ok := sl.ByPosition(uint64(op.Key))
+				if assert.Equal(t, op.Key < len(keys), ok) && ok {
+					assert.Equal(t, keys[op.Key], entry)
+				}
+			}
+			require.NoError(t, checkInvariants(sl))
+		}
+		assert.Equal(t, slices.Sorted(maps.Keys(model)), slices.Collect(sl.All()))
+	})
+}
@@ -21,7 +21,7 @@ const iteratorExhausted = -2
 // iterator represents an object that can be iterated. It will
 // return false on Next and zero value on Value if there are no further
 // values to be iterated.
-type iterator[T Comparable[T]] struct {
+type iterator[T any] struct {
 	first bool
 	n     *node[T]
 }
@@ -66,6 +66,6 @@ func (iter *iterator[T]) exhaust() []T {
 
 // nilIterator returns an iterator that will always return false
 // for Next and zero value for Value.
-func nilIterator[T Comparable[T]]() *iterator[T] {
+func nilIterator[T any]() *iterator[T] {
 	return &iterator[T]{}
 }
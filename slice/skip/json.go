@@ -0,0 +1,44 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skip
+
+import (
+	"errors"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+var errUnmarshalUninitialized = errors.New(`skip: cannot unmarshal into a SkipList not created with New`)
+
+// MarshalJSON encodes the skiplist as a JSON array of its entries, in
+// order.
+func (sl *SkipList[T]) MarshalJSON() ([]byte, error) {
+	return common.MarshalJSONArray(sl.All(), common.DefaultJSONCodec[T]())
+}
+
+// UnmarshalJSON decodes a JSON array, inserting its entries into the
+// skiplist, which must have been created with New or
+// NewWithCompareFunc so that it knows how to order them.  Entries equal
+// to ones already in the list replace them.
+func (sl *SkipList[T]) UnmarshalJSON(data []byte) error {
+	if sl.compare == nil {
+		return errUnmarshalUninitialized
+	}
+	return common.UnmarshalJSONArray(data, common.DefaultJSONCodec[T](), func(entry T) {
+		sl.insert(entry)
+	})
+}
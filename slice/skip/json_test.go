@@ -0,0 +1,46 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skip
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+func TestSkipListJSON(t *testing.T) {
+	sl := NewWithCompareFunc(common.OrderedCompare[int](), uint64(0))
+	sl.Insert(5, 1, 3)
+	b, err := json.Marshal(sl)
+	require.NoError(t, err)
+	assert.Equal(t, `[1,3,5]`, string(b))
+
+	decoded := NewWithCompareFunc(common.OrderedCompare[int](), uint64(0))
+	decoded.Insert(2)
+	require.NoError(t, json.Unmarshal(b, decoded))
+	assert.Equal(t, uint64(4), decoded.Len())
+	assert.Equal(t, []int{1, 2, 3, 5}, decoded.IterAtPosition(0).(*iterator[int]).exhaust())
+
+	assert.Error(t, json.Unmarshal(b, &SkipList[int]{}))
+	b, err = json.Marshal(&SkipList[int]{})
+	require.NoError(t, err)
+	assert.Equal(t, `[]`, string(b))
+}
@@ -18,9 +18,9 @@ package skip
 
 type widths []uint64
 
-type nodes[T Comparable[T]] []*node[T]
+type nodes[T any] []*node[T]
 
-type node[T Comparable[T]] struct {
+type node[T any] struct {
 	// forward denotes the forward pointing pointers in this
 	// node.
 	forward nodes[T]
@@ -33,14 +33,10 @@ type node[T Comparable[T]] struct {
 	hasEntry bool // needed since T might not be nillable
 }
 
-func (n *node[T]) Compare(e T) int {
-	return n.entry.Compare(e)
-}
-
 // newNode will allocate and return a new node with the entry
 // provided. maxLevels will determine the length of the forward
 // pointer list associated with this node.
-func newNode[T Comparable[T]](cmp T, hasEntry bool, maxLevels uint8) *node[T] {
+func newNode[T any](cmp T, hasEntry bool, maxLevels uint8) *node[T] {
 	return &node[T]{
 		entry:    cmp,
 		hasEntry: hasEntry,
@@ -48,3 +44,16 @@ func newNode[T Comparable[T]](cmp T, hasEntry bool, maxLevels uint8) *node[T] {
 		widths:   make(widths, maxLevels),
 	}
 }
+
+// clone returns an unlinked copy of this node with the same level and
+// widths, allocated from a, copying the entry with copyFn if it is not
+// nil.
+func (n *node[T]) clone(a *Arena[T], copyFn func(T) T) *node[T] {
+	entry := n.entry
+	if n.hasEntry && copyFn != nil {
+		entry = copyFn(entry)
+	}
+	nn := a.newNode(entry, n.hasEntry, uint8(len(n.forward)))
+	copy(nn.widths, n.widths)
+	return nn
+}
@@ -67,10 +67,14 @@ interface dispatch and get better performance with concrete types.
 package skip
 
 import (
+	"iter"
 	"math/rand"
 	"sync"
 	"sync/atomic"
 	"time"
+	"unsafe"
+
+	"github.com/Workiva/go-datastructures/common"
 )
 
 const p = .5 // the p level defines the probability that a node
@@ -121,9 +125,9 @@ func generateLevel(maxLevel uint8) uint8 {
 	return level
 }
 
-func insertNode[T Comparable[T]](sl *SkipList[T], n *node[T], cmp T, pos uint64, cache nodes[T], posCache widths, allowDuplicate bool) (T, bool) {
+func insertNode[T any](sl *SkipList[T], n *node[T], cmp T, pos uint64, cache nodes[T], posCache widths, allowDuplicate bool) (T, bool) {
 	var zero T
-	if !allowDuplicate && n != nil && n.hasEntry && n.Compare(cmp) == 0 { // a simple update in this case
+	if !allowDuplicate && n != nil && n.hasEntry && sl.compare(n.entry, cmp) == 0 { // a simple update in this case
 		oldEntry := n.entry
 		n.entry = cmp
 		return oldEntry, true
@@ -138,7 +142,7 @@ func insertNode[T Comparable[T]](sl *SkipList[T], n *node[T], cmp T, pos uint64,
 		sl.level = nodeLevel
 	}
 
-	nn := newNode(cmp, true, nodeLevel)
+	nn := sl.arena.newNode(cmp, true, nodeLevel)
 	for i := range nodeLevel {
 		nn.forward[i] = cache[i].forward[i]
 		cache[i].forward[i] = nn
@@ -163,14 +167,14 @@ func insertNode[T Comparable[T]](sl *SkipList[T], n *node[T], cmp T, pos uint64,
 	return zero, false
 }
 
-func splitAt[T Comparable[T]](sl *SkipList[T], index uint64) (*SkipList[T], *SkipList[T]) {
+func splitAt[T any](sl *SkipList[T], index uint64) (*SkipList[T], *SkipList[T]) {
 	var zero T
-	right := &SkipList[T]{}
+	right := &SkipList[T]{compare: sl.compare, arena: sl.arena}
 	right.maxLevel = sl.maxLevel
 	right.level = sl.level
 	right.cache = make(nodes[T], sl.maxLevel)
 	right.posCache = make(widths, sl.maxLevel)
-	right.head = newNode(zero, false, sl.maxLevel)
+	right.head = sl.arena.newNode(zero, false, sl.maxLevel)
 	sl.searchByPosition(index, sl.cache, sl.posCache) // populate the cache that needs updating
 
 	for i := uint8(0); i <= sl.level; i++ {
@@ -195,7 +199,8 @@ func splitAt[T Comparable[T]](sl *SkipList[T], index uint64) (*SkipList[T], *Ski
 // relationships between nodes. This results in a structure
 // that performs similarly to a BST but is much easier to build
 // from a programmatic perspective (no rotations).
-type SkipList[T Comparable[T]] struct {
+type SkipList[T any] struct {
+	compare         common.CompareFunc[T]
 	maxLevel, level uint8
 	head            *node[T]
 	num             uint64
@@ -203,6 +208,7 @@ type SkipList[T Comparable[T]] struct {
 	// the number of allocations in the insert/delete case.
 	cache    nodes[T]
 	posCache widths
+	arena    *Arena[T]
 }
 
 // init will initialize this skiplist. The parameter is expected
@@ -222,7 +228,7 @@ func (sl *SkipList[T]) init(ifc any) {
 	var zero T
 	sl.cache = make(nodes[T], sl.maxLevel)
 	sl.posCache = make(widths, sl.maxLevel)
-	sl.head = newNode(zero, false, sl.maxLevel)
+	sl.head = sl.arena.newNode(zero, false, sl.maxLevel)
 }
 
 func (sl *SkipList[T]) search(cmp T, update nodes[T], widthCache widths) (*node[T], uint64) {
@@ -236,7 +242,7 @@ func (sl *SkipList[T]) search(cmp T, update nodes[T], widthCache widths) (*node[
 	n := sl.head
 	for i := uint8(0); i <= sl.level; i++ {
 		offset = sl.level - i
-		for n.forward[offset] != nil && n.forward[offset] != alreadyChecked && n.forward[offset].hasEntry && n.forward[offset].Compare(cmp) < 0 {
+		for n.forward[offset] != nil && n.forward[offset] != alreadyChecked && n.forward[offset].hasEntry && sl.compare(n.forward[offset].entry, cmp) < 0 {
 			pos += n.widths[offset]
 			n = n.forward[offset]
 		}
@@ -299,7 +305,7 @@ func (sl *SkipList[T]) Get(comparators ...T) ([]T, []bool) {
 	var n *node[T]
 	for i, cmp := range comparators {
 		n, _ = sl.search(cmp, nil, nil)
-		if n != nil && n.hasEntry && n.Compare(cmp) == 0 {
+		if n != nil && n.hasEntry && sl.compare(n.entry, cmp) == 0 {
 			results[i] = n.entry
 			found[i] = true
 		}
@@ -386,7 +392,7 @@ func (sl *SkipList[T]) ReplaceAtPosition(position uint64, cmp T) {
 func (sl *SkipList[T]) delete(cmp T) (T, bool) {
 	n, _ := sl.search(cmp, sl.cache, sl.posCache)
 
-	if n == nil || !n.hasEntry || n.Compare(cmp) != 0 {
+	if n == nil || !n.hasEntry || sl.compare(n.entry, cmp) != 0 {
 		var zero T
 		return zero, false
 	}
@@ -470,6 +476,39 @@ func (sl *SkipList[T]) Iter(cmp T) Iterator[T] {
 	return sl.iter(cmp)
 }
 
+// All returns an iterator over the entries in the skiplist, in
+// order.  The list must not be modified during iteration.
+func (sl *SkipList[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if sl.head == nil {
+			return
+		}
+		it := sl.iterAtPosition(1)
+		for it.Next() {
+			if !yield(it.Value()) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the entries in the skiplist in
+// reverse order.  Nodes only link forward, so each step is a search
+// by position and iterating the whole list is O(n log n).
+func (sl *SkipList[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if sl.head == nil {
+			return
+		}
+		for position := sl.Len(); position > 0; position-- {
+			entry, ok := sl.ByPosition(position - 1)
+			if !ok || !yield(entry) {
+				return
+			}
+		}
+	}
+}
+
 // SplitAt will split the current skiplist into two lists. The first
 // skiplist returned is the "left" list and the second is the "right."
 // The index defines the last item in the left list. If index is greater
@@ -484,13 +523,87 @@ func (sl *SkipList[T]) SplitAt(index uint64) (*SkipList[T], *SkipList[T]) {
 	return splitAt(sl, index)
 }
 
+// Clone returns a copy of the skiplist that can be modified
+// independently.  The copy keeps the shape of the original, so this
+// is O(n) rather than the O(n log n) of inserting each entry again.
+// Entries are copied as they are, so pointers are shared.
+func (sl *SkipList[T]) Clone() *SkipList[T] {
+	return sl.CloneWith(nil)
+}
+
+// CloneWith is like Clone, but copies each entry with copyFn, which
+// must not change how the entry orders against the others.
+func (sl *SkipList[T]) CloneWith(copyFn func(T) T) *SkipList[T] {
+	if sl.head == nil {
+		return &SkipList[T]{compare: sl.compare, arena: sl.arena}
+	}
+
+	clone := &SkipList[T]{
+		compare:  sl.compare,
+		arena:    sl.arena,
+		maxLevel: sl.maxLevel,
+		level:    sl.level,
+		num:      sl.Len(),
+		cache:    make(nodes[T], sl.maxLevel),
+		posCache: make(widths, sl.maxLevel),
+		head:     sl.head.clone(sl.arena, nil),
+	}
+
+	// last holds the most recent node copied at each level, which is
+	// the one to link the next node at that level from.
+	last := make(nodes[T], sl.maxLevel)
+	for i := range last {
+		last[i] = clone.head
+	}
+	for n := sl.head.forward[0]; n != nil; n = n.forward[0] {
+		nn := n.clone(sl.arena, copyFn)
+		for i := range nn.forward {
+			last[i].forward[i] = nn
+			last[i] = nn
+		}
+	}
+	return clone
+}
+
+// MemStats estimates the memory held by the skiplist.  Nodes includes
+// the head, and Slots counts the forward pointers and widths of every
+// node.  This walks the list, so it is O(n).
+func (sl *SkipList[T]) MemStats() common.MemStats {
+	var (
+		ptr   = uint64(unsafe.Sizeof((*node[T])(nil)))
+		width = uint64(unsafe.Sizeof(uint64(0)))
+	)
+	stats := common.MemStats{
+		Items: sl.Len(),
+		Slots: uint64(cap(sl.cache) + cap(sl.posCache)),
+		Bytes: uint64(unsafe.Sizeof(*sl)) + uint64(cap(sl.cache))*ptr + uint64(cap(sl.posCache))*width,
+	}
+	for n := sl.head; n != nil; n = n.forward[0] {
+		stats.Nodes++
+		stats.Slots += uint64(cap(n.forward) + cap(n.widths))
+		stats.Bytes += uint64(unsafe.Sizeof(*n)) + uint64(cap(n.forward))*ptr + uint64(cap(n.widths))*width
+	}
+	return stats
+}
+
 // New will allocate, initialize, and return a new skiplist.
 // The provided parameter should be of type uint and will determine
 // the maximum possible level that will be created to ensure
 // a random and quick distribution of levels. Parameter must
 // be a uint type.
-func New[T Comparable[T]](ifc any) *SkipList[T] {
-	sl := &SkipList[T]{}
+func New[T Comparable[T]](ifc any, opts ...Option[T]) *SkipList[T] {
+	return NewWithCompareFunc(common.MethodCompare[T](), ifc, opts...)
+}
+
+// NewWithCompareFunc is like New, but orders entries with the provided
+// function rather than requiring them to implement Comparable.  This
+// allows entries of any type, including ones ordered by a field or by
+// state captured in a closure.
+func NewWithCompareFunc[T any](compare common.CompareFunc[T], ifc any, opts ...Option[T]) *SkipList[T] {
+	sl := &SkipList[T]{compare: compare}
+	for _, opt := range opts {
+		opt(sl)
+	}
 	sl.init(ifc)
 	return sl
 }
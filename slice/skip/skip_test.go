@@ -18,9 +18,12 @@ package skip
 
 import (
 	"math/rand"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/Workiva/go-datastructures/common"
 )
 
 func generateMockEntries(num int) []mockEntry {
@@ -507,3 +510,122 @@ func BenchmarkInsertAtPosition(b *testing.B) {
 		sl.InsertAtPosition(0, entries[i%numItems])
 	}
 }
+
+func TestBoxedValues(t *testing.T) {
+	sl := New[common.Box[string]](uint8(0))
+	sl.Insert(common.Wrap([]string{"c", "a", "b"})...)
+
+	var values []string
+	for iter := sl.Iter(common.NewBox("")); iter.Next(); {
+		values = append(values, iter.Value().Value)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, values)
+}
+
+func TestCompareFunc(t *testing.T) {
+	sl := NewWithCompareFunc(common.Reverse(common.OrderedCompare[int]()), uint8(0))
+	sl.Insert(3, 1, 4, 1, 5)
+	assert.Equal(t, uint64(4), sl.Len())
+
+	var values []int
+	for iter := sl.Iter(10); iter.Next(); {
+		values = append(values, iter.Value())
+	}
+	assert.Equal(t, []int{5, 4, 3, 1}, values)
+
+	result, ok := sl.Delete(4)
+	assert.Equal(t, []int{4}, result)
+	assert.Equal(t, []bool{true}, ok)
+	_, ok = sl.Get(4)
+	assert.Equal(t, []bool{false}, ok)
+}
+
+func TestAllBackward(t *testing.T) {
+	sl := NewWithCompareFunc(common.OrderedCompare[int](), uint64(0))
+	assert.Empty(t, slices.Collect(sl.All()))
+	assert.Empty(t, slices.Collect((&SkipList[int]{}).Backward()))
+
+	sl.Insert(3, 1, 2, 5, 4)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, slices.Collect(sl.All()))
+	assert.Equal(t, []int{5, 4, 3, 2, 1}, slices.Collect(sl.Backward()))
+	for entry := range sl.Backward() {
+		assert.Equal(t, 5, entry)
+		break
+	}
+}
+
+func TestClone(t *testing.T) {
+	sl := NewWithCompareFunc(common.OrderedCompare[int](), uint8(0))
+	for i := range 100 {
+		sl.Insert(i * 2)
+	}
+
+	clone := sl.Clone()
+	assert.NoError(t, checkInvariants(clone))
+	assert.Equal(t, slices.Collect(sl.All()), slices.Collect(clone.All()))
+	entry, ok := clone.ByPosition(50)
+	assert.True(t, ok)
+	assert.Equal(t, 100, entry)
+
+	clone.Insert(1)
+	clone.Delete(0)
+	assert.NoError(t, checkInvariants(clone))
+	assert.NoError(t, checkInvariants(sl))
+	assert.Equal(t, uint64(100), sl.Len())
+	_, found := sl.Get(1)
+	assert.Equal(t, []bool{false}, found)
+	_, found = sl.Get(0)
+	assert.Equal(t, []bool{true}, found)
+
+	empty := NewWithCompareFunc(common.OrderedCompare[int](), uint8(0)).Clone()
+	empty.Insert(1)
+	assert.Equal(t, []int{1}, slices.Collect(empty.All()))
+}
+
+func TestCloneWith(t *testing.T) {
+	compare := func(a, b *int) int { return *a - *b }
+	one, two := 1, 2
+	sl := NewWithCompareFunc(compare, uint8(0))
+	sl.Insert(&two, &one)
+
+	clone := sl.CloneWith(func(p *int) *int {
+		v := *p
+		return &v
+	})
+	var values []int
+	for entry := range clone.All() {
+		assert.NotSame(t, &one, entry)
+		assert.NotSame(t, &two, entry)
+		values = append(values, *entry)
+	}
+	assert.Equal(t, []int{1, 2}, values)
+}
+
+func TestSharedInterfaces(t *testing.T) {
+	sl := New[common.Box[int]](uint8(0))
+	sl.Insert(common.Wrap([]int{3, 1, 2})...)
+
+	var it common.Iterator[common.Box[int]] = sl.Iter(common.NewBox(2))
+	assert.Equal(t, []int{2, 3}, common.Unwrap(slices.Collect(common.Seq(it))))
+}
+
+func TestMemStats(t *testing.T) {
+	sl := NewWithCompareFunc(common.OrderedCompare[int](), uint8(0))
+	empty := sl.MemStats()
+	assert.Equal(t, uint64(0), empty.Items)
+	assert.Equal(t, uint64(1), empty.Nodes)
+	assert.Equal(t, uint64(4*8), empty.Slots) // both caches, the head's links and widths
+	assert.NotZero(t, empty.Bytes)
+
+	for i := range 100 {
+		sl.Insert(i)
+	}
+	stats := sl.MemStats()
+	assert.Equal(t, uint64(100), stats.Items)
+	assert.Equal(t, uint64(101), stats.Nodes)
+	assert.GreaterOrEqual(t, stats.Slots, empty.Slots+2*100)
+	assert.Greater(t, stats.Bytes, empty.Bytes+100*8)
+
+	sl.Delete(0)
+	assert.Equal(t, uint64(100), sl.MemStats().Nodes)
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skip
+
+import (
+	"iter"
+	"sync"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+// Synchronized wraps a skiplist with a read-write mutex so it can be
+// shared between goroutines.  Lookups take the read lock and
+// mutations take the write lock, so this suits light contention; the
+// ConcurrentMap is better suited to heavily contended maps.
+//
+// The iterators returned by Iter and IterAtPosition, and the
+// sequences returned by All and Backward, work on a copy of the
+// entries taken under the read lock, so they are O(n) to start but
+// are unaffected by later mutations and may mutate the list freely.
+type Synchronized[T any] struct {
+	lock sync.RWMutex
+	sl   *SkipList[T]
+}
+
+// NewSynchronized returns a Synchronized that guards the provided
+// skiplist.  The skiplist must not be used directly afterwards.
+func NewSynchronized[T any](sl *SkipList[T]) *Synchronized[T] {
+	return &Synchronized[T]{sl: sl}
+}
+
+// Get is the synchronized version of SkipList.Get.
+func (s *Synchronized[T]) Get(comparators ...T) ([]T, []bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.sl.Get(comparators...)
+}
+
+// GetWithPosition is the synchronized version of
+// SkipList.GetWithPosition.
+func (s *Synchronized[T]) GetWithPosition(cmp T) (T, uint64, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.sl.GetWithPosition(cmp)
+}
+
+// ByPosition is the synchronized version of SkipList.ByPosition.
+func (s *Synchronized[T]) ByPosition(position uint64) (T, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.sl.ByPosition(position)
+}
+
+// Insert is the synchronized version of SkipList.Insert.
+func (s *Synchronized[T]) Insert(comparators ...T) ([]T, []bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.sl.Insert(comparators...)
+}
+
+// InsertAtPosition is the synchronized version of
+// SkipList.InsertAtPosition.
+func (s *Synchronized[T]) InsertAtPosition(position uint64, cmp T) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.sl.InsertAtPosition(position, cmp)
+}
+
+// ReplaceAtPosition is the synchronized version of
+// SkipList.ReplaceAtPosition.
+func (s *Synchronized[T]) ReplaceAtPosition(position uint64, cmp T) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.sl.ReplaceAtPosition(position, cmp)
+}
+
+// Delete is the synchronized version of SkipList.Delete.
+func (s *Synchronized[T]) Delete(comparators ...T) ([]T, []bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.sl.Delete(comparators...)
+}
+
+// Len is the synchronized version of SkipList.Len.
+func (s *Synchronized[T]) Len() uint64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.sl.Len()
+}
+
+// IterAtPosition returns an iterator over a copy of the entries from
+// the provided position onwards.
+func (s *Synchronized[T]) IterAtPosition(pos uint64) Iterator[T] {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return &sliceIterator[T]{entries: s.sl.iterAtPosition(pos + 1).exhaust(), index: -1}
+}
+
+// Iter returns an iterator over a copy of the entries equal to or
+// greater than the provided key.
+func (s *Synchronized[T]) Iter(cmp T) Iterator[T] {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return &sliceIterator[T]{entries: s.sl.iter(cmp).exhaust(), index: -1}
+}
+
+// All returns an iterator over a copy of the entries, in order.  The
+// copy is taken when iteration begins.
+func (s *Synchronized[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, entry := range s.snapshot() {
+			if !yield(entry) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over a copy of the entries in reverse
+// order.  The copy is taken when iteration begins.
+func (s *Synchronized[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		entries := s.snapshot()
+		for i := len(entries) - 1; i >= 0; i-- {
+			if !yield(entries[i]) {
+				return
+			}
+		}
+	}
+}
+
+func (s *Synchronized[T]) snapshot() []T {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	entries := make([]T, 0, s.sl.Len())
+	for entry := range s.sl.All() {
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// SplitAt is the synchronized version of SkipList.SplitAt.  The left
+// list returned is this one and the right list, if any, is newly
+// wrapped.
+func (s *Synchronized[T]) SplitAt(index uint64) (*Synchronized[T], *Synchronized[T]) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	left, right := s.sl.SplitAt(index)
+	s.sl = left
+	if right == nil {
+		return s, nil
+	}
+	return s, NewSynchronized(right)
+}
+
+// Clone returns a synchronized copy of the skiplist.
+func (s *Synchronized[T]) Clone() *Synchronized[T] {
+	return s.CloneWith(nil)
+}
+
+// CloneWith returns a synchronized copy of the skiplist, copying each
+// entry with copyFn as SkipList.CloneWith does.
+func (s *Synchronized[T]) CloneWith(copyFn func(T) T) *Synchronized[T] {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return NewSynchronized(s.sl.CloneWith(copyFn))
+}
+
+// MemStats is the synchronized version of SkipList.MemStats.
+func (s *Synchronized[T]) MemStats() common.MemStats {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.sl.MemStats()
+}
+
+// MarshalBinary is the synchronized version of SkipList.MarshalBinary.
+func (s *Synchronized[T]) MarshalBinary() ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.sl.MarshalBinary()
+}
+
+// UnmarshalBinary is the synchronized version of
+// SkipList.UnmarshalBinary.
+func (s *Synchronized[T]) UnmarshalBinary(data []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.sl.UnmarshalBinary(data)
+}
+
+// MarshalJSON is the synchronized version of SkipList.MarshalJSON.
+func (s *Synchronized[T]) MarshalJSON() ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.sl.MarshalJSON()
+}
+
+// UnmarshalJSON is the synchronized version of SkipList.UnmarshalJSON.
+func (s *Synchronized[T]) UnmarshalJSON(data []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.sl.UnmarshalJSON(data)
+}
+
+// sliceIterator iterates over entries copied out of a skiplist.
+type sliceIterator[T any] struct {
+	entries []T
+	index   int
+}
+
+// Next returns a bool indicating if there are any further values
+// in this iterator.
+func (iter *sliceIterator[T]) Next() bool {
+	if iter.index >= len(iter.entries) {
+		return false
+	}
+	iter.index++
+	return iter.index < len(iter.entries)
+}
+
+// Value returns the entry at the iterator's present position, or the
+// zero value if no values remain.
+func (iter *sliceIterator[T]) Value() T {
+	if iter.index < 0 || iter.index >= len(iter.entries) {
+		var zero T
+		return zero
+	}
+	return iter.entries[iter.index]
+}
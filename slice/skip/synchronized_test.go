@@ -0,0 +1,83 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package skip
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSynchronizedConcurrentInsert(t *testing.T) {
+	s := NewSynchronized(New[mockEntry](uint64(0)))
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				s.Insert(newMockEntry(uint64(i*100 + j)))
+				s.Get(newMockEntry(uint64(j)))
+				s.Len()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, uint64(400), s.Len())
+	assert.Equal(t, generateMockEntries(400), slices.Collect(s.All()))
+}
+
+func TestSynchronizedIterIsSnapshot(t *testing.T) {
+	s := NewSynchronized(New[mockEntry](uint64(0)))
+	s.Insert(generateMockEntries(5)...)
+
+	it := s.Iter(newMockEntry(2))
+	s.Delete(newMockEntry(3))
+	s.Insert(newMockEntry(10))
+
+	var got []mockEntry
+	for it.Next() {
+		got = append(got, it.Value())
+	}
+	assert.Equal(t, []mockEntry{2, 3, 4}, got)
+	assert.False(t, it.Next())
+	assert.Equal(t, mockEntry(0), it.Value())
+
+	for entry := range s.All() {
+		// mutating during iteration must not deadlock
+		s.Delete(entry)
+	}
+	assert.Equal(t, uint64(0), s.Len())
+}
+
+func TestSynchronizedSplitAt(t *testing.T) {
+	s := NewSynchronized(New[mockEntry](uint64(0)))
+	s.Insert(generateMockEntries(6)...)
+
+	left, right := s.SplitAt(2)
+	assert.Same(t, s, left)
+	assert.Equal(t, []mockEntry{0, 1, 2}, slices.Collect(left.All()))
+	assert.Equal(t, []mockEntry{5, 4, 3}, slices.Collect(right.Backward()))
+
+	clone := left.Clone()
+	clone.Delete(newMockEntry(0))
+	assert.Equal(t, uint64(3), left.Len())
+	assert.Equal(t, uint64(2), clone.Len())
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package slidingwindow tracks counts and sums of values over a rolling
+window of time, in fixed memory.  The window is divided into a ring of
+buckets, each covering an equal slice of time, and a value is added to
+the bucket for the current slice.  As time moves on, the oldest bucket
+is reused for the newest slice, so values leave the window one bucket
+at a time rather than one by one.
+
+Adding a value is O(1), and reading the aggregates is O(buckets).  More
+buckets make values leave the window more smoothly, at the cost of
+memory and reads.
+*/
+package slidingwindow
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// options holds the settings that may be given when creating a window.
+type options struct {
+	now func() time.Time
+}
+
+// Option configures a window when it is created.
+type Option func(*options)
+
+// WithClock sets the function a window reads the current time from.
+// It defaults to time.Now, and is usually replaced to control time in
+// tests.
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+type bucket struct {
+	// slice is the number of the slice of time the bucket covers,
+	// counting from when the window was created.
+	slice    int64
+	count    int64
+	sum      float64
+	min, max float64
+}
+
+// Window aggregates the values added over the most recent span of
+// time.  It is safe for concurrent use.
+type Window struct {
+	mutex   sync.Mutex
+	buckets []bucket
+	width   time.Duration
+	now     func() time.Time
+	start   time.Time
+}
+
+// New returns a Window spanning the given length of time, divided into
+// the given number of buckets.
+func New(span time.Duration, buckets int, opts ...Option) *Window {
+	if buckets < 1 {
+		panic(`slidingwindow: there must be at least one bucket`)
+	}
+	if span < time.Duration(buckets) {
+		panic(`slidingwindow: span must be at least a nanosecond per bucket`)
+	}
+	o := options{now: time.Now}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	w := &Window{
+		buckets: make([]bucket, buckets),
+		width:   span / time.Duration(buckets),
+		now:     o.now,
+	}
+	w.start = w.now()
+	for i := range w.buckets {
+		// mark every bucket as covering a slice long gone.
+		w.buckets[i].slice = math.MinInt64
+	}
+	return w
+}
+
+// Span returns the length of time the window covers, which is rounded
+// down to a multiple of the number of buckets.
+func (w *Window) Span() time.Duration {
+	return w.width * time.Duration(len(w.buckets))
+}
+
+// slice returns the number of the current slice of time.
+func (w *Window) slice() int64 {
+	return max(int64(w.now().Sub(w.start)/w.width), 0)
+}
+
+// Add adds a value to the window.
+func (w *Window) Add(value float64) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	slice := w.slice()
+	b := &w.buckets[slice%int64(len(w.buckets))]
+	if b.slice != slice {
+		*b = bucket{slice: slice, min: value, max: value}
+	}
+	b.count++
+	b.sum += value
+	b.min = min(b.min, value)
+	b.max = max(b.max, value)
+}
+
+// Aggregate holds aggregates of the values in a window.
+type Aggregate struct {
+	// Count is the number of values.
+	Count int64
+	// Sum is the total of the values.
+	Sum float64
+	// Min and Max are the least and greatest values, or 0 if there are
+	// none.
+	Min, Max float64
+}
+
+// Mean returns the mean of the values, or 0 if there are none.
+func (a Aggregate) Mean() float64 {
+	if a.Count == 0 {
+		return 0
+	}
+	return a.Sum / float64(a.Count)
+}
+
+// Aggregate returns the aggregates of the values in the window.
+func (w *Window) Aggregate() Aggregate {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	var a Aggregate
+	oldest := w.slice() - int64(len(w.buckets)) + 1
+	for _, b := range w.buckets {
+		if b.slice < oldest || b.count == 0 {
+			continue
+		}
+		if a.Count == 0 {
+			a.Min, a.Max = b.min, b.max
+		}
+		a.Count += b.count
+		a.Sum += b.sum
+		a.Min = min(a.Min, b.min)
+		a.Max = max(a.Max, b.max)
+	}
+	return a
+}
+
+// Count returns the number of values in the window.
+func (w *Window) Count() int64 {
+	return w.Aggregate().Count
+}
+
+// Sum returns the total of the values in the window.
+func (w *Window) Sum() float64 {
+	return w.Aggregate().Sum
+}
+
+// Rate returns the number of values in the window per second of its
+// span.
+func (w *Window) Rate() float64 {
+	return float64(w.Count()) / w.Span().Seconds()
+}
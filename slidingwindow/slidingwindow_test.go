@@ -0,0 +1,121 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slidingwindow
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestWindow(span time.Duration, buckets int) (*Window, func(time.Duration)) {
+	now := time.Unix(0, 0)
+	w := New(span, buckets, WithClock(func() time.Time { return now }))
+	return w, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestWindow(t *testing.T) {
+	w, sleep := newTestWindow(time.Second, 4)
+	assert.Equal(t, time.Second, w.Span())
+	assert.Equal(t, Aggregate{}, w.Aggregate())
+
+	w.Add(5)
+	w.Add(-1)
+	sleep(300 * time.Millisecond)
+	w.Add(2)
+	assert.Equal(t, Aggregate{Count: 3, Sum: 6, Min: -1, Max: 5}, w.Aggregate())
+	assert.Equal(t, 2.0, w.Aggregate().Mean())
+	assert.Equal(t, 3.0, w.Rate())
+
+	// the first bucket leaves the window a whole span after it began.
+	sleep(700 * time.Millisecond)
+	assert.Equal(t, int64(1), w.Count())
+	assert.Equal(t, 2.0, w.Sum())
+
+	sleep(time.Hour)
+	assert.Equal(t, Aggregate{}, w.Aggregate())
+	assert.Equal(t, 0.0, w.Aggregate().Mean())
+	w.Add(7)
+	assert.Equal(t, Aggregate{Count: 1, Sum: 7, Min: 7, Max: 7}, w.Aggregate())
+}
+
+func TestWindowRandom(t *testing.T) {
+	const span, buckets = time.Second, 10
+	width := span / buckets
+	w, sleep := newTestWindow(span, buckets)
+	rng := rand.New(rand.NewSource(17))
+
+	type added struct {
+		at    time.Duration
+		value float64
+	}
+	var values []added
+	var now time.Duration
+	for op := 0; op < 2000; op++ {
+		if rng.Intn(3) == 0 {
+			d := time.Duration(rng.Int63n(int64(span / 3)))
+			sleep(d)
+			now += d
+		}
+		value := float64(rng.Intn(100))
+		w.Add(value)
+		values = append(values, added{now, value})
+
+		// values stay in the window while their bucket is among the
+		// latest, so for up to one bucket longer than the span.
+		oldest := (now/width - buckets + 1) * width
+		var expected Aggregate
+		for _, v := range values {
+			if v.at < oldest {
+				continue
+			}
+			if expected.Count == 0 {
+				expected.Min, expected.Max = v.value, v.value
+			}
+			expected.Count++
+			expected.Sum += v.value
+			expected.Min = min(expected.Min, v.value)
+			expected.Max = max(expected.Max, v.value)
+		}
+		assert.Equal(t, expected, w.Aggregate())
+	}
+}
+
+func TestWindowConcurrent(t *testing.T) {
+	w := New(time.Hour, 60)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				w.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(8000), w.Count())
+	assert.Equal(t, 8000.0, w.Sum())
+}
+
+func TestNewInvalid(t *testing.T) {
+	assert.Panics(t, func() { New(time.Second, 0) })
+	assert.Panics(t, func() { New(3, 4) })
+}
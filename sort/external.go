@@ -0,0 +1,364 @@
+package merge
+
+import (
+	"bufio"
+	"container/heap"
+	"errors"
+	"io"
+	"iter"
+	"os"
+	"slices"
+)
+
+// defaultRunSize is the number of items sorted in memory at a time when
+// no run size is configured.
+const defaultRunSize = 1 << 16
+
+// defaultFanIn is the number of runs merged at once when no fan-in is
+// configured.
+const defaultFanIn = 64
+
+var (
+	// ErrNoCodec is returned by ExternalSort when no Codec is configured.
+	ErrNoCodec = errors.New("merge: external sort requires a Codec")
+	// ErrNoCompare is returned by ExternalSort when no Compare function
+	// is configured.
+	ErrNoCompare = errors.New("merge: external sort requires a Compare function")
+)
+
+// Codec writes items to and reads them back from the temporary files
+// used by ExternalSort.
+type Codec[T any] interface {
+	// Encode writes a single item to w.
+	Encode(w io.Writer, item T) error
+	// Decode reads the next item from r, returning io.EOF once there
+	// are no more.
+	Decode(r io.Reader) (T, error)
+}
+
+// ExternalConfig configures an ExternalSort.
+type ExternalConfig[T any] struct {
+	// Codec writes sorted runs to disk and reads them back.
+	Codec Codec[T]
+	// Compare returns a negative number if a sorts before b, a positive
+	// number if b sorts before a and zero if they are equal.
+	Compare func(a, b T) int
+	// RunSize is the number of items held in memory and sorted at once.
+	// Defaults to 65536.
+	RunSize int
+	// FanIn is the greatest number of runs merged, and so of temporary
+	// files open, at once.  Defaults to 64, and is at least 2.
+	FanIn int
+	// TempDir is the directory the runs are written to. Defaults to
+	// os.TempDir.
+	TempDir string
+}
+
+// ExternalSort sorts items that may not fit in memory.  Items are read
+// RunSize at a time, each run is sorted in memory and written to a
+// temporary file through the codec, and the runs are then merged
+// k ways as the returned iterator is advanced, so at most one run and
+// one item per run are ever held in memory.  If there are more than
+// FanIn runs, they are first merged FanIn at a time into longer runs,
+// in as many passes as needed, so that no more than FanIn files are
+// ever open at once.  If every item fits in a single run nothing is
+// written to disk.  The sort is stable.  The iterator must be closed to
+// remove the temporary files.
+func ExternalSort[T any](items iter.Seq[T], config ExternalConfig[T]) (*ExternalIterator[T], error) {
+	if config.Codec == nil {
+		return nil, ErrNoCodec
+	}
+	if config.Compare == nil {
+		return nil, ErrNoCompare
+	}
+	runSize := config.RunSize
+	if runSize <= 0 {
+		runSize = defaultRunSize
+	}
+	fanIn := config.FanIn
+	if fanIn <= 0 {
+		fanIn = defaultFanIn
+	}
+	fanIn = max(fanIn, 2)
+
+	ei := &ExternalIterator[T]{codec: config.Codec, compare: config.Compare}
+	run := make([]T, 0, runSize)
+	for item := range items {
+		run = append(run, item)
+		if len(run) < runSize {
+			continue
+		}
+		if err := ei.spill(run, config.TempDir); err != nil {
+			ei.Close()
+			return nil, err
+		}
+		run = run[:0]
+	}
+
+	slices.SortStableFunc(run, config.Compare)
+	if len(ei.runs) == 0 {
+		ei.memory, ei.inMemory = run, true
+		return ei, nil
+	}
+	if len(run) > 0 {
+		if err := ei.spill(run, config.TempDir); err != nil {
+			ei.Close()
+			return nil, err
+		}
+	}
+
+	for len(ei.runs) > fanIn {
+		if err := ei.mergePass(fanIn, config.TempDir); err != nil {
+			ei.Close()
+			return nil, err
+		}
+	}
+
+	if err := ei.start(); err != nil {
+		ei.Close()
+		return nil, err
+	}
+	return ei, nil
+}
+
+// ExternalIterator iterates over the items sorted by ExternalSort in
+// order.
+type ExternalIterator[T any] struct {
+	codec    Codec[T]
+	compare  func(a, b T) int
+	runs     []string
+	files    []*os.File
+	readers  runHeap[T]
+	memory   []T
+	inMemory bool
+	value    T
+	err      error
+	started  bool
+}
+
+// spill sorts run and writes it to a new temporary file.
+func (ei *ExternalIterator[T]) spill(run []T, dir string) error {
+	slices.SortStableFunc(run, ei.compare)
+	return ei.writeRun(dir, slices.Values(run))
+}
+
+// writeRun writes the items to a new temporary file, which is closed
+// once written and added to the end of the runs.
+func (ei *ExternalIterator[T]) writeRun(dir string, items iter.Seq[T]) error {
+	file, err := os.CreateTemp(dir, "externalsort-*")
+	if err != nil {
+		return err
+	}
+	ei.runs = append(ei.runs, file.Name())
+
+	w := bufio.NewWriter(file)
+	for item := range items {
+		if err = ei.codec.Encode(w, item); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	return errors.Join(err, file.Close())
+}
+
+// mergePass merges the runs fanIn at a time, in order so that the sort
+// stays stable, replacing them with the merged runs.
+func (ei *ExternalIterator[T]) mergePass(fanIn int, dir string) error {
+	runs := ei.runs
+	ei.runs = nil
+	for len(runs) > 0 {
+		group := runs[:min(fanIn, len(runs))]
+		runs = runs[len(group):]
+
+		if len(group) == 1 {
+			ei.runs = append(ei.runs, group[0])
+			continue
+		}
+		err := ei.open(group)
+		if err == nil {
+			// iterating stops early on a read error, which is
+			// recorded in ei.err.
+			err = errors.Join(ei.writeRun(dir, ei.All()), ei.err)
+		}
+		err = errors.Join(err, ei.closeFiles())
+		for _, name := range group {
+			err = errors.Join(err, os.Remove(name))
+		}
+		if err != nil {
+			// leave the remaining runs for Close to remove.
+			ei.runs = append(ei.runs, runs...)
+			return err
+		}
+	}
+	return nil
+}
+
+// open opens the runs and reads the first item of each, positioning
+// the iterator before the first item of their merge.
+func (ei *ExternalIterator[T]) open(runs []string) error {
+	ei.readers = runHeap[T]{compare: ei.compare}
+	ei.started = false
+	for i, name := range runs {
+		file, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		ei.files = append(ei.files, file)
+
+		reader := &runReader[T]{index: i, r: bufio.NewReader(file)}
+		ok, err := reader.next(ei.codec)
+		if err != nil {
+			return err
+		}
+		if ok {
+			ei.readers.items = append(ei.readers.items, reader)
+		}
+	}
+	heap.Init(&ei.readers)
+	return nil
+}
+
+// start opens the final runs for merging as the iterator is advanced.
+func (ei *ExternalIterator[T]) start() error {
+	return ei.open(ei.runs)
+}
+
+// closeFiles closes the open runs.
+func (ei *ExternalIterator[T]) closeFiles() error {
+	var errs []error
+	for _, file := range ei.files {
+		errs = append(errs, file.Close())
+	}
+	ei.files = nil
+	ei.readers.items = nil
+	return errors.Join(errs...)
+}
+
+// Next advances the iterator, returning false once every item has been
+// returned or an error has occurred.
+func (ei *ExternalIterator[T]) Next() bool {
+	if ei.err != nil {
+		return false
+	}
+
+	if ei.inMemory {
+		if ei.started && len(ei.memory) > 0 {
+			ei.memory = ei.memory[1:]
+		}
+		ei.started = true
+		if len(ei.memory) == 0 {
+			return false
+		}
+		ei.value = ei.memory[0]
+		return true
+	}
+
+	if ei.started && ei.readers.Len() > 0 {
+		reader := ei.readers.items[0]
+		ok, err := reader.next(ei.codec)
+		if err != nil {
+			ei.err = err
+			return false
+		}
+		if ok {
+			heap.Fix(&ei.readers, 0)
+		} else {
+			heap.Pop(&ei.readers)
+		}
+	}
+	ei.started = true
+
+	if ei.readers.Len() == 0 {
+		return false
+	}
+	ei.value = ei.readers.items[0].value
+	return true
+}
+
+// Value returns the item the iterator is positioned on.
+func (ei *ExternalIterator[T]) Value() T {
+	return ei.value
+}
+
+// Err returns the error, if any, that stopped iteration early.
+func (ei *ExternalIterator[T]) Err() error {
+	return ei.err
+}
+
+// All returns the remaining items as a sequence. Check Err once the
+// sequence ends.
+func (ei *ExternalIterator[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for ei.Next() {
+			if !yield(ei.Value()) {
+				return
+			}
+		}
+	}
+}
+
+// Close removes the temporary files. The iterator returns no more items
+// once closed.
+func (ei *ExternalIterator[T]) Close() error {
+	errs := []error{ei.closeFiles()}
+	for _, name := range ei.runs {
+		errs = append(errs, os.Remove(name))
+	}
+	ei.runs = nil
+	ei.memory = nil
+	ei.started = true
+	return errors.Join(errs...)
+}
+
+// runReader holds the next unread item of a run.
+type runReader[T any] struct {
+	index int
+	r     io.Reader
+	value T
+}
+
+func (rr *runReader[T]) next(codec Codec[T]) (bool, error) {
+	value, err := codec.Decode(rr.r)
+	if err == io.EOF {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	rr.value = value
+	return true, nil
+}
+
+// runHeap orders runs by their next item, breaking ties by the order
+// of the runs so that the merge is stable.
+type runHeap[T any] struct {
+	items   []*runReader[T]
+	compare func(a, b T) int
+}
+
+func (rh runHeap[T]) Len() int {
+	return len(rh.items)
+}
+
+func (rh runHeap[T]) Less(i, j int) bool {
+	if c := rh.compare(rh.items[i].value, rh.items[j].value); c != 0 {
+		return c < 0
+	}
+	return rh.items[i].index < rh.items[j].index
+}
+
+func (rh runHeap[T]) Swap(i, j int) {
+	rh.items[i], rh.items[j] = rh.items[j], rh.items[i]
+}
+
+func (rh *runHeap[T]) Push(x any) {
+	rh.items = append(rh.items, x.(*runReader[T]))
+}
+
+func (rh *runHeap[T]) Pop() any {
+	last := rh.items[len(rh.items)-1]
+	rh.items = rh.items[:len(rh.items)-1]
+	return last
+}
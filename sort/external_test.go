@@ -0,0 +1,195 @@
+package merge
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/rand"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type record struct {
+	Key, Position int32
+}
+
+type recordCodec struct{}
+
+func (recordCodec) Encode(w io.Writer, item record) error {
+	return binary.Write(w, binary.LittleEndian, item)
+}
+
+func (recordCodec) Decode(r io.Reader) (record, error) {
+	var item record
+	err := binary.Read(r, binary.LittleEndian, &item)
+	return item, err
+}
+
+func compareRecords(a, b record) int {
+	return int(a.Key - b.Key)
+}
+
+func randomRecords(n int) []record {
+	r := rand.New(rand.NewSource(int64(n)))
+	records := make([]record, n)
+	for i := range records {
+		records[i] = record{Key: int32(r.Intn(100)), Position: int32(i)}
+	}
+	return records
+}
+
+func TestExternalSort(t *testing.T) {
+	dir := t.TempDir()
+	for _, n := range []int{0, 1, 99, 100, 101, 10000} {
+		records := randomRecords(n)
+
+		it, err := ExternalSort(slices.Values(records), ExternalConfig[record]{
+			Codec:   recordCodec{},
+			Compare: compareRecords,
+			RunSize: 100,
+			TempDir: dir,
+		})
+		require.NoError(t, err)
+
+		result := slices.AppendSeq(make([]record, 0, n), it.All())
+		require.NoError(t, it.Err())
+		require.NoError(t, it.Close())
+
+		slices.SortStableFunc(records, compareRecords)
+		assert.Equal(t, records, result)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	}
+}
+
+func TestExternalSortInMemory(t *testing.T) {
+	dir := t.TempDir()
+	records := randomRecords(50)
+
+	it, err := ExternalSort(slices.Values(records), ExternalConfig[record]{
+		Codec:   recordCodec{},
+		Compare: compareRecords,
+		TempDir: dir,
+	})
+	require.NoError(t, err)
+	defer it.Close()
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	slices.SortStableFunc(records, compareRecords)
+	assert.Equal(t, records, slices.Collect(it.All()))
+	assert.False(t, it.Next())
+}
+
+func TestExternalSortClose(t *testing.T) {
+	dir := t.TempDir()
+
+	it, err := ExternalSort(slices.Values(randomRecords(1000)), ExternalConfig[record]{
+		Codec:   recordCodec{},
+		Compare: compareRecords,
+		RunSize: 100,
+		TempDir: dir,
+	})
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 10)
+
+	require.True(t, it.Next())
+	require.NoError(t, it.Close())
+	assert.False(t, it.Next())
+
+	entries, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestExternalSortFanIn(t *testing.T) {
+	dir := t.TempDir()
+	records := randomRecords(10000)
+
+	it, err := ExternalSort(slices.Values(records), ExternalConfig[record]{
+		Codec:   recordCodec{},
+		Compare: compareRecords,
+		RunSize: 100,
+		FanIn:   3,
+		TempDir: dir,
+	})
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(entries), 3)
+	assert.LessOrEqual(t, len(it.files), 3)
+
+	result := slices.Collect(it.All())
+	require.NoError(t, it.Err())
+	require.NoError(t, it.Close())
+
+	slices.SortStableFunc(records, compareRecords)
+	assert.Equal(t, records, result)
+
+	entries, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestExternalSortConfig(t *testing.T) {
+	_, err := ExternalSort(slices.Values(randomRecords(10)), ExternalConfig[record]{
+		Compare: compareRecords,
+	})
+	assert.Equal(t, ErrNoCodec, err)
+
+	_, err = ExternalSort(slices.Values(randomRecords(10)), ExternalConfig[record]{
+		Codec: recordCodec{},
+	})
+	assert.Equal(t, ErrNoCompare, err)
+}
+
+type failingCodec struct {
+	recordCodec
+	err error
+}
+
+func (fc failingCodec) Decode(r io.Reader) (record, error) {
+	return record{}, fc.err
+}
+
+func TestExternalSortDecodeError(t *testing.T) {
+	failure := errors.New("corrupt")
+
+	_, err := ExternalSort(slices.Values(randomRecords(1000)), ExternalConfig[record]{
+		Codec:   failingCodec{err: failure},
+		Compare: compareRecords,
+		RunSize: 100,
+		TempDir: t.TempDir(),
+	})
+	assert.Equal(t, failure, err)
+}
+
+func TestExternalSortMergePassError(t *testing.T) {
+	dir := t.TempDir()
+	failure := errors.New("corrupt")
+
+	_, err := ExternalSort(slices.Values(randomRecords(1000)), ExternalConfig[record]{
+		Codec:   failingCodec{err: failure},
+		Compare: compareRecords,
+		RunSize: 100,
+		FanIn:   2,
+		TempDir: dir,
+	})
+	assert.ErrorIs(t, err, failure)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,92 @@
+package merge
+
+import (
+	"container/heap"
+	"iter"
+)
+
+// Merge merges sequences that are each already sorted by compare into
+// a single sorted sequence, pulling from every input as the result is
+// ranged over so that only one item per input is held at a time.  The
+// merge is stable: items that compare equal are yielded in the order
+// of the sequences they came from, so callers can resolve duplicates
+// by keeping the first.  Inputs that are not sorted produce an
+// unspecified order.
+func Merge[T any](compare func(a, b T) int, seqs ...iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		sources := seqHeap[T]{compare: compare}
+		defer func() {
+			for _, source := range sources.items {
+				source.stop()
+			}
+		}()
+
+		for i, seq := range seqs {
+			next, stop := iter.Pull(seq)
+			value, ok := next()
+			if !ok {
+				stop()
+				continue
+			}
+			sources.items = append(sources.items, &seqSource[T]{
+				index: i, value: value, next: next, stop: stop,
+			})
+		}
+		heap.Init(&sources)
+
+		for sources.Len() > 0 {
+			source := sources.items[0]
+			if !yield(source.value) {
+				return
+			}
+			value, ok := source.next()
+			if ok {
+				source.value = value
+				heap.Fix(&sources, 0)
+				continue
+			}
+			source.stop()
+			heap.Pop(&sources)
+		}
+	}
+}
+
+// seqSource is an input to Merge along with its next item.
+type seqSource[T any] struct {
+	index int
+	value T
+	next  func() (T, bool)
+	stop  func()
+}
+
+// seqHeap orders the inputs to Merge by their next item, breaking ties
+// by the order of the inputs.
+type seqHeap[T any] struct {
+	items   []*seqSource[T]
+	compare func(a, b T) int
+}
+
+func (sh seqHeap[T]) Len() int {
+	return len(sh.items)
+}
+
+func (sh seqHeap[T]) Less(i, j int) bool {
+	if c := sh.compare(sh.items[i].value, sh.items[j].value); c != 0 {
+		return c < 0
+	}
+	return sh.items[i].index < sh.items[j].index
+}
+
+func (sh seqHeap[T]) Swap(i, j int) {
+	sh.items[i], sh.items[j] = sh.items[j], sh.items[i]
+}
+
+func (sh *seqHeap[T]) Push(x any) {
+	sh.items = append(sh.items, x.(*seqSource[T]))
+}
+
+func (sh *seqHeap[T]) Pop() any {
+	last := sh.items[len(sh.items)-1]
+	sh.items = sh.items[:len(sh.items)-1]
+	return last
+}
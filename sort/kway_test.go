@@ -0,0 +1,84 @@
+package merge
+
+import (
+	"cmp"
+	"iter"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tagged struct {
+	value, source int
+}
+
+func TestMerge(t *testing.T) {
+	merged := Merge(cmp.Compare[int],
+		slices.Values([]int{1, 4, 7}),
+		slices.Values([]int{}),
+		slices.Values([]int{2, 5, 8, 9}),
+		slices.Values([]int{3, 6}),
+	)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9}, slices.Collect(merged))
+	assert.Empty(t, slices.Collect(Merge(cmp.Compare[int])))
+}
+
+func TestMergeIsStable(t *testing.T) {
+	compare := func(a, b tagged) int {
+		return cmp.Compare(a.value, b.value)
+	}
+	merged := slices.Collect(Merge(compare,
+		slices.Values([]tagged{{1, 0}, {2, 0}}),
+		slices.Values([]tagged{{1, 1}, {2, 1}}),
+		slices.Values([]tagged{{2, 2}}),
+	))
+	assert.Equal(t, []tagged{{1, 0}, {1, 1}, {2, 0}, {2, 1}, {2, 2}}, merged)
+}
+
+func TestMergeMatchesSort(t *testing.T) {
+	var seqs [][]int
+	var all []int
+	for range 10 {
+		seq := make([]int, rand.Intn(50))
+		for i := range seq {
+			seq[i] = rand.Intn(100)
+		}
+		slices.Sort(seq)
+		seqs = append(seqs, seq)
+		all = append(all, seq...)
+	}
+	slices.Sort(all)
+
+	inputs := make([]iter.Seq[int], 0, len(seqs))
+	for _, seq := range seqs {
+		inputs = append(inputs, slices.Values(seq))
+	}
+	got := slices.Collect(Merge(cmp.Compare[int], inputs...))
+	assert.True(t, slices.Equal(all, got))
+}
+
+func TestMergeStopsEarly(t *testing.T) {
+	stopped := 0
+	counting := func(values ...int) iter.Seq[int] {
+		return func(yield func(int) bool) {
+			defer func() { stopped++ }()
+			for _, v := range values {
+				if !yield(v) {
+					return
+				}
+			}
+		}
+	}
+
+	var got []int
+	for v := range Merge(cmp.Compare[int], counting(1, 3, 5), counting(2, 4, 6)) {
+		got = append(got, v)
+		if v == 3 {
+			break
+		}
+	}
+	assert.Equal(t, []int{1, 2, 3}, got)
+	assert.Equal(t, 2, stopped)
+}
@@ -0,0 +1,115 @@
+package merge
+
+import (
+	"math/bits"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// parallelSortThreshold is the size below which a partition is sorted
+// on the current goroutine rather than split further.
+const parallelSortThreshold = 1 << 11
+
+// MultithreadedQuickSortComparators sorts the provided list in place
+// using as many threads as are available.  The list is partitioned
+// around pivots chosen by Tukey's ninther, with the partitions sorted
+// concurrently, and partitions that are small enough or once the
+// threads are all busy are sorted with the standard library's
+// pattern-defeating quicksort.  Already sorted partitions are detected
+// and skipped, and elements equal to a pivot are gathered in one pass,
+// so sorted, reversed and heavily duplicated inputs all stay fast.
+// Unlike MultithreadedSortComparators the sort is not stable, but it
+// allocates no memory and is usually quicker.
+func MultithreadedQuickSortComparators(comparators Comparators) {
+	depth := bits.Len(uint(runtime.NumCPU())) + 1
+	parallelQuickSort(comparators, depth)
+}
+
+// parallelQuickSort partitions u and sorts the partitions concurrently
+// until depth is exhausted.
+func parallelQuickSort(u Comparators, depth int) {
+	for len(u) >= parallelSortThreshold && depth > 0 {
+		if isSorted(u) {
+			return
+		}
+
+		lt, gt := partition3(u, ninther(u))
+		depth--
+
+		// sort the smaller side on a new goroutine and keep going with
+		// the larger one here
+		less, greater := u[:lt], u[gt:]
+		if len(less) > len(greater) {
+			less, greater = greater, less
+		}
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func(less Comparators) {
+			parallelQuickSort(less, depth)
+			wg.Done()
+		}(less)
+		parallelQuickSort(greater, depth)
+		wg.Wait()
+		return
+	}
+
+	sort.Sort(u)
+}
+
+func isSorted(u Comparators) bool {
+	for i := 1; i < len(u); i++ {
+		if u[i].Compare(u[i-1]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// partition3 rearranges u into those less than the pivot, those equal
+// to it and those greater, returning the bounds of the middle section.
+func partition3(u Comparators, pivot Comparator) (lt, gt int) {
+	lt, i, gt := 0, 0, len(u)
+	for i < gt {
+		switch c := u[i].Compare(pivot); {
+		case c < 0:
+			u[lt], u[i] = u[i], u[lt]
+			lt++
+			i++
+		case c > 0:
+			gt--
+			u[i], u[gt] = u[gt], u[i]
+		default:
+			i++
+		}
+	}
+	return lt, gt
+}
+
+// ninther returns the median of the medians of three spread out
+// samples of three, which is a good pivot for large lists whatever
+// their order.
+func ninther(u Comparators) Comparator {
+	n := len(u)
+	step := n / 8
+	m := n / 2
+	return median(
+		median(u[0], u[step], u[2*step]),
+		median(u[m-step], u[m], u[m+step]),
+		median(u[n-1-2*step], u[n-1-step], u[n-1]),
+	)
+}
+
+func median(a, b, c Comparator) Comparator {
+	if a.Compare(b) > 0 {
+		a, b = b, a
+	}
+	if b.Compare(c) > 0 {
+		b = c
+		if a.Compare(b) > 0 {
+			b = a
+		}
+	}
+	return b
+}
@@ -0,0 +1,120 @@
+package merge
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func constructRandomMockComparators(n int) Comparators {
+	r := rand.New(rand.NewSource(int64(n)))
+	comparators := make(Comparators, 0, n)
+	for range n {
+		comparators = append(comparators, mockComparator(r.Intn(n)))
+	}
+	return comparators
+}
+
+// constructOrganPipeMockComparators returns values that rise and then
+// fall, a classic bad case for naive pivot selection.
+func constructOrganPipeMockComparators(n int) Comparators {
+	comparators := make(Comparators, 0, n)
+	for i := range n {
+		comparators = append(comparators, mockComparator(min(i, n-i)))
+	}
+	return comparators
+}
+
+func TestMultiThreadedQuickSort(t *testing.T) {
+	inputs := map[string]func(int) Comparators{
+		"random":     constructRandomMockComparators,
+		"sorted":     constructOrderedMockComparators,
+		"organ pipe": constructOrganPipeMockComparators,
+		"reversed": func(n int) Comparators {
+			return reverseComparators(constructOrderedMockComparators(n))
+		},
+		"duplicates": func(n int) Comparators {
+			comparators := constructRandomMockComparators(n)
+			for i, c := range comparators {
+				comparators[i] = c.(mockComparator) % 3
+			}
+			return comparators
+		},
+	}
+
+	for name, construct := range inputs {
+		for _, n := range []int{0, 1, 2, 100, 100003} {
+			comparators := construct(n)
+			expected := slices.Clone(comparators)
+			slices.SortFunc(expected, func(a, b Comparator) int {
+				return a.Compare(b)
+			})
+
+			MultithreadedQuickSortComparators(comparators)
+			assert.Len(t, comparators, n, name)
+			assert.Equal(t, expected, comparators, name)
+		}
+	}
+}
+
+func TestPartition3(t *testing.T) {
+	comparators := constructMockComparators(3, 1, 2, 3, 5, 0, 3, 4)
+
+	lt, gt := partition3(comparators, mockComparator(3))
+	assert.Equal(t, 3, lt)
+	assert.Equal(t, 6, gt)
+	assert.ElementsMatch(t, constructMockComparators(0, 1, 2), comparators[:lt])
+	assert.Equal(t, constructMockComparators(3, 3, 3), comparators[lt:gt])
+	assert.ElementsMatch(t, constructMockComparators(4, 5), comparators[gt:])
+}
+
+func TestMedian(t *testing.T) {
+	for _, values := range [][]int{{1, 2, 3}, {1, 3, 2}, {2, 1, 3}, {2, 3, 1}, {3, 1, 2}, {3, 2, 1}} {
+		a, b, c := mockComparator(values[0]), mockComparator(values[1]), mockComparator(values[2])
+		assert.Equal(t, mockComparator(2), median(a, b, c))
+	}
+}
+
+var benchmarkInputs = []struct {
+	name      string
+	construct func(int) Comparators
+}{
+	{"Random", constructRandomMockComparators},
+	{"Sorted", constructOrderedMockComparators},
+	{"OrganPipe", constructOrganPipeMockComparators},
+}
+
+func benchmarkSort(b *testing.B, sortFn func(Comparators)) {
+	for _, input := range benchmarkInputs {
+		b.Run(input.name, func(b *testing.B) {
+			original := input.construct(100000)
+			comparators := make(Comparators, len(original))
+			for b.Loop() {
+				b.StopTimer()
+				copy(comparators, original)
+				b.StartTimer()
+				sortFn(comparators)
+			}
+		})
+	}
+}
+
+func BenchmarkQuickSortMultiThreaded(b *testing.B) {
+	benchmarkSort(b, MultithreadedQuickSortComparators)
+}
+
+func BenchmarkQuickSortMergeSort(b *testing.B) {
+	benchmarkSort(b, func(comparators Comparators) {
+		MultithreadedSortComparators(comparators)
+	})
+}
+
+func BenchmarkQuickSortStdlib(b *testing.B) {
+	benchmarkSort(b, func(comparators Comparators) {
+		slices.SortFunc(comparators, func(a, c Comparator) int {
+			return a.Compare(c)
+		})
+	})
+}
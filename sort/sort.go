@@ -1,31 +1,39 @@
 package merge
 
 import (
+	"math/bits"
 	"runtime"
 	"sort"
 	"sync"
 )
 
-func sortBucket(comparators Comparators) {
-	sort.Sort(comparators)
-}
+// parallelMergeThreshold is the size below which a merge is not worth
+// splitting across goroutines.
+const parallelMergeThreshold = 1 << 12
 
-func copyChunk(chunk []Comparators) []Comparators {
-	cp := make([]Comparators, len(chunk))
-	copy(cp, chunk)
-	return cp
+func sortBucket(comparators Comparators) {
+	sort.Stable(comparators)
 }
 
 // MultithreadedSortComparators will take a list of comparators
 // and sort it using as many threads as are available.  The list
 // is split into buckets for a bucket sort and then recursively
-// merged using SymMerge.
+// merged using the algorithm behind SymMerge.  The sort is stable:
+// comparators that compare equal keep the order they had in the
+// provided list, which is left unmodified.
 func MultithreadedSortComparators(comparators Comparators) Comparators {
 	toBeSorted := make(Comparators, len(comparators))
 	copy(toBeSorted, comparators)
+	MultithreadedSortComparatorsInPlace(toBeSorted)
+	return toBeSorted
+}
 
-	var wg sync.WaitGroup
-
+// MultithreadedSortComparatorsInPlace is like MultithreadedSortComparators
+// but sorts the provided list itself.  Buckets are merged by rotating
+// them in place rather than into new lists, so no memory is allocated
+// beyond the goroutines doing the work, at the cost of making
+// O(N*log(N)^2) moves rather than O(N*log(N)).
+func MultithreadedSortComparatorsInPlace(comparators Comparators) {
 	numCPU := int64(runtime.NumCPU())
 	if numCPU == 1 { // single core machine
 		numCPU = 2
@@ -34,44 +42,76 @@ func MultithreadedSortComparators(comparators Comparators) Comparators {
 		numCPU = int64(prevPowerOfTwo(uint64(numCPU)))
 	}
 
-	chunks := chunk(toBeSorted, numCPU)
-	wg.Add(len(chunks))
-	for i := 0; i < len(chunks); i++ {
+	bounds := chunkBounds(len(comparators), numCPU)
+	numParts := len(bounds) - 1
+
+	var wg sync.WaitGroup
+	wg.Add(numParts)
+	for i := range numParts {
 		go func(i int) {
-			sortBucket(chunks[i])
+			sortBucket(comparators[bounds[i]:bounds[i+1]])
 			wg.Done()
 		}(i)
 	}
 
 	wg.Wait()
-	todo := make([]Comparators, len(chunks)/2)
-	for {
-		todo = todo[:len(chunks)/2]
-		wg.Add(len(chunks) / 2)
-		for i := 0; i < len(chunks); i += 2 {
+	for width := 1; width < numParts; width *= 2 {
+		// every merge at this level may split itself this many times
+		// so that all threads remain busy as the merges get fewer
+		depth := bits.Len(uint(width))
+		wg.Add(numParts / (2 * width))
+		for i := 0; i < numParts; i += 2 * width {
 			go func(i int) {
-				todo[i/2] = SymMerge(chunks[i], chunks[i+1])
+				parallelSymMerge(comparators, bounds[i], bounds[i+width], bounds[i+2*width], depth)
 				wg.Done()
 			}(i)
 		}
 
 		wg.Wait()
+	}
+}
 
-		chunks = copyChunk(todo)
-		if len(chunks) == 1 {
-			break
-		}
+// parallelSymMerge is symMerge, except that the two halves left to merge
+// after each rotation are merged concurrently until depth is exhausted.
+// The halves never overlap, so this is safe.
+func parallelSymMerge(u Comparators, start1, start2, last, depth int) {
+	if depth == 0 || last-start1 < parallelMergeThreshold {
+		symMerge(u, start1, start2, last)
+		return
 	}
 
-	return chunks[0]
+	if start1 < start2 && start2 < last {
+		mid := (start1 + last) / 2
+		n := mid + start2
+		var start int
+		if start2 > mid {
+			start = symBinarySearch(u, n-last, mid, n-1)
+		} else {
+			start = symBinarySearch(u, start1, start2, n-1)
+		}
+		end := n - start
+
+		symRotate(u, start, start2, end)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			parallelSymMerge(u, start1, start, mid, depth-1)
+			wg.Done()
+		}()
+		parallelSymMerge(u, mid, end, last, depth-1)
+		wg.Wait()
+	}
 }
 
-func chunk(comparators Comparators, numParts int64) []Comparators {
-	parts := make([]Comparators, numParts)
-	for i := range numParts {
-		parts[i] = comparators[i*int64(len(comparators))/numParts : (i+1)*int64(len(comparators))/numParts]
+// chunkBounds splits length into numParts ranges of nearly equal size,
+// returning the numParts+1 indices that delimit them.
+func chunkBounds(length int, numParts int64) []int {
+	bounds := make([]int, numParts+1)
+	for i := range numParts + 1 {
+		bounds[i] = int(i * int64(length) / numParts)
 	}
-	return parts
+	return bounds
 }
 
 func prevPowerOfTwo(x uint64) uint64 {
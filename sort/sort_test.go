@@ -1,6 +1,7 @@
 package merge
 
 import (
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -36,3 +37,66 @@ func BenchmarkMultiThreadedSort(b *testing.B) {
 		MultithreadedSortComparators(comparators)
 	}
 }
+
+// keyedComparator compares by key only, so comparators with equal keys
+// can be told apart by their position.
+type keyedComparator struct {
+	key, position int
+}
+
+func (kc keyedComparator) Compare(other Comparator) int {
+	return kc.key - other.(keyedComparator).key
+}
+
+func constructKeyedComparators(n, keys int) Comparators {
+	r := rand.New(rand.NewSource(int64(n)))
+	comparators := make(Comparators, 0, n)
+	for i := range n {
+		comparators = append(comparators, keyedComparator{key: r.Intn(keys), position: i})
+	}
+	return comparators
+}
+
+func assertStablySorted(t *testing.T, comparators Comparators) {
+	for i := 1; i < len(comparators); i++ {
+		prev, cur := comparators[i-1].(keyedComparator), comparators[i].(keyedComparator)
+		if !assert.True(t, prev.key < cur.key || prev.key == cur.key && prev.position < cur.position) {
+			return
+		}
+	}
+}
+
+func TestMultiThreadedSortIsStable(t *testing.T) {
+	for _, n := range []int{0, 1, 7, 100, 10000, 100003} {
+		comparators := constructKeyedComparators(n, 10)
+		original := make(Comparators, len(comparators))
+		copy(original, comparators)
+
+		result := MultithreadedSortComparators(comparators)
+		assert.Len(t, result, n)
+		assertStablySorted(t, result)
+		assert.Equal(t, original, comparators)
+	}
+}
+
+func TestMultiThreadedSortInPlace(t *testing.T) {
+	for _, n := range []int{0, 1, 9, 10000, 100003} {
+		comparators := constructKeyedComparators(n, n/3+1)
+
+		MultithreadedSortComparatorsInPlace(comparators)
+		assert.Len(t, comparators, n)
+		assertStablySorted(t, comparators)
+	}
+}
+
+func BenchmarkMultiThreadedSortInPlace(b *testing.B) {
+	numCells := 100000
+
+	comparators := constructOrderedMockComparators(numCells)
+	for b.Loop() {
+		b.StopTimer()
+		reverseComparators(comparators)
+		b.StartTimer()
+		MultithreadedSortComparatorsInPlace(comparators)
+	}
+}
@@ -0,0 +1,70 @@
+package merge
+
+import "slices"
+
+// TopK returns the k smallest items according to cmp, sorted, without
+// sorting the rest of the list.  For the k largest, reverse cmp.  It
+// keeps the positions of the best k items seen so far in a heap,
+// making O(N*log(k)) comparisons and allocating only that heap and the
+// returned list, and is stable: items that compare equal keep the
+// order they had in items.  If k is
+// at least len(items), all of them are returned sorted.  The provided
+// list is left unmodified.
+func TopK[T any](items []T, k int, cmp func(a, b T) int) []T {
+	if k <= 0 {
+		return []T{}
+	}
+	if k >= len(items) {
+		result := slices.Clone(items)
+		slices.SortStableFunc(result, cmp)
+		return result
+	}
+
+	// the heap is ordered with the worst of the best k at its root, and
+	// among equal items the latest is the worst so that earlier items
+	// win ties
+	indices := make([]int, 0, k)
+	worse := func(i, j int) bool {
+		if c := cmp(items[indices[i]], items[indices[j]]); c != 0 {
+			return c > 0
+		}
+		return indices[i] > indices[j]
+	}
+	down := func(i int) {
+		for {
+			worst := i
+			for _, child := range [2]int{2*i + 1, 2*i + 2} {
+				if child < len(indices) && worse(child, worst) {
+					worst = child
+				}
+			}
+			if worst == i {
+				return
+			}
+			indices[i], indices[worst] = indices[worst], indices[i]
+			i = worst
+		}
+	}
+
+	for i := range k {
+		indices = append(indices, i)
+	}
+	for i := k/2 - 1; i >= 0; i-- {
+		down(i)
+	}
+	for i := k; i < len(items); i++ {
+		// a later item must be strictly better to displace an equal one
+		if cmp(items[i], items[indices[0]]) < 0 {
+			indices[0] = i
+			down(0)
+		}
+	}
+
+	slices.Sort(indices)
+	result := make([]T, len(indices))
+	for i, index := range indices {
+		result[i] = items[index]
+	}
+	slices.SortStableFunc(result, cmp)
+	return result
+}
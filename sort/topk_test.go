@@ -0,0 +1,49 @@
+package merge
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopK(t *testing.T) {
+	items := []int{5, 3, 9, 1, 7, 3, 8}
+
+	assert.Equal(t, []int{1, 3, 3}, TopK(items, 3, cmp.Compare[int]))
+	assert.Equal(t, []int{9, 8}, TopK(items, 2, func(a, b int) int {
+		return cmp.Compare(b, a)
+	}))
+	assert.Equal(t, []int{1, 3, 3, 5, 7, 8, 9}, TopK(items, 10, cmp.Compare[int]))
+	assert.Equal(t, []int{}, TopK(items, 0, cmp.Compare[int]))
+	assert.Equal(t, []int{5, 3, 9, 1, 7, 3, 8}, items)
+}
+
+func TestTopKMatchesSort(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	items := make([]record, 10000)
+	for i := range items {
+		items[i] = record{Key: int32(r.Intn(50)), Position: int32(i)}
+	}
+
+	sorted := slices.Clone(items)
+	slices.SortStableFunc(sorted, compareRecords)
+
+	for _, k := range []int{1, 10, 500, 9999} {
+		assert.Equal(t, sorted[:k], TopK(items, k, compareRecords))
+	}
+}
+
+func BenchmarkTopK(b *testing.B) {
+	items := make([]int, 100000)
+	r := rand.New(rand.NewSource(1))
+	for i := range items {
+		items[i] = r.Int()
+	}
+
+	for b.Loop() {
+		TopK(items, 100, cmp.Compare[int])
+	}
+}
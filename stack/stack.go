@@ -0,0 +1,278 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package stack implements last in, first out stacks, the counterpart to
+the queue package: a plain slice-backed Stack, a Bounded stack that
+refuses pushes once full, and a ThreadSafe stack, bounded or not, that
+may be shared between goroutines.
+*/
+package stack
+
+import (
+	"errors"
+	"iter"
+	"sync"
+)
+
+// ErrFull is returned when pushing to a bounded stack that is full.
+var ErrFull = errors.New(`stack: full`)
+
+// minShrink is the capacity below which a stack's storage is not
+// shrunk as items are popped.
+const minShrink = 64
+
+// Stack is a slice-backed stack.  The zero value is an empty stack.  It
+// is not safe for concurrent use.
+type Stack[T any] struct {
+	items []T
+}
+
+// New returns an empty stack with room for hint items before it grows.
+func New[T any](hint int) *Stack[T] {
+	return &Stack[T]{items: make([]T, 0, hint)}
+}
+
+// Push adds items to the top of the stack, in order, so that the last
+// of them is on top.
+func (s *Stack[T]) Push(items ...T) {
+	s.items = append(s.items, items...)
+}
+
+// Pop removes and returns the item on top of the stack, and whether
+// there was one.
+func (s *Stack[T]) Pop() (T, bool) {
+	var zero T
+	if len(s.items) == 0 {
+		return zero, false
+	}
+	last := len(s.items) - 1
+	item := s.items[last]
+	s.items[last] = zero
+	s.items = s.items[:last]
+
+	// give back storage left over from a burst of pushes
+	if cap(s.items) > minShrink && len(s.items) < cap(s.items)/4 {
+		s.items = append(make([]T, 0, cap(s.items)/2), s.items...)
+	}
+	return item, true
+}
+
+// Peek returns the item on top of the stack without removing it, and
+// whether there was one.
+func (s *Stack[T]) Peek() (T, bool) {
+	if len(s.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return s.items[len(s.items)-1], true
+}
+
+// Len returns the number of items in the stack.
+func (s *Stack[T]) Len() int {
+	return len(s.items)
+}
+
+// Empty returns whether the stack holds no items.
+func (s *Stack[T]) Empty() bool {
+	return len(s.items) == 0
+}
+
+// Clear removes every item from the stack.
+func (s *Stack[T]) Clear() {
+	clear(s.items)
+	s.items = s.items[:0]
+}
+
+// All returns an iterator over the items from the top of the stack to
+// the bottom.  The stack must not be modified while it is being
+// iterated.
+func (s *Stack[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := len(s.items) - 1; i >= 0; i-- {
+			if !yield(s.items[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Bounded is a stack that holds at most a fixed number of items.  It is
+// not safe for concurrent use.
+type Bounded[T any] struct {
+	stack    Stack[T]
+	capacity int
+}
+
+// NewBounded returns an empty stack that holds at most capacity items.
+func NewBounded[T any](capacity int) *Bounded[T] {
+	if capacity < 0 {
+		panic(`stack: negative capacity`)
+	}
+	return &Bounded[T]{stack: Stack[T]{items: make([]T, 0, capacity)}, capacity: capacity}
+}
+
+// Push adds item to the top of the stack, or returns ErrFull if the
+// stack is full.
+func (b *Bounded[T]) Push(item T) error {
+	if b.Full() {
+		return ErrFull
+	}
+	b.stack.items = append(b.stack.items, item)
+	return nil
+}
+
+// Pop removes and returns the item on top of the stack, and whether
+// there was one.
+func (b *Bounded[T]) Pop() (T, bool) {
+	var zero T
+	if len(b.stack.items) == 0 {
+		return zero, false
+	}
+	// the storage is allocated up front, so it is never shrunk
+	last := len(b.stack.items) - 1
+	item := b.stack.items[last]
+	b.stack.items[last] = zero
+	b.stack.items = b.stack.items[:last]
+	return item, true
+}
+
+// Peek returns the item on top of the stack without removing it, and
+// whether there was one.
+func (b *Bounded[T]) Peek() (T, bool) {
+	return b.stack.Peek()
+}
+
+// Len returns the number of items in the stack.
+func (b *Bounded[T]) Len() int {
+	return b.stack.Len()
+}
+
+// Cap returns the number of items the stack can hold.
+func (b *Bounded[T]) Cap() int {
+	return b.capacity
+}
+
+// Empty returns whether the stack holds no items.
+func (b *Bounded[T]) Empty() bool {
+	return b.stack.Empty()
+}
+
+// Full returns whether the stack holds as many items as it can.
+func (b *Bounded[T]) Full() bool {
+	return len(b.stack.items) >= b.capacity
+}
+
+// Clear removes every item from the stack.
+func (b *Bounded[T]) Clear() {
+	b.stack.Clear()
+}
+
+// All returns an iterator over the items from the top of the stack to
+// the bottom.  The stack must not be modified while it is being
+// iterated.
+func (b *Bounded[T]) All() iter.Seq[T] {
+	return b.stack.All()
+}
+
+// ThreadSafe is a stack that may be used by many goroutines at once,
+// optionally holding at most a fixed number of items.
+type ThreadSafe[T any] struct {
+	lock     sync.Mutex
+	stack    Stack[T]
+	capacity int
+	bounded  bool
+}
+
+// NewThreadSafe returns an empty, unbounded stack that is safe for
+// concurrent use.
+func NewThreadSafe[T any]() *ThreadSafe[T] {
+	return &ThreadSafe[T]{}
+}
+
+// NewBoundedThreadSafe returns an empty stack that is safe for
+// concurrent use and holds at most capacity items.
+func NewBoundedThreadSafe[T any](capacity int) *ThreadSafe[T] {
+	if capacity < 0 {
+		panic(`stack: negative capacity`)
+	}
+	return &ThreadSafe[T]{stack: Stack[T]{items: make([]T, 0, capacity)}, capacity: capacity, bounded: true}
+}
+
+// Push adds item to the top of the stack, or returns ErrFull if the
+// stack is bounded and full.
+func (ts *ThreadSafe[T]) Push(item T) error {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	if ts.bounded && len(ts.stack.items) >= ts.capacity {
+		return ErrFull
+	}
+	ts.stack.Push(item)
+	return nil
+}
+
+// Pop removes and returns the item on top of the stack, and whether
+// there was one.
+func (ts *ThreadSafe[T]) Pop() (T, bool) {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	return ts.stack.Pop()
+}
+
+// Peek returns the item on top of the stack without removing it, and
+// whether there was one.
+func (ts *ThreadSafe[T]) Peek() (T, bool) {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	return ts.stack.Peek()
+}
+
+// Len returns the number of items in the stack.
+func (ts *ThreadSafe[T]) Len() int {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	return ts.stack.Len()
+}
+
+// Empty returns whether the stack holds no items.
+func (ts *ThreadSafe[T]) Empty() bool {
+	return ts.Len() == 0
+}
+
+// Clear removes every item from the stack.
+func (ts *ThreadSafe[T]) Clear() {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	ts.stack.Clear()
+}
+
+// Items returns a copy of the items from the top of the stack to the
+// bottom.
+func (ts *ThreadSafe[T]) Items() []T {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+
+	items := make([]T, 0, ts.stack.Len())
+	for item := range ts.stack.All() {
+		items = append(items, item)
+	}
+	return items
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stack
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStack(t *testing.T) {
+	var s Stack[int]
+	assert.True(t, s.Empty())
+	_, ok := s.Pop()
+	assert.False(t, ok)
+	_, ok = s.Peek()
+	assert.False(t, ok)
+
+	s.Push(1, 2)
+	s.Push(3)
+	assert.Equal(t, 3, s.Len())
+	assert.Equal(t, []int{3, 2, 1}, slices.Collect(s.All()))
+
+	top, ok := s.Peek()
+	assert.True(t, ok)
+	assert.Equal(t, 3, top)
+	for _, expected := range []int{3, 2, 1} {
+		item, ok := s.Pop()
+		assert.True(t, ok)
+		assert.Equal(t, expected, item)
+	}
+	assert.True(t, s.Empty())
+
+	s.Push(4, 5)
+	s.Clear()
+	assert.Equal(t, 0, s.Len())
+}
+
+func TestStackShrinks(t *testing.T) {
+	s := New[*int](0)
+	for range 1000 {
+		s.Push(new(int))
+	}
+	grown := cap(s.items)
+	for range 990 {
+		s.Pop()
+	}
+	assert.Less(t, cap(s.items), grown/4)
+	assert.Equal(t, 10, s.Len())
+	// popped slots no longer reference their items
+	assert.Nil(t, s.items[:cap(s.items)][10])
+}
+
+func TestBounded(t *testing.T) {
+	b := NewBounded[string](2)
+	assert.Equal(t, 2, b.Cap())
+	assert.NoError(t, b.Push(`a`))
+	assert.NoError(t, b.Push(`b`))
+	assert.True(t, b.Full())
+	assert.Equal(t, ErrFull, b.Push(`c`))
+	assert.Equal(t, []string{`b`, `a`}, slices.Collect(b.All()))
+
+	item, ok := b.Pop()
+	assert.True(t, ok)
+	assert.Equal(t, `b`, item)
+	assert.NoError(t, b.Push(`c`))
+	item, _ = b.Peek()
+	assert.Equal(t, `c`, item)
+	assert.Equal(t, 2, b.Len())
+
+	b.Clear()
+	assert.True(t, b.Empty())
+	_, ok = b.Pop()
+	assert.False(t, ok)
+
+	assert.Equal(t, ErrFull, NewBounded[int](0).Push(1))
+	assert.Panics(t, func() { NewBounded[int](-1) })
+}
+
+func TestThreadSafe(t *testing.T) {
+	s := NewThreadSafe[int]()
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				assert.NoError(t, s.Push(i*100+j))
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 800, s.Len())
+
+	seen := make([]bool, 800)
+	var mu sync.Mutex
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				item, ok := s.Pop()
+				if !ok {
+					return
+				}
+				mu.Lock()
+				assert.False(t, seen[item])
+				seen[item] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.True(t, s.Empty())
+	assert.NotContains(t, seen, false)
+}
+
+func TestBoundedThreadSafe(t *testing.T) {
+	s := NewBoundedThreadSafe[int](3)
+	full := 0
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if s.Push(i) == ErrFull {
+				mu.Lock()
+				full++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 7, full)
+	assert.Len(t, s.Items(), 3)
+
+	top, _ := s.Peek()
+	assert.Equal(t, top, s.Items()[0])
+	s.Clear()
+	assert.Equal(t, 0, s.Len())
+}
@@ -0,0 +1,284 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package timingwheel implements a hashed timing wheel, which schedules
+very large numbers of timers far more cheaply than a runtime timer each.
+Time is divided into ticks, and the wheel is a ring of slots, one per
+tick, each holding a list of the timers due on that tick.  Scheduling
+and cancelling a timer are O(1), and advancing the wheel by a tick only
+touches the timers that are due.
+
+Timers due further away than the wheel spans wait in a priority queue
+ordered by deadline, moving onto the wheel once it comes within reach,
+so the wheel can be small without limiting how far ahead timers can be
+scheduled.  Cancelled timers in the overflow queue are only discarded
+once their deadline comes within reach.
+
+Timers fire no earlier than their delay, and up to a tick late,
+depending on how finely the wheel is advanced.  A Wheel is advanced by
+calling Advance, for instance from a loop driven by the caller, or by
+Run, which advances it every tick until it is cancelled.
+*/
+package timingwheel
+
+import (
+	"cmp"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Workiva/go-datastructures/queue"
+)
+
+// Timer is a scheduled call to a function.
+type Timer struct {
+	wheel    *Wheel
+	fn       func()
+	deadline uint64
+	seq      uint64
+	// prev and next link the timer into its slot, which is -1 while it
+	// waits in the overflow queue.
+	prev, next *Timer
+	slot       int
+	active     bool
+}
+
+// Cancel stops the timer from firing, reporting whether it was stopped,
+// which it is not if it has already fired or been cancelled.
+func (t *Timer) Cancel() bool {
+	w := t.wheel
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if !t.active {
+		return false
+	}
+	t.active = false
+	w.len--
+	// timers in the overflow queue are left there, and skipped when
+	// they come up.
+	if t.slot >= 0 {
+		w.unlink(t)
+	}
+	return true
+}
+
+func compareTimers(a, b *Timer) int {
+	if c := cmp.Compare(a.deadline, b.deadline); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.seq, b.seq)
+}
+
+// slot is a list of timers due on the same tick, in the order they
+// were scheduled.
+type slot struct {
+	head, tail *Timer
+}
+
+// Wheel schedules timers.  It is safe for concurrent use.
+type Wheel struct {
+	mutex    sync.Mutex
+	tick     time.Duration
+	slots    []slot
+	mask     uint64
+	start    time.Time
+	current  uint64
+	seq      uint64
+	len      int
+	slotted  int
+	overflow *queue.PriorityQueue[*Timer]
+	now      func() time.Time
+}
+
+// New returns a Wheel that advances in steps of tick, with the given
+// number of slots, rounded up to a power of two.  The wheel spans tick
+// times slots, and timers due beyond that wait in an overflow queue.
+func New(tick time.Duration, slots int) *Wheel {
+	if tick <= 0 {
+		panic(`timingwheel: tick must be positive`)
+	}
+	n := 1
+	for n < slots {
+		n <<= 1
+	}
+	w := &Wheel{
+		tick:     tick,
+		slots:    make([]slot, n),
+		mask:     uint64(n - 1),
+		overflow: queue.NewPriorityQueueWithCompareFunc(compareTimers, 0, true),
+		now:      time.Now,
+	}
+	w.start = w.now()
+	return w
+}
+
+// Len returns the number of timers waiting to fire.
+func (w *Wheel) Len() int {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.len
+}
+
+// Schedule returns a timer that calls fn once delay has passed.  fn is
+// called by whichever goroutine advances the wheel past its deadline,
+// so it should return quickly, handing any long work to a goroutine of
+// its own.
+func (w *Wheel) Schedule(delay time.Duration, fn func()) *Timer {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	// count from the tick now under way, which the wheel may not have
+	// been advanced to yet and which may be partly over, and round up,
+	// so that the timer never fires early.
+	deadline := max(w.current, w.ticks()) + 1
+	if delay > 0 {
+		deadline += uint64((delay + w.tick - 1) / w.tick)
+	}
+	w.seq++
+	t := &Timer{wheel: w, fn: fn, deadline: deadline, seq: w.seq, slot: -1, active: true}
+	w.len++
+	w.place(t)
+	return t
+}
+
+// ticks returns the number of ticks that have passed since the wheel
+// was created.
+func (w *Wheel) ticks() uint64 {
+	if elapsed := w.now().Sub(w.start); elapsed > 0 {
+		return uint64(elapsed / w.tick)
+	}
+	return 0
+}
+
+// place puts the timer into its slot if the wheel reaches that far, or
+// else into the overflow queue.
+func (w *Wheel) place(t *Timer) {
+	if t.deadline-w.current > w.mask {
+		t.slot = -1
+		w.overflow.Put(t)
+		return
+	}
+
+	t.slot = int(t.deadline & w.mask)
+	s := &w.slots[t.slot]
+	t.prev, t.next = s.tail, nil
+	if s.tail == nil {
+		s.head = t
+	} else {
+		s.tail.next = t
+	}
+	s.tail = t
+	w.slotted++
+}
+
+// unlink removes the timer from its slot.
+func (w *Wheel) unlink(t *Timer) {
+	s := &w.slots[t.slot]
+	if t.prev == nil {
+		s.head = t.next
+	} else {
+		t.prev.next = t.next
+	}
+	if t.next == nil {
+		s.tail = t.prev
+	} else {
+		t.next.prev = t.prev
+	}
+	t.prev, t.next = nil, nil
+	t.slot = -1
+	w.slotted--
+}
+
+// Advance moves the wheel up to the current time, calling the
+// functions of the timers that have become due, in order of their
+// deadlines, and returns how many were called.  They are called after
+// the wheel is unlocked, so they may schedule and cancel timers.
+func (w *Wheel) Advance() int {
+	w.mutex.Lock()
+	target := w.ticks()
+	var due []func()
+	for w.current < target {
+		if w.slotted == 0 {
+			// nothing is on the wheel, so skip to the tick before the
+			// next timer in the overflow queue comes within reach.
+			next := target
+			if t, ok := w.overflow.Peek(); ok && t.deadline-w.mask-1 < next {
+				next = max(w.current, t.deadline-w.mask-1)
+			}
+			w.current = next
+			w.fill()
+			if w.current == target {
+				break
+			}
+		}
+		w.current++
+		w.fill()
+		due = w.expire(due)
+	}
+	w.mutex.Unlock()
+
+	for _, fn := range due {
+		fn()
+	}
+	return len(due)
+}
+
+// fill moves the timers in the overflow queue that the wheel now
+// reaches onto the wheel.
+func (w *Wheel) fill() {
+	for {
+		t, ok := w.overflow.Peek()
+		if !ok || t.deadline-w.current > w.mask {
+			return
+		}
+		w.overflow.Get(1)
+		if t.active {
+			w.place(t)
+		}
+	}
+}
+
+// expire removes the timers due on the current tick, appending their
+// functions to due.
+func (w *Wheel) expire(due []func()) []func() {
+	s := &w.slots[w.current&w.mask]
+	for s.head != nil {
+		t := s.head
+		w.unlink(t)
+		t.active = false
+		w.len--
+		due = append(due, t.fn)
+	}
+	return due
+}
+
+// Run advances the wheel every tick until the context is done.
+func (w *Wheel) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.Advance()
+		}
+	}
+}
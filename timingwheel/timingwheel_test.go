@@ -0,0 +1,189 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package timingwheel
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestWheel returns a wheel whose clock only moves when the
+// returned function is called.
+func newTestWheel(tick time.Duration, slots int) (*Wheel, func(time.Duration)) {
+	w := New(tick, slots)
+	now := w.start
+	w.now = func() time.Time { return now }
+	return w, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestSchedule(t *testing.T) {
+	w, sleep := newTestWheel(time.Millisecond, 8)
+	var fired []int
+	for _, delay := range []int{3, 1, 0, 2, 3} {
+		w.Schedule(time.Duration(delay)*time.Millisecond, func() { fired = append(fired, delay) })
+	}
+	assert.Equal(t, 5, w.Len())
+
+	assert.Equal(t, 0, w.Advance())
+	sleep(time.Millisecond)
+	assert.Equal(t, 1, w.Advance())
+	assert.Equal(t, []int{0}, fired)
+	sleep(3 * time.Millisecond)
+	assert.Equal(t, 4, w.Advance())
+	assert.Equal(t, []int{0, 1, 2, 3, 3}, fired)
+	assert.Equal(t, 0, w.Len())
+}
+
+func TestCancel(t *testing.T) {
+	w, sleep := newTestWheel(time.Millisecond, 4)
+	fired := make(map[string]bool)
+	near := w.Schedule(time.Millisecond, func() { fired["near"] = true })
+	far := w.Schedule(time.Second, func() { fired["far"] = true })
+	w.Schedule(2*time.Millisecond, func() { fired["kept"] = true })
+
+	assert.True(t, near.Cancel())
+	assert.False(t, near.Cancel())
+	assert.True(t, far.Cancel())
+	assert.Equal(t, 1, w.Len())
+
+	sleep(2 * time.Second)
+	assert.Equal(t, 1, w.Advance())
+	assert.Equal(t, map[string]bool{"kept": true}, fired)
+	assert.Equal(t, 0, w.Len())
+	assert.True(t, w.overflow.Empty())
+}
+
+func TestScheduleFromCallback(t *testing.T) {
+	w, sleep := newTestWheel(time.Millisecond, 4)
+	var count int
+	var reschedule func()
+	reschedule = func() {
+		count++
+		if count < 3 {
+			w.Schedule(0, reschedule)
+		}
+	}
+	w.Schedule(0, reschedule)
+	for i := 0; i < 5; i++ {
+		sleep(time.Millisecond)
+		w.Advance()
+	}
+	assert.Equal(t, 3, count)
+}
+
+func TestScheduleWithoutAdvancing(t *testing.T) {
+	// the wheel falls behind the clock when it is not advanced, which
+	// must not make timers scheduled meanwhile fire early.
+	w, sleep := newTestWheel(time.Millisecond, 4)
+	sleep(time.Second)
+	var fired bool
+	w.Schedule(10*time.Millisecond, func() { fired = true })
+	sleep(9 * time.Millisecond)
+	w.Advance()
+	assert.False(t, fired)
+	sleep(2 * time.Millisecond)
+	w.Advance()
+	assert.True(t, fired)
+}
+
+func TestRandom(t *testing.T) {
+	const tick = time.Millisecond
+	rng := rand.New(rand.NewSource(13))
+	w, sleep := newTestWheel(tick, 16)
+	var now time.Duration
+
+	type scheduled struct {
+		due   time.Duration
+		fired time.Duration
+		timer *Timer
+	}
+	var timers []*scheduled
+	cancelled := make(map[*scheduled]bool)
+	for op := 0; op < 3000; op++ {
+		switch rng.Intn(3) {
+		case 0:
+			s := &scheduled{due: now + time.Duration(rng.Intn(200))*tick/3, fired: -1}
+			s.timer = w.Schedule(s.due-now, func() { s.fired = now })
+			timers = append(timers, s)
+		case 1:
+			if len(timers) > 0 {
+				s := timers[rng.Intn(len(timers))]
+				if s.timer.Cancel() {
+					cancelled[s] = true
+				}
+			}
+		default:
+			d := time.Duration(rng.Intn(int(5 * tick)))
+			sleep(d)
+			now += d
+			w.Advance()
+		}
+
+		waiting := timers[:0]
+		for _, s := range timers {
+			if cancelled[s] {
+				require.Equal(t, time.Duration(-1), s.fired)
+				continue
+			}
+			if s.fired >= 0 {
+				require.GreaterOrEqual(t, s.fired, s.due)
+				continue
+			}
+			// a timer fires at most two ticks late: one for rounding
+			// its delay, and one for the tick under way when it was
+			// scheduled.
+			require.Less(t, now, s.due+2*tick)
+			waiting = append(waiting, s)
+		}
+		timers = waiting
+		require.Equal(t, len(timers), w.Len())
+	}
+}
+
+func TestRun(t *testing.T) {
+	w := New(time.Millisecond, 8)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		w.Run(ctx)
+		close(done)
+	}()
+
+	fired := make(chan struct{})
+	w.Schedule(5*time.Millisecond, func() { close(fired) })
+	select {
+	case <-fired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timer did not fire")
+	}
+	cancel()
+	<-done
+}
+
+func BenchmarkScheduleCancel(b *testing.B) {
+	w := New(time.Millisecond, 512)
+	fn := func() {}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w.Schedule(time.Duration(i%1000)*time.Millisecond, fn).Cancel()
+	}
+}
@@ -44,14 +44,21 @@ Example usage with generics:
 */
 package avl
 
-import "math"
+import (
+	"iter"
+	"math"
+	"unsafe"
+
+	"github.com/Workiva/go-datastructures/common"
+)
 
 // Immutable represents an immutable AVL tree. This is achieved
 // by branch copying.
-type Immutable[T Comparable[T]] struct {
-	root   *node[T]
-	number uint64
-	dummy  node[T] // helper for inserts.
+type Immutable[T any] struct {
+	root    *node[T]
+	number  uint64
+	dummy   node[T] // helper for inserts.
+	compare common.CompareFunc[T]
 }
 
 // copy returns a copy of this immutable tree with a copy
@@ -63,9 +70,10 @@ func (immutable *Immutable[T]) copy() *Immutable[T] {
 	}
 	var zero T
 	cp := &Immutable[T]{
-		root:   root,
-		number: immutable.number,
-		dummy:  *newNode(zero, false),
+		root:    root,
+		number:  immutable.number,
+		dummy:   *newNode(zero, false),
+		compare: immutable.compare,
 	}
 	return cp
 }
@@ -85,7 +93,7 @@ func (immutable *Immutable[T]) get(entry T) (T, bool) {
 	n := immutable.root
 	var result int
 	for n != nil {
-		switch result = n.entry.Compare(entry); {
+		switch result = immutable.compare(n.entry, entry); {
 		case result == 0:
 			return n.entry, true
 		case result > 0:
@@ -115,6 +123,49 @@ func (immutable *Immutable[T]) Len() uint64 {
 	return immutable.number
 }
 
+// MemStats estimates the memory held by this version of the tree,
+// which has one node per entry.  Versions share the nodes they have in
+// common, so the stats of several versions overlap.  This is O(1).
+func (immutable *Immutable[T]) MemStats() common.MemStats {
+	return common.MemStats{
+		Items: immutable.number,
+		Nodes: immutable.number,
+		Bytes: uint64(unsafe.Sizeof(*immutable)) + immutable.number*uint64(unsafe.Sizeof(immutable.dummy)),
+	}
+}
+
+// All returns an iterator over the entries in the tree, in order.
+func (immutable *Immutable[T]) All() iter.Seq[T] {
+	return immutable.walk(0)
+}
+
+// Backward returns an iterator over the entries in the tree in
+// reverse order.
+func (immutable *Immutable[T]) Backward() iter.Seq[T] {
+	return immutable.walk(1)
+}
+
+// walk returns an in-order iterator that visits the children in
+// direction first, so 0 walks forward and 1 backward.
+func (immutable *Immutable[T]) walk(first int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		var stack []*node[T]
+		n := immutable.root
+		for n != nil || len(stack) > 0 {
+			for n != nil {
+				stack = append(stack, n)
+				n = n.children[first]
+			}
+			n = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !yield(n.entry) {
+				return
+			}
+			n = n.children[1-first]
+		}
+	}
+}
+
 func (immutable *Immutable[T]) insert(entry T) (T, bool) {
 	var zero T
 	if immutable.root == nil {
@@ -136,7 +187,7 @@ func (immutable *Immutable[T]) insert(entry T) (T, bool) {
 	// we'll go ahead and copy on the way down as we'll need to branch
 	// copy no matter what.
 	for s, p = helper.children[1], helper.children[1]; ; {
-		dir = p.entry.Compare(entry)
+		dir = immutable.compare(p.entry, entry)
 
 		normalized = normalizeComparison(dir)
 		if dir > 0 { // go left
@@ -175,7 +226,7 @@ func (immutable *Immutable[T]) insert(entry T) (T, bool) {
 
 	immutable.root = dummy.children[1]
 	for p = s; p != q; p = p.children[normalized] {
-		normalized = normalizeComparison(p.entry.Compare(entry))
+		normalized = normalizeComparison(immutable.compare(p.entry, entry))
 		if normalized == 0 {
 			p.balance--
 		} else {
@@ -186,7 +237,7 @@ func (immutable *Immutable[T]) insert(entry T) (T, bool) {
 	q = s
 
 	if math.Abs(float64(s.balance)) > 1 {
-		normalized = normalizeComparison(s.entry.Compare(entry))
+		normalized = normalizeComparison(immutable.compare(s.entry, entry))
 		s = insertBalance(s, normalized)
 	}
 
@@ -237,7 +288,7 @@ func (immutable *Immutable[T]) delete(entry T) (T, bool) {
 			return zero, false
 		}
 
-		dir = it.entry.Compare(entry)
+		dir = immutable.compare(it.entry, entry)
 		if dir == 0 {
 			break
 		}
@@ -272,7 +323,10 @@ func (immutable *Immutable[T]) delete(entry T) (T, bool) {
 			immutable.root = it.children[dir]
 		}
 	} else {
-		heir := it.children[1]
+		// the path down to the heir is rebalanced below, so it is
+		// copied too.
+		heir := it.children[1].copy()
+		it.children[1] = heir
 		dirs[top] = 1
 		cache[top] = it
 		top++
@@ -281,7 +335,9 @@ func (immutable *Immutable[T]) delete(entry T) (T, bool) {
 			dirs[top] = 0
 			cache[top] = heir
 			top++
-			heir = heir.children[0]
+			next := heir.children[0].copy()
+			heir.children[0] = next
+			heir = next
 		}
 
 		it.entry = heir.entry
@@ -335,7 +391,7 @@ func (immutable *Immutable[T]) Delete(entries ...T) (*Immutable[T], []T, []bool)
 	return cp, deleted, wasDeleted
 }
 
-func insertBalance[T Comparable[T]](root *node[T], dir int) *node[T] {
+func insertBalance[T any](root *node[T], dir int) *node[T] {
 	n := root.children[dir]
 	var bal int8
 	if dir == 0 {
@@ -355,7 +411,7 @@ func insertBalance[T Comparable[T]](root *node[T], dir int) *node[T] {
 	return root
 }
 
-func removeBalance[T Comparable[T]](root *node[T], dir int, done *int) *node[T] {
+func removeBalance[T any](root *node[T], dir int, done *int) *node[T] {
 	n := root.children[takeOpposite(dir)].copy()
 	root.children[takeOpposite(dir)] = n
 	var bal int8
@@ -369,6 +425,9 @@ func removeBalance[T Comparable[T]](root *node[T], dir int, done *int) *node[T]
 		root.balance, n.balance = 0, 0
 		root = rotate(root, dir)
 	} else if n.balance == bal {
+		// the double rotation rewires n's inner child, which may be
+		// shared with other trees.
+		n.children[dir] = n.children[dir].copy()
 		adjustBalance(root, takeOpposite(dir), int(-bal))
 		root = doubleRotate(root, dir)
 	} else {
@@ -392,7 +451,7 @@ func takeOpposite(value int) int {
 	return 1 - value
 }
 
-func adjustBalance[T Comparable[T]](root *node[T], dir, bal int) {
+func adjustBalance[T any](root *node[T], dir, bal int) {
 	n := root.children[dir]
 	nn := n.children[takeOpposite(dir)]
 
@@ -408,7 +467,7 @@ func adjustBalance[T Comparable[T]](root *node[T], dir, bal int) {
 	nn.balance = 0
 }
 
-func rotate[T Comparable[T]](parent *node[T], dir int) *node[T] {
+func rotate[T any](parent *node[T], dir int) *node[T] {
 	otherDir := takeOpposite(dir)
 
 	child := parent.children[otherDir]
@@ -418,7 +477,7 @@ func rotate[T Comparable[T]](parent *node[T], dir int) *node[T] {
 	return child
 }
 
-func doubleRotate[T Comparable[T]](parent *node[T], dir int) *node[T] {
+func doubleRotate[T any](parent *node[T], dir int) *node[T] {
 	otherDir := takeOpposite(dir)
 
 	parent.children[otherDir] = rotate(parent.children[otherDir], otherDir)
@@ -439,7 +498,13 @@ func normalizeComparison(i int) int {
 
 // New allocates, initializes, and returns a new immutable AVL tree.
 func New[T Comparable[T]]() *Immutable[T] {
-	immutable := &Immutable[T]{}
+	return NewWithCompareFunc(common.MethodCompare[T]())
+}
+
+// NewWithCompareFunc is like New, but orders entries with the provided
+// function rather than requiring them to implement Comparable.
+func NewWithCompareFunc[T any](compare common.CompareFunc[T]) *Immutable[T] {
+	immutable := &Immutable[T]{compare: compare}
 	immutable.init()
 	return immutable
 }
@@ -17,9 +17,14 @@ limitations under the License.
 package avl
 
 import (
+	"slices"
+	"strings"
 	"testing"
+	"unsafe"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/Workiva/go-datastructures/common"
 )
 
 func generateMockEntries(num int) []mockEntry {
@@ -477,3 +482,48 @@ func BenchmarkImmutableBulkDelete(b *testing.B) {
 		sl.Delete(entries...)
 	}
 }
+
+func TestCompareFunc(t *testing.T) {
+	type user struct {
+		name string
+		age  int
+	}
+	immutable := NewWithCompareFunc(func(a, b user) int {
+		return strings.Compare(a.name, b.name)
+	})
+	immutable, overwritten, _ := immutable.Insert(user{"carol", 30}, user{"alice", 25})
+	assert.Equal(t, []user{{}, {}}, overwritten)
+
+	immutable, overwritten, _ = immutable.Insert(user{"alice", 26})
+	assert.Equal(t, []user{{"alice", 25}}, overwritten)
+	assert.Equal(t, uint64(2), immutable.Len())
+
+	result, found := immutable.Get(user{name: "alice"}, user{name: "bob"})
+	assert.Equal(t, []user{{"alice", 26}, {}}, result)
+	assert.Equal(t, []bool{true, false}, found)
+}
+
+func TestAllBackward(t *testing.T) {
+	tree := NewWithCompareFunc(common.OrderedCompare[int]())
+	assert.Empty(t, slices.Collect(tree.All()))
+
+	tree, _, _ = tree.Insert(5, 3, 8, 1, 4, 7, 9, 2, 6)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9}, slices.Collect(tree.All()))
+	assert.Equal(t, []int{9, 8, 7, 6, 5, 4, 3, 2, 1}, slices.Collect(tree.Backward()))
+	for entry := range tree.All() {
+		assert.Equal(t, 1, entry)
+		break
+	}
+}
+
+func TestMemStats(t *testing.T) {
+	empty := NewWithCompareFunc(common.OrderedCompare[int]())
+	assert.Equal(t, common.MemStats{Bytes: empty.MemStats().Bytes}, empty.MemStats())
+
+	tree, _, _ := empty.Insert(3, 1, 2)
+	stats := tree.MemStats()
+	assert.Equal(t, uint64(3), stats.Items)
+	assert.Equal(t, uint64(3), stats.Nodes)
+	assert.Zero(t, stats.Slots)
+	assert.Equal(t, empty.MemStats().Bytes+3*uint64(unsafe.Sizeof(node[int]{})), stats.Bytes)
+}
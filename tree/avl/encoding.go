@@ -0,0 +1,54 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package avl
+
+import "github.com/Workiva/go-datastructures/codec"
+
+// MarshalBinary encodes the tree's entries, in order, with the codec
+// package's encoding for their type.
+func (immutable *Immutable[T]) MarshalBinary() ([]byte, error) {
+	entries, err := codec.For[T]()
+	if err != nil {
+		return nil, err
+	}
+	return codec.MarshalSeq(immutable.All(), entries)
+}
+
+// UnmarshalBinary decodes entries encoded by MarshalBinary, setting the
+// tree to one holding them along with those it already held.  The tree
+// must have been created with New or NewWithCompareFunc so that it
+// knows how to order them.  Since trees are immutable, a tree sharing
+// nodes with this one is unaffected.
+func (immutable *Immutable[T]) UnmarshalBinary(data []byte) error {
+	if immutable.compare == nil {
+		return errUnmarshalUninitialized
+	}
+	entries, err := codec.For[T]()
+	if err != nil {
+		return err
+	}
+	var decoded []T
+	err = codec.UnmarshalSeq(data, entries, func(entry T) {
+		decoded = append(decoded, entry)
+	})
+	if err != nil {
+		return err
+	}
+	tree, _, _ := immutable.Insert(decoded...)
+	*immutable = *tree
+	return nil
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package avl
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+func TestImmutableBinary(t *testing.T) {
+	tree := NewWithCompareFunc(common.OrderedCompare[string]())
+	tree, _, _ = tree.Insert(`m`, `c`, `x`, `a`, `e`)
+	data, err := tree.MarshalBinary()
+	require.NoError(t, err)
+
+	original := NewWithCompareFunc(common.OrderedCompare[string]())
+	original, _, _ = original.Insert(`b`)
+	decoded := *original
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, uint64(6), decoded.Len())
+	assert.Equal(t, uint64(1), original.Len())
+	assert.Equal(t, []string{`a`, `b`, `c`, `e`, `m`, `x`}, slices.Collect(decoded.All()))
+
+	assert.Error(t, decoded.UnmarshalBinary(data[:len(data)-1]))
+	assert.Equal(t, uint64(6), decoded.Len())
+	assert.Equal(t, errUnmarshalUninitialized, (&Immutable[string]{}).UnmarshalBinary(data))
+}
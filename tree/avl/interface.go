@@ -16,14 +16,14 @@ limitations under the License.
 
 package avl
 
+import "github.com/Workiva/go-datastructures/common"
+
 // Comparable is a constraint for types that can be compared for ordering.
 // The Compare method should return:
 //   - negative value if receiver < other
 //   - zero if receiver == other
 //   - positive value if receiver > other
-type Comparable[T any] interface {
-	Compare(other T) int
-}
+type Comparable[T any] = common.ComparableItem[T]
 
 // Entry is kept for backward compatibility.
 // Deprecated: Use Comparable[T] with the generic Immutable[T] instead.
@@ -0,0 +1,107 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package avl
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Workiva/go-datastructures/common"
+	"github.com/Workiva/go-datastructures/internal/check"
+)
+
+// checkInvariants returns an error if any node's recorded balance is
+// wrong or beyond one, or if the entries are out of order.
+func checkInvariants[T any](tree *Immutable[T]) error {
+	var count uint64
+	var height func(n *node[T]) (int, error)
+	height = func(n *node[T]) (int, error) {
+		if n == nil {
+			return 0, nil
+		}
+		count++
+		left, err := height(n.children[0])
+		if err != nil {
+			return 0, err
+		}
+		right, err := height(n.children[1])
+		if err != nil {
+			return 0, err
+		}
+		if balance := right - left; balance != int(n.balance) || balance < -1 || balance > 1 {
+			return 0, check.Errorf(`node %v has balance %d, but its subtrees differ by %d`, n.entry, n.balance, balance)
+		}
+		return max(left, right) + 1, nil
+	}
+
+	if _, err := height(tree.root); err != nil {
+		return err
+	}
+	if count != tree.Len() {
+		return check.Errorf(`%d nodes in a tree of length %d`, count, tree.Len())
+	}
+	return check.Sorted(tree.All(), tree.compare, true)
+}
+
+func TestInvariants(t *testing.T) {
+	tree := NewWithCompareFunc(common.OrderedCompare[int]())
+	require.NoError(t, checkInvariants(tree))
+	for i := range 1000 {
+		tree, _, _ = tree.Insert((i * 7919) % 1000)
+	}
+	require.NoError(t, checkInvariants(tree))
+	for i := range 500 {
+		tree, _, _ = tree.Delete(i * 2)
+	}
+	require.NoError(t, checkInvariants(tree))
+
+	tree.root.balance = 2
+	assert.Error(t, checkInvariants(tree))
+}
+
+func FuzzImmutable(f *testing.F) {
+	f.Add([]byte{0, 1, 0, 2, 0, 3, 1, 2, 2, 3, 0, 0, 1, 1})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		tree := NewWithCompareFunc(common.OrderedCompare[int]())
+		model := map[int]bool{}
+		for op := range check.Ops(data, 3) {
+			// every version of the tree is left as it was
+			previous, keys := tree, slices.Sorted(maps.Keys(model))
+			switch op.Kind {
+			case 0:
+				tree, _, _ = tree.Insert(op.Key)
+				model[op.Key] = true
+			case 1:
+				var deleted []bool
+				tree, _, deleted = tree.Delete(op.Key)
+				assert.Equal(t, model[op.Key], deleted[0])
+				delete(model, op.Key)
+			case 2:
+				_, found := tree.Get(op.Key)
+				assert.Equal(t, model[op.Key], found[0])
+			}
+			require.NoError(t, checkInvariants(tree))
+			require.NoError(t, checkInvariants(previous))
+			assert.Equal(t, keys, slices.Collect(previous.All()))
+		}
+		assert.Equal(t, slices.Sorted(maps.Keys(model)), slices.Collect(tree.All()))
+	})
+}
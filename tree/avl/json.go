@@ -0,0 +1,52 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package avl
+
+import (
+	"errors"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+var errUnmarshalUninitialized = errors.New(`avl: cannot unmarshal into a tree not created with New`)
+
+// MarshalJSON encodes the tree as a JSON array of its entries, in
+// order.
+func (immutable *Immutable[T]) MarshalJSON() ([]byte, error) {
+	return common.MarshalJSONArray(immutable.All(), common.DefaultJSONCodec[T]())
+}
+
+// UnmarshalJSON decodes a JSON array, setting the tree to one holding
+// its entries along with those it already held.  The tree must have
+// been created with New or NewWithCompareFunc so that it knows how to
+// order them.  Since trees are immutable, a tree sharing nodes with
+// this one is unaffected.
+func (immutable *Immutable[T]) UnmarshalJSON(data []byte) error {
+	if immutable.compare == nil {
+		return errUnmarshalUninitialized
+	}
+	var entries []T
+	err := common.UnmarshalJSONArray(data, common.DefaultJSONCodec[T](), func(entry T) {
+		entries = append(entries, entry)
+	})
+	if err != nil {
+		return err
+	}
+	tree, _, _ := immutable.Insert(entries...)
+	*immutable = *tree
+	return nil
+}
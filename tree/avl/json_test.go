@@ -0,0 +1,46 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package avl
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+func TestImmutableJSON(t *testing.T) {
+	tree := NewWithCompareFunc(common.OrderedCompare[string]())
+	tree, _, _ = tree.Insert(`m`, `c`, `x`, `a`, `e`)
+	b, err := json.Marshal(tree)
+	require.NoError(t, err)
+	assert.Equal(t, `["a","c","e","m","x"]`, string(b))
+
+	original := NewWithCompareFunc(common.OrderedCompare[string]())
+	original, _, _ = original.Insert(`b`)
+	decoded := original
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, uint64(6), decoded.Len())
+	b, err = json.Marshal(decoded)
+	require.NoError(t, err)
+	assert.Equal(t, `["a","b","c","e","m","x"]`, string(b))
+
+	assert.Error(t, json.Unmarshal(b, &Immutable[string]{}))
+}
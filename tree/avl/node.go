@@ -16,7 +16,7 @@ limitations under the License.
 
 package avl
 
-type nodes[T Comparable[T]] []*node[T]
+type nodes[T any] []*node[T]
 
 func (ns nodes[T]) reset() {
 	for i := range ns {
@@ -24,7 +24,7 @@ func (ns nodes[T]) reset() {
 	}
 }
 
-type node[T Comparable[T]] struct {
+type node[T any] struct {
 	balance  int8 // bounded, |balance| should be <= 1
 	children [2]*node[T]
 	entry    T
@@ -42,7 +42,7 @@ func (n *node[T]) copy() *node[T] {
 }
 
 // newNode returns a new node for the provided entry.
-func newNode[T Comparable[T]](entry T, hasEntry bool) *node[T] {
+func newNode[T any](entry T, hasEntry bool) *node[T] {
 	return &node[T]{
 		entry:    entry,
 		hasEntry: hasEntry,
@@ -0,0 +1,144 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package avl
+
+import (
+	"iter"
+	"sync"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+// Synchronized is a mutable tree built on Immutable that can be shared
+// between goroutines.  It holds the latest version of the tree, and
+// Insert and Delete replace that version under a mutex.  Since
+// versions never change, reads only hold the lock long enough to find
+// the latest one, and the sequences returned by All and Backward
+// iterate over the version current when iteration begins.
+type Synchronized[T any] struct {
+	lock sync.RWMutex
+	tree *Immutable[T]
+}
+
+// NewSynchronized returns a Synchronized whose first version is the
+// provided tree.
+func NewSynchronized[T any](tree *Immutable[T]) *Synchronized[T] {
+	return &Synchronized[T]{tree: tree}
+}
+
+// Snapshot returns the latest version of the tree, which is unaffected
+// by later calls to Insert and Delete.
+func (s *Synchronized[T]) Snapshot() *Immutable[T] {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.tree
+}
+
+// Insert inserts the provided entries, returning any entries that were
+// overwritten and a parallel slice of bools indicating whether each
+// one was.
+func (s *Synchronized[T]) Insert(entries ...T) ([]T, []bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	tree, overwritten, ok := s.tree.Insert(entries...)
+	s.tree = tree
+	return overwritten, ok
+}
+
+// Delete deletes the provided entries, returning the entries that were
+// deleted and a parallel slice of bools indicating whether each one
+// was found.
+func (s *Synchronized[T]) Delete(entries ...T) ([]T, []bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	tree, deleted, ok := s.tree.Delete(entries...)
+	s.tree = tree
+	return deleted, ok
+}
+
+// Get is the synchronized version of Immutable.Get.
+func (s *Synchronized[T]) Get(entries ...T) ([]T, []bool) {
+	return s.Snapshot().Get(entries...)
+}
+
+// Len is the synchronized version of Immutable.Len.
+func (s *Synchronized[T]) Len() uint64 {
+	return s.Snapshot().Len()
+}
+
+// MemStats is the synchronized version of Immutable.MemStats.
+func (s *Synchronized[T]) MemStats() common.MemStats {
+	return s.Snapshot().MemStats()
+}
+
+// All returns an iterator over the entries in the tree, in order.
+func (s *Synchronized[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.Snapshot().All()(yield)
+	}
+}
+
+// Backward returns an iterator over the entries in the tree in
+// reverse order.
+func (s *Synchronized[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.Snapshot().Backward()(yield)
+	}
+}
+
+// MarshalBinary is the synchronized version of Immutable.MarshalBinary.
+func (s *Synchronized[T]) MarshalBinary() ([]byte, error) {
+	return s.Snapshot().MarshalBinary()
+}
+
+// UnmarshalBinary is the synchronized version of
+// Immutable.UnmarshalBinary.
+func (s *Synchronized[T]) UnmarshalBinary(data []byte) error {
+	return s.replace(func(tree *Immutable[T]) error {
+		return tree.UnmarshalBinary(data)
+	})
+}
+
+// MarshalJSON is the synchronized version of Immutable.MarshalJSON.
+func (s *Synchronized[T]) MarshalJSON() ([]byte, error) {
+	return s.Snapshot().MarshalJSON()
+}
+
+// UnmarshalJSON is the synchronized version of Immutable.UnmarshalJSON.
+func (s *Synchronized[T]) UnmarshalJSON(data []byte) error {
+	return s.replace(func(tree *Immutable[T]) error {
+		return tree.UnmarshalJSON(data)
+	})
+}
+
+// replace applies fn to a copy of the latest version and, if it
+// succeeds, makes the copy the latest version.  Unmarshaling updates
+// its receiver, which must not be a version readers may hold.
+func (s *Synchronized[T]) replace(fn func(*Immutable[T]) error) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	tree := *s.tree
+	if err := fn(&tree); err != nil {
+		return err
+	}
+	s.tree = &tree
+	return nil
+}
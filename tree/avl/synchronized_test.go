@@ -0,0 +1,71 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package avl
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSynchronizedConcurrentInsert(t *testing.T) {
+	s := NewSynchronized(New[mockEntry]())
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				s.Insert(mockEntry(i*100 + j))
+				s.Get(mockEntry(j))
+				s.Len()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, uint64(400), s.Len())
+	entries := slices.Collect(s.All())
+	assert.Len(t, entries, 400)
+	assert.True(t, slices.IsSorted(entries))
+}
+
+func TestSynchronizedSnapshot(t *testing.T) {
+	s := NewSynchronized(New[mockEntry]())
+	overwritten, ok := s.Insert(1, 2, 3)
+	assert.Equal(t, []mockEntry{0, 0, 0}, overwritten)
+	assert.Equal(t, []bool{false, false, false}, ok)
+
+	snapshot := s.Snapshot()
+	deleted, ok := s.Delete(2, 4)
+	assert.Equal(t, []mockEntry{2, 0}, deleted)
+	assert.Equal(t, []bool{true, false}, ok)
+
+	assert.Equal(t, []mockEntry{1, 2, 3}, slices.Collect(snapshot.All()))
+	assert.Equal(t, []mockEntry{3, 1}, slices.Collect(s.Backward()))
+
+	data, err := s.MarshalJSON()
+	assert.NoError(t, err)
+	other := NewSynchronized(New[mockEntry]())
+	other.Insert(5)
+	before := other.Snapshot()
+	assert.NoError(t, other.UnmarshalJSON(data))
+	assert.Equal(t, []mockEntry{1, 3, 5}, slices.Collect(other.All()))
+	assert.Equal(t, []mockEntry{5}, slices.Collect(before.All()))
+}
@@ -0,0 +1,459 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package redblack implements a mutable ordered map on top of a red-black
+tree.  Unlike the immutable AVL tree, which copies a branch on every
+write, a Map is updated in place, and it stores a value alongside each
+key.
+
+Get, Put and Delete are O(log n), as are Floor and Ceiling, which find
+the closest keys at or below and at or above a given key.  Iterating
+over the map, in whole or over a range of keys, visits keys in order in
+O(log n + k) time for k keys.  A Map is not threadsafe, and it must not
+be modified while it is being iterated over.
+*/
+package redblack
+
+import (
+	"iter"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+type node[K, V any] struct {
+	key                 K
+	value               V
+	left, right, parent *node[K, V]
+	red                 bool
+}
+
+// next returns the node following n in key order, or nil if n is the
+// last node.
+func (n *node[K, V]) next() *node[K, V] {
+	if n.right != nil {
+		n = n.right
+		for n.left != nil {
+			n = n.left
+		}
+		return n
+	}
+	for n.parent != nil && n == n.parent.right {
+		n = n.parent
+	}
+	return n.parent
+}
+
+// prev returns the node preceding n in key order, or nil if n is the
+// first node.
+func (n *node[K, V]) prev() *node[K, V] {
+	if n.left != nil {
+		n = n.left
+		for n.right != nil {
+			n = n.right
+		}
+		return n
+	}
+	for n.parent != nil && n == n.parent.left {
+		n = n.parent
+	}
+	return n.parent
+}
+
+func isRed[K, V any](n *node[K, V]) bool {
+	return n != nil && n.red
+}
+
+// Map is an ordered map from keys to values.
+type Map[K, V any] struct {
+	root    *node[K, V]
+	compare common.CompareFunc[K]
+	number  int
+}
+
+// New returns an empty map ordering keys by their natural order.
+func New[K common.Ordered, V any]() *Map[K, V] {
+	return NewWithCompareFunc[K, V](common.OrderedCompare[K]())
+}
+
+// NewWithCompareFunc returns an empty map ordering keys with the
+// provided function.
+func NewWithCompareFunc[K, V any](compare common.CompareFunc[K]) *Map[K, V] {
+	return &Map[K, V]{compare: compare}
+}
+
+// Len returns the number of keys in the map.
+func (m *Map[K, V]) Len() int {
+	return m.number
+}
+
+func (m *Map[K, V]) find(key K) *node[K, V] {
+	n := m.root
+	for n != nil {
+		c := m.compare(key, n.key)
+		switch {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// Get returns the value stored for the key and whether it was found.
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	if n := m.find(key); n != nil {
+		return n.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Put stores the value for the key.  If the key was already in the map,
+// its previous value is returned along with true.
+func (m *Map[K, V]) Put(key K, value V) (V, bool) {
+	var parent *node[K, V]
+	n, c := m.root, 0
+	for n != nil {
+		parent = n
+		c = m.compare(key, n.key)
+		switch {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			old := n.value
+			n.value = value
+			return old, true
+		}
+	}
+
+	n = &node[K, V]{key: key, value: value, parent: parent, red: true}
+	switch {
+	case parent == nil:
+		m.root = n
+	case c < 0:
+		parent.left = n
+	default:
+		parent.right = n
+	}
+	m.number++
+	m.insertFixup(n)
+
+	var zero V
+	return zero, false
+}
+
+func (m *Map[K, V]) insertFixup(n *node[K, V]) {
+	for isRed(n.parent) {
+		// the parent is red so it is not the root, and the
+		// grandparent exists.
+		p := n.parent
+		g := p.parent
+		if p == g.left {
+			if u := g.right; isRed(u) {
+				p.red, u.red, g.red = false, false, true
+				n = g
+				continue
+			}
+			if n == p.right {
+				n = p
+				m.rotateLeft(n)
+				p = n.parent
+			}
+			p.red, g.red = false, true
+			m.rotateRight(g)
+		} else {
+			if u := g.left; isRed(u) {
+				p.red, u.red, g.red = false, false, true
+				n = g
+				continue
+			}
+			if n == p.left {
+				n = p
+				m.rotateRight(n)
+				p = n.parent
+			}
+			p.red, g.red = false, true
+			m.rotateLeft(g)
+		}
+	}
+	m.root.red = false
+}
+
+// Delete removes the key from the map.  If it was in the map, its value
+// is returned along with true.
+func (m *Map[K, V]) Delete(key K) (V, bool) {
+	n := m.find(key)
+	if n == nil {
+		var zero V
+		return zero, false
+	}
+
+	// child takes the place of the node removed from the tree, which
+	// is n or, if n has two children, its successor.  It may be nil,
+	// so its parent is tracked separately.
+	var child, parent *node[K, V]
+	removedRed := n.red
+	switch {
+	case n.left == nil:
+		child, parent = n.right, n.parent
+		m.transplant(n, n.right)
+	case n.right == nil:
+		child, parent = n.left, n.parent
+		m.transplant(n, n.left)
+	default:
+		s := n.right
+		for s.left != nil {
+			s = s.left
+		}
+		removedRed = s.red
+		child = s.right
+		if s.parent == n {
+			parent = s
+		} else {
+			parent = s.parent
+			m.transplant(s, s.right)
+			s.right = n.right
+			s.right.parent = s
+		}
+		m.transplant(n, s)
+		s.left = n.left
+		s.left.parent = s
+		s.red = n.red
+	}
+
+	m.number--
+	if !removedRed {
+		m.deleteFixup(child, parent)
+	}
+	return n.value, true
+}
+
+func (m *Map[K, V]) deleteFixup(n, parent *node[K, V]) {
+	for n != m.root && !isRed(n) {
+		// n carries an extra black, so its sibling cannot be nil.
+		if n == parent.left {
+			s := parent.right
+			if s.red {
+				s.red, parent.red = false, true
+				m.rotateLeft(parent)
+				s = parent.right
+			}
+			if !isRed(s.left) && !isRed(s.right) {
+				s.red = true
+				n, parent = parent, parent.parent
+				continue
+			}
+			if !isRed(s.right) {
+				s.left.red, s.red = false, true
+				m.rotateRight(s)
+				s = parent.right
+			}
+			s.red, parent.red, s.right.red = parent.red, false, false
+			m.rotateLeft(parent)
+		} else {
+			s := parent.left
+			if s.red {
+				s.red, parent.red = false, true
+				m.rotateRight(parent)
+				s = parent.left
+			}
+			if !isRed(s.left) && !isRed(s.right) {
+				s.red = true
+				n, parent = parent, parent.parent
+				continue
+			}
+			if !isRed(s.left) {
+				s.right.red, s.red = false, true
+				m.rotateLeft(s)
+				s = parent.left
+			}
+			s.red, parent.red, s.left.red = parent.red, false, false
+			m.rotateRight(parent)
+		}
+		n = m.root
+	}
+	if n != nil {
+		n.red = false
+	}
+}
+
+// transplant replaces the subtree rooted at u with the one rooted at v.
+func (m *Map[K, V]) transplant(u, v *node[K, V]) {
+	switch {
+	case u.parent == nil:
+		m.root = v
+	case u == u.parent.left:
+		u.parent.left = v
+	default:
+		u.parent.right = v
+	}
+	if v != nil {
+		v.parent = u.parent
+	}
+}
+
+func (m *Map[K, V]) rotateLeft(n *node[K, V]) {
+	r := n.right
+	n.right = r.left
+	if r.left != nil {
+		r.left.parent = n
+	}
+	m.transplant(n, r)
+	r.left = n
+	n.parent = r
+}
+
+func (m *Map[K, V]) rotateRight(n *node[K, V]) {
+	l := n.left
+	n.left = l.right
+	if l.right != nil {
+		l.right.parent = n
+	}
+	m.transplant(n, l)
+	l.right = n
+	n.parent = l
+}
+
+// floor returns the node with the greatest key less than or equal to
+// the key, or nil if there is none.
+func (m *Map[K, V]) floor(key K) *node[K, V] {
+	var best *node[K, V]
+	for n := m.root; n != nil; {
+		c := m.compare(key, n.key)
+		switch {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			best = n
+			n = n.right
+		default:
+			return n
+		}
+	}
+	return best
+}
+
+// ceiling returns the node with the least key greater than or equal to
+// the key, or nil if there is none.
+func (m *Map[K, V]) ceiling(key K) *node[K, V] {
+	var best *node[K, V]
+	for n := m.root; n != nil; {
+		c := m.compare(key, n.key)
+		switch {
+		case c < 0:
+			best = n
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n
+		}
+	}
+	return best
+}
+
+func entry[K, V any](n *node[K, V]) (K, V, bool) {
+	if n == nil {
+		var (
+			key   K
+			value V
+		)
+		return key, value, false
+	}
+	return n.key, n.value, true
+}
+
+// Floor returns the greatest key less than or equal to the provided key,
+// along with its value.  The bool is false if there is no such key.
+func (m *Map[K, V]) Floor(key K) (K, V, bool) {
+	return entry(m.floor(key))
+}
+
+// Ceiling returns the least key greater than or equal to the provided
+// key, along with its value.  The bool is false if there is no such key.
+func (m *Map[K, V]) Ceiling(key K) (K, V, bool) {
+	return entry(m.ceiling(key))
+}
+
+func (m *Map[K, V]) first() *node[K, V] {
+	n := m.root
+	for n != nil && n.left != nil {
+		n = n.left
+	}
+	return n
+}
+
+func (m *Map[K, V]) last() *node[K, V] {
+	n := m.root
+	for n != nil && n.right != nil {
+		n = n.right
+	}
+	return n
+}
+
+// Min returns the least key in the map along with its value.  The bool
+// is false if the map is empty.
+func (m *Map[K, V]) Min() (K, V, bool) {
+	return entry(m.first())
+}
+
+// Max returns the greatest key in the map along with its value.  The
+// bool is false if the map is empty.
+func (m *Map[K, V]) Max() (K, V, bool) {
+	return entry(m.last())
+}
+
+// All returns an iterator over the keys and values in the map in
+// ascending key order.
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for n := m.first(); n != nil; n = n.next() {
+			if !yield(n.key, n.value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the keys and values in the map in
+// descending key order.
+func (m *Map[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for n := m.last(); n != nil; n = n.prev() {
+			if !yield(n.key, n.value) {
+				return
+			}
+		}
+	}
+}
+
+// Range returns an iterator over the keys in [from, to), and their
+// values, in ascending key order.
+func (m *Map[K, V]) Range(from, to K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for n := m.ceiling(from); n != nil && m.compare(n.key, to) < 0; n = n.next() {
+			if !yield(n.key, n.value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package redblack
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+// checkInvariants verifies the parent links, key order and red-black
+// properties of the subtree, returning its black height.
+func checkInvariants[K, V any](t *testing.T, m *Map[K, V], n *node[K, V]) int {
+	if n == nil {
+		return 1
+	}
+	if n.red {
+		require.False(t, isRed(n.left) || isRed(n.right), "red node has a red child")
+	}
+	for _, c := range []*node[K, V]{n.left, n.right} {
+		if c != nil {
+			require.Same(t, n, c.parent)
+		}
+	}
+	if n.left != nil {
+		require.Negative(t, m.compare(n.left.key, n.key))
+	}
+	if n.right != nil {
+		require.Positive(t, m.compare(n.right.key, n.key))
+	}
+
+	left, right := checkInvariants(t, m, n.left), checkInvariants(t, m, n.right)
+	require.Equal(t, left, right, "black heights differ")
+	if n.red {
+		return left
+	}
+	return left + 1
+}
+
+func collect[K, V any](seq func(func(K, V) bool)) []K {
+	var keys []K
+	for k := range seq {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func TestPutGetDelete(t *testing.T) {
+	m := New[int, string]()
+
+	_, ok := m.Get(1)
+	assert.False(t, ok)
+
+	old, replaced := m.Put(1, "one")
+	assert.False(t, replaced)
+	assert.Equal(t, "", old)
+	m.Put(2, "two")
+
+	old, replaced = m.Put(1, "uno")
+	assert.True(t, replaced)
+	assert.Equal(t, "one", old)
+	assert.Equal(t, 2, m.Len())
+
+	value, ok := m.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, "uno", value)
+
+	value, ok = m.Delete(1)
+	assert.True(t, ok)
+	assert.Equal(t, "uno", value)
+	_, ok = m.Delete(1)
+	assert.False(t, ok)
+	assert.Equal(t, 1, m.Len())
+	_, ok = m.Get(1)
+	assert.False(t, ok)
+}
+
+func TestFloorCeiling(t *testing.T) {
+	m := New[int, int]()
+	for _, k := range []int{10, 20, 30} {
+		m.Put(k, k*10)
+	}
+
+	tests := []struct {
+		key                  int
+		floor, ceiling       int
+		hasFloor, hasCeiling bool
+	}{
+		{5, 0, 10, false, true},
+		{10, 10, 10, true, true},
+		{15, 10, 20, true, true},
+		{30, 30, 30, true, true},
+		{35, 30, 0, true, false},
+	}
+	for _, tt := range tests {
+		k, v, ok := m.Floor(tt.key)
+		assert.Equal(t, tt.hasFloor, ok, "floor of %d", tt.key)
+		if ok {
+			assert.Equal(t, tt.floor, k)
+			assert.Equal(t, tt.floor*10, v)
+		}
+
+		k, v, ok = m.Ceiling(tt.key)
+		assert.Equal(t, tt.hasCeiling, ok, "ceiling of %d", tt.key)
+		if ok {
+			assert.Equal(t, tt.ceiling, k)
+			assert.Equal(t, tt.ceiling*10, v)
+		}
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	m := New[int, int]()
+	_, _, ok := m.Min()
+	assert.False(t, ok)
+	_, _, ok = m.Max()
+	assert.False(t, ok)
+
+	for _, k := range []int{5, 3, 9, 1} {
+		m.Put(k, -k)
+	}
+	k, v, ok := m.Min()
+	assert.True(t, ok)
+	assert.Equal(t, 1, k)
+	assert.Equal(t, -1, v)
+	k, _, ok = m.Max()
+	assert.True(t, ok)
+	assert.Equal(t, 9, k)
+}
+
+func TestIteration(t *testing.T) {
+	m := New[int, int]()
+	for _, k := range []int{4, 8, 2, 6, 0} {
+		m.Put(k, k)
+	}
+
+	assert.Equal(t, []int{0, 2, 4, 6, 8}, collect(m.All()))
+	assert.Equal(t, []int{8, 6, 4, 2, 0}, collect(m.Backward()))
+	assert.Equal(t, []int{2, 4, 6}, collect(m.Range(1, 8)))
+	assert.Equal(t, []int{4}, collect(m.Range(4, 5)))
+	assert.Empty(t, collect(m.Range(5, 6)))
+	assert.Empty(t, collect(m.Range(9, 20)))
+
+	var keys []int
+	for k := range m.All() {
+		keys = append(keys, k)
+		if k == 4 {
+			break
+		}
+	}
+	assert.Equal(t, []int{0, 2, 4}, keys)
+}
+
+func TestCompareFunc(t *testing.T) {
+	m := NewWithCompareFunc[string, int](common.By(strings.ToLower))
+	m.Put("b", 1)
+	m.Put("A", 2)
+	m.Put("B", 3)
+
+	assert.Equal(t, 2, m.Len())
+	assert.Equal(t, []string{"A", "b"}, collect(m.All()))
+	value, ok := m.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+	value, _ = m.Get("b")
+	assert.Equal(t, 3, value)
+}
+
+func TestRandomOperations(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	m := New[int, int]()
+	expected := make(map[int]int)
+
+	for i := range 20000 {
+		key := rng.Intn(500)
+		if rng.Intn(2) == 0 {
+			old, replaced := m.Put(key, i)
+			prev, ok := expected[key]
+			require.Equal(t, ok, replaced)
+			require.Equal(t, prev, old)
+			expected[key] = i
+		} else {
+			old, ok := m.Delete(key)
+			prev, existed := expected[key]
+			require.Equal(t, existed, ok)
+			require.Equal(t, prev, old)
+			delete(expected, key)
+		}
+
+		if i%100 != 0 {
+			continue
+		}
+		require.Equal(t, len(expected), m.Len())
+		checkInvariants(t, m, m.root)
+		require.False(t, isRed(m.root))
+
+		keys := make([]int, 0, len(expected))
+		for k := range expected {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		require.Equal(t, keys, collect(m.All()))
+	}
+}
+
+func BenchmarkPut(b *testing.B) {
+	keys := rand.New(rand.NewSource(1)).Perm(100000)
+	for b.Loop() {
+		m := New[int, int]()
+		for _, k := range keys {
+			m.Put(k, k)
+		}
+	}
+}
+
+func BenchmarkGet(b *testing.B) {
+	keys := rand.New(rand.NewSource(1)).Perm(100000)
+	m := New[int, int]()
+	for _, k := range keys {
+		m.Put(k, k)
+	}
+
+	for i := 0; b.Loop(); i++ {
+		m.Get(keys[i%len(keys)])
+	}
+}
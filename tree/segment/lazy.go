@@ -0,0 +1,202 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package segment
+
+import "math/bits"
+
+// Updater describes how an update of type U changes the values in a
+// Lazy tree.  Apply returns the aggregate of n values after the update
+// is applied to each of them, given their aggregate before.  Compose
+// returns a single update with the same effect as applying first and
+// then second.
+//
+// For example, adding to values is applied to their sum by adding the
+// addend multiplied by n, and to their minimum by adding the addend,
+// while assigning values is applied to their sum by multiplying by n.
+type Updater[T, U any] struct {
+	Apply   func(update U, aggregate T, n int) T
+	Compose func(first, second U) U
+}
+
+// Lazy aggregates ranges of a fixed length sequence of values that can
+// be updated a range at a time.
+type Lazy[T, U any] struct {
+	monoid  Monoid[T]
+	updater Updater[T, U]
+	// nodes is a complete binary tree rooted at 1, whose leaves, from
+	// size on, are the values padded with the identity.  pending holds
+	// the update yet to be applied to the children of each branch.
+	nodes   []T
+	pending []U
+	has     []bool
+	size    int
+	n       int
+}
+
+// NewLazy returns a Lazy tree over a copy of values.
+func NewLazy[T, U any](monoid Monoid[T], updater Updater[T, U], values []T) *Lazy[T, U] {
+	n := len(values)
+	size := 1
+	if n > 1 {
+		size = 1 << bits.Len(uint(n-1))
+	}
+	t := &Lazy[T, U]{
+		monoid:  monoid,
+		updater: updater,
+		nodes:   make([]T, 2*size),
+		pending: make([]U, size),
+		has:     make([]bool, size),
+		size:    size,
+		n:       n,
+	}
+	copy(t.nodes[size:], values)
+	for i := size + n; i < 2*size; i++ {
+		t.nodes[i] = monoid.Identity
+	}
+	for i := size - 1; i > 0; i-- {
+		t.nodes[i] = monoid.Combine(t.nodes[2*i], t.nodes[2*i+1])
+	}
+	return t
+}
+
+// Len returns the number of values in the tree.
+func (t *Lazy[T, U]) Len() int {
+	return t.n
+}
+
+// Get returns the value at index i in O(log n) time.
+func (t *Lazy[T, U]) Get(i int) T {
+	checkIndex(i, t.n)
+	leaf := t.size + i
+	t.push(leaf)
+	return t.nodes[leaf]
+}
+
+// Set changes the value at index i in O(log n) time.
+func (t *Lazy[T, U]) Set(i int, value T) {
+	checkIndex(i, t.n)
+	leaf := t.size + i
+	t.push(leaf)
+	t.nodes[leaf] = value
+	t.pull(leaf)
+}
+
+// Update applies the update to each value in [from, to) in O(log n)
+// time.
+func (t *Lazy[T, U]) Update(from, to int, update U) {
+	checkRange(from, to, t.n)
+	if from == to {
+		return
+	}
+	t.update(1, 0, t.size, from, to, update)
+}
+
+func (t *Lazy[T, U]) update(node, low, high, from, to int, update U) {
+	if from <= low && high <= to {
+		t.apply(node, high-low, update)
+		return
+	}
+	t.pushDown(node, high-low)
+	mid := (low + high) / 2
+	if from < mid {
+		t.update(2*node, low, mid, from, to, update)
+	}
+	if to > mid {
+		t.update(2*node+1, mid, high, from, to, update)
+	}
+	t.nodes[node] = t.monoid.Combine(t.nodes[2*node], t.nodes[2*node+1])
+}
+
+// Query returns the aggregate of the values in [from, to) in O(log n)
+// time, or the monoid's identity if the range is empty.
+func (t *Lazy[T, U]) Query(from, to int) T {
+	checkRange(from, to, t.n)
+	if from == to {
+		return t.monoid.Identity
+	}
+	return t.query(1, 0, t.size, from, to)
+}
+
+func (t *Lazy[T, U]) query(node, low, high, from, to int) T {
+	if from <= low && high <= to {
+		return t.nodes[node]
+	}
+	t.pushDown(node, high-low)
+	mid := (low + high) / 2
+	switch {
+	case to <= mid:
+		return t.query(2*node, low, mid, from, to)
+	case from >= mid:
+		return t.query(2*node+1, mid, high, from, to)
+	}
+	return t.monoid.Combine(
+		t.query(2*node, low, mid, from, to),
+		t.query(2*node+1, mid, high, from, to),
+	)
+}
+
+// Values returns a copy of the values in the tree, applying any
+// pending updates, in O(n) time.
+func (t *Lazy[T, U]) Values() []T {
+	for node := 1; node < t.size; node++ {
+		t.pushDown(node, t.size>>(bits.Len(uint(node))-1))
+	}
+	values := make([]T, t.n)
+	copy(values, t.nodes[t.size:])
+	return values
+}
+
+// apply applies the update to the n values under the node, deferring
+// it for the node's children.
+func (t *Lazy[T, U]) apply(node, n int, update U) {
+	t.nodes[node] = t.updater.Apply(update, t.nodes[node], n)
+	if node >= t.size {
+		return
+	}
+	if t.has[node] {
+		update = t.updater.Compose(t.pending[node], update)
+	}
+	t.pending[node], t.has[node] = update, true
+}
+
+// pushDown applies the update pending at the node, which has n values
+// under it, to its children.
+func (t *Lazy[T, U]) pushDown(node, n int) {
+	if !t.has[node] {
+		return
+	}
+	t.apply(2*node, n/2, t.pending[node])
+	t.apply(2*node+1, n/2, t.pending[node])
+	var zero U
+	t.pending[node], t.has[node] = zero, false
+}
+
+// push applies every update pending above the leaf, from the root
+// down.
+func (t *Lazy[T, U]) push(leaf int) {
+	height := bits.Len(uint(t.size)) - 1
+	for h := height; h > 0; h-- {
+		t.pushDown(leaf>>h, 1<<h)
+	}
+}
+
+// pull recomputes the aggregates above the leaf.
+func (t *Lazy[T, U]) pull(leaf int) {
+	for node := leaf / 2; node > 0; node /= 2 {
+		t.nodes[node] = t.monoid.Combine(t.nodes[2*node], t.nodes[2*node+1])
+	}
+}
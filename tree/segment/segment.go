@@ -0,0 +1,152 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package segment implements segment trees, which aggregate the values in
+any range of a sequence in O(log n) time while still allowing values to
+be changed.  The aggregate is described by a Monoid, such as the sum,
+minimum or maximum of the values, or anything else with an associative
+combining function and an identity.
+
+A Tree supports changing one value at a time.  A Lazy tree additionally
+supports updating every value in a range at once, such as adding to or
+assigning them all, by deferring the update of each subtree until it is
+next visited.  Both take O(n) space, and neither is threadsafe.
+
+Ranges are half-open, running from the first index up to but excluding
+the second, and like slicing, the methods panic if given a range or an
+index that is out of bounds.
+*/
+package segment
+
+import (
+	"cmp"
+	"fmt"
+)
+
+// Number is a constraint matching the integer and floating point types.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Monoid describes how values are aggregated.  Combine must be
+// associative, and combining any value with Identity, on either side,
+// must return the value unchanged.  Combine need not be commutative:
+// the left argument always aggregates the lower indices.
+type Monoid[T any] struct {
+	Identity T
+	Combine  func(a, b T) T
+}
+
+// Sum returns a Monoid that adds values.
+func Sum[T Number]() Monoid[T] {
+	return Monoid[T]{Combine: func(a, b T) T { return a + b }}
+}
+
+// Min returns a Monoid that finds the least value.  Identity must be
+// at least as great as every value, such as math.MaxInt or +Inf.
+func Min[T cmp.Ordered](identity T) Monoid[T] {
+	return Monoid[T]{Identity: identity, Combine: func(a, b T) T { return min(a, b) }}
+}
+
+// Max returns a Monoid that finds the greatest value.  Identity must
+// be at most as great as every value, such as math.MinInt or -Inf.
+func Max[T cmp.Ordered](identity T) Monoid[T] {
+	return Monoid[T]{Identity: identity, Combine: func(a, b T) T { return max(a, b) }}
+}
+
+// Tree aggregates ranges of a fixed length sequence of values that can
+// be changed one at a time.
+type Tree[T any] struct {
+	monoid Monoid[T]
+	// nodes holds the values at n through 2n-1, and at each i below n,
+	// the aggregate of nodes 2i and 2i+1.
+	nodes []T
+	n     int
+}
+
+// New returns a Tree over a copy of values.
+func New[T any](monoid Monoid[T], values []T) *Tree[T] {
+	n := len(values)
+	t := &Tree[T]{monoid: monoid, nodes: make([]T, 2*n), n: n}
+	copy(t.nodes[n:], values)
+	for i := n - 1; i > 0; i-- {
+		t.nodes[i] = monoid.Combine(t.nodes[2*i], t.nodes[2*i+1])
+	}
+	return t
+}
+
+// Len returns the number of values in the tree.
+func (t *Tree[T]) Len() int {
+	return t.n
+}
+
+// Get returns the value at index i.
+func (t *Tree[T]) Get(i int) T {
+	checkIndex(i, t.n)
+	return t.nodes[t.n+i]
+}
+
+// Set changes the value at index i in O(log n) time.
+func (t *Tree[T]) Set(i int, value T) {
+	checkIndex(i, t.n)
+	i += t.n
+	t.nodes[i] = value
+	for i /= 2; i > 0; i /= 2 {
+		t.nodes[i] = t.monoid.Combine(t.nodes[2*i], t.nodes[2*i+1])
+	}
+}
+
+// Query returns the aggregate of the values in [from, to) in O(log n)
+// time, or the monoid's identity if the range is empty.
+func (t *Tree[T]) Query(from, to int) T {
+	checkRange(from, to, t.n)
+	// the aggregates from either end are kept apart so that they are
+	// combined in order, for monoids that are not commutative.
+	left, right := t.monoid.Identity, t.monoid.Identity
+	for from, to = from+t.n, to+t.n; from < to; from, to = from/2, to/2 {
+		if from&1 == 1 {
+			left = t.monoid.Combine(left, t.nodes[from])
+			from++
+		}
+		if to&1 == 1 {
+			to--
+			right = t.monoid.Combine(t.nodes[to], right)
+		}
+	}
+	return t.monoid.Combine(left, right)
+}
+
+// Values returns a copy of the values in the tree.
+func (t *Tree[T]) Values() []T {
+	values := make([]T, t.n)
+	copy(values, t.nodes[t.n:])
+	return values
+}
+
+func checkIndex(i, n int) {
+	if i < 0 || i >= n {
+		panic(fmt.Sprintf("segment: index %d out of range [0:%d]", i, n))
+	}
+}
+
+func checkRange(from, to, n int) {
+	if from < 0 || to < from || to > n {
+		panic(fmt.Sprintf("segment: range [%d:%d] out of range [0:%d]", from, to, n))
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package segment
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTreeSum(t *testing.T) {
+	tree := New(Sum[int](), []int{5, 3, 8, 1, 4})
+	assert.Equal(t, 5, tree.Len())
+	assert.Equal(t, 21, tree.Query(0, 5))
+	assert.Equal(t, 12, tree.Query(1, 4))
+	assert.Equal(t, 0, tree.Query(2, 2))
+
+	tree.Set(2, -2)
+	assert.Equal(t, -2, tree.Get(2))
+	assert.Equal(t, 11, tree.Query(0, 5))
+	assert.Equal(t, []int{5, 3, -2, 1, 4}, tree.Values())
+}
+
+func TestTreeNotCommutative(t *testing.T) {
+	concat := Monoid[string]{Combine: func(a, b string) string { return a + b }}
+	values := strings.Split("the quick brown fox jumps", "")
+	tree := New(concat, values)
+	for from := 0; from <= len(values); from++ {
+		for to := from; to <= len(values); to++ {
+			assert.Equal(t, strings.Join(values[from:to], ""), tree.Query(from, to))
+		}
+	}
+}
+
+func TestTreeOutOfRange(t *testing.T) {
+	tree := New(Max(math.MinInt), []int{1, 2, 3})
+	assert.Panics(t, func() { tree.Get(3) })
+	assert.Panics(t, func() { tree.Set(-1, 0) })
+	assert.Panics(t, func() { tree.Query(2, 1) })
+	assert.Panics(t, func() { tree.Query(0, 4) })
+
+	empty := New(Sum[int](), nil)
+	assert.Equal(t, 0, empty.Len())
+	assert.Equal(t, 0, empty.Query(0, 0))
+	assert.Panics(t, func() { empty.Get(0) })
+}
+
+func TestTreeRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	for round := 0; round < 20; round++ {
+		values := make([]int, rng.Intn(100))
+		for i := range values {
+			values[i] = rng.Intn(1000) - 500
+		}
+		tree := New(Min(math.MaxInt), values)
+		for op := 0; op < 500; op++ {
+			if len(values) > 0 && rng.Intn(2) == 0 {
+				i := rng.Intn(len(values))
+				values[i] = rng.Intn(1000) - 500
+				tree.Set(i, values[i])
+				continue
+			}
+			from := rng.Intn(len(values) + 1)
+			to := from + rng.Intn(len(values)-from+1)
+			expected := math.MaxInt
+			for _, v := range values[from:to] {
+				expected = min(expected, v)
+			}
+			assert.Equal(t, expected, tree.Query(from, to))
+		}
+		assert.Equal(t, values, tree.Values())
+	}
+}
+
+func addToSum() Updater[int, int] {
+	return Updater[int, int]{
+		Apply:   func(addend, sum, n int) int { return sum + addend*n },
+		Compose: func(first, second int) int { return first + second },
+	}
+}
+
+func TestLazyAddToSum(t *testing.T) {
+	tree := NewLazy(Sum[int](), addToSum(), []int{1, 2, 3, 4, 5})
+	tree.Update(1, 4, 10)
+	assert.Equal(t, 45, tree.Query(0, 5))
+	assert.Equal(t, 12, tree.Get(1))
+	assert.Equal(t, 32, tree.Query(2, 5))
+
+	tree.Set(2, 0)
+	tree.Update(0, 5, -1)
+	assert.Equal(t, []int{0, 11, -1, 13, 4}, tree.Values())
+	assert.Equal(t, 27, tree.Query(0, 5))
+}
+
+// affine is the update x -> mul*x + add.
+type affine struct{ mul, add int }
+
+func TestLazyRandom(t *testing.T) {
+	// assigning and adding are both affine updates, which compose, and
+	// apply to sums and to the number of values alike.
+	type agg struct{ sum, count int }
+	monoid := Monoid[agg]{Combine: func(a, b agg) agg { return agg{a.sum + b.sum, a.count + b.count} }}
+	updater := Updater[agg, affine]{
+		Apply: func(u affine, a agg, n int) agg {
+			return agg{u.mul*a.sum + u.add*a.count, a.count}
+		},
+		Compose: func(first, second affine) affine {
+			return affine{first.mul * second.mul, second.mul*first.add + second.add}
+		},
+	}
+
+	rng := rand.New(rand.NewSource(11))
+	for round := 0; round < 20; round++ {
+		values := make([]int, rng.Intn(70))
+		aggs := make([]agg, len(values))
+		for i := range values {
+			values[i] = rng.Intn(100)
+			aggs[i] = agg{values[i], 1}
+		}
+		tree := NewLazy(monoid, updater, aggs)
+		for op := 0; op < 500; op++ {
+			from := rng.Intn(len(values) + 1)
+			to := from + rng.Intn(len(values)-from+1)
+			switch rng.Intn(4) {
+			case 0:
+				u := affine{mul: rng.Intn(2), add: rng.Intn(10) - 5}
+				for i := from; i < to; i++ {
+					values[i] = u.mul*values[i] + u.add
+				}
+				tree.Update(from, to, u)
+			case 1:
+				if from < len(values) {
+					values[from] = rng.Intn(100)
+					tree.Set(from, agg{values[from], 1})
+				}
+			case 2:
+				if from < len(values) {
+					assert.Equal(t, agg{values[from], 1}, tree.Get(from))
+				}
+			default:
+				var expected int
+				for _, v := range values[from:to] {
+					expected += v
+				}
+				assert.Equal(t, agg{expected, to - from}, tree.Query(from, to))
+			}
+		}
+		for i, a := range tree.Values() {
+			assert.Equal(t, agg{values[i], 1}, a)
+		}
+	}
+}
+
+func BenchmarkLazyUpdate(b *testing.B) {
+	const n = 1 << 16
+	tree := NewLazy(Sum[int](), addToSum(), make([]int, n))
+	rng := rand.New(rand.NewSource(1))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		from := rng.Intn(n)
+		tree.Update(from, from+rng.Intn(n-from+1), 1)
+	}
+}
@@ -0,0 +1,332 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package splay implements an ordered map on top of a splay tree, a
+self-adjusting binary search tree that moves every key it looks up,
+inserts or finds the neighbor of to the root.  Recently used keys are
+therefore cheap to reach again, and a workload that repeatedly touches
+a small working set of keys runs in time proportional to the log of
+the working set rather than of the whole map.
+
+Operations run in amortized O(log n) time, although any single one may
+take O(n).  As lookups restructure the tree, a Map is not threadsafe
+even for readers, and it must not be modified, or read with Get, Floor,
+Ceiling, Min or Max, while it is being iterated over.
+*/
+package splay
+
+import (
+	"iter"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+type node[K, V any] struct {
+	key         K
+	value       V
+	left, right *node[K, V]
+}
+
+// Map is an ordered map from keys to values.
+type Map[K, V any] struct {
+	root    *node[K, V]
+	compare common.CompareFunc[K]
+	number  int
+}
+
+// New returns an empty map ordering keys by their natural order.
+func New[K common.Ordered, V any]() *Map[K, V] {
+	return NewWithCompareFunc[K, V](common.OrderedCompare[K]())
+}
+
+// NewWithCompareFunc returns an empty map ordering keys with the
+// provided function.
+func NewWithCompareFunc[K, V any](compare common.CompareFunc[K]) *Map[K, V] {
+	return &Map[K, V]{compare: compare}
+}
+
+// Len returns the number of keys in the map.
+func (m *Map[K, V]) Len() int {
+	return m.number
+}
+
+// splay moves the node with the key to the root using top-down
+// splaying.  If the key is not in the map, the last node on the search
+// path, which holds either its predecessor or its successor, becomes
+// the root instead.
+func (m *Map[K, V]) splay(key K) {
+	if m.root == nil {
+		return
+	}
+
+	// header collects the left tree in its right child and the right
+	// tree in its left child, which are reassembled below the new root.
+	var header node[K, V]
+	l, r, n := &header, &header, m.root
+	for {
+		c := m.compare(key, n.key)
+		if c < 0 {
+			if n.left == nil {
+				break
+			}
+			if m.compare(key, n.left.key) < 0 {
+				child := n.left
+				n.left = child.right
+				child.right = n
+				n = child
+				if n.left == nil {
+					break
+				}
+			}
+			r.left = n
+			r = n
+			n = n.left
+		} else if c > 0 {
+			if n.right == nil {
+				break
+			}
+			if m.compare(key, n.right.key) > 0 {
+				child := n.right
+				n.right = child.left
+				child.left = n
+				n = child
+				if n.right == nil {
+					break
+				}
+			}
+			l.right = n
+			l = n
+			n = n.right
+		} else {
+			break
+		}
+	}
+
+	l.right, r.left = n.left, n.right
+	n.left, n.right = header.right, header.left
+	m.root = n
+}
+
+// Get returns the value stored for the key and whether it was found.
+// The key, or its closest neighbor, becomes the root of the tree.
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	m.splay(key)
+	if m.root != nil && m.compare(key, m.root.key) == 0 {
+		return m.root.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Put stores the value for the key, which becomes the root of the tree.
+// If the key was already in the map, its previous value is returned
+// along with true.
+func (m *Map[K, V]) Put(key K, value V) (V, bool) {
+	m.splay(key)
+
+	n := &node[K, V]{key: key, value: value}
+	if m.root != nil {
+		c := m.compare(key, m.root.key)
+		switch {
+		case c == 0:
+			old := m.root.value
+			m.root.value = value
+			return old, true
+		case c < 0:
+			n.left, n.right = m.root.left, m.root
+			m.root.left = nil
+		default:
+			n.left, n.right = m.root, m.root.right
+			m.root.right = nil
+		}
+	}
+	m.root = n
+	m.number++
+
+	var zero V
+	return zero, false
+}
+
+// Delete removes the key from the map.  If it was in the map, its value
+// is returned along with true.
+func (m *Map[K, V]) Delete(key K) (V, bool) {
+	m.splay(key)
+	if m.root == nil || m.compare(key, m.root.key) != 0 {
+		var zero V
+		return zero, false
+	}
+
+	n := m.root
+	if n.left == nil {
+		m.root = n.right
+	} else {
+		// every key on the left is less than the key, so splaying it
+		// there brings the greatest of them, which has no right child,
+		// to the root.
+		m.root = n.left
+		m.splay(key)
+		m.root.right = n.right
+	}
+	m.number--
+	return n.value, true
+}
+
+func entry[K, V any](n *node[K, V]) (K, V, bool) {
+	if n == nil {
+		var (
+			key   K
+			value V
+		)
+		return key, value, false
+	}
+	return n.key, n.value, true
+}
+
+// Floor returns the greatest key less than or equal to the provided key,
+// along with its value.  The bool is false if there is no such key.
+func (m *Map[K, V]) Floor(key K) (K, V, bool) {
+	m.splay(key)
+	if m.root == nil || m.compare(m.root.key, key) <= 0 {
+		return entry(m.root)
+	}
+	if m.root.left == nil {
+		return entry[K, V](nil)
+	}
+
+	n := m.root.left
+	for n.right != nil {
+		n = n.right
+	}
+	m.splay(n.key)
+	return entry(m.root)
+}
+
+// Ceiling returns the least key greater than or equal to the provided
+// key, along with its value.  The bool is false if there is no such key.
+func (m *Map[K, V]) Ceiling(key K) (K, V, bool) {
+	m.splay(key)
+	if m.root == nil || m.compare(m.root.key, key) >= 0 {
+		return entry(m.root)
+	}
+	if m.root.right == nil {
+		return entry[K, V](nil)
+	}
+
+	n := m.root.right
+	for n.left != nil {
+		n = n.left
+	}
+	m.splay(n.key)
+	return entry(m.root)
+}
+
+// Min returns the least key in the map along with its value.  The bool
+// is false if the map is empty.
+func (m *Map[K, V]) Min() (K, V, bool) {
+	n := m.root
+	for n != nil && n.left != nil {
+		n = n.left
+	}
+	if n != nil {
+		m.splay(n.key)
+	}
+	return entry(n)
+}
+
+// Max returns the greatest key in the map along with its value.  The
+// bool is false if the map is empty.
+func (m *Map[K, V]) Max() (K, V, bool) {
+	n := m.root
+	for n != nil && n.right != nil {
+		n = n.right
+	}
+	if n != nil {
+		m.splay(n.key)
+	}
+	return entry(n)
+}
+
+// All returns an iterator over the keys and values in the map in
+// ascending key order.  Iterating does not restructure the tree.
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		var stack []*node[K, V]
+		for n := m.root; n != nil || len(stack) > 0; n = n.right {
+			for ; n != nil; n = n.left {
+				stack = append(stack, n)
+			}
+			n = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !yield(n.key, n.value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the keys and values in the map in
+// descending key order.  Iterating does not restructure the tree.
+func (m *Map[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		var stack []*node[K, V]
+		for n := m.root; n != nil || len(stack) > 0; n = n.left {
+			for ; n != nil; n = n.right {
+				stack = append(stack, n)
+			}
+			n = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !yield(n.key, n.value) {
+				return
+			}
+		}
+	}
+}
+
+// Range returns an iterator over the keys in [from, to), and their
+// values, in ascending key order.  Starting the iteration splays from
+// to the root, after which the tree is not restructured.
+func (m *Map[K, V]) Range(from, to K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.splay(from)
+
+		// the stack starts with the path to the least key not less
+		// than from, holding the nodes whose left subtree is entered.
+		var stack []*node[K, V]
+		for n := m.root; n != nil; {
+			if m.compare(n.key, from) < 0 {
+				n = n.right
+			} else {
+				stack = append(stack, n)
+				n = n.left
+			}
+		}
+
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if m.compare(n.key, to) >= 0 {
+				return
+			}
+			if !yield(n.key, n.value) {
+				return
+			}
+			for n = n.right; n != nil; n = n.left {
+				stack = append(stack, n)
+			}
+		}
+	}
+}
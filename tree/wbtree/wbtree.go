@@ -0,0 +1,479 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package wbtree implements a persistent ordered map on top of a
+weight-balanced tree.  A Map is immutable: Put and Delete return a new
+Map that shares all but O(log n) of its nodes with the old one, which
+remains valid.
+
+Every node records the size of its subtree, which keeps the tree
+balanced and lets two trees be joined around a key in time proportional
+to the difference of their heights.  Split, Union, Intersect and
+Difference are all built on joining, so that combining a map of m keys
+with one of n >= m keys takes O(m log(n/m + 1)) time rather than the
+O(m log n) of putting the keys in one at a time, and merging two
+versions of an index that differ in a few keys is cheap.
+
+Maps are safe for concurrent use, since they never change.  Maps that
+are combined must order their keys the same way.
+*/
+package wbtree
+
+import (
+	"iter"
+
+	"github.com/Workiva/go-datastructures/common"
+)
+
+const (
+	// delta bounds how many times heavier one subtree may be than its
+	// sibling, and ratio decides between single and double rotations.
+	// Together they are one of the few integer pairs that keep every
+	// operation balanced.
+	delta = 3
+	ratio = 2
+)
+
+type node[K, V any] struct {
+	key         K
+	value       V
+	left, right *node[K, V]
+	size        int
+}
+
+func size[K, V any](n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func bin[K, V any](key K, value V, left, right *node[K, V]) *node[K, V] {
+	return &node[K, V]{key: key, value: value, left: left, right: right, size: size(left) + size(right) + 1}
+}
+
+// balance returns a node of the key and subtrees, rotating them if one
+// subtree has become too heavy after a small change to the other.
+func balance[K, V any](key K, value V, left, right *node[K, V]) *node[K, V] {
+	sl, sr := size(left), size(right)
+	switch {
+	case sl+sr <= 1:
+	case sr > delta*sl:
+		if size(right.left) < ratio*size(right.right) {
+			return bin(right.key, right.value, bin(key, value, left, right.left), right.right)
+		}
+		rl := right.left
+		return bin(rl.key, rl.value, bin(key, value, left, rl.left), bin(right.key, right.value, rl.right, right.right))
+	case sl > delta*sr:
+		if size(left.right) < ratio*size(left.left) {
+			return bin(left.key, left.value, left.left, bin(key, value, left.right, right))
+		}
+		lr := left.right
+		return bin(lr.key, lr.value, bin(left.key, left.value, left.left, lr.left), bin(key, value, lr.right, right))
+	}
+	return bin(key, value, left, right)
+}
+
+// join returns a tree of the left tree, the key and the right tree,
+// whose keys must be in that order, however different their sizes.
+func join[K, V any](key K, value V, left, right *node[K, V]) *node[K, V] {
+	switch {
+	case left == nil:
+		return insertMin(key, value, right)
+	case right == nil:
+		return insertMax(key, value, left)
+	case delta*left.size < right.size:
+		return balance(right.key, right.value, join(key, value, left, right.left), right.right)
+	case delta*right.size < left.size:
+		return balance(left.key, left.value, left.left, join(key, value, left.right, right))
+	}
+	return bin(key, value, left, right)
+}
+
+func insertMin[K, V any](key K, value V, n *node[K, V]) *node[K, V] {
+	if n == nil {
+		return bin[K, V](key, value, nil, nil)
+	}
+	return balance(n.key, n.value, insertMin(key, value, n.left), n.right)
+}
+
+func insertMax[K, V any](key K, value V, n *node[K, V]) *node[K, V] {
+	if n == nil {
+		return bin[K, V](key, value, nil, nil)
+	}
+	return balance(n.key, n.value, n.left, insertMax(key, value, n.right))
+}
+
+// merge returns a tree of the left and right trees, whose keys must be
+// in that order.
+func merge[K, V any](left, right *node[K, V]) *node[K, V] {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	case delta*left.size < right.size:
+		return balance(right.key, right.value, merge(left, right.left), right.right)
+	case delta*right.size < left.size:
+		return balance(left.key, left.value, left.left, merge(left.right, right))
+	}
+	return glue(left, right)
+}
+
+// glue returns a tree of two balanced siblings, whose keys must be in
+// order, rooted at the extreme key of the larger.
+func glue[K, V any](left, right *node[K, V]) *node[K, V] {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	case left.size > right.size:
+		last := left
+		for last.right != nil {
+			last = last.right
+		}
+		return balance(last.key, last.value, deleteMax(left), right)
+	}
+	first := right
+	for first.left != nil {
+		first = first.left
+	}
+	return balance(first.key, first.value, left, deleteMin(right))
+}
+
+func deleteMin[K, V any](n *node[K, V]) *node[K, V] {
+	if n.left == nil {
+		return n.right
+	}
+	return balance(n.key, n.value, deleteMin(n.left), n.right)
+}
+
+func deleteMax[K, V any](n *node[K, V]) *node[K, V] {
+	if n.right == nil {
+		return n.left
+	}
+	return balance(n.key, n.value, n.left, deleteMax(n.right))
+}
+
+// Map is a persistent ordered map from keys to values.
+type Map[K, V any] struct {
+	root    *node[K, V]
+	compare common.CompareFunc[K]
+}
+
+// New returns an empty map ordering keys by their natural order.
+func New[K common.Ordered, V any]() *Map[K, V] {
+	return NewWithCompareFunc[K, V](common.OrderedCompare[K]())
+}
+
+// NewWithCompareFunc returns an empty map ordering keys with the
+// provided function.
+func NewWithCompareFunc[K, V any](compare common.CompareFunc[K]) *Map[K, V] {
+	return &Map[K, V]{compare: compare}
+}
+
+func (m *Map[K, V]) with(root *node[K, V]) *Map[K, V] {
+	return &Map[K, V]{root: root, compare: m.compare}
+}
+
+// Len returns the number of keys in the map.
+func (m *Map[K, V]) Len() int {
+	return size(m.root)
+}
+
+// Get returns the value stored for the key and whether it was found.
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	for n := m.root; n != nil; {
+		switch c := m.compare(key, n.key); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n.value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Put returns a map with the value stored for the key, replacing any
+// value already stored for it.
+func (m *Map[K, V]) Put(key K, value V) *Map[K, V] {
+	return m.with(m.put(m.root, key, value))
+}
+
+func (m *Map[K, V]) put(n *node[K, V], key K, value V) *node[K, V] {
+	if n == nil {
+		return bin[K, V](key, value, nil, nil)
+	}
+	switch c := m.compare(key, n.key); {
+	case c < 0:
+		return balance(n.key, n.value, m.put(n.left, key, value), n.right)
+	case c > 0:
+		return balance(n.key, n.value, n.left, m.put(n.right, key, value))
+	}
+	return bin(key, value, n.left, n.right)
+}
+
+// Delete returns a map without the key, which is this map if the key
+// is not in it.
+func (m *Map[K, V]) Delete(key K) *Map[K, V] {
+	root, ok := m.delete(m.root, key)
+	if !ok {
+		return m
+	}
+	return m.with(root)
+}
+
+func (m *Map[K, V]) delete(n *node[K, V], key K) (*node[K, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+	switch c := m.compare(key, n.key); {
+	case c < 0:
+		left, ok := m.delete(n.left, key)
+		if !ok {
+			return n, false
+		}
+		return balance(n.key, n.value, left, n.right), true
+	case c > 0:
+		right, ok := m.delete(n.right, key)
+		if !ok {
+			return n, false
+		}
+		return balance(n.key, n.value, n.left, right), true
+	}
+	return glue(n.left, n.right), true
+}
+
+// Split returns a map of the keys less than the key and a map of the
+// keys greater than it, along with the value stored for the key and
+// whether it was found, in O(log n) time.
+func (m *Map[K, V]) Split(key K) (*Map[K, V], *Map[K, V], V, bool) {
+	less, value, found, greater := m.split(m.root, key)
+	return m.with(less), m.with(greater), value, found
+}
+
+func (m *Map[K, V]) split(n *node[K, V], key K) (*node[K, V], V, bool, *node[K, V]) {
+	if n == nil {
+		var zero V
+		return nil, zero, false, nil
+	}
+	switch c := m.compare(key, n.key); {
+	case c < 0:
+		less, value, found, greater := m.split(n.left, key)
+		return less, value, found, join(n.key, n.value, greater, n.right)
+	case c > 0:
+		less, value, found, greater := m.split(n.right, key)
+		return join(n.key, n.value, n.left, less), value, found, greater
+	}
+	return n.left, n.value, true, n.right
+}
+
+// Union returns a map of the keys in either map.  For keys in both,
+// the value is resolve(key, this map's value, the other's), or the
+// other's value if resolve is nil.
+func (m *Map[K, V]) Union(other *Map[K, V], resolve func(key K, a, b V) V) *Map[K, V] {
+	if resolve == nil {
+		resolve = func(_ K, _, b V) V { return b }
+	}
+	return m.with(m.union(m.root, other.root, resolve))
+}
+
+func (m *Map[K, V]) union(a, b *node[K, V], resolve func(K, V, V) V) *node[K, V] {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	}
+	less, value, found, greater := m.split(b, a.key)
+	if found {
+		value = resolve(a.key, a.value, value)
+	} else {
+		value = a.value
+	}
+	return join(a.key, value, m.union(a.left, less, resolve), m.union(a.right, greater, resolve))
+}
+
+// Intersect returns a map of the keys in both maps, with values of
+// combine(key, this map's value, the other's), or this map's value if
+// combine is nil.
+func (m *Map[K, V]) Intersect(other *Map[K, V], combine func(key K, a, b V) V) *Map[K, V] {
+	if combine == nil {
+		combine = func(_ K, a, _ V) V { return a }
+	}
+	return m.with(m.intersect(m.root, other.root, combine))
+}
+
+func (m *Map[K, V]) intersect(a, b *node[K, V], combine func(K, V, V) V) *node[K, V] {
+	if a == nil || b == nil {
+		return nil
+	}
+	less, value, found, greater := m.split(b, a.key)
+	left, right := m.intersect(a.left, less, combine), m.intersect(a.right, greater, combine)
+	if found {
+		return join(a.key, combine(a.key, a.value, value), left, right)
+	}
+	return merge(left, right)
+}
+
+// Difference returns a map of the keys in this map that are not in the
+// other, with their values.
+func (m *Map[K, V]) Difference(other *Map[K, V]) *Map[K, V] {
+	return m.with(m.difference(m.root, other.root))
+}
+
+func (m *Map[K, V]) difference(a, b *node[K, V]) *node[K, V] {
+	if a == nil || b == nil {
+		return a
+	}
+	less, _, _, greater := m.split(a, b.key)
+	return merge(m.difference(less, b.left), m.difference(greater, b.right))
+}
+
+// Floor returns the greatest key less than or equal to the provided key,
+// along with its value.  The bool is false if there is no such key.
+func (m *Map[K, V]) Floor(key K) (K, V, bool) {
+	var floor *node[K, V]
+	for n := m.root; n != nil; {
+		switch c := m.compare(key, n.key); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			floor, n = n, n.right
+		default:
+			return n.key, n.value, true
+		}
+	}
+	return entry(floor)
+}
+
+// Ceiling returns the least key greater than or equal to the provided
+// key, along with its value.  The bool is false if there is no such key.
+func (m *Map[K, V]) Ceiling(key K) (K, V, bool) {
+	var ceiling *node[K, V]
+	for n := m.root; n != nil; {
+		switch c := m.compare(key, n.key); {
+		case c < 0:
+			ceiling, n = n, n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n.key, n.value, true
+		}
+	}
+	return entry(ceiling)
+}
+
+// Min returns the least key in the map along with its value.  The bool
+// is false if the map is empty.
+func (m *Map[K, V]) Min() (K, V, bool) {
+	n := m.root
+	for n != nil && n.left != nil {
+		n = n.left
+	}
+	return entry(n)
+}
+
+// Max returns the greatest key in the map along with its value.  The
+// bool is false if the map is empty.
+func (m *Map[K, V]) Max() (K, V, bool) {
+	n := m.root
+	for n != nil && n.right != nil {
+		n = n.right
+	}
+	return entry(n)
+}
+
+func entry[K, V any](n *node[K, V]) (K, V, bool) {
+	if n == nil {
+		var key K
+		var value V
+		return key, value, false
+	}
+	return n.key, n.value, true
+}
+
+// At returns the key at index i in ascending key order, along with its
+// value, in O(log n) time.  The bool is false if i is out of range.
+func (m *Map[K, V]) At(i int) (K, V, bool) {
+	if i < 0 || i >= m.Len() {
+		return entry[K, V](nil)
+	}
+	n := m.root
+	for {
+		switch left := size(n.left); {
+		case i < left:
+			n = n.left
+		case i > left:
+			i -= left + 1
+			n = n.right
+		default:
+			return entry(n)
+		}
+	}
+}
+
+// All returns an iterator over the keys and values in the map in
+// ascending key order.
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		ascend(m.root, yield)
+	}
+}
+
+func ascend[K, V any](n *node[K, V], yield func(K, V) bool) bool {
+	return n == nil || ascend(n.left, yield) && yield(n.key, n.value) && ascend(n.right, yield)
+}
+
+// Backward returns an iterator over the keys and values in the map in
+// descending key order.
+func (m *Map[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		descend(m.root, yield)
+	}
+}
+
+func descend[K, V any](n *node[K, V], yield func(K, V) bool) bool {
+	return n == nil || descend(n.right, yield) && yield(n.key, n.value) && descend(n.left, yield)
+}
+
+// Range returns an iterator over the keys in [from, to), and their
+// values, in ascending key order.
+func (m *Map[K, V]) Range(from, to K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.ascendRange(m.root, from, to, yield)
+	}
+}
+
+func (m *Map[K, V]) ascendRange(n *node[K, V], from, to K, yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	afterFrom := m.compare(n.key, from) >= 0
+	beforeTo := m.compare(n.key, to) < 0
+	if afterFrom && !m.ascendRange(n.left, from, to, yield) {
+		return false
+	}
+	if afterFrom && beforeTo && !yield(n.key, n.value) {
+		return false
+	}
+	return !beforeTo || m.ascendRange(n.right, from, to, yield)
+}
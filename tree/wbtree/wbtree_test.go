@@ -0,0 +1,250 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package wbtree
+
+import (
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// check verifies the order, sizes and balance of the tree.
+func check[K, V any](t *testing.T, m *Map[K, V]) {
+	var walk func(n *node[K, V]) int
+	walk = func(n *node[K, V]) int {
+		if n == nil {
+			return 0
+		}
+		sl, sr := walk(n.left), walk(n.right)
+		require.Equal(t, sl+sr+1, n.size, "size")
+		if sl+sr > 1 {
+			require.LessOrEqual(t, sl, delta*sr, "left heavy")
+			require.LessOrEqual(t, sr, delta*sl, "right heavy")
+		}
+		return n.size
+	}
+	walk(m.root)
+
+	var prev *K
+	for key := range m.All() {
+		if prev != nil {
+			require.Negative(t, m.compare(*prev, key), "order")
+		}
+		prev = &key
+	}
+}
+
+func keys[K, V any](m *Map[K, V]) []K {
+	var all []K
+	for key := range m.All() {
+		all = append(all, key)
+	}
+	return all
+}
+
+func collect(m *Map[int, int]) map[int]int {
+	return maps.Collect(m.All())
+}
+
+func fromMap(values map[int]int) *Map[int, int] {
+	m := New[int, int]()
+	for key, value := range values {
+		m = m.Put(key, value)
+	}
+	return m
+}
+
+func TestMap(t *testing.T) {
+	empty := New[string, int]()
+	_, ok := empty.Get("a")
+	assert.False(t, ok)
+	assert.Same(t, empty, empty.Delete("a"))
+	_, _, ok = empty.Min()
+	assert.False(t, ok)
+
+	m := empty.Put("b", 2).Put("a", 1).Put("d", 4).Put("c", 3)
+	replaced := m.Put("b", 20)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, keys(m))
+	value, _ := m.Get("b")
+	assert.Equal(t, 2, value)
+	value, _ = replaced.Get("b")
+	assert.Equal(t, 20, value)
+	assert.Equal(t, 4, replaced.Len())
+
+	deleted := m.Delete("c")
+	assert.Equal(t, []string{"a", "b", "d"}, keys(deleted))
+	assert.Equal(t, []string{"a", "b", "c", "d"}, keys(m))
+
+	key, _, ok := deleted.Floor("c")
+	assert.True(t, ok)
+	assert.Equal(t, "b", key)
+	key, _, ok = deleted.Ceiling("c")
+	assert.True(t, ok)
+	assert.Equal(t, "d", key)
+	_, _, ok = deleted.Ceiling("e")
+	assert.False(t, ok)
+	key, _, _ = m.Max()
+	assert.Equal(t, "d", key)
+	key, _, ok = m.At(2)
+	assert.True(t, ok)
+	assert.Equal(t, "c", key)
+	_, _, ok = m.At(4)
+	assert.False(t, ok)
+
+	var backward, ranged []string
+	for key := range m.Backward() {
+		backward = append(backward, key)
+	}
+	for key := range m.Range("b", "d") {
+		ranged = append(ranged, key)
+	}
+	assert.Equal(t, []string{"d", "c", "b", "a"}, backward)
+	assert.Equal(t, []string{"b", "c"}, ranged)
+}
+
+func TestSplit(t *testing.T) {
+	m := New[int, string]()
+	for i := 0; i < 100; i += 2 {
+		m = m.Put(i, "")
+	}
+	less, greater, _, found := m.Split(50)
+	assert.True(t, found)
+	check(t, less)
+	check(t, greater)
+	assert.Equal(t, 25, less.Len())
+	assert.Equal(t, 24, greater.Len())
+	key, _, _ := less.Max()
+	assert.Equal(t, 48, key)
+	key, _, _ = greater.Min()
+	assert.Equal(t, 52, key)
+
+	less, greater, _, found = m.Split(51)
+	assert.False(t, found)
+	assert.Equal(t, 26, less.Len())
+	assert.Equal(t, 24, greater.Len())
+	assert.Equal(t, 50, m.Len())
+}
+
+func TestSetOperations(t *testing.T) {
+	a := fromMap(map[int]int{1: 1, 2: 2, 3: 3})
+	b := fromMap(map[int]int{2: 20, 3: 30, 4: 40})
+
+	assert.Equal(t, map[int]int{1: 1, 2: 20, 3: 30, 4: 40}, collect(a.Union(b, nil)))
+	sum := func(_ int, x, y int) int { return x + y }
+	assert.Equal(t, map[int]int{1: 1, 2: 22, 3: 33, 4: 40}, collect(a.Union(b, sum)))
+	assert.Equal(t, map[int]int{2: 2, 3: 3}, collect(a.Intersect(b, nil)))
+	assert.Equal(t, map[int]int{2: 22, 3: 33}, collect(a.Intersect(b, sum)))
+	assert.Equal(t, map[int]int{1: 1}, collect(a.Difference(b)))
+	assert.Equal(t, map[int]int{4: 40}, collect(b.Difference(a)))
+	assert.Equal(t, map[int]int{1: 1, 2: 2, 3: 3}, collect(a))
+}
+
+func TestRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(23))
+	m := New[int, int]()
+	expected := make(map[int]int)
+	for op := 0; op < 5000; op++ {
+		key := rng.Intn(1000)
+		if rng.Intn(3) == 0 {
+			m = m.Delete(key)
+			delete(expected, key)
+		} else {
+			m = m.Put(key, op)
+			expected[key] = op
+		}
+	}
+	check(t, m)
+	assert.Equal(t, expected, collect(m))
+	sorted := slices.Sorted(maps.Keys(expected))
+	for i, key := range sorted {
+		k, _, ok := m.At(i)
+		require.True(t, ok)
+		assert.Equal(t, key, k)
+	}
+}
+
+func TestSetOperationsRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(29))
+	random := func() map[int]int {
+		// sizes vary widely, to exercise joining uneven trees.
+		n, span := rng.Intn(1<<rng.Intn(11)), 1+rng.Intn(3000)
+		values := make(map[int]int)
+		for i := 0; i < n; i++ {
+			values[rng.Intn(span)] = rng.Int()
+		}
+		return values
+	}
+	for round := 0; round < 100; round++ {
+		x, y := random(), random()
+		a, b := fromMap(x), fromMap(y)
+
+		union := maps.Clone(x)
+		maps.Copy(union, y)
+		intersection := make(map[int]int)
+		difference := make(map[int]int)
+		for key, value := range x {
+			if _, ok := y[key]; ok {
+				intersection[key] = value
+			} else {
+				difference[key] = value
+			}
+		}
+
+		for _, c := range []struct {
+			result   *Map[int, int]
+			expected map[int]int
+		}{
+			{a.Union(b, nil), union},
+			{a.Intersect(b, nil), intersection},
+			{a.Difference(b), difference},
+		} {
+			check(t, c.result)
+			assert.Equal(t, len(c.expected), c.result.Len())
+			assert.Equal(t, c.expected, collect(c.result))
+		}
+
+		split := rng.Intn(3000)
+		less, greater, _, found := a.Split(split)
+		check(t, less)
+		check(t, greater)
+		_, ok := x[split]
+		assert.Equal(t, ok, found)
+		if found {
+			assert.Equal(t, a.Len(), less.Len()+greater.Len()+1)
+		} else {
+			assert.Equal(t, a.Len(), less.Len()+greater.Len())
+		}
+	}
+}
+
+func BenchmarkUnionSmallIntoLarge(b *testing.B) {
+	large, small := New[int, int](), New[int, int]()
+	for i := 0; i < 1<<16; i++ {
+		large = large.Put(i*2, i)
+	}
+	for i := 0; i < 16; i++ {
+		small = small.Put(i*8191, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		large.Union(small, nil)
+	}
+}
@@ -25,9 +25,9 @@ package ctrie
 
 import (
 	"bytes"
-	"errors"
 	"hash"
 	"hash/fnv"
+	"iter"
 	"sync/atomic"
 	"unsafe"
 
@@ -354,6 +354,19 @@ func (c *Ctrie) Clear() {
 	}
 }
 
+// All returns an iterator over the keys and values of the Ctrie.  It
+// iterates a read-only snapshot taken when iteration starts, so it is
+// unaffected by concurrent modification, and unlike Iterator it holds
+// no goroutine and needs no cancel channel.
+func (c *Ctrie) All() iter.Seq2[[]byte, any] {
+	return func(yield func([]byte, any) bool) {
+		snapshot := c.ReadOnlySnapshot()
+		snapshot.walk(snapshot.readRoot(), func(entry *Entry) bool {
+			return yield(entry.Key, entry.Value)
+		})
+	}
+}
+
 // Iterator returns a channel which yields the Entries of the Ctrie. If a
 // cancel channel is provided, closing it will terminate and close the iterator
 // channel. Note that if a cancel channel is not used and not every entry is
@@ -362,8 +375,15 @@ func (c *Ctrie) Iterator(cancel <-chan struct{}) <-chan *Entry {
 	ch := make(chan *Entry)
 	snapshot := c.ReadOnlySnapshot()
 	go func() {
-		snapshot.traverse(snapshot.readRoot(), ch, cancel)
-		close(ch)
+		defer close(ch)
+		snapshot.walk(snapshot.readRoot(), func(entry *Entry) bool {
+			select {
+			case ch <- entry:
+				return true
+			case <-cancel:
+				return false
+			}
+		})
 	}()
 	return ch
 }
@@ -376,50 +396,40 @@ func (c *Ctrie) Size() uint {
 	// computation is amortized across the update operations that occurred
 	// since the last snapshot.
 	size := uint(0)
-	for _ = range c.Iterator(nil) {
+	for range c.All() {
 		size++
 	}
 	return size
 }
 
-var errCanceled = errors.New("canceled")
-
-func (c *Ctrie) traverse(i *iNode, ch chan<- *Entry, cancel <-chan struct{}) error {
+// walk calls yield with each entry under i, stopping early and
+// returning false if yield does.
+func (c *Ctrie) walk(i *iNode, yield func(*Entry) bool) bool {
 	main := gcasRead(i, c)
 	switch {
 	case main.cNode != nil:
 		for _, br := range main.cNode.array {
 			switch b := br.(type) {
 			case *iNode:
-				if err := c.traverse(b, ch, cancel); err != nil {
-					return err
+				if !c.walk(b, yield) {
+					return false
 				}
 			case *sNode:
-				select {
-				case ch <- b.Entry:
-				case <-cancel:
-					return errCanceled
+				if !yield(b.Entry) {
+					return false
 				}
 			}
 		}
 	case main.lNode != nil:
-		for _, e := range main.lNode.Map(func(sn any) any {
-			return sn.(*sNode).Entry
-		}) {
-			select {
-			case ch <- e.(*Entry):
-			case <-cancel:
-				return errCanceled
+		for sn := range main.lNode.All() {
+			if !yield(sn.(*sNode).Entry) {
+				return false
 			}
 		}
 	case main.tNode != nil:
-		select {
-		case ch <- main.tNode.Entry:
-		case <-cancel:
-			return errCanceled
-		}
+		return yield(main.tNode.Entry)
 	}
-	return nil
+	return true
 }
 
 func (c *Ctrie) assertReadWrite() {
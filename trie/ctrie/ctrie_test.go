@@ -286,6 +286,42 @@ func TestSnapshot(t *testing.T) {
 	assert.Equal("man", val)
 }
 
+// TestSnapshotWhileWriting checks that snapshots taken while a writer
+// continues are consistent.  Keys are inserted in order, so every
+// snapshot must hold exactly the first n keys for some n.
+func TestSnapshotWhileWriting(t *testing.T) {
+	assert := assert.New(t)
+	ctrie := New(nil)
+	const numKeys = 5000
+	done := make(chan struct{})
+
+	go func() {
+		for i := range numKeys {
+			ctrie.Insert([]byte(strconv.Itoa(i)), i)
+		}
+		close(done)
+	}()
+
+	for finished := false; !finished; {
+		select {
+		case <-done:
+			finished = true
+		default:
+		}
+
+		snapshot := ctrie.ReadOnlySnapshot()
+		size := int(snapshot.Size())
+		for i := range numKeys {
+			_, ok := snapshot.Lookup([]byte(strconv.Itoa(i)))
+			if !assert.Equal(i < size, ok, "key %d in snapshot of size %d", i, size) {
+				return
+			}
+		}
+	}
+
+	assert.Equal(uint(numKeys), ctrie.Size())
+}
+
 func TestReadOnlySnapshot(t *testing.T) {
 	assert := assert.New(t)
 	ctrie := New(nil)
@@ -406,6 +442,30 @@ func TestIteratorCoversTNodes(t *testing.T) {
 	assert.Len(seenKeys, 1)
 }
 
+func TestAll(t *testing.T) {
+	ctrie := New(nil)
+	for i := range 10 {
+		ctrie.Insert([]byte(strconv.Itoa(i)), i)
+	}
+
+	seen := map[string]any{}
+	for key, value := range ctrie.All() {
+		seen[string(key)] = value
+		// modifying the ctrie does not affect the iteration
+		ctrie.Remove(key)
+		ctrie.Insert([]byte("new"+string(key)), value)
+	}
+	assert.Len(t, seen, 10)
+	assert.Equal(t, 3, seen["3"])
+
+	count := 0
+	for range ctrie.All() {
+		count++
+		break
+	}
+	assert.Equal(t, 1, count)
+}
+
 func TestSize(t *testing.T) {
 	ctrie := New(nil)
 	for i := range 10 {
@@ -32,6 +32,8 @@ OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
 // Jurgen J. Vinju
 package dtrie
 
+import "iter"
+
 // Dtrie is a persistent hash trie that dynamically expands or shrinks
 // to provide efficient memory allocation.
 type Dtrie struct {
@@ -71,9 +73,10 @@ func New(hasher func(v any) uint32) *Dtrie {
 
 // Size returns the number of entries in the Dtrie.
 func (d *Dtrie) Size() (size int) {
-	for _ = range iterate(d.root, nil) {
+	walk(d.root, func(Entry) bool {
 		size++
-	}
+		return true
+	})
 	return size
 }
 
@@ -101,6 +104,17 @@ func (d *Dtrie) Remove(key any) *Dtrie {
 	return &Dtrie{root, d.hasher}
 }
 
+// All returns an iterator over the keys and values of the Dtrie.  As
+// a Dtrie is immutable, it needs no stop channel and leaks nothing if
+// iteration stops early.
+func (d *Dtrie) All() iter.Seq2[any, any] {
+	return func(yield func(any, any) bool) {
+		walk(d.root, func(e Entry) bool {
+			return yield(e.Key(), e.Value())
+		})
+	}
+}
+
 // Iterator returns a read-only channel of Entries from the Dtrie. If a stop
 // channel is provided, closing it will terminate and close the iterator
 // channel. Note that if a cancel channel is not used and not every entry is
@@ -128,6 +128,29 @@ func TestIterate(t *testing.T) {
 	assert.Equal(t, 1000, c)
 }
 
+func TestAll(t *testing.T) {
+	for _, hasher := range []func(any) uint32{defaultHasher, collisionHash} {
+		d := &Dtrie{insertTest(t, hasher, 1000), hasher}
+		seen := map[any]any{}
+		for key, value := range d.All() {
+			seen[key] = value
+		}
+		assert.Len(t, seen, 1000)
+		for key, value := range seen {
+			assert.Equal(t, d.Get(key), value)
+		}
+
+		count := 0
+		for range d.All() {
+			count++
+			if count == 10 {
+				break
+			}
+		}
+		assert.Equal(t, 10, count)
+	}
+}
+
 func TestSize(t *testing.T) {
 	n := insertTest(t, defaultHasher, 10000)
 	d := &Dtrie{n, defaultHasher}
@@ -28,7 +28,6 @@ package dtrie
 
 import (
 	"fmt"
-	"sync"
 
 	"github.com/Workiva/go-datastructures/bitarray"
 )
@@ -196,37 +195,39 @@ func iterate(n *node, stop <-chan struct{}) <-chan Entry {
 	out := make(chan Entry)
 	go func() {
 		defer close(out)
-		pushEntries(n, stop, out)
+		walk(n, func(e Entry) bool {
+			select {
+			case out <- e:
+				return true
+			case <-stop:
+				return false
+			}
+		})
 	}()
 	return out
 }
 
-func pushEntries(n *node, stop <-chan struct{}, out chan Entry) {
-	var wg sync.WaitGroup
+// walk calls yield with each entry under n, stopping early and
+// returning false if yield does.
+func walk(n *node, yield func(Entry) bool) bool {
 	for i, e := range n.entries {
-		select {
-		case <-stop:
-			return
-		default:
-			index := uint(i)
-			switch {
-			case n.dataMap.GetBit(index):
-				out <- e
-			case n.nodeMap.GetBit(index):
-				wg.Go(func() {
-					pushEntries(e.(*node), stop, out)
-				})
-				wg.Wait()
-			case n.level == 6 && e != nil:
-				for _, ce := range n.entries[index].(*collisionNode).entries {
-					select {
-					case <-stop:
-						return
-					default:
-						out <- ce
-					}
+		index := uint(i)
+		switch {
+		case n.dataMap.GetBit(index):
+			if !yield(e) {
+				return false
+			}
+		case n.nodeMap.GetBit(index):
+			if !walk(e.(*node), yield) {
+				return false
+			}
+		case n.level == 6 && e != nil:
+			for _, ce := range e.(*collisionNode).entries {
+				if !yield(ce) {
+					return false
 				}
 			}
 		}
 	}
+	return true
 }
@@ -0,0 +1,277 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package radix implements a radix tree, also known as a compressed or
+Patricia trie, mapping string keys to values.  Keys sharing a prefix
+share the nodes along it, and chains of nodes with a single child are
+compressed into one edge, so a lookup touches at most one node per
+branch point in the key.
+
+In addition to the usual map operations, which are O(k) for a key of
+length k, a tree finds the longest key that is a prefix of a string,
+as in a routing table, and iterates over every key with a given prefix
+in lexicographic order.  A Tree is not threadsafe.
+*/
+package radix
+
+import (
+	"iter"
+	"sort"
+	"strings"
+)
+
+type node[V any] struct {
+	// prefix is the label of the edge leading to this node.
+	prefix string
+	// leaf is true if the path to this node is a key in the tree.
+	leaf  bool
+	value V
+	// children are ordered by the first byte of their prefix, which
+	// is unique among them.
+	children []*node[V]
+}
+
+// child returns the index of the child whose prefix starts with the
+// byte, and whether there is one.
+func (n *node[V]) child(b byte) (int, bool) {
+	i := sort.Search(len(n.children), func(i int) bool {
+		return n.children[i].prefix[0] >= b
+	})
+	return i, i < len(n.children) && n.children[i].prefix[0] == b
+}
+
+func (n *node[V]) addChild(c *node[V]) {
+	i, _ := n.child(c.prefix[0])
+	n.children = append(n.children, nil)
+	copy(n.children[i+1:], n.children[i:])
+	n.children[i] = c
+}
+
+func (n *node[V]) removeChild(i int) {
+	copy(n.children[i:], n.children[i+1:])
+	n.children[len(n.children)-1] = nil
+	n.children = n.children[:len(n.children)-1]
+}
+
+// mergeChild absorbs the node's only child, which is possible once the
+// node holds no key of its own.
+func (n *node[V]) mergeChild() {
+	c := n.children[0]
+	n.prefix += c.prefix
+	n.leaf, n.value, n.children = c.leaf, c.value, c.children
+}
+
+// Tree is a radix tree mapping strings to values of type V.  The zero
+// value is an empty tree ready to use.
+type Tree[V any] struct {
+	root   node[V]
+	length int
+}
+
+// New returns an empty tree.
+func New[V any]() *Tree[V] {
+	return &Tree[V]{}
+}
+
+// Len returns the number of keys in the tree.
+func (t *Tree[V]) Len() int {
+	return t.length
+}
+
+// Insert sets the value of the key, returning the value it replaces
+// and whether there was one.
+func (t *Tree[V]) Insert(key string, value V) (V, bool) {
+	n := &t.root
+	search := key
+	for search != "" {
+		i, ok := n.child(search[0])
+		if !ok {
+			n.addChild(&node[V]{prefix: search, leaf: true, value: value})
+			t.length++
+			var zero V
+			return zero, false
+		}
+
+		c := n.children[i]
+		l := commonPrefix(search, c.prefix)
+		if l < len(c.prefix) {
+			// split the edge where the key leaves it
+			mid := &node[V]{prefix: c.prefix[:l], children: []*node[V]{c}}
+			c.prefix = c.prefix[l:]
+			n.children[i] = mid
+			c = mid
+		}
+
+		n = c
+		search = search[l:]
+	}
+
+	old, replaced := n.value, n.leaf
+	n.leaf, n.value = true, value
+	if !replaced {
+		t.length++
+	}
+	return old, replaced
+}
+
+// Get returns the value of the key and whether it is in the tree.
+func (t *Tree[V]) Get(key string) (V, bool) {
+	n := &t.root
+	search := key
+	for search != "" {
+		i, ok := n.child(search[0])
+		if !ok || !strings.HasPrefix(search, n.children[i].prefix) {
+			var zero V
+			return zero, false
+		}
+		n = n.children[i]
+		search = search[len(n.prefix):]
+	}
+	return n.value, n.leaf
+}
+
+// Delete removes the key from the tree, returning its value and
+// whether it was in the tree.
+func (t *Tree[V]) Delete(key string) (V, bool) {
+	var zero V
+	var parent *node[V]
+	var index int
+	n := &t.root
+	search := key
+	for search != "" {
+		i, ok := n.child(search[0])
+		if !ok || !strings.HasPrefix(search, n.children[i].prefix) {
+			return zero, false
+		}
+		parent, index = n, i
+		n = n.children[i]
+		search = search[len(n.prefix):]
+	}
+
+	if !n.leaf {
+		return zero, false
+	}
+
+	value := n.value
+	n.leaf, n.value = false, zero
+	t.length--
+
+	// keep the tree compressed: no node other than the root may be
+	// without a key and have fewer than two children
+	if parent != nil {
+		switch len(n.children) {
+		case 0:
+			parent.removeChild(index)
+			if parent != &t.root && !parent.leaf && len(parent.children) == 1 {
+				parent.mergeChild()
+			}
+		case 1:
+			n.mergeChild()
+		}
+	}
+	return value, true
+}
+
+// LongestPrefix returns the longest key in the tree that is a prefix of
+// s, along with its value.  Returns false if no key is a prefix of s.
+func (t *Tree[V]) LongestPrefix(s string) (string, V, bool) {
+	n := &t.root
+	var match *node[V]
+	var matched int
+	if n.leaf {
+		match = n
+	}
+
+	search := s
+	for search != "" {
+		i, ok := n.child(search[0])
+		if !ok || !strings.HasPrefix(search, n.children[i].prefix) {
+			break
+		}
+		n = n.children[i]
+		search = search[len(n.prefix):]
+		if n.leaf {
+			match, matched = n, len(s)-len(search)
+		}
+	}
+
+	if match == nil {
+		var zero V
+		return "", zero, false
+	}
+	return s[:matched], match.value, true
+}
+
+// All returns an iterator over every key in the tree and its value, in
+// lexicographic order of the keys.
+func (t *Tree[V]) All() iter.Seq2[string, V] {
+	return t.Prefix("")
+}
+
+// Prefix returns an iterator over every key in the tree beginning with
+// prefix and its value, in lexicographic order of the keys.
+func (t *Tree[V]) Prefix(prefix string) iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		n := &t.root
+		path := []byte(nil)
+		search := prefix
+		for search != "" {
+			i, ok := n.child(search[0])
+			if !ok {
+				return
+			}
+			c := n.children[i]
+			switch {
+			case strings.HasPrefix(search, c.prefix):
+				search = search[len(c.prefix):]
+			case strings.HasPrefix(c.prefix, search):
+				search = ""
+			default:
+				return
+			}
+			path = append(path, c.prefix...)
+			n = c
+		}
+
+		walk(n, path, yield)
+	}
+}
+
+// walk yields every key in the subtree rooted at n, whose own key is
+// path, returning false if iteration was stopped.
+func walk[V any](n *node[V], path []byte, yield func(string, V) bool) bool {
+	if n.leaf && !yield(string(path), n.value) {
+		return false
+	}
+
+	for _, c := range n.children {
+		if !walk(c, append(path, c.prefix...), yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// commonPrefix returns the length of the longest common prefix of a
+// and b.
+func commonPrefix(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radix
+
+import (
+	"maps"
+	"math/rand"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func keys[V any](t *Tree[V]) []string {
+	var result []string
+	for key := range t.All() {
+		result = append(result, key)
+	}
+	return result
+}
+
+// checkCompressed asserts that every node below the root holds a key
+// or branches.
+func checkCompressed[V any](t *testing.T, n *node[V], root bool) {
+	if !root {
+		assert.True(t, n.leaf || len(n.children) > 1, "uncompressed node %q", n.prefix)
+	}
+	for i, c := range n.children {
+		require.NotEmpty(t, c.prefix)
+		if i > 0 {
+			assert.Less(t, n.children[i-1].prefix[0], c.prefix[0])
+		}
+		checkCompressed(t, c, false)
+	}
+}
+
+func TestInsertGet(t *testing.T) {
+	tree := New[int]()
+	for i, key := range []string{"romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus", "rom"} {
+		_, replaced := tree.Insert(key, i)
+		assert.False(t, replaced)
+	}
+	assert.Equal(t, 8, tree.Len())
+	checkCompressed(t, &tree.root, true)
+
+	value, ok := tree.Get("rubicon")
+	assert.True(t, ok)
+	assert.Equal(t, 5, value)
+
+	for _, key := range []string{"r", "ro", "roma", "rubiconx", "x", ""} {
+		_, ok := tree.Get(key)
+		assert.False(t, ok, key)
+	}
+
+	old, replaced := tree.Insert("rom", 100)
+	assert.True(t, replaced)
+	assert.Equal(t, 7, old)
+	value, _ = tree.Get("rom")
+	assert.Equal(t, 100, value)
+	assert.Equal(t, 8, tree.Len())
+
+	tree.Insert("", -1)
+	value, ok = tree.Get("")
+	assert.True(t, ok)
+	assert.Equal(t, -1, value)
+}
+
+func TestDelete(t *testing.T) {
+	tree := New[int]()
+	for i, key := range []string{"test", "team", "tea", "toast", "t"} {
+		tree.Insert(key, i)
+	}
+
+	_, ok := tree.Delete("te")
+	assert.False(t, ok)
+	_, ok = tree.Delete("teams")
+	assert.False(t, ok)
+
+	value, ok := tree.Delete("tea")
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+	checkCompressed(t, &tree.root, true)
+
+	tree.Delete("team")
+	checkCompressed(t, &tree.root, true)
+	tree.Delete("t")
+	checkCompressed(t, &tree.root, true)
+
+	assert.Equal(t, []string{"test", "toast"}, keys(tree))
+	assert.Equal(t, 2, tree.Len())
+
+	tree.Delete("test")
+	tree.Delete("toast")
+	assert.Equal(t, 0, tree.Len())
+	assert.Empty(t, tree.root.children)
+}
+
+func TestLongestPrefix(t *testing.T) {
+	tree := New[string]()
+	tree.Insert("/api", "api")
+	tree.Insert("/api/users", "users")
+	tree.Insert("/static", "static")
+
+	key, value, ok := tree.LongestPrefix("/api/users/42")
+	assert.True(t, ok)
+	assert.Equal(t, "/api/users", key)
+	assert.Equal(t, "users", value)
+
+	key, value, ok = tree.LongestPrefix("/api/user")
+	assert.True(t, ok)
+	assert.Equal(t, "/api", key)
+	assert.Equal(t, "api", value)
+
+	_, _, ok = tree.LongestPrefix("/ap")
+	assert.False(t, ok)
+
+	tree.Insert("", "root")
+	key, value, ok = tree.LongestPrefix("/other")
+	assert.True(t, ok)
+	assert.Equal(t, "", key)
+	assert.Equal(t, "root", value)
+}
+
+func TestPrefix(t *testing.T) {
+	tree := New[int]()
+	for i, key := range []string{"banana", "band", "bandana", "ban", "apple", "bank", "can"} {
+		tree.Insert(key, i)
+	}
+
+	assert.Equal(t, []string{"apple", "ban", "banana", "band", "bandana", "bank", "can"}, keys(tree))
+
+	var result []string
+	for key := range tree.Prefix("band") {
+		result = append(result, key)
+	}
+	assert.Equal(t, []string{"band", "bandana"}, result)
+
+	result = nil
+	for key, value := range tree.Prefix("ba") {
+		result = append(result, key+"="+strconv.Itoa(value))
+		if len(result) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []string{"ban=3", "banana=0"}, result)
+
+	for _, prefix := range []string{"bx", "bandanas", "d"} {
+		for key := range tree.Prefix(prefix) {
+			t.Errorf("unexpected key %q for prefix %q", key, prefix)
+		}
+	}
+}
+
+func TestRandomOperations(t *testing.T) {
+	tree := New[int]()
+	expected := map[string]int{}
+	for i := range 5000 {
+		key := strconv.FormatInt(rand.Int63n(2000), 4)
+		if rand.Intn(3) == 0 {
+			_, ok := tree.Delete(key)
+			_, exists := expected[key]
+			assert.Equal(t, exists, ok)
+			delete(expected, key)
+			continue
+		}
+		tree.Insert(key, i)
+		expected[key] = i
+	}
+
+	checkCompressed(t, &tree.root, true)
+	assert.Equal(t, len(expected), tree.Len())
+	assert.Equal(t, slices.Sorted(maps.Keys(expected)), keys(tree))
+	for key, value := range expected {
+		got, ok := tree.Get(key)
+		assert.True(t, ok)
+		assert.Equal(t, value, got)
+	}
+}
+
+func BenchmarkGet(b *testing.B) {
+	numItems := 100000
+	tree := New[int]()
+	items := make([]string, 0, numItems)
+	for i := range numItems {
+		key := strconv.Itoa(rand.Int())
+		items = append(items, key)
+		tree.Insert(key, i)
+	}
+
+	for i := 0; b.Loop(); i++ {
+		tree.Get(items[i%numItems])
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xfast
+
+import "github.com/Workiva/go-datastructures/arena"
+
+// Arena allocates the nodes of one or more tries from chunks, so
+// building a large trie creates a handful of allocations for the
+// garbage collector to track rather than one per node.  Pass it to New
+// with WithArena.  Nodes are not returned to the arena when entries
+// are deleted; Reset frees them all at once, after which every trie
+// using the arena must no longer be used.  The maps indexing each
+// layer of a trie are still allocated from the heap.  It is not safe
+// for concurrent use.
+type Arena struct {
+	nodes arena.Arena[node]
+}
+
+// NewArena returns an empty arena that allocates chunks of chunkSize
+// nodes, or of 1024 if chunkSize is not positive.
+func NewArena(chunkSize int) *Arena {
+	return &Arena{nodes: *arena.New[node](chunkSize)}
+}
+
+// Reset frees every node allocated from the arena, keeping its chunks
+// to allocate from again.
+func (a *Arena) Reset() {
+	a.nodes.Reset()
+}
+
+// Release drops the arena's chunks, returning their memory to the
+// runtime once no trie built from them is referenced.
+func (a *Arena) Release() {
+	a.nodes.Release()
+}
+
+// Option configures a trie created by New.
+type Option func(*XFastTrie)
+
+// WithArena allocates the trie's nodes from a rather than from the
+// heap.
+func WithArena(a *Arena) Option {
+	return func(xft *XFastTrie) {
+		xft.arena = a
+	}
+}
+
+// newNode is like the package's newNode, but allocates from the arena
+// unless it is nil.
+func (a *Arena) newNode(parent *node, entry Entry) *node {
+	if a == nil {
+		return newNode(parent, entry)
+	}
+	n := a.nodes.New()
+	n.parent = parent
+	n.entry = entry
+	return n
+}
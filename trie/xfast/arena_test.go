@@ -0,0 +1,75 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xfast
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArena(t *testing.T) {
+	a := NewArena(16)
+	xft := New(uint16(0), WithArena(a))
+	for i := uint64(0); i < 100; i += 2 {
+		xft.Insert(newMockEntry(i))
+	}
+	xft.Delete(10, 20)
+	checkTrie(t, xft)
+
+	assert.Equal(t, uint64(48), xft.Len())
+	assert.Equal(t, uint64(12), xft.Successor(9).Key())
+	assert.Equal(t, uint64(8), xft.Predecessor(11).Key())
+
+	a.Reset()
+	xft = New(uint16(0), WithArena(a))
+	xft.Insert(newMockEntry(5), newMockEntry(3))
+	checkTrie(t, xft)
+	assert.Equal(t, uint64(3), xft.Min().Key())
+	assert.Equal(t, uint64(5), xft.Max().Key())
+}
+
+// BenchmarkBuildAndDiscard builds and discards a trie of 1,000 keys,
+// reporting the garbage collections it causes with and without an
+// arena.
+func BenchmarkBuildAndDiscard(b *testing.B) {
+	entries := make(Entries, 0, 1000)
+	for i := range uint64(1000) {
+		entries = append(entries, uintEntry(i*61))
+	}
+	build := func(b *testing.B, opts func() []Option, reset func()) {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		b.ReportAllocs()
+		for b.Loop() {
+			xft := New(uint16(0), opts()...)
+			xft.Insert(entries...)
+			reset()
+		}
+		runtime.ReadMemStats(&after)
+		b.ReportMetric(float64(after.NumGC-before.NumGC)/float64(b.N), "gcs/op")
+	}
+
+	b.Run("heap", func(b *testing.B) {
+		build(b, func() []Option { return nil }, func() {})
+	})
+	b.Run("arena", func(b *testing.B) {
+		a := NewArena(4096)
+		build(b, func() []Option { return []Option{WithArena(a)} }, a.Reset)
+	})
+}
@@ -44,7 +44,11 @@ insert and delete in O(log log M) time and consumes O(n) space.
 */
 package xfast
 
-import "fmt"
+import (
+	"fmt"
+	"iter"
+	"math/bits"
+)
 
 // isInternal returns a bool indicating if the provided
 // node is an internal node, that is, non-leaf node.
@@ -55,21 +59,6 @@ func isInternal(n *node) bool {
 	return n.entry == nil
 }
 
-// hasInternal returns a bool indicating if the provided
-// node has a child that is an internal node.
-func hasInternal(n *node) bool {
-	return isInternal(n.children[0]) || isInternal(n.children[1])
-}
-
-// isLeaf returns a bool indicating if the provided node
-// is a leaf node, that is, has a valid entry value.
-func isLeaf(n *node) bool {
-	if n == nil {
-		return false
-	}
-	return !isInternal(n)
-}
-
 // Entry defines items that can be inserted into the x-fast
 // trie.
 type Entry interface {
@@ -206,6 +195,8 @@ type XFastTrie struct {
 	// constraints and allows min/max operations to be performed
 	// in O(1) time.
 	min, max *node
+	// arena, if not nil, allocates the trie's nodes.
+	arena *Arena
 }
 
 // init will initialize the XFastTrie with the provided byte-size.
@@ -236,7 +227,7 @@ func (xft *XFastTrie) init(intType any) {
 		xft.layers[i] = make(map[uint64]*node, 50) // we can obviously be more intelligent about this.
 	}
 	xft.num = 0
-	xft.root = newNode(nil, nil)
+	xft.root = xft.arena.newNode(nil, nil)
 }
 
 // Exists returns a bool indicating if the provided
@@ -274,98 +265,69 @@ func (xft *XFastTrie) Min() Entry {
 	return xft.min.entry
 }
 
+// side returns the side, 0 for left and 1 for right, on which the key
+// lies below a node at the provided depth, where the root has depth 0.
+func (xft *XFastTrie) side(key uint64, depth uint8) uint64 {
+	return (key & positions[xft.diff+depth]) >> (xft.bits - 1 - depth)
+}
+
 // insert will add the provided entry to the trie or overwrite the existing
 // entry if it exists.
+//
+// Any child missing from an internal node is replaced by a thread: a
+// missing left child by the predecessor of the node's subtree and a
+// missing right child by its successor.  Leaves use their children as
+// a doubly-linked list in key order.
 func (xft *XFastTrie) insert(entry Entry) {
-	key := entry.Key() // so we aren't calling this interface method over and over, fucking Go
+	key := entry.Key() // so we aren't calling this interface method over and over
 	n := xft.layers[xft.bits-1][key]
 	if n != nil {
 		n.entry = entry
 		return
 	}
 
-	// we need to find a predecessor or successor if it exists
-	// to help us set threads later in this method.
-	var predecessor, successor *node
-	if xft.min != nil && key < xft.min.entry.Key() {
-		successor = xft.min
+	// find the leaves the new leaf goes between.
+	successor := xft.successor(key)
+	var predecessor *node
+	if successor != nil {
+		predecessor = successor.children[0]
 	} else {
-		successor = xft.successor(key)
-	}
-
-	// only need to find predecessor if successor is nil as otherwise
-	// the successor will provide us is the predecessor if it exists.
-	if successor == nil {
-		if xft.max != nil && key > xft.max.entry.Key() {
-			predecessor = xft.max
-		} else {
-			predecessor = xft.predecessor(key)
-		}
+		predecessor = xft.max
 	}
 
 	// find the deepest root with a matching prefix, this should
 	// save us some time, assuming the hashmap has perfect hashing.
-	layer, root := binarySearchHashMaps(xft.layers, key)
-	if root == nil {
+	layer, n := binarySearchHashMaps(xft.layers, key)
+	if n == nil {
 		n = xft.root
-		layer = 0
-	} else {
-		n = root
 	}
 
-	var leftOrRight uint64
-
-	// from the existing node, create new nodes.
+	// from the existing node, create new nodes.  Their only key is
+	// the new one, so until their children are created the threads
+	// on both sides lead to the predecessor and successor.
 	for i := uint8(layer); i < xft.bits; i++ {
-		// on 0th, this will be root
-		// find out if we need to go left or right
-		leftOrRight = (key & positions[xft.diff+i]) >> (xft.bits - 1 - i)
-		if n.children[leftOrRight] == nil || isLeaf(n.children[leftOrRight]) {
-			var nn *node
-			if i < xft.bits-1 {
-				nn = newNode(n, nil)
-			} else {
-				nn = newNode(n, entry)
-				xft.num++
-			}
-
-			n.children[leftOrRight] = nn
-			xft.layers[i][key&masks[xft.diff+i]] = nn // prefix for this layer
+		var nn *node
+		if i < xft.bits-1 {
+			nn = xft.arena.newNode(n, nil)
+		} else {
+			nn = xft.arena.newNode(n, entry)
 		}
+		nn.children[0], nn.children[1] = predecessor, successor
 
-		n = n.children[leftOrRight]
+		n.children[xft.side(key, i)] = nn
+		xft.layers[i][key&masks[xft.diff+i]] = nn // prefix for this layer
+		n = nn
 	}
 
-	// we need to put the new node where it belongs in the doubly-linked
-	// list comprised of all the leaves.
-	if successor != nil { // we have to walk predecessor and successor threads
-		predecessor = successor.children[0]
-		if predecessor != nil {
-			predecessor.children[1] = n
-			n.children[0] = predecessor
-		}
-		n.children[1] = successor
-		successor.children[0] = n
-	} else if predecessor != nil {
-		n.children[0] = predecessor
+	if predecessor != nil {
 		predecessor.children[1] = n
+		xft.rethread(predecessor, key, 1, n)
 	}
-
-	// walk up the successor if it exists to set that branch's new
-	// predecessor.
 	if successor != nil {
-		xft.walkUpSuccessor(root, n, successor)
-	}
-
-	// walk up the predecessor if it exists to set that branch's
-	// new successor.
-	if predecessor != nil {
-		xft.walkUpPredecessor(root, n, predecessor)
+		successor.children[0] = n
+		xft.rethread(successor, key, 0, n)
 	}
-
-	// finally, walk up our own branch to set both successors
-	// and predecessors.
-	xft.walkUpNode(root, n, predecessor, successor)
+	xft.num++
 
 	// and then do a final check against the min/max indicies.
 	if xft.max == nil || key > xft.max.entry.Key() {
@@ -377,45 +339,16 @@ func (xft *XFastTrie) insert(entry Entry) {
 	}
 }
 
-// walkUpSuccessor will walk up the successor branch setting
-// the predecessor where possible.  This breaks when a common
-// ancestor between successor and node is found, ie, the root.
-func (xft *XFastTrie) walkUpSuccessor(root, node, successor *node) {
-	n := successor.parent
-	for n != nil && n != root {
-		// we don't really want to overwrite existing internal nodes,
-		// or where the child is a leaf that is the successor
-		if !isInternal(n.children[0]) && n.children[0] != successor {
-			n.children[0] = node
-		}
-		n = n.parent
-	}
-}
-
-// walkUpPredecessor will walk up the predecessor branch setting
-// the successor where possible.  This breaks when a common
-// ancestor between predecessor and node is found, ie, the root.
-func (xft *XFastTrie) walkUpPredecessor(root, node, predecessor *node) {
-	n := predecessor.parent
-	for n != nil && n != root {
-		if !isInternal(n.children[1]) && n.children[1] != predecessor {
-			n.children[1] = node
-		}
-		n = n.parent
-	}
-}
-
-// walkUpNode will walk up the newly created branch and set predecessor
-// and successor where possible.  If predecessor or successor are nil,
-// this will set nil where possible.
-func (xft *XFastTrie) walkUpNode(root, node, predecessor, successor *node) {
-	n := node.parent
-	for n != nil && n != root {
-		if !isInternal(n.children[1]) && n.children[1] != successor && n.children[1] != node {
-			n.children[1] = successor
-		}
-		if !isInternal(n.children[0]) && n.children[0] != predecessor && n.children[0] != node {
-			n.children[0] = predecessor
+// rethread points the threads on the given side of the ancestors of
+// leaf at to, up to the ancestor shared with key.  Those are the
+// subtrees that key neighbors, which have a new predecessor or
+// successor when key is inserted or deleted.
+func (xft *XFastTrie) rethread(leaf *node, key uint64, side int, to *node) {
+	common := uint8(bits.LeadingZeros64((leaf.entry.Key() ^ key) << xft.diff))
+	n := leaf.parent
+	for depth := xft.bits - 1; depth > common; depth-- {
+		if child := n.children[side]; child == nil || child.parent != n {
+			n.children[side] = to
 		}
 		n = n.parent
 	}
@@ -436,51 +369,42 @@ func (xft *XFastTrie) delete(key uint64) {
 		return
 	}
 
-	successor, predecessor := n.children[1], n.children[0]
-
-	i := uint8(1)
+	predecessor, successor := n.children[0], n.children[1]
 	delete(xft.layers[xft.bits-1], key)
-	leftOrRight := whichSide(n, n.parent)
-	n.parent.children[leftOrRight] = nil
-	n.children[0], n.children[1] = nil, nil
-	n = n.parent
-	hasImmediateSibling := false
-	if successor != nil && successor.parent == n {
-		hasImmediateSibling = true
-	}
-	if predecessor != nil && predecessor.parent == n {
-		hasImmediateSibling = true
-	}
-
-	// this loop will kill any nodes that no longer link to internal
-	// nodes
-	for n != nil && n.parent != nil {
-		// if we have an internal node remaining we should abort
-		// now as no further node will be removed.  We should also
-		// abort if the first parent of a leaf references the pre
-		if hasInternal(n) || (i == 1 && hasImmediateSibling) {
-			n = n.parent // we had one side deleted, need to set the other
-			break
+	if predecessor != nil {
+		predecessor.children[1] = successor
+	}
+	if successor != nil {
+		successor.children[0] = predecessor
+	}
+
+	// remove the leaf and any ancestors left without children,
+	// replacing each with a thread in its parent.
+	for depth := xft.bits - 1; ; depth-- {
+		parent := n.parent
+		n.children[0], n.children[1], n.parent = nil, nil, nil
+
+		side := xft.side(key, depth)
+		if side == 0 {
+			parent.children[0] = predecessor
+		} else {
+			parent.children[1] = successor
 		}
 
-		leftOrRight = whichSide(n, n.parent)
-		n.parent.children[leftOrRight] = nil
-		n.children[0], n.children[1] = nil, nil
-		delete(xft.layers[xft.bits-i-1], key&masks[len(masks)-1-int(i)])
-		n = n.parent
-		i++
-	}
+		other := parent.children[1-side]
+		if parent == xft.root || (other != nil && other.parent == parent) {
+			break
+		}
 
-	// we need to check now and update threads, but in the leaves
-	// and in their branches
-	if predecessor != nil {
-		predecessor.children[1] = successor
-		xft.walkUpPredecessor(n, successor, predecessor)
+		delete(xft.layers[depth-1], key&masks[xft.diff+depth-1])
+		n = parent
 	}
 
 	if successor != nil {
-		successor.children[0] = predecessor
-		xft.walkUpSuccessor(n, predecessor, successor)
+		xft.rethread(successor, key, 0, predecessor)
+	}
+	if predecessor != nil {
+		xft.rethread(predecessor, key, 1, successor)
 	}
 
 	// check max/min indices
@@ -508,7 +432,7 @@ func (xft *XFastTrie) Delete(keys ...uint64) {
 // predecessor will find the node equal to or immediately less
 // than the provided key.
 func (xft *XFastTrie) predecessor(key uint64) *node {
-	if xft.root == nil || xft.max == nil { // no successor if no nodes
+	if xft.max == nil { // no predecessor if no nodes
 		return nil
 	}
 
@@ -520,28 +444,31 @@ func (xft *XFastTrie) predecessor(key uint64) *node {
 		return nil
 	}
 
-	n := xft.layers[xft.bits-1][key]
-	if n != nil {
+	if n := xft.layers[xft.bits-1][key]; n != nil {
 		return n
 	}
 
+	// the key diverges from every key below the deepest node sharing
+	// its prefix, and the thread on that side leads out of the node's
+	// subtree.
 	layer, n := binarySearchHashMaps(xft.layers, key)
-	if n == nil && layer > 1 {
-		return nil
-	} else if n == nil {
+	if n == nil {
 		n = xft.root
 	}
 
-	if isInternal(n.children[0]) && isLeaf(n.children[1]) {
-		return n.children[1].children[0]
+	if xft.side(key, uint8(layer)) == 0 {
+		return n.children[0]
+	}
+	if n.children[1] == nil {
+		return xft.max
 	}
-	return n.children[0]
+	return n.children[1].children[0]
 }
 
 // successor will find the node equal to or immediately more
 // than the provided key.
 func (xft *XFastTrie) successor(key uint64) *node {
-	if xft.root == nil || xft.min == nil { // no successor if no nodes
+	if xft.min == nil { // no successor if no nodes
 		return nil
 	}
 
@@ -553,22 +480,22 @@ func (xft *XFastTrie) successor(key uint64) *node {
 		return nil
 	}
 
-	n := xft.layers[xft.bits-1][key]
-	if n != nil {
+	if n := xft.layers[xft.bits-1][key]; n != nil {
 		return n
 	}
 
 	layer, n := binarySearchHashMaps(xft.layers, key)
-	if n == nil && layer > 1 {
-		return nil
-	} else if n == nil {
+	if n == nil {
 		n = xft.root
 	}
 
-	if isInternal(n.children[1]) && isLeaf(n.children[0]) {
-		return n.children[0].children[1]
+	if xft.side(key, uint8(layer)) == 1 {
+		return n.children[1]
 	}
-	return n.children[1]
+	if n.children[0] == nil {
+		return xft.min
+	}
+	return n.children[0].children[1]
 }
 
 // Successor will return an Entry which matches the provided
@@ -605,6 +532,18 @@ func (xft *XFastTrie) Iter(key uint64) *Iterator {
 	}
 }
 
+// All returns an iterator over the entries in the trie in ascending
+// order of key.  The trie must not be modified during iteration.
+func (xft *XFastTrie) All() iter.Seq[Entry] {
+	return func(yield func(Entry) bool) {
+		for it := xft.Iter(0); it.Next(); {
+			if !yield(it.Value()) {
+				return
+			}
+		}
+	}
+}
+
 // Get will return a value in the trie associated with the provided
 // key if it exists.  Returns nil if the key does not exist.  This
 // is expected to take O(1) time.
@@ -624,8 +563,11 @@ func (xft *XFastTrie) Get(key uint64) Entry {
 // a uint of some sort, ie, uint8, uint16, etc.  The size of the
 // universe will be 2^n-1 and will affect the speed of all operations.
 // IFC MUST be a uint type.
-func New(ifc any) *XFastTrie {
+func New(ifc any, opts ...Option) *XFastTrie {
 	xft := &XFastTrie{}
+	for _, opt := range opts {
+		opt(xft)
+	}
 	xft.init(ifc)
 	return xft
 }
@@ -19,6 +19,8 @@ package xfast
 import (
 	"fmt"
 	"math"
+	"math/rand"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -154,6 +156,19 @@ func TestEmptyMinMax(t *testing.T) {
 	assert.Nil(t, xft.Max())
 }
 
+func TestAll(t *testing.T) {
+	xft := New(uint8(0))
+	assert.Empty(t, slices.Collect(xft.All()))
+
+	e1, e2, e3 := newMockEntry(5), newMockEntry(12), newMockEntry(6)
+	xft.Insert(e1, e2, e3)
+	assert.Equal(t, []Entry{e1, e3, e2}, slices.Collect(xft.All()))
+	for entry := range xft.All() {
+		assert.Equal(t, e1, entry)
+		break
+	}
+}
+
 func TestMask(t *testing.T) {
 	assert.Equal(t, uint64(math.MaxUint64), masks[63])
 }
@@ -522,6 +537,64 @@ func TestDeleteNotFound(t *testing.T) {
 	checkTrie(t, xft)
 }
 
+// uintEntry is a lightweight Entry for tests with many operations.
+type uintEntry uint64
+
+func (ue uintEntry) Key() uint64 {
+	return uint64(ue)
+}
+
+func TestRandomOperations(t *testing.T) {
+	for _, universe := range []any{uint8(0), uint16(0), uint64(0)} {
+		// spread keys across the universe
+		shift := 0
+		if _, ok := universe.(uint64); ok {
+			shift = 50
+		}
+
+		xft := New(universe)
+		var keys []uint64
+		for range 2000 {
+			key := uint64(rand.Intn(250)) << shift
+			i, found := slices.BinarySearch(keys, key)
+			if rand.Intn(3) == 0 {
+				xft.Delete(key)
+				if found {
+					keys = slices.Delete(keys, i, i+1)
+				}
+			} else {
+				xft.Insert(uintEntry(key))
+				if !found {
+					keys = slices.Insert(keys, i, key)
+				}
+			}
+
+			query := uint64(rand.Intn(250)) << shift
+			i, found = slices.BinarySearch(keys, query)
+			if i < len(keys) {
+				assert.Equal(t, uintEntry(keys[i]), xft.Successor(query))
+			} else {
+				assert.Nil(t, xft.Successor(query))
+			}
+			if !found {
+				i--
+			}
+			if i >= 0 {
+				assert.Equal(t, uintEntry(keys[i]), xft.Predecessor(query))
+			} else {
+				assert.Nil(t, xft.Predecessor(query))
+			}
+		}
+
+		checkTrie(t, xft)
+		var result []uint64
+		for iter := xft.Iter(0); iter.Next(); {
+			result = append(result, iter.Value().Key())
+		}
+		assert.Equal(t, keys, result)
+	}
+}
+
 func BenchmarkSuccessor(b *testing.B) {
 	numItems := 10000
 	xft := New(uint64(0))
@@ -36,7 +36,12 @@ aid in multithreading these functions for performance optimization.
 */
 package yfast
 
-import "github.com/Workiva/go-datastructures/trie/xfast"
+import (
+	"iter"
+	"math"
+
+	"github.com/Workiva/go-datastructures/trie/xfast"
+)
 
 // YFastTrie implements all the methods available to the y-fast
 // trie datastructure.  The top half is composed of an x-fast trie
@@ -179,6 +184,29 @@ func (yfast *YFastTrie) Len() uint64 {
 	return yfast.num
 }
 
+// Max returns the Entry with the highest key in the y-fast trie, or
+// nil if the trie is empty.  This is an O(1) operation.
+func (yfast *YFastTrie) Max() Entry {
+	bundle := yfast.xfast.Max()
+	if bundle == nil {
+		return nil
+	}
+
+	entries := bundle.(*entriesWrapper).entries
+	return entries[len(entries)-1]
+}
+
+// Min returns the Entry with the lowest key in the y-fast trie, or
+// nil if the trie is empty.  This is an O(1) operation.
+func (yfast *YFastTrie) Min() Entry {
+	bundle := yfast.xfast.Min()
+	if bundle == nil {
+		return nil
+	}
+
+	return bundle.(*entriesWrapper).entries[0]
+}
+
 func (yfast *YFastTrie) successor(key uint64) Entry {
 	bundle := yfast.xfast.Successor(key)
 	if bundle == nil {
@@ -186,11 +214,22 @@ func (yfast *YFastTrie) successor(key uint64) Entry {
 	}
 
 	entry, _ := bundle.(*entriesWrapper).entries.successor(key)
-	if entry == nil {
+	if entry != nil {
+		return entry
+	}
+
+	// every entry in the bundle is less than the key, so the
+	// successor is the first entry of the next bundle
+	bundleKey := bundle.Key()
+	if bundleKey == math.MaxUint64 {
+		return nil
+	}
+	bundle = yfast.xfast.Successor(bundleKey + 1)
+	if bundle == nil {
 		return nil
 	}
 
-	return entry
+	return bundle.(*entriesWrapper).entries[0]
 }
 
 // Successor returns an Entry with a key equal to or immediately
@@ -271,6 +310,18 @@ func (yfast *YFastTrie) Iter(key uint64) *Iterator {
 	return yfast.iter(key)
 }
 
+// All returns an iterator over the entries in the trie in ascending
+// order of key.  The trie must not be modified during iteration.
+func (yfast *YFastTrie) All() iter.Seq[Entry] {
+	return func(yield func(Entry) bool) {
+		for it := yfast.Iter(0); it.Next(); {
+			if !yield(it.Value()) {
+				return
+			}
+		}
+	}
+}
+
 // New constructs, initializes, and returns a new y-fast trie.
 // Provided should be a uint type that specifies the number
 // of bits in the desired universe.  This will affect the time
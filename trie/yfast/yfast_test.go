@@ -17,6 +17,8 @@ limitations under the License.
 package yfast
 
 import (
+	"math/rand"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -191,6 +193,83 @@ func TestTrieIterator(t *testing.T) {
 	assert.Equal(t, Entries{}, iter.exhaust())
 }
 
+func TestTrieAll(t *testing.T) {
+	yfast := New(uint8(0))
+	assert.Empty(t, slices.Collect(yfast.All()))
+
+	e1, e2, e3 := newMockEntry(5), newMockEntry(12), newMockEntry(6)
+	yfast.Insert(e1, e2, e3)
+	assert.Equal(t, []Entry{e1, e3, e2}, slices.Collect(yfast.All()))
+	for entry := range yfast.All() {
+		assert.Equal(t, e1, entry)
+		break
+	}
+}
+
+func TestTrieMinMax(t *testing.T) {
+	yfast := New(uint8(0))
+	assert.Nil(t, yfast.Min())
+	assert.Nil(t, yfast.Max())
+
+	e1 := newMockEntry(5)
+	e2 := newMockEntry(40)
+	e3 := newMockEntry(7)
+	yfast.Insert(e1, e2, e3)
+	assert.Equal(t, e1, yfast.Min())
+	assert.Equal(t, e2, yfast.Max())
+
+	yfast.Delete(5, 40)
+	assert.Equal(t, e3, yfast.Min())
+	assert.Equal(t, e3, yfast.Max())
+}
+
+func TestTrieRandomOperations(t *testing.T) {
+	yfast := New(uint16(0))
+	var keys []uint64
+	for range 5000 {
+		key := uint64(rand.Intn(2000))
+		i, found := slices.BinarySearch(keys, key)
+		if rand.Intn(3) == 0 {
+			yfast.Delete(key)
+			if found {
+				keys = slices.Delete(keys, i, i+1)
+			}
+		} else {
+			yfast.Insert(newMockEntry(key))
+			if !found {
+				keys = slices.Insert(keys, i, key)
+			}
+		}
+		assert.Equal(t, uint64(len(keys)), yfast.Len())
+
+		query := uint64(rand.Intn(2000))
+		i, found = slices.BinarySearch(keys, query)
+		if i < len(keys) {
+			assert.Equal(t, keys[i], yfast.Successor(query).Key())
+		} else {
+			assert.Nil(t, yfast.Successor(query))
+		}
+		if !found {
+			i--
+		}
+		if i >= 0 {
+			assert.Equal(t, keys[i], yfast.Predecessor(query).Key())
+		} else {
+			assert.Nil(t, yfast.Predecessor(query))
+		}
+		if len(keys) > 0 {
+			assert.Equal(t, keys[0], yfast.Min().Key())
+			assert.Equal(t, keys[len(keys)-1], yfast.Max().Key())
+		}
+	}
+
+	var result []uint64
+	for iter := yfast.Iter(0); iter.Next(); {
+		result = append(result, iter.Value().Key())
+	}
+	assert.Equal(t, keys, result)
+}
+
 func BenchmarkInsert(b *testing.B) {
 	yfast := New(uint64(0))
 	entries := generateEntries(b.N)
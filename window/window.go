@@ -0,0 +1,248 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package window implements a fixed-capacity buffer of the most recent
+values in a stream, which keeps their sum, minimum, maximum and mean
+up to date as values are added and the oldest fall off, for streaming
+statistics over the last n values.  The slidingwindow package covers
+windows of time instead.
+
+The buffer is a ring, and the minimum and maximum are each kept in a
+monotonic deque: the values that could still become the extreme as
+older ones leave, in order.  Adding a value and reading any aggregate
+are O(1), amortized for the minimum and maximum.  NaNs are not
+supported.
+*/
+package window
+
+import "iter"
+
+// Number is a constraint matching the integer and floating point types.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Window holds the last Cap values added to it.  It is not threadsafe.
+type Window[T Number] struct {
+	values []T
+	// added is the number of values ever added, which numbers the next
+	// value; the values held are those numbered from added-len(values)
+	// onwards, and the value numbered n is at values[n%capacity]
+	added uint64
+	len   int
+	sum   T
+	// evictions counts values dropped since the sum was last
+	// recomputed, to stop floating point error from accumulating
+	evictions int
+	mins      deque
+	maxes     deque
+}
+
+// New returns an empty window that holds up to capacity values.
+func New[T Number](capacity int) *Window[T] {
+	if capacity <= 0 {
+		panic(`window: capacity must be positive`)
+	}
+	return &Window[T]{
+		values: make([]T, capacity),
+		mins:   newDeque(capacity),
+		maxes:  newDeque(capacity),
+	}
+}
+
+// Push adds value to the window, returning the oldest value and true if
+// it fell off to make room.
+func (w *Window[T]) Push(value T) (T, bool) {
+	var evicted T
+	full := w.Full()
+	n := w.added
+	if full {
+		oldest := n - uint64(len(w.values))
+		evicted = w.values[w.index(oldest)]
+		w.sum -= evicted
+		w.mins.dropFront(oldest)
+		w.maxes.dropFront(oldest)
+		w.evictions++
+	} else {
+		w.len++
+	}
+
+	w.values[w.index(n)] = value
+	w.added++
+	w.sum += value
+	for !w.mins.empty() && w.at(w.mins.back()) >= value {
+		w.mins.popBack()
+	}
+	w.mins.pushBack(n)
+	for !w.maxes.empty() && w.at(w.maxes.back()) <= value {
+		w.maxes.popBack()
+	}
+	w.maxes.pushBack(n)
+
+	if w.evictions >= len(w.values) {
+		w.recomputeSum()
+	}
+	return evicted, full
+}
+
+func (w *Window[T]) index(n uint64) int {
+	return int(n % uint64(len(w.values)))
+}
+
+// at returns the value numbered n.
+func (w *Window[T]) at(n uint64) T {
+	return w.values[w.index(n)]
+}
+
+func (w *Window[T]) recomputeSum() {
+	var sum T
+	for value := range w.All() {
+		sum += value
+	}
+	w.sum = sum
+	w.evictions = 0
+}
+
+// Len returns the number of values in the window.
+func (w *Window[T]) Len() int {
+	return w.len
+}
+
+// Cap returns the number of values the window holds once full.
+func (w *Window[T]) Cap() int {
+	return len(w.values)
+}
+
+// Full returns whether the window holds Cap values, so that the next
+// push drops the oldest.
+func (w *Window[T]) Full() bool {
+	return w.len == len(w.values)
+}
+
+// At returns the ith oldest value in the window, panicking if i is out
+// of range.
+func (w *Window[T]) At(i int) T {
+	if i < 0 || i >= w.len {
+		panic(`window: index out of range`)
+	}
+	return w.at(w.added - uint64(w.len) + uint64(i))
+}
+
+// All returns an iterator over the values in the window, oldest first.
+// The window must not be modified while it is being iterated.
+func (w *Window[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for n := w.added - uint64(w.len); n < w.added; n++ {
+			if !yield(w.at(n)) {
+				return
+			}
+		}
+	}
+}
+
+// Sum returns the sum of the values in the window, or zero if it is
+// empty.
+func (w *Window[T]) Sum() T {
+	return w.sum
+}
+
+// Mean returns the mean of the values in the window, or zero if it is
+// empty.
+func (w *Window[T]) Mean() float64 {
+	if w.len == 0 {
+		return 0
+	}
+	return float64(w.sum) / float64(w.len)
+}
+
+// Min returns the smallest value in the window, and false if it is
+// empty.
+func (w *Window[T]) Min() (T, bool) {
+	if w.mins.empty() {
+		var zero T
+		return zero, false
+	}
+	return w.at(w.mins.front()), true
+}
+
+// Max returns the largest value in the window, and false if it is
+// empty.
+func (w *Window[T]) Max() (T, bool) {
+	if w.maxes.empty() {
+		var zero T
+		return zero, false
+	}
+	return w.at(w.maxes.front()), true
+}
+
+// Reset empties the window.
+func (w *Window[T]) Reset() {
+	clear(w.values)
+	w.len = 0
+	w.sum = 0
+	w.evictions = 0
+	w.mins.reset()
+	w.maxes.reset()
+}
+
+// deque is a ring of the numbers of values in a window, in increasing
+// order, holding at most as many as the window.
+type deque struct {
+	numbers     []uint64
+	head, count int
+}
+
+func newDeque(capacity int) deque {
+	return deque{numbers: make([]uint64, capacity)}
+}
+
+func (d *deque) empty() bool {
+	return d.count == 0
+}
+
+func (d *deque) front() uint64 {
+	return d.numbers[d.head]
+}
+
+func (d *deque) back() uint64 {
+	return d.numbers[(d.head+d.count-1)%len(d.numbers)]
+}
+
+func (d *deque) pushBack(n uint64) {
+	d.numbers[(d.head+d.count)%len(d.numbers)] = n
+	d.count++
+}
+
+func (d *deque) popBack() {
+	d.count--
+}
+
+// dropFront removes n from the front of the deque if it is there, as
+// the value numbered n leaves the window.
+func (d *deque) dropFront(n uint64) {
+	if d.count > 0 && d.front() == n {
+		d.head = (d.head + 1) % len(d.numbers)
+		d.count--
+	}
+}
+
+func (d *deque) reset() {
+	d.head = 0
+	d.count = 0
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2014 Workiva, LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+ http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package window
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindow(t *testing.T) {
+	w := New[int](3)
+	_, ok := w.Min()
+	assert.False(t, ok)
+	_, ok = w.Max()
+	assert.False(t, ok)
+	assert.Equal(t, 0.0, w.Mean())
+
+	for _, v := range []int{5, 1, 3} {
+		_, evicted := w.Push(v)
+		assert.False(t, evicted)
+	}
+	assert.True(t, w.Full())
+	assert.Equal(t, 9, w.Sum())
+	assert.Equal(t, 3.0, w.Mean())
+	min, _ := w.Min()
+	max, _ := w.Max()
+	assert.Equal(t, 1, min)
+	assert.Equal(t, 5, max)
+
+	old, evicted := w.Push(2)
+	assert.True(t, evicted)
+	assert.Equal(t, 5, old)
+	assert.Equal(t, []int{1, 3, 2}, slices.Collect(w.All()))
+	assert.Equal(t, 1, w.At(0))
+	assert.Equal(t, 2, w.At(2))
+	assert.Panics(t, func() { w.At(3) })
+	max, _ = w.Max()
+	assert.Equal(t, 3, max)
+
+	w.Push(4)
+	min, _ = w.Min()
+	assert.Equal(t, 2, min)
+
+	w.Reset()
+	assert.Equal(t, 0, w.Len())
+	assert.Equal(t, 0, w.Sum())
+	w.Push(7)
+	assert.Equal(t, []int{7}, slices.Collect(w.All()))
+	min, _ = w.Min()
+	assert.Equal(t, 7, min)
+
+	assert.Panics(t, func() { New[int](0) })
+}
+
+func TestWindowMatchesBruteForce(t *testing.T) {
+	for _, capacity := range []int{1, 2, 7, 64} {
+		w := New[int](capacity)
+		var all []int
+		for range 1000 {
+			v := rand.Intn(50) - 25
+			w.Push(v)
+			all = append(all, v)
+
+			last := all[max(0, len(all)-capacity):]
+			assert.Equal(t, last, slices.Collect(w.All()))
+			sum := 0
+			for _, x := range last {
+				sum += x
+			}
+			assert.Equal(t, sum, w.Sum())
+			min, _ := w.Min()
+			max, _ := w.Max()
+			assert.Equal(t, slices.Min(last), min)
+			assert.Equal(t, slices.Max(last), max)
+		}
+	}
+}
+
+func TestWindowFloatSumDoesNotDrift(t *testing.T) {
+	w := New[float64](10)
+	w.Push(1e16)
+	for range 9 {
+		w.Push(1)
+	}
+	for range 100 {
+		w.Push(0.1)
+	}
+	assert.InDelta(t, 1.0, w.Sum(), 1e-9)
+	assert.False(t, math.IsNaN(w.Mean()))
+}